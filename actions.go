@@ -0,0 +1,233 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ActionStats measures how much the action cards changed the outcome of
+// simulated rounds, by the strategy of the player involved
+type ActionStats struct {
+	Strategies map[string]*ActionRecord
+}
+
+// ActionRecord is one strategy's action card tally. Rounds and RoundPoints
+// are its rounds that no Freeze cut short, the yardstick for what a frozen
+// player would have scored. Freezes counts the Freeze cards it played on
+// other players by the target's strategy, and FrozenPoints what those
+// players scored. FlipThrees counts the Flip Three cards it played on
+// other players and FlipThreeBusts how many made the target bust.
+// SecondChances counts the busts a Second Chance saved it from and
+// SavedPoints what it went on to score in those rounds. Stayed, Frozen
+// and Busted count how each of its rounds ended.
+type ActionRecord struct {
+	Rounds         int
+	RoundPoints    int
+	Freezes        map[string]int
+	FrozenPoints   map[string]int
+	FlipThrees     int
+	FlipThreeBusts int
+	SecondChances  int
+	SavedPoints    int
+	Stayed         int
+	Frozen         int
+	Busted         int
+}
+
+// NewActionStats creates an empty tally
+func NewActionStats() *ActionStats {
+	return &ActionStats{Strategies: make(map[string]*ActionRecord)}
+}
+
+// SetActionStats turns on the action card report for simulations
+func (g *Game) SetActionStats(enabled bool) {
+	g.actionStats = enabled
+}
+
+// record returns the tally for a strategy
+func (a *ActionStats) record(strategy string) *ActionRecord {
+	r := a.Strategies[strategy]
+	if r == nil {
+		r = &ActionRecord{Freezes: make(map[string]int), FrozenPoints: make(map[string]int)}
+		a.Strategies[strategy] = r
+	}
+	return r
+}
+
+// strategyOf names a player's strategy for the action card report
+func strategyOf(p PlayerInterface) string {
+	switch p := p.(type) {
+	case *ComputerPlayer:
+		return p.Spec.Name()
+	case *ChatPlayer:
+		return "chat"
+	}
+	return "human"
+}
+
+// flipThree is a Flip Three being dealt to its target
+type flipThree struct {
+	by   string
+	left int
+}
+
+// trackActions follows the action cards played in the game's rounds
+func (g *Game) trackActions(a *ActionStats) {
+	// Who froze each frozen player this round, "" when they froze
+	// themselves; the Flip Three each player is being dealt; and how
+	// many busts each player's Second Chances saved them from
+	frozen := make(map[PlayerInterface]string)
+	flipping := make(map[PlayerInterface]*flipThree)
+	saved := make(map[PlayerInterface]int)
+
+	g.Subscribe(func(event Event) {
+		p := event.Player
+		switch event.Type {
+		case RoundStarted:
+			clear(frozen)
+			clear(flipping)
+			clear(saved)
+		case ActionPlayed:
+			self := event.Target == p
+			switch event.Card.Action {
+			case Freeze:
+				if self {
+					frozen[p] = ""
+				} else {
+					frozen[event.Target] = strategyOf(p)
+					a.record(strategyOf(p)).Freezes[strategyOf(event.Target)]++
+				}
+			case FlipThree:
+				if !self {
+					flipping[event.Target] = &flipThree{by: strategyOf(p), left: 3}
+					a.record(strategyOf(p)).FlipThrees++
+				}
+			}
+		case CardDrawn:
+			// A draw after the third card is the target's own
+			if f := flipping[p]; f != nil {
+				if f.left == 0 {
+					delete(flipping, p)
+				} else {
+					f.left--
+				}
+			}
+		case PlayerStayed:
+			delete(flipping, p)
+		case PlayerBusted:
+			if f := flipping[p]; f != nil {
+				a.record(f.by).FlipThreeBusts++
+				delete(flipping, p)
+			}
+		case SecondChanceUsed:
+			saved[p]++
+			a.record(strategyOf(p)).SecondChances++
+		case PlayerScored:
+			r := a.record(strategyOf(p))
+			switch p.GetState() {
+			case Stayed:
+				r.Stayed++
+			case Frozen:
+				r.Frozen++
+			case Busted:
+				r.Busted++
+			}
+			r.SavedPoints += saved[p] * event.Score
+			if by, ok := frozen[p]; !ok {
+				r.Rounds++
+				r.RoundPoints += event.Score
+			} else if by != "" {
+				a.record(by).FrozenPoints[strategyOf(p)] += event.Score
+			}
+		}
+	})
+}
+
+// denied is the average number of points the strategy's Freeze cards
+// cost their targets, compared with each target strategy's unfrozen
+// rounds
+func (a *ActionStats) denied(r *ActionRecord) float64 {
+	freezes, points := 0, 0.0
+	for target, n := range r.Freezes {
+		freezes += n
+		if t := a.Strategies[target]; t != nil && t.Rounds > 0 {
+			points += float64(n) * float64(t.RoundPoints) / float64(t.Rounds)
+		}
+		points -= float64(r.FrozenPoints[target])
+	}
+	if freezes == 0 {
+		return 0
+	}
+	return points / float64(freezes)
+}
+
+// actionImpact is one strategy's line of the action card report
+type actionImpact struct {
+	Strategy string
+	Freezes  int
+	// Denied is the average points a Freeze cost its target
+	Denied     float64
+	FlipThrees int
+	// Busted is the percentage of Flip Threes that made the target bust
+	Busted        float64
+	SecondChances int
+	// Kept is the average round score after a Second Chance saved a bust
+	Kept float64
+	// Stayed, Frozen and Busted are the percentages of rounds that ended
+	// each way, or negative if the strategy has no finished rounds
+	EndStayed, EndFrozen, EndBusted float64
+}
+
+// impacts returns every strategy's line of the report, by name
+func (a *ActionStats) impacts() []actionImpact {
+	var impacts []actionImpact
+	for _, name := range slices.Sorted(maps.Keys(a.Strategies)) {
+		r := a.Strategies[name]
+		impact := actionImpact{Strategy: name, Denied: a.denied(r), FlipThrees: r.FlipThrees, SecondChances: r.SecondChances}
+		for _, n := range r.Freezes {
+			impact.Freezes += n
+		}
+		if r.FlipThrees > 0 {
+			impact.Busted = 100 * float64(r.FlipThreeBusts) / float64(r.FlipThrees)
+		}
+		if r.SecondChances > 0 {
+			impact.Kept = float64(r.SavedPoints) / float64(r.SecondChances)
+		}
+		impact.EndStayed, impact.EndFrozen, impact.EndBusted = -1, -1, -1
+		if rounds := r.Stayed + r.Frozen + r.Busted; rounds > 0 {
+			percent := func(n int) float64 { return 100 * float64(n) / float64(rounds) }
+			impact.EndStayed, impact.EndFrozen, impact.EndBusted = percent(r.Stayed), percent(r.Frozen), percent(r.Busted)
+		}
+		impacts = append(impacts, impact)
+	}
+	return impacts
+}
+
+// displayActionStatistics shows what each strategy's action cards did
+func (g *Game) displayActionStatistics(a *ActionStats) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf("%s\n", T("🃏 ACTION CARD IMPACT"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-14s %7s %7s %7s %7s %6s %6s\n", T("STRATEGY"), T("FREEZES"), T("DENIED"), T("FLIP 3"), T("BUSTED"), T("SAVES"), T("KEPT"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+
+	impacts := a.impacts()
+	for _, i := range impacts {
+		g.resultf("%-14.14s %7d %7.1f %7d %6.1f%% %6d %6.1f\n",
+			i.Strategy, i.Freezes, i.Denied, i.FlipThrees, i.Busted, i.SecondChances, i.Kept)
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%s\n", T("DENIED: points a Freeze cost its target against their strategy's average round;\n        below zero, freezing them banked more than they usually score"))
+	g.resultf("%s\n", T("BUSTED: Flip Threes on other players that made them bust"))
+	g.resultf("%s\n", T("KEPT: average round score after a Second Chance saved a bust"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%-14s %7s %7s %7s\n", T("ROUND ENDS"), T("STAYED"), T("FROZEN"), T("BUSTED"))
+	for _, i := range impacts {
+		if i.EndStayed < 0 {
+			continue
+		}
+		g.resultf("%-14.14s %6.1f%% %6.1f%% %6.1f%%\n", i.Strategy, i.EndStayed, i.EndFrozen, i.EndBusted)
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// adaptiveTargetWinRate is the share of games the human should win
+	adaptiveTargetWinRate = 0.4
+	// adaptiveStep is how far one result moves the computers' skill
+	adaptiveStep = 0.1
+	// adaptiveRecentGames is how many results are kept for the win rate
+	adaptiveRecentGames = 20
+)
+
+// AdaptiveProfile is a human's record against adaptive computer players.
+// Skill runs from 0 (timid and sloppy) to 1 (card counting, sharp targeting).
+type AdaptiveProfile struct {
+	Skill  float64
+	Games  int
+	Wins   int
+	Recent []bool
+}
+
+// adaptiveProfilesPath is where adaptive profiles are kept
+func adaptiveProfilesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flip7", "profiles.json"), nil
+}
+
+// loadAdaptiveProfiles reads every human's adaptive profile
+func loadAdaptiveProfiles(path string) (map[string]AdaptiveProfile, error) {
+	profiles := make(map[string]AdaptiveProfile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("reading profiles %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// saveAdaptiveProfiles writes every human's adaptive profile
+func saveAdaptiveProfiles(path string, profiles map[string]AdaptiveProfile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// WinRate returns the human's win rate over their recent games
+func (p AdaptiveProfile) WinRate() float64 {
+	if len(p.Recent) == 0 {
+		return 0
+	}
+	wins := 0
+	for _, won := range p.Recent {
+		if won {
+			wins++
+		}
+	}
+	return float64(wins) / float64(len(p.Recent))
+}
+
+// Spec returns the strategy computers play at the profile's skill: the
+// bust threshold rises toward card-counting play and random decisions and
+// targets fade out as skill goes up
+func (p AdaptiveProfile) Spec() StrategySpec {
+	return StrategySpec{
+		Choice:          2,
+		BustProbability: 0.12 + 0.18*p.Skill,
+		Noise:           0.3 * (1 - p.Skill),
+		Difficulty:      "adaptive",
+	}
+}
+
+// Record moves the skill after a game so the win rate drifts toward the
+// target: a win raises it by step*(1-target), a loss lowers it by step*target
+func (p *AdaptiveProfile) Record(won bool) {
+	p.Games++
+	result := 0.0
+	if won {
+		p.Wins++
+		result = 1
+	}
+	p.Skill = min(1, max(0, p.Skill+adaptiveStep*(result-adaptiveTargetWinRate)))
+
+	p.Recent = append(p.Recent, won)
+	if len(p.Recent) > adaptiveRecentGames {
+		p.Recent = p.Recent[len(p.Recent)-adaptiveRecentGames:]
+	}
+}
+
+// adaptiveSpec loads the human's profile and returns the strategy for the
+// computers. The result of the game is recorded when it ends.
+func (g *Game) adaptiveSpec(human PlayerInterface) (StrategySpec, error) {
+	path, err := adaptiveProfilesPath()
+	if err != nil {
+		return StrategySpec{}, err
+	}
+	profiles, err := loadAdaptiveProfiles(path)
+	if err != nil {
+		return StrategySpec{}, err
+	}
+
+	profile, ok := profiles[human.GetName()]
+	if !ok {
+		profile.Skill = 0.5
+	}
+	g.printf(T("🎚️ Adaptive computers at skill %.2f (your recent win rate: %.0f%% over %d games)\n"),
+		profile.Skill, profile.WinRate()*100, len(profile.Recent))
+
+	g.Subscribe(func(event Event) {
+		if event.Type != GameEnded {
+			return
+		}
+		profile.Record(event.Player == human)
+		profiles[human.GetName()] = profile
+		if err := saveAdaptiveProfiles(path, profiles); err != nil {
+			g.logger.Warn("saving adaptive profile", "error", err)
+		}
+	})
+
+	return profile.Spec(), nil
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// analysisStandIn is the strategy that plays for humans when a recorded
+// position is played out
+var analysisStandIn = difficulties[len(difficulties)-1].Spec
+
+// Assessment is how one recorded decision compares with the other choice:
+// each choice's chance of winning the game from the position, by playing
+// it out many times
+type Assessment struct {
+	Decision ReplayDecision
+	// HitWins and StayWins are the chances of winning after each choice
+	HitWins, StayWins float64
+}
+
+// chosen returns the chance of winning after the choice that was made,
+// and after the better choice
+func (a Assessment) chosen() (chosen, best float64) {
+	chosen = a.StayWins
+	if a.Decision.Hit {
+		chosen = a.HitWins
+	}
+	return chosen, max(a.HitWins, a.StayWins)
+}
+
+// Loss is the chance of winning the choice gave away
+func (a Assessment) Loss() float64 {
+	chosen, best := a.chosen()
+	return best - chosen
+}
+
+// Analysis is a review of every decision in a replay
+type Analysis struct {
+	Replay      *Replay
+	Rollouts    int
+	Seed        int64
+	Threshold   float64
+	Parallel    int
+	Assessments []Assessment
+}
+
+// RunAnalyze handles the analyze subcommand. It plays every recorded
+// hit/stay decision out both ways, many times from the same shuffles of
+// the unseen cards, flags the decisions that gave away more of the
+// chance of winning than the threshold, and scores each player's
+// accuracy: how much of the best choice's chance of winning their
+// choices kept, on average.
+func RunAnalyze(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	rollouts := fs.Int("n", 200, "Games to play out from each position for each choice")
+	seed := fs.Int64("seed", 1, "Seed for the shuffles of the unseen cards")
+	threshold := fs.Float64("threshold", 0.1, "Chance of winning a decision may give away before it's a blunder")
+	parallel := fs.Int("parallel", 0, "How many positions to play out at once (0 uses every CPU)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: flip7 analyze [-n GAMES] [-seed N] [-threshold P] [-parallel N] REPLAY.f7r")
+	}
+	if *rollouts < 1 {
+		return fmt.Errorf("-n must be positive")
+	}
+	if *threshold <= 0 || *threshold >= 1 {
+		return fmt.Errorf("-threshold must be between 0 and 1")
+	}
+	if *parallel <= 0 {
+		*parallel = runtime.NumCPU()
+	}
+	replay, err := LoadReplay(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	a := &Analysis{Replay: replay, Rollouts: *rollouts, Seed: *seed, Threshold: *threshold, Parallel: *parallel}
+	report := NewGame()
+	report.SetOutput(out)
+	report.printf(T("🔍 Playing out %d decisions both ways, %d games each...\n"), len(replay.Decisions), *rollouts)
+	start := time.Now()
+	if err := a.Run(); err != nil {
+		return err
+	}
+	report.printf(T("🔍 Analyzed in %.1fs\n"), time.Since(start).Seconds())
+	report.displayAnalysis(a)
+	return nil
+}
+
+// Run assesses every decision, several at once
+func (a *Analysis) Run() error {
+	type finished struct {
+		idx        int
+		assessment Assessment
+		err        error
+	}
+	done := make(chan finished)
+	slots := make(chan struct{}, a.Parallel)
+	for i, d := range a.Replay.Decisions {
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			assessment, err := a.assess(d)
+			done <- finished{i, assessment, err}
+		}()
+	}
+
+	a.Assessments = make([]Assessment, len(a.Replay.Decisions))
+	var errs []error
+	for range a.Replay.Decisions {
+		f := <-done
+		if f.err != nil {
+			errs = append(errs, fmt.Errorf("decision %d: %w", f.idx+1, f.err))
+			continue
+		}
+		a.Assessments[f.idx] = f.assessment
+	}
+	return errors.Join(errs...)
+}
+
+// assess plays the decision's position out both ways. Both choices are
+// played from the same shuffles, so luck of the draw doesn't favour
+// either.
+func (a *Analysis) assess(d ReplayDecision) (Assessment, error) {
+	assessment := Assessment{Decision: d}
+	for _, hit := range []bool{true, false} {
+		wins := 0
+		for i := range a.Rollouts {
+			winner, err := a.Replay.playOut(d, &hit, a.Seed+int64(i))
+			if err != nil {
+				return assessment, err
+			}
+			if winner == d.Seat {
+				wins++
+			}
+		}
+		rate := float64(wins) / float64(a.Rollouts)
+		if hit {
+			assessment.HitWins = rate
+		} else {
+			assessment.StayWins = rate
+		}
+	}
+	return assessment, nil
+}
+
+// forcedChoice is the deciding player when a position is played out: it
+// makes the choice being assessed, then plays on with its strategy
+type forcedChoice struct {
+	*ComputerPlayer
+	hit  bool
+	made bool
+}
+
+func (p *forcedChoice) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	if !p.made {
+		p.made = true
+		return p.hit, nil
+	}
+	return p.ComputerPlayer.MakeHitStayDecision(ctx, gameState)
+}
+
+// playOut plays the rest of the game from the position and returns the
+// winner's seat. If hit is given, the player about to act makes that
+// choice first. The unseen cards are shuffled from seed; computer players
+// play their own strategies and humans the stand-in's.
+func (r *Replay) playOut(d ReplayDecision, hit *bool, seed int64) (int, error) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetSeed(seed)
+	g.seedRandom(seed)
+	g.SetWinningScore(r.WinningScore)
+	g.SetHoldActions(r.HoldActions)
+	g.SetBurnCards(r.Burn)
+	if r.Reshuffle != "" {
+		policy, err := ParseReshufflePolicy(r.Reshuffle)
+		if err != nil {
+			return 0, err
+		}
+		g.SetReshufflePolicy(policy)
+	}
+	if r.Scoring != nil {
+		g.SetScorer(*r.Scoring)
+	}
+	if len(r.Cards) > 0 {
+		if err := g.SetCardSet(r.Cards); err != nil {
+			return 0, err
+		}
+	}
+
+	var inPlay []*Card
+	for i, sp := range r.Players {
+		spec := analysisStandIn
+		if !sp.Human {
+			spec = *sp.Strategy
+		}
+		p := NewComputerPlayerFromSpec(sp.Name, spec)
+		p.TotalScore = d.Totals[i]
+		hand, err := parseCards(d.Hands[i])
+		if err != nil {
+			return 0, err
+		}
+		for _, card := range hand {
+			p.AddCard(card)
+		}
+		inPlay = append(inPlay, hand...)
+		state, ok := scenarioStates[d.States[i]]
+		if !ok {
+			return 0, fmt.Errorf("%s has unknown state %q", sp.Name, d.States[i])
+		}
+		p.State = state
+
+		if i == d.Seat && hit != nil {
+			g.players = append(g.players, &forcedChoice{ComputerPlayer: p, hit: *hit})
+		} else {
+			g.players = append(g.players, p)
+		}
+	}
+	discards, err := parseCards(d.Discards)
+	if err != nil {
+		return 0, err
+	}
+	if err := g.stackDeck(nil, discards, append(inPlay, discards...)); err != nil {
+		return 0, err
+	}
+
+	n := len(g.players)
+	g.round = d.Round
+	g.dealerIdx = d.Dealer
+	g.startTurn = (d.Seat - d.Dealer - 1 + 2*n) % n
+	g.midRound = true
+	if err := g.Run(context.Background()); err != nil {
+		return 0, err
+	}
+	return slices.Index(g.players, g.getWinner()), nil
+}
+
+// accuracy is a player's accuracy over their assessed decisions: the
+// average share of the better choice's chance of winning that their
+// choice kept. A position lost either way costs nothing.
+type accuracy struct {
+	name      string
+	decisions int
+	blunders  int
+	kept      float64
+}
+
+// displayAnalysis lists the blunders and every player's accuracy
+func (g *Game) displayAnalysis(a *Analysis) {
+	players := make([]accuracy, len(a.Replay.Players))
+	for i, p := range a.Replay.Players {
+		players[i].name = p.Name
+	}
+
+	g.resultf("\n%s\n", strings.Repeat("=", 70))
+	g.resultf(T("🔍 GAME REVIEW - %d games per choice, blunders give away %.0f%% or more\n"), a.Rollouts, 100*a.Threshold)
+	g.resultf("%s\n", strings.Repeat("=", 70))
+	for _, as := range a.Assessments {
+		d := as.Decision
+		acc := &players[d.Seat]
+		acc.decisions++
+		chosen, best := as.chosen()
+		if best > 0 {
+			acc.kept += chosen / best
+		} else {
+			acc.kept++
+		}
+		if as.Loss() < a.Threshold {
+			continue
+		}
+		acc.blunders++
+		choice, better := T("chose to stay"), T("hitting")
+		if d.Hit {
+			choice, better = T("chose to hit"), T("staying")
+		}
+		hand, _ := parseCards(d.Hands[d.Seat])
+		cards := make([]string, len(hand))
+		for i, card := range hand {
+			cards[i] = g.renderer.Card(card)
+		}
+		g.resultf(T("❌ Round %d, %s %s holding %s (total %d)\n"), d.Round, acc.name, choice, strings.Join(cards, " "), d.Totals[d.Seat])
+		g.resultf(T("   wins %.0f%% of games played out; %s wins %.0f%% (-%.0f%%)\n"), 100*chosen, better, 100*best, 100*as.Loss())
+	}
+	if !slices.ContainsFunc(players, func(p accuracy) bool { return p.blunders > 0 }) {
+		g.resultf("%s\n", T("No blunders"))
+	}
+
+	g.resultf("%s\n", strings.Repeat("-", 70))
+	g.resultf("%-24s %10s %10s %10s\n", T("PLAYER"), T("DECISIONS"), T("BLUNDERS"), T("ACCURACY"))
+	g.resultf("%s\n", strings.Repeat("-", 70))
+	for _, p := range players {
+		if p.decisions == 0 {
+			g.resultf("%-24s %10d %10s %10s\n", p.name, 0, "-", "-")
+			continue
+		}
+		g.resultf("%-24s %10d %10d %9.1f%%\n", p.name, p.decisions, p.blunders, 100*p.kept/float64(p.decisions))
+	}
+	g.resultf("%s\n", strings.Repeat("-", 70))
+	if a.Replay.Winner != "" {
+		g.resultf(T("%s won the game\n"), a.Replay.Winner)
+	}
+	g.resultf("%s\n", strings.Repeat("=", 70))
+}
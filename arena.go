@@ -0,0 +1,652 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Arena is a bot competition, read from YAML or TOML: the competing
+// bots, how they're confined, and the tournament they play. Every
+// combination of Table bots plays Games games, dealt from seeds Seed on.
+type Arena struct {
+	Bots  []ArenaBot  `yaml:"bots" toml:"bots"`
+	Games int         `yaml:"games" toml:"games"`
+	Table int         `yaml:"table" toml:"table"`
+	Score int         `yaml:"score" toml:"score"`
+	Seed  int64       `yaml:"seed" toml:"seed"`
+	Limit ArenaLimits `yaml:"limits" toml:"limits"`
+}
+
+// ArenaBot is a competitor: a command to run, or a container image to
+// run with Runtime, "docker" unless set
+type ArenaBot struct {
+	Name    string   `yaml:"name" toml:"name"`
+	Command []string `yaml:"command" toml:"command"`
+	Image   string   `yaml:"image" toml:"image"`
+	Runtime string   `yaml:"runtime" toml:"runtime"`
+}
+
+// ArenaLimits confine each bot. A command gets CPU seconds and memory
+// through ulimit; a container gets CPUs and memory from its runtime and
+// no network. Turn is how long a bot has for each decision and Game how
+// long for all of a game's. A bot out of time is disqualified, or with
+// OnTimeout set to "stay" or "hit" has that decided for it.
+type ArenaLimits struct {
+	CPUTime   string  `yaml:"cpu_time" toml:"cpu_time"`
+	CPUs      float64 `yaml:"cpus" toml:"cpus"`
+	Memory    int     `yaml:"memory_mb" toml:"memory_mb"`
+	Turn      string  `yaml:"turn" toml:"turn"`
+	Game      string  `yaml:"game" toml:"game"`
+	OnTimeout string  `yaml:"on_timeout" toml:"on_timeout"`
+
+	cpuTime, turn, game time.Duration
+	fallback            *TurnTimeout // nil disqualifies
+}
+
+// ArenaStanding is a bot's record in the tournament
+type ArenaStanding struct {
+	Rank         int     `json:"rank"`
+	Name         string  `json:"name"`
+	Games        int     `json:"games"`
+	Wins         int     `json:"wins"`
+	AverageScore float64 `json:"average_score"`
+	Timeouts     int     `json:"timeouts,omitempty"`
+	Disqualified string  `json:"disqualified,omitempty"`
+	// Rounds it played, busted and flipped 7 in, and its round scores
+	// summed over them
+	Rounds int `json:"rounds"`
+	Busts  int `json:"busts"`
+	Flip7s int `json:"flip7s"`
+	Points int `json:"points"`
+}
+
+// ArenaResults is the tournament's summary in the results bundle
+type ArenaResults struct {
+	Started   time.Time       `json:"started"`
+	Finished  time.Time       `json:"finished"`
+	Games     int             `json:"games"`
+	Skipped   int             `json:"skipped"`
+	Score     int             `json:"score"`
+	Standings []ArenaStanding `json:"standings"`
+	// Notables keeps the most notable games, by seed
+	Notables *NotableGames `json:"-"`
+}
+
+// LoadArena reads and validates an arena manifest
+func LoadArena(path string) (*Arena, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var a Arena
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &a)
+	} else {
+		err = yaml.Unmarshal(data, &a)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading arena %s: %w", path, err)
+	}
+	if a.Games == 0 {
+		a.Games = 10
+	}
+	if a.Table == 0 {
+		a.Table = 2
+	}
+	if a.Score == 0 {
+		a.Score = 200
+	}
+	if a.Seed == 0 {
+		a.Seed = 1
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid arena %s:\n%w", path, err)
+	}
+	return &a, nil
+}
+
+// Validate reports every problem with the arena at once
+func (a *Arena) Validate() error {
+	var errs []error
+	if len(a.Bots) < 2 {
+		errs = append(errs, fmt.Errorf("  bots: need at least two"))
+	}
+	names := make(map[string]bool)
+	for i, b := range a.Bots {
+		where := fmt.Sprintf("  bots[%d]", i)
+		if b.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", where))
+		} else if strings.ContainsAny(b.Name, `/\`) || b.Name[0] == '.' {
+			errs = append(errs, fmt.Errorf("%s: name %q can't name a log file", where, b.Name))
+		} else if names[strings.ToLower(b.Name)] {
+			errs = append(errs, fmt.Errorf("%s: name %q is used twice", where, b.Name))
+		}
+		names[strings.ToLower(b.Name)] = true
+		if (len(b.Command) == 0) == (b.Image == "") {
+			errs = append(errs, fmt.Errorf("%s: needs a command or an image, not both", where))
+		}
+	}
+	if a.Table < 2 || a.Table > 18 {
+		errs = append(errs, fmt.Errorf("  table: must be between 2 and 18"))
+	} else if a.Table > len(a.Bots) {
+		errs = append(errs, fmt.Errorf("  table: %d players but only %d bots", a.Table, len(a.Bots)))
+	}
+	if a.Games < 1 {
+		errs = append(errs, fmt.Errorf("  games: must be positive"))
+	}
+	if a.Score < 1 {
+		errs = append(errs, fmt.Errorf("  score: must be positive"))
+	}
+	if a.Limit.CPUs < 0 || a.Limit.Memory < 0 {
+		errs = append(errs, fmt.Errorf("  limits: cpus and memory_mb can't be negative"))
+	}
+	for _, d := range []struct {
+		name, value, fallback string
+		to                    *time.Duration
+	}{
+		{"cpu_time", a.Limit.CPUTime, "60s", &a.Limit.cpuTime},
+		{"turn", a.Limit.Turn, "2s", &a.Limit.turn},
+		{"game", a.Limit.Game, "60s", &a.Limit.game},
+	} {
+		value := d.value
+		if value == "" {
+			value = d.fallback
+		}
+		v, err := time.ParseDuration(value)
+		if err != nil || v <= 0 {
+			errs = append(errs, fmt.Errorf("  limits: %s: %q is not a duration such as %s", d.name, d.value, d.fallback))
+		}
+		*d.to = v
+	}
+	switch a.Limit.OnTimeout {
+	case "", "disqualify":
+		a.Limit.fallback = nil
+	case "stay", "hit":
+		t, _ := ParseTurnTimeout(a.Limit.turn, a.Limit.OnTimeout)
+		a.Limit.fallback = &t
+	default:
+		errs = append(errs, fmt.Errorf("  limits: on_timeout: %q isn't disqualify, stay or hit", a.Limit.OnTimeout))
+	}
+	return errors.Join(errs...)
+}
+
+// tables lists every combination of Table bots, by index
+func (a *Arena) tables() [][]int {
+	var tables [][]int
+	var pick func(start int, table []int)
+	pick = func(start int, table []int) {
+		if len(table) == a.Table {
+			tables = append(tables, slices.Clone(table))
+			return
+		}
+		for i := start; i < len(a.Bots); i++ {
+			pick(i+1, append(table, i))
+		}
+	}
+	pick(0, nil)
+	return tables
+}
+
+// arenaProcess is a running bot: its input, and its output a line at a
+// time until it exits
+type arenaProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	lines     chan []byte
+	stopped   chan struct{}
+	container string // the container to remove, if any
+}
+
+// arenaContainers numbers the arena's containers
+var arenaContainers atomic.Int64
+
+// command builds the bot's command line inside its limits
+func (b ArenaBot) command(limits ArenaLimits) *exec.Cmd {
+	if b.Image != "" {
+		runtime := b.Runtime
+		if runtime == "" {
+			runtime = "docker"
+		}
+		name := fmt.Sprintf("flip7-arena-%d-%d", os.Getpid(), arenaContainers.Add(1))
+		args := []string{"run", "--rm", "-i", "--name", name, "--network", "none", "--pids-limit", "64"}
+		if limits.CPUs > 0 {
+			args = append(args, "--cpus", strconv.FormatFloat(limits.CPUs, 'f', -1, 64))
+		}
+		if limits.Memory > 0 {
+			args = append(args, "--memory", strconv.Itoa(limits.Memory)+"m")
+		}
+		return exec.Command(runtime, append(args, b.Image)...)
+	}
+
+	ulimit := fmt.Sprintf("ulimit -t %d", int(limits.cpuTime.Seconds()+0.5))
+	if limits.Memory > 0 {
+		ulimit += fmt.Sprintf(" -v %d", limits.Memory*1024)
+	}
+	return exec.Command("sh", append([]string{"-c", ulimit + ` && exec "$@"`, "sh"}, b.Command...)...)
+}
+
+// start runs the bot, writing what it prints to standard error to log
+func (b ArenaBot) start(limits ArenaLimits, log io.Writer) (*arenaProcess, error) {
+	cmd := b.command(limits)
+	cmd.Stderr = log
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", b.Name, err)
+	}
+	p := &arenaProcess{cmd: cmd, stdin: stdin, lines: make(chan []byte, 1), stopped: make(chan struct{})}
+	if b.Image != "" {
+		p.container = cmd.Args[slices.Index(cmd.Args, "--name")+1]
+	}
+	go func() {
+		defer close(p.lines)
+		r := bufio.NewReader(stdout)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case p.lines <- line:
+				case <-p.stopped:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return p, nil
+}
+
+// send writes a message to the bot. A bot that has stopped reading
+// fails its next turn, so the error is left to that.
+func (p *arenaProcess) send(m ladderMessage) {
+	data, _ := json.Marshal(m)
+	p.stdin.Write(append(data, '\n'))
+}
+
+// stop closes the bot's input, gives it a moment to exit, and kills it
+// if it hasn't
+func (p *arenaProcess) stop() {
+	close(p.stopped)
+	p.stdin.Close()
+	exited := make(chan struct{})
+	go func() {
+		p.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		if p.container != "" {
+			exec.Command(p.cmd.Path, "rm", "-f", p.container).Run()
+		}
+		p.cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// ArenaPlayer is a bot's seat in an arena game. Its hit/stay decisions
+// come from the bot in the ladder's messages; action targets follow the
+// expert strategy. A bot that breaks the rules is disqualified, and its
+// seat stays from then on so the game can finish.
+type ArenaPlayer struct {
+	*ComputerPlayer
+	proc     *arenaProcess
+	game     *Game
+	limits   ArenaLimits
+	thought  time.Duration
+	events   []EventMessage
+	timeouts int
+	fault    string
+}
+
+// MakeHitStayDecision sends the bot its observation and waits for its
+// decision, within the turn and game limits
+func (p *ArenaPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	if p.fault != "" {
+		return false, nil
+	}
+	step := observe(p.game, p, gameState)
+	step.Events, p.events = p.events, nil
+	step.LegalActions = actionIndexes(p.game.LegalActions(p))
+	p.proc.send(ladderMessage{Type: "turn", Step: &step})
+
+	wait := min(p.limits.turn, p.limits.game-p.thought)
+	start := time.Now()
+	turn, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	select {
+	case line, ok := <-p.proc.lines:
+		p.thought += time.Since(start)
+		if !ok {
+			p.fault = "exited during its turn"
+			return false, nil
+		}
+		var req envRequest
+		if err := json.Unmarshal(line, &req); err != nil || req.Op != "step" || req.Action < 0 || req.Action >= len(envActions) {
+			p.fault = fmt.Sprintf("answered a turn with %.60q", strings.TrimSpace(string(line)))
+			return false, nil
+		}
+		return req.Action == 1, nil
+	case <-turn.Done():
+		p.thought += time.Since(start)
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if p.limits.fallback != nil {
+			p.timeouts++
+			return p.limits.fallback.Hit, nil
+		}
+		if wait < p.limits.turn {
+			p.fault = fmt.Sprintf("took more than %v over a game", p.limits.game)
+		} else {
+			p.fault = fmt.Sprintf("took more than %v over a turn", p.limits.turn)
+		}
+		return false, nil
+	}
+}
+
+// DecideAction plays Freeze and Flip Three on the leader and gives a
+// spare Second Chance to last place
+func (p *ArenaPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	return p.targetStrategy(decision.Action)(p, gameState, decision.Action), nil
+}
+
+// arenaGame is how an arena game went: its result, the faults of bots
+// that broke the rules, how many turns each bot let run out, and each
+// bot's rounds
+type arenaGame struct {
+	result   GameResult
+	faults   map[string]string
+	timeouts map[string]int
+	rounds   map[string]*ArenaStanding
+}
+
+// play runs one game between the bots at a table, writing their logs to
+// dir and keeping it in notables if it stands out
+func (a *Arena) play(ctx context.Context, table []int, game int, seed int64, dir string, notables *NotableGames) (arenaGame, error) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetRandom(rand.New(rand.NewSource(seed)))
+	g.SetWinningScore(a.Score)
+
+	var players []*ArenaPlayer
+	var names []string
+	outcome := arenaGame{faults: make(map[string]string), timeouts: make(map[string]int), rounds: make(map[string]*ArenaStanding)}
+	faults := outcome.faults
+	defer func() {
+		for _, p := range players {
+			p.proc.stop()
+		}
+	}()
+	for _, i := range table {
+		bot := a.Bots[i]
+		log, err := os.OpenFile(filepath.Join(dir, "logs", bot.Name+".log"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return outcome, err
+		}
+		defer log.Close()
+		fmt.Fprintf(log, "=== game %d, seed %d\n", game, seed)
+		proc, err := bot.start(a.Limit, log)
+		if err != nil {
+			faults[bot.Name] = err.Error()
+			continue
+		}
+		p := &ArenaPlayer{ComputerPlayer: NewComputerPlayerFromSpec(bot.Name, StrategySpec{Choice: 6}), proc: proc, game: g, limits: a.Limit}
+		players = append(players, p)
+		g.players = append(g.players, p)
+		names = append(names, bot.Name)
+		outcome.rounds[bot.Name] = &ArenaStanding{}
+	}
+	if len(faults) > 0 {
+		return outcome, nil
+	}
+	g.Subscribe(func(event Event) {
+		for _, p := range players {
+			p.events = append(p.events, event.Message())
+		}
+		outcome.observe(event)
+	})
+	g.trackNotables(notables)
+	notables.startGame(seed, true)
+	g.resetGameState()
+	g.deck.Seed(seed)
+	g.seedRandom(seed)
+	g.dealerIdx = firstDealer(DealerRandom, 0, seed, len(g.players))
+	for _, p := range players {
+		p.proc.send(ladderMessage{Type: "match", Players: names})
+	}
+
+	if err := g.runSingleGame(ctx); err != nil {
+		return outcome, err
+	}
+	result := GameResult{Game: game, Seed: seed, Dealer: names[firstDealer(DealerRandom, 0, seed, len(names))],
+		Rounds: g.round, Winner: g.getWinner().GetName(), Scores: make(map[string]int)}
+	for _, p := range players {
+		result.Scores[p.GetName()] = p.GetTotalScore()
+		outcome.timeouts[p.GetName()] = p.timeouts
+		if p.fault != "" {
+			faults[p.GetName()] = p.fault
+			continue
+		}
+		step := observe(g, p, g.buildGameState())
+		step.Events = p.events
+		step.Done, step.LegalActions = true, []int{}
+		if PlayerInterface(p) == g.getWinner() {
+			step.Reward = 1
+		}
+		p.proc.send(ladderMessage{Type: "result", Step: &step})
+	}
+	notables.endGame(g.getWinner())
+	outcome.result = result
+	return outcome, nil
+}
+
+// observe counts each bot's rounds, busts, Flip 7s and round scores
+func (outcome arenaGame) observe(event Event) {
+	switch event.Type {
+	case RoundStarted:
+		for _, s := range outcome.rounds {
+			s.Rounds++
+		}
+	case PlayerBusted:
+		outcome.rounds[event.Player.GetName()].Busts++
+	case Flip7Achieved:
+		outcome.rounds[event.Player.GetName()].Flip7s++
+	case PlayerScored:
+		outcome.rounds[event.Player.GetName()].Points += event.Score
+	}
+}
+
+// Run plays the tournament, writing the results bundle to dir: every
+// game to games.jsonl as it finishes, each bot's standard error to
+// logs/, and the standings to results.json. A disqualified bot's
+// remaining games are skipped. Cancelling ctx stops the game in
+// progress and its bots.
+func (a *Arena) Run(ctx context.Context, dir string, progress func(done, total int)) (*ArenaResults, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0o755); err != nil {
+		return nil, err
+	}
+	rw, err := openResults(filepath.Join(dir, "games.jsonl"), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer rw.close()
+
+	results := &ArenaResults{Started: time.Now(), Score: a.Score, Notables: NewNotableGames()}
+	standings := make([]ArenaStanding, len(a.Bots))
+	scores := make([]int, len(a.Bots))
+	for i, b := range a.Bots {
+		standings[i].Name = b.Name
+	}
+	tables := a.tables()
+	total := len(tables) * a.Games
+	seed := a.Seed
+	for _, table := range tables {
+		for range a.Games {
+			game := results.Games + results.Skipped + 1
+			if slices.ContainsFunc(table, func(i int) bool { return standings[i].Disqualified != "" }) {
+				results.Skipped++
+				seed++
+				progress(game, total)
+				continue
+			}
+			outcome, err := a.play(ctx, table, game, seed, dir, results.Notables)
+			seed++
+			if err != nil {
+				return nil, fmt.Errorf("game %d: %w", game, err)
+			}
+			result, faults := outcome.result, outcome.faults
+			for _, i := range table {
+				if fault, ok := faults[a.Bots[i].Name]; ok {
+					standings[i].Disqualified = fmt.Sprintf("game %d: %s", game, fault)
+				}
+				standings[i].Timeouts += outcome.timeouts[a.Bots[i].Name]
+			}
+			if result.Winner == "" {
+				results.Skipped++
+				progress(game, total)
+				continue
+			}
+			results.Games++
+			for _, i := range table {
+				name := a.Bots[i].Name
+				standings[i].Games++
+				scores[i] += result.Scores[name]
+				rounds := outcome.rounds[name]
+				standings[i].Rounds += rounds.Rounds
+				standings[i].Busts += rounds.Busts
+				standings[i].Flip7s += rounds.Flip7s
+				standings[i].Points += rounds.Points
+				if result.Winner == name && faults[name] == "" {
+					standings[i].Wins++
+				}
+			}
+			if err := rw.write(result); err != nil {
+				return nil, err
+			}
+			if _, err := rw.flush(); err != nil {
+				return nil, err
+			}
+			progress(game, total)
+		}
+	}
+
+	for i := range standings {
+		if standings[i].Games > 0 {
+			standings[i].AverageScore = float64(scores[i]) / float64(standings[i].Games)
+		}
+	}
+	// Disqualified bots rank last, the rest by wins and then average score
+	slices.SortStableFunc(standings, func(x, y ArenaStanding) int {
+		switch {
+		case (x.Disqualified == "") != (y.Disqualified == ""):
+			if x.Disqualified == "" {
+				return -1
+			}
+			return 1
+		case x.Wins != y.Wins:
+			return y.Wins - x.Wins
+		case x.AverageScore > y.AverageScore:
+			return -1
+		case x.AverageScore < y.AverageScore:
+			return 1
+		}
+		return 0
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+	}
+	results.Standings = standings
+	results.Finished = time.Now()
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return results, os.WriteFile(filepath.Join(dir, "results.json"), append(data, '\n'), 0o644)
+}
+
+// RunArena handles the arena subcommand: it runs a bot tournament from
+// a manifest and publishes its results bundle
+func RunArena(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("arena", flag.ContinueOnError)
+	dir := fs.String("out", "arena-results", "Directory for the results bundle")
+	markdown := fs.String("markdown", "", "Also write the standings as Markdown to this file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: flip7 arena [-out DIR] [-markdown FILE] MANIFEST")
+	}
+	arena, err := LoadArena(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	tables := len(arena.tables())
+	report.printf(T("🏟️  %d bots, %d tables of %d, %d games each\n"), len(arena.Bots), tables, arena.Table, arena.Games)
+	results, err := arena.Run(context.Background(), *dir, func(done, total int) {
+		report.printf(T("🎲 Game %d/%d\n"), done, total)
+	})
+	if err != nil {
+		return err
+	}
+
+	report.resultf("\n%s\n", strings.Repeat("=", 60))
+	report.resultf(T("🏟️  ARENA - %d games, first to %d\n"), results.Games, results.Score)
+	report.resultf("%s\n", strings.Repeat("=", 60))
+	report.resultf("%4s  %-20s %6s %6s %10s\n", "#", T("PLAYER"), T("GAMES"), T("WINS"), T("AVG SCORE"))
+	report.resultf("%s\n", strings.Repeat("-", 60))
+	for _, s := range results.Standings {
+		report.resultf("%4d  %-20s %6d %6d %10.1f\n", s.Rank, s.Name, s.Games, s.Wins, s.AverageScore)
+		if s.Timeouts > 0 {
+			report.resultf(T("      ⏰ ran out of time on %d turns\n"), s.Timeouts)
+		}
+		if s.Disqualified != "" {
+			report.resultf(T("      ❌ disqualified, %s\n"), s.Disqualified)
+		}
+	}
+	report.resultf("%s\n", strings.Repeat("=", 60))
+	if results.Skipped > 0 {
+		report.resultf(T("%d games skipped for disqualified bots\n"), results.Skipped)
+	}
+	report.printf(T("📦 Results written to %s\n"), *dir)
+	if *markdown != "" {
+		if err := writeMarkdown(*markdown, func(w io.Writer) { results.writeMarkdown(w, len(arena.Bots), arena.Table) }); err != nil {
+			return err
+		}
+		report.printf(T("📝 Markdown report written to %s\n"), *markdown)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Remote players whose decisions follow the hidden deck this closely, in
+// standard deviations, over at least auditMinDecisions decisions are
+// flagged
+const (
+	auditThreshold    = 4
+	auditMinDecisions = 50
+)
+
+// DecisionAudit tallies a remote player's hit/stay decisions against the
+// card each would have drawn, which the player can't see. An honest
+// player's chance of hitting doesn't depend on the next card once the
+// bust chance they can see is known, so Edge, the busts they avoided
+// beyond that chance, averages zero. A player who can see the deck hits
+// into safe cards and stays before busting ones, and Edge grows with
+// every decision. Rejected counts messages that weren't a legal decision
+// on their turn and Timeouts the turns they let run out.
+type DecisionAudit struct {
+	Decisions int     `json:"decisions"`
+	Edge      float64 `json:"edge"`
+	Variance  float64 `json:"variance"`
+	Rejected  int     `json:"rejected"`
+	Timeouts  int     `json:"timeouts"`
+}
+
+// record adds a decision taken with a visible bust chance of p, when the
+// next card would have been a number the player holds
+func (a *DecisionAudit) record(p float64, hit, duplicate bool) {
+	d := 0.0
+	if duplicate {
+		d = 1
+	}
+	if hit {
+		a.Edge += p - d
+	} else {
+		a.Edge += d - p
+	}
+	a.Variance += p * (1 - p)
+	a.Decisions++
+}
+
+// Suspicion is how far Edge is above zero, in standard deviations
+func (a *DecisionAudit) Suspicion() float64 {
+	if a.Variance == 0 {
+		return 0
+	}
+	return a.Edge / math.Sqrt(a.Variance)
+}
+
+// Flagged reports play too lucky to be honest
+func (a *DecisionAudit) Flagged() bool {
+	return a.Decisions >= auditMinDecisions && a.Suspicion() >= auditThreshold
+}
+
+// auditDecision works out what a hit/stay decision would have drawn, and
+// the bust chance the player could see, so the decision can be recorded
+func auditDecision(g *Game, player PlayerInterface, gameState *GameState) (p float64, duplicate, ok bool) {
+	cards := g.deck.Cards()
+	if len(cards) == 0 {
+		return 0, false, false
+	}
+	next := cards[len(cards)-1]
+	duplicate = next.Type == NumberCard && player.HeldNumbers()&(1<<next.Value) != 0
+	return CalculateBustProbability(player, gameState), duplicate, true
+}
+
+// checkTarget rejects an action card target who isn't a legal one,
+// whoever chose them
+func checkTarget(player PlayerInterface, gameState *GameState, actionType ActionType, target PlayerInterface) error {
+	if target == nil {
+		return fmt.Errorf("%s chose no target", player.GetName())
+	}
+	if !slices.Contains(gameState.LegalTargets(player, actionType), target) {
+		return fmt.Errorf("%s chose %s, who isn't in the round", player.GetName(), target.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// banditRewardCap is the round score that earns a bandit arm its full
+// reward. Bigger rounds are capped, so one lucky Flip 7 doesn't decide
+// which arm looks best.
+const banditRewardCap = 40
+
+// BanditArm is one hit/stay strategy a Bandit can follow, with how many
+// rounds it has been followed and the reward they earned
+type BanditArm struct {
+	Name     string
+	Strategy HitOrStayStrategy
+	Pulls    int
+	Reward   float64
+}
+
+// Mean returns the arm's average reward per round, from 0 to 1
+func (a *BanditArm) Mean() float64 {
+	if a.Pulls == 0 {
+		return 0
+	}
+	return a.Reward / float64(a.Pulls)
+}
+
+// Bandit is a learning meta-strategy. It treats several hit/stay
+// strategies as the arms of a multi-armed bandit, follows one of them
+// for a whole round, and rewards it with the points the round banked.
+// UCB1 picks each round's arm: every arm is tried once, then the arms
+// that have done well are favoured while the others still get tried now
+// and then. What it learns carries across rounds and games.
+type Bandit struct {
+	Arms  []*BanditArm
+	pulls int
+	// current is the arm followed this round, -1 between rounds. round
+	// is the round it was picked in and start the total before it.
+	current int
+	round   int
+	start   int
+}
+
+// NewBandit returns a bandit choosing between bust probability
+// thresholds of 0.25, 0.30 and 0.35 and the gap-based strategy
+func NewBandit() *Bandit {
+	return &Bandit{
+		Arms: []*BanditArm{
+			{Name: "bust-probability:0.25", Strategy: PlayToBustProbability(0.25)},
+			{Name: "bust-probability:0.30", Strategy: PlayToBustProbability(0.30)},
+			{Name: "bust-probability:0.35", Strategy: PlayToBustProbability(0.35)},
+			{Name: "gap-based", Strategy: GapBasedStrategy},
+		},
+		current: -1,
+	}
+}
+
+// BanditStrategy follows the arm its player's Bandit picked for the
+// round. A player without a Bandit plays to a 0.30 bust probability.
+func BanditStrategy(self PlayerInterface, gameState *GameState) bool {
+	if p, ok := self.(learningPlayer); ok {
+		if b, ok := p.learning().(*Bandit); ok {
+			return b.decide(self, gameState)
+		}
+	}
+	return PlayToBustProbability(0.30)(self, gameState)
+}
+
+// decide settles the last round's arm once a new round has started,
+// picks one for this round if it hasn't yet, and follows it
+func (b *Bandit) decide(self PlayerInterface, gameState *GameState) bool {
+	if b.current >= 0 && b.round != gameState.Round {
+		b.settle(self)
+	}
+	if b.current < 0 {
+		b.current = b.choose()
+		b.round = gameState.Round
+		b.start = self.GetTotalScore()
+	}
+	return b.Arms[b.current].Strategy(self, gameState)
+}
+
+// choose returns the arm with the highest upper confidence bound, or the
+// first arm not tried yet
+func (b *Bandit) choose() int {
+	best, bestBound := 0, math.Inf(-1)
+	for i, arm := range b.Arms {
+		if arm.Pulls == 0 {
+			return i
+		}
+		bound := arm.Mean() + math.Sqrt(2*math.Log(float64(b.pulls))/float64(arm.Pulls))
+		if bound > bestBound {
+			best, bestBound = i, bound
+		}
+	}
+	return best
+}
+
+// settle rewards the arm followed this round with what the round added
+// to the player's total
+func (b *Bandit) settle(self PlayerInterface) {
+	gained := min(max(self.GetTotalScore()-b.start, 0), banditRewardCap)
+	arm := b.Arms[b.current]
+	arm.Pulls++
+	arm.Reward += float64(gained) / banditRewardCap
+	b.pulls++
+	b.current = -1
+}
+
+// GameOver settles the game's last round
+func (b *Bandit) GameOver(self, winner PlayerInterface) {
+	if b.current >= 0 {
+		b.settle(self)
+	}
+}
+
+// Forget clears every arm's record
+func (b *Bandit) Forget() {
+	for _, arm := range b.Arms {
+		arm.Pulls, arm.Reward = 0, 0
+	}
+	b.pulls = 0
+	b.current = -1
+}
+
+// Weights returns the share of rounds each arm has been followed, in
+// arm order; UCB1 follows the arms it has learned are best most often
+func (b *Bandit) Weights() []float64 {
+	weights := make([]float64, len(b.Arms))
+	if b.pulls == 0 {
+		return weights
+	}
+	for i, arm := range b.Arms {
+		weights[i] = float64(arm.Pulls) / float64(b.pulls)
+	}
+	return weights
+}
+
+// cloneLearning copies the arms' records, so lookahead on a cloned game
+// doesn't teach the live bandit
+func (b *Bandit) cloneLearning() Learner {
+	clone := *b
+	clone.Arms = make([]*BanditArm, len(b.Arms))
+	for i, arm := range b.Arms {
+		a := *arm
+		clone.Arms[i] = &a
+	}
+	return &clone
+}
+
+// report shows the arm weights the bandit learned
+func (b *Bandit) report(g *Game, name string) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🎰 BANDIT ARMS - %s\n"), name)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-24s %8s %8s %10s\n", T("ARM"), T("ROUNDS"), T("WEIGHT"), T("AVG PTS"))
+	weights := b.Weights()
+	for i, arm := range b.Arms {
+		g.resultf("%-24s %8d %7.1f%% %10.1f\n", arm.Name, arm.Pulls, weights[i]*100, arm.Mean()*banditRewardCap)
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Batch is a manifest of simulation experiments, read from YAML or TOML.
+// With Compare, every experiment is paired with a baseline game by game.
+type Batch struct {
+	Experiments []Experiment `yaml:"experiments" toml:"experiments"`
+	Compare     *Comparison  `yaml:"compare" toml:"compare"`
+}
+
+// Experiment is one configuration in a batch: a lineup and rules like a
+// config file, and the seeds to deal its games from, e.g. "1-500". Game i
+// deals from the i-th seed of the range.
+type Experiment struct {
+	Name   string `yaml:"name" toml:"name"`
+	Config `yaml:",inline"`
+	Seeds  string `yaml:"seeds" toml:"seeds"`
+}
+
+// ExperimentResult is the outcome of running one experiment
+type ExperimentResult struct {
+	*Experiment
+	Players []string
+	Sim     *Simulation
+	Took    time.Duration
+}
+
+// LoadBatch reads and validates a batch manifest
+func LoadBatch(path string) (*Batch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Batch
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &b)
+	} else {
+		err = yaml.Unmarshal(data, &b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading batch %s: %w", path, err)
+	}
+
+	if err := b.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch %s:\n%w", path, err)
+	}
+	return &b, nil
+}
+
+// Validate reports every problem with the batch at once
+func (b *Batch) Validate() error {
+	var errs []error
+	if len(b.Experiments) == 0 {
+		errs = append(errs, fmt.Errorf("  experiments: need at least one"))
+	}
+
+	names := make(map[string]bool)
+	for i := range b.Experiments {
+		e := &b.Experiments[i]
+		where := fmt.Sprintf("  experiments[%d]", i)
+		if e.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", where))
+		} else if names[e.Name] {
+			errs = append(errs, fmt.Errorf("%s: name %q is used twice", where, e.Name))
+		}
+		names[e.Name] = true
+
+		if len(e.Players) == 0 {
+			errs = append(errs, fmt.Errorf("%s: players: need 2-18 players, got none", where))
+		}
+		for _, p := range e.Players {
+			if p.Human {
+				errs = append(errs, fmt.Errorf("%s: players: %s is human, experiments are AI-only", where, p.Name))
+			}
+		}
+		if err := e.Config.Validate(); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				errs = append(errs, fmt.Errorf("%s: %s", where, strings.TrimSpace(line)))
+			}
+		}
+		if _, _, err := e.seedRange(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", where, err))
+		}
+	}
+	if b.Compare != nil && len(errs) == 0 {
+		errs = b.Compare.validate(b.Experiments)
+	}
+	return errors.Join(errs...)
+}
+
+// seedRange returns the first seed and the number of games. Seeds "a-b"
+// plays a game for every seed from a to b; without seeds the games use
+// seeds from 1 on.
+func (e *Experiment) seedRange() (first int64, games int, err error) {
+	if e.Seeds == "" {
+		if e.Games < 1 {
+			return 0, 0, fmt.Errorf("games: need games or seeds")
+		}
+		return 1, e.Games, nil
+	}
+
+	lo, hi, ok := strings.Cut(e.Seeds, "-")
+	first, err1 := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+	last, err2 := strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+	if !ok || err1 != nil || err2 != nil || last < first {
+		return 0, 0, fmt.Errorf("seeds: %q is not a range such as 1-500", e.Seeds)
+	}
+	games = int(last - first + 1)
+	if e.Games > 0 && e.Games != games {
+		return 0, 0, fmt.Errorf("seeds: %s is %d games, but games is %d", e.Seeds, games, e.Games)
+	}
+	return first, games, nil
+}
+
+// run plays the experiment silently with its own random source, so
+// experiments can run side by side. A seat above 0 keeps that player's
+// result in every game, for pairing with another experiment's.
+func (e *Experiment) run(seat int) (*ExperimentResult, error) {
+	first, games, err := e.seedRange()
+	if err != nil {
+		return nil, err
+	}
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetRandom(rand.New(rand.NewSource(first)))
+
+	cfg := e.Config
+	cfg.Games = games
+	cfg.Seed = 0
+	if err := cfg.Apply(g); err != nil {
+		return nil, err
+	}
+	// The first game deals from the seed after the table's
+	g.SetSeed(first - 1)
+
+	result := &ExperimentResult{Experiment: e}
+	for _, p := range g.players {
+		result.Players = append(result.Players, p.GetName())
+	}
+	start := time.Now()
+	if result.Sim, err = g.newSimulation(games); err != nil {
+		return nil, err
+	}
+	if seat > 0 {
+		result.Sim.Outcomes = &GameOutcomes{Player: result.Players[seat-1]}
+	}
+	if err := g.simulate(context.Background(), result.Sim); err != nil {
+		return nil, err
+	}
+	result.Took = time.Since(start)
+	return result, nil
+}
+
+// RunSimulate handles the simulate subcommand. "simulate -batch FILE" runs
+// every experiment in a manifest, several at once with -parallel, and
+// reports them side by side.
+func RunSimulate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	path := fs.String("batch", "", "Manifest of experiments to run")
+	parallel := fs.Int("parallel", 1, "How many experiments to run at once (0 uses every CPU)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return fmt.Errorf("usage: flip7 simulate -batch FILE [-parallel N]")
+	}
+	if *parallel <= 0 {
+		*parallel = runtime.NumCPU()
+	}
+
+	batch, err := LoadBatch(*path)
+	if err != nil {
+		return err
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	report.printf(T("🧪 Running %d experiments, %d at a time...\n"), len(batch.Experiments), *parallel)
+
+	type finished struct {
+		idx    int
+		result *ExperimentResult
+		err    error
+	}
+	base, seat := 0, 0
+	if batch.Compare != nil {
+		base, _ = batch.Compare.baseline(batch.Experiments)
+		seat = batch.Compare.seat()
+	}
+	done := make(chan finished)
+	slots := make(chan struct{}, *parallel)
+	for i := range batch.Experiments {
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			result, err := batch.Experiments[i].run(seat)
+			done <- finished{i, result, err}
+		}()
+	}
+
+	results := make([]*ExperimentResult, len(batch.Experiments))
+	var errs []error
+	for range batch.Experiments {
+		f := <-done
+		name := batch.Experiments[f.idx].Name
+		if f.err != nil {
+			errs = append(errs, fmt.Errorf("experiment %s: %w", name, f.err))
+			continue
+		}
+		results[f.idx] = f.result
+		report.printf(T("✅ %s: %d games in %.1fs\n"), name, f.result.Sim.gamesPlayed(len(f.result.Players)), f.result.Took.Seconds())
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	report.displayBatchReport(results)
+	if batch.Compare != nil {
+		report.displayPairedComparison(results, base, seat)
+	}
+	return nil
+}
+
+// displayBatchReport shows every experiment's players, then compares the
+// experiments
+func (g *Game) displayBatchReport(results []*ExperimentResult) {
+	for _, r := range results {
+		first, seeds, _ := r.seedRange()
+		sim := r.Sim
+		games := sim.gamesPlayed(len(r.Players))
+		g.resultf("\n%s\n", strings.Repeat("=", 60))
+		g.resultf(T("🧪 %s - %d games, seeds %d-%d, first to %d\n"), r.Name, games, first, first+int64(seeds)-1, sim.WinningScore)
+		g.resultf("%s\n", strings.Repeat("=", 60))
+		g.resultf("%-24s %8s %10s %12s\n", T("PLAYER"), T("WINS"), T("WIN RATE"), T("AVG SCORE"))
+		g.resultf("%s\n", strings.Repeat("-", 60))
+
+		players := append([]string(nil), r.Players...)
+		sort.SliceStable(players, func(i, j int) bool { return sim.Wins[players[i]] > sim.Wins[players[j]] })
+		for _, name := range players {
+			g.resultf("%-24s %8d %9.1f%% %12.1f\n", name, sim.Wins[name],
+				100*float64(sim.Wins[name])/float64(games), float64(sim.Scores[name])/float64(games))
+		}
+		g.resultf("%s\n", strings.Repeat("-", 60))
+		g.resultf(T("Average game: %.1f rounds\n"), float64(sim.Rounds)/float64(games))
+	}
+
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf("%s\n", T("🧪 EXPERIMENTS COMPARED"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-18s %7s %7s  %-16s %8s\n", T("EXPERIMENT"), T("GAMES"), T("ROUNDS"), T("TOP PLAYER"), T("WIN RATE"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	for _, r := range results {
+		sim := r.Sim
+		games := sim.gamesPlayed(len(r.Players))
+		top := r.Players[0]
+		for _, name := range r.Players {
+			if sim.Wins[name] > sim.Wins[top] {
+				top = name
+			}
+		}
+		g.resultf("%-18s %7d %7.1f  %-16s %7.1f%%\n", r.Name, games,
+			float64(sim.Rounds)/float64(games), top, 100*float64(sim.Wins[top])/float64(games))
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,145 @@
+// Package bot helps write Flip 7 bots in Go for the arena and the ladder.
+// It reads the JSON lines they send, works out what an observation says
+// about the hand and the cards left with the same card math the engine's
+// computer players use, and can log every decision for training a model.
+package bot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Actions a bot can answer a turn with
+const (
+	Stay = 0
+	Hit  = 1
+)
+
+// Message is a message from the arena or the ladder
+type Message struct {
+	Type    string    `json:"type"` // queued, match, turn, result or error
+	Waiting int       `json:"waiting,omitempty"`
+	Players []string  `json:"players,omitempty"`
+	Step    *TimeStep `json:"step,omitempty"`
+	Rating  int       `json:"rating,omitempty"`
+	Change  int       `json:"change,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// TimeStep is what a bot sees at a decision, or at the end of a game
+type TimeStep struct {
+	Observation  []float64      `json:"observation"`
+	LegalActions []int          `json:"legal_actions"`
+	Reward       float64        `json:"reward"`
+	Done         bool           `json:"done"`
+	Round        int            `json:"round"`
+	Scores       map[string]int `json:"scores"`
+	Events       []Event        `json:"events"`
+	State        State          `json:"state"`
+}
+
+// State is the table as every player can see it
+type State struct {
+	Round        int      `json:"round"`
+	Dealer       string   `json:"dealer"`
+	WinningScore int      `json:"winning_score"`
+	CardsLeft    int      `json:"cards_left"`
+	Players      []Player `json:"players"`
+}
+
+// Player is one player at the table
+type Player struct {
+	Name         string `json:"name"`
+	Hand         string `json:"hand"`
+	RoundScore   int    `json:"round_score"`
+	TotalScore   int    `json:"total_score"`
+	Active       bool   `json:"active"`
+	SecondChance bool   `json:"second_chance"`
+	State        string `json:"state,omitempty"`
+	BustedBy     string `json:"busted_by,omitempty"`
+}
+
+// Cards reads the player's hand
+func (p Player) Cards() (Hand, error) {
+	return ParseHand(p.Hand)
+}
+
+// Event is something that happened since the bot's last decision
+type Event struct {
+	Type   string `json:"type"`
+	Round  int    `json:"round"`
+	Player string `json:"player,omitempty"`
+	Target string `json:"target,omitempty"`
+	Card   string `json:"card,omitempty"`
+	Score  int    `json:"score,omitempty"`
+}
+
+// Turn is a decision to make: the time step as sent, and its observation
+// decoded
+type Turn struct {
+	*TimeStep
+	Features
+}
+
+// Legal reports whether the turn allows the action
+func (t *Turn) Legal(action int) bool {
+	return slices.Contains(t.LegalActions, action)
+}
+
+// Bot plays games over the arena's JSON lines. Decide returns true to
+// hit. With a Log, every decision is logged with the game's outcome.
+type Bot struct {
+	Decide func(t *Turn) bool
+	Log    *DecisionLog
+}
+
+// Run plays until in ends: the arena's standard input and output, or a
+// ladder WebSocket's messages one per line. A decision the turn doesn't
+// allow is sent as the other action.
+func (b *Bot) Run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(out)
+	for scanner.Scan() {
+		var m Message
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			return fmt.Errorf("reading message: %w", err)
+		}
+		switch m.Type {
+		case "turn":
+			if m.Step == nil {
+				return fmt.Errorf("turn without a time step")
+			}
+			features, err := Decode(m.Step.Observation)
+			if err != nil {
+				return err
+			}
+			turn := &Turn{m.Step, features}
+			action := Stay
+			if b.Decide(turn) {
+				action = Hit
+			}
+			if !turn.Legal(action) {
+				action = Hit - action
+			}
+			if b.Log != nil {
+				b.Log.Decision(m.Step.Round, m.Step.Observation, action)
+			}
+			if err := encoder.Encode(map[string]any{"op": "step", "action": action}); err != nil {
+				return err
+			}
+		case "result":
+			if b.Log != nil && m.Step != nil {
+				if err := b.Log.GameOver(m.Step.Reward > 0); err != nil {
+					return err
+				}
+			}
+		case "error":
+			return fmt.Errorf("%s", m.Error)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Kind is what sort of card a card is
+type Kind int
+
+const (
+	Number Kind = iota
+	Modifier
+	Action
+)
+
+// Flip7Bonus is the extra score for collecting seven different numbers
+const Flip7Bonus = 15
+
+// Card is a card as the protocol writes it: "0" to "12", "+2" to "+10",
+// "x2", "freeze", "flip3" or "chance". Value is a number card's number or
+// a +N modifier's N, and 0 for the rest.
+type Card struct {
+	Kind  Kind
+	Value int
+	Name  string
+}
+
+// ParseCard reads a card as the protocol writes it
+func ParseCard(s string) (Card, error) {
+	name := strings.ToLower(s)
+	switch name {
+	case "+2", "+4", "+6", "+8", "+10":
+		value, _ := strconv.Atoi(name[1:])
+		return Card{Modifier, value, name}, nil
+	case "x2":
+		return Card{Modifier, 0, name}, nil
+	case "freeze", "flip3", "chance":
+		return Card{Action, 0, name}, nil
+	}
+	value, err := strconv.Atoi(name)
+	if err != nil || value < 0 || value > 12 || strconv.Itoa(value) != name {
+		return Card{}, fmt.Errorf("unknown card %q", s)
+	}
+	return Card{Number, value, name}, nil
+}
+
+// String returns the card as the protocol writes it
+func (c Card) String() string {
+	return c.Name
+}
+
+// Hand is the cards in front of a player
+type Hand []Card
+
+// ParseHand reads a hand written as the protocol writes them, cards
+// separated by spaces
+func ParseHand(s string) (Hand, error) {
+	var hand Hand
+	for _, field := range strings.Fields(s) {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		hand = append(hand, card)
+	}
+	return hand, nil
+}
+
+// Held returns the numbers in the hand as a bit mask, bit n set for n
+func (h Hand) Held() uint16 {
+	var held uint16
+	for _, card := range h {
+		if card.Kind == Number {
+			held |= 1 << card.Value
+		}
+	}
+	return held
+}
+
+// Has reports whether the hand holds the number, so drawing it would bust
+func (h Hand) Has(number int) bool {
+	return h.Held()&(1<<number) != 0
+}
+
+// Numbers returns how many different numbers the hand holds; seven is a
+// Flip 7
+func (h Hand) Numbers() int {
+	return bits.OnesCount16(h.Held())
+}
+
+// Doubled reports whether the hand has the x2 modifier
+func (h Hand) Doubled() bool {
+	for _, card := range h {
+		if card.Name == "x2" {
+			return true
+		}
+	}
+	return false
+}
+
+// SecondChance reports whether the hand has a Second Chance to save it
+// from busting
+func (h Hand) SecondChance() bool {
+	for _, card := range h {
+		if card.Name == "chance" {
+			return true
+		}
+	}
+	return false
+}
+
+// Score returns what the hand would bank if the player stayed now, by
+// the standard rules: the numbers, doubled by x2, plus the +N modifiers,
+// plus 15 for a Flip 7
+func (h Hand) Score() int {
+	numbers, modifiers := 0, 0
+	for _, card := range h {
+		switch {
+		case card.Kind == Number:
+			numbers += card.Value
+		case card.Kind == Modifier:
+			modifiers += card.Value
+		}
+	}
+	if h.Doubled() {
+		numbers *= 2
+	}
+	score := numbers + modifiers
+	if h.Numbers() == 7 {
+		score += Flip7Bonus
+	}
+	return score
+}
+
+// Counts tallies a set of cards, such as the cards no player has seen
+type Counts struct {
+	Numbers        [13]int // copies of each number card
+	Modifiers      int
+	ModifierPoints int // points on the +N modifiers; x2 counts as 0
+	Actions        int
+	Total          int
+}
+
+// FullDeck tallies the full card set: one 0 and n copies of every other
+// number n, the five +N modifiers and x2, and three each of Freeze, Flip
+// Three and Second Chance
+func FullDeck() Counts {
+	var c Counts
+	for value := range c.Numbers {
+		c.Numbers[value] = max(value, 1)
+		c.Total += c.Numbers[value]
+	}
+	c.Modifiers, c.ModifierPoints = 6, 2+4+6+8+10
+	c.Actions = 9
+	c.Total += c.Modifiers + c.Actions
+	return c
+}
+
+// Remove takes cards that have been seen out of the tally
+func (c *Counts) Remove(cards ...Card) {
+	for _, card := range cards {
+		switch card.Kind {
+		case Number:
+			c.Numbers[card.Value]--
+		case Modifier:
+			c.Modifiers--
+			c.ModifierPoints -= card.Value
+		case Action:
+			c.Actions--
+		}
+		c.Total--
+	}
+}
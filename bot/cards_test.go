@@ -0,0 +1,91 @@
+package bot
+
+import "testing"
+
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		in    string
+		kind  Kind
+		value int
+	}{
+		{"0", Number, 0},
+		{"12", Number, 12},
+		{"+2", Modifier, 2},
+		{"+10", Modifier, 10},
+		{"x2", Modifier, 0},
+		{"X2", Modifier, 0},
+		{"freeze", Action, 0},
+		{"flip3", Action, 0},
+		{"chance", Action, 0},
+	}
+	for _, tt := range tests {
+		card, err := ParseCard(tt.in)
+		if err != nil {
+			t.Errorf("ParseCard(%q): %v", tt.in, err)
+			continue
+		}
+		if card.Kind != tt.kind || card.Value != tt.value {
+			t.Errorf("ParseCard(%q) = %+v, want kind %d value %d", tt.in, card, tt.kind, tt.value)
+		}
+	}
+	for _, in := range []string{"", "13", "-1", "+3", "x3", "seven"} {
+		if card, err := ParseCard(in); err == nil {
+			t.Errorf("ParseCard(%q) = %+v, want an error", in, card)
+		}
+	}
+}
+
+func TestHandScore(t *testing.T) {
+	tests := []struct {
+		hand string
+		want int
+	}{
+		{"", 0},
+		{"7 9", 16},
+		{"7 9 x2", 32},
+		{"7 9 +4 x2", 36},
+		{"12 chance freeze", 12},
+		{"0 1 2 3 4 5 6", 36},
+		{"0 1 2 3 4 5 6 x2 +10", 67},
+	}
+	for _, tt := range tests {
+		h, err := ParseHand(tt.hand)
+		if err != nil {
+			t.Fatalf("ParseHand(%q): %v", tt.hand, err)
+		}
+		if got := h.Score(); got != tt.want {
+			t.Errorf("Score of %q = %d, want %d", tt.hand, got, tt.want)
+		}
+	}
+}
+
+func TestHandHeld(t *testing.T) {
+	h, err := ParseHand("0 3 12 +4 chance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.Held(), uint16(1<<0|1<<3|1<<12); got != want {
+		t.Errorf("Held = %013b, want %013b", got, want)
+	}
+	if !h.Has(3) || h.Has(4) {
+		t.Errorf("Has(3) = %v and Has(4) = %v, want true and false", h.Has(3), h.Has(4))
+	}
+	if h.Numbers() != 3 || h.Doubled() || !h.SecondChance() {
+		t.Errorf("Numbers %d, Doubled %v, SecondChance %v; want 3, false, true", h.Numbers(), h.Doubled(), h.SecondChance())
+	}
+}
+
+func TestFullDeckAndRemove(t *testing.T) {
+	c := FullDeck()
+	if c.Total != 94 || c.Numbers[0] != 1 || c.Numbers[12] != 12 || c.Modifiers != 6 || c.ModifierPoints != 30 || c.Actions != 9 {
+		t.Fatalf("FullDeck = %+v, want the 94 card set", c)
+	}
+	h, err := ParseHand("12 +4 freeze")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Remove(h...)
+	if c.Total != 91 || c.Numbers[12] != 11 || c.Modifiers != 5 || c.ModifierPoints != 26 || c.Actions != 8 {
+		t.Errorf("after removing %q: %+v", "12 +4 freeze", c)
+	}
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// modifierNames are the modifiers in the order the observation has them
+var modifierNames = []string{"+2", "+4", "+6", "+8", "+10", "x2"}
+
+// ObservationNames name the entries of an observation, in order
+var ObservationNames = func() []string {
+	var names []string
+	for value := range 13 {
+		names = append(names, "held_"+strconv.Itoa(value))
+	}
+	for _, m := range modifierNames {
+		names = append(names, "held_"+m)
+	}
+	names = append(names, "second_chance", "round_score", "total_score", "best_opponent_score",
+		"winning_score", "active_opponents", "cards_left")
+	for value := range 13 {
+		names = append(names, "left_"+strconv.Itoa(value))
+	}
+	return append(names, "left_modifiers", "left_actions", "bust_probability")
+}()
+
+// Features is an observation read back into what it says about the
+// player deciding and the table. Hand holds the player's numbers and
+// modifiers, and a Second Chance if they have one. Unseen is the cards no
+// player can see; the observation doesn't say which modifiers those are,
+// so their points are the average of the modifiers not in Hand.
+type Features struct {
+	Hand            Hand
+	RoundScore      int
+	TotalScore      int
+	BestOpponent    int
+	WinningScore    int
+	ActiveOpponents int
+	Unseen          Counts
+	BustProbability float64
+}
+
+// Decode reads an observation
+func Decode(observation []float64) (Features, error) {
+	if len(observation) != len(ObservationNames) {
+		return Features{}, fmt.Errorf("observation has %d entries, expected %d", len(observation), len(ObservationNames))
+	}
+	next := func() float64 {
+		v := observation[0]
+		observation = observation[1:]
+		return v
+	}
+	var f Features
+	for value := range 13 {
+		if next() != 0 {
+			f.Hand = append(f.Hand, Card{Number, value, strconv.Itoa(value)})
+		}
+	}
+	for _, m := range modifierNames {
+		if next() != 0 {
+			card, _ := ParseCard(m)
+			f.Hand = append(f.Hand, card)
+		}
+	}
+	if next() != 0 {
+		f.Hand = append(f.Hand, Card{Action, 0, "chance"})
+	}
+	f.RoundScore, f.TotalScore, f.BestOpponent = int(next()), int(next()), int(next())
+	f.WinningScore, f.ActiveOpponents, f.Unseen.Total = int(next()), int(next()), int(next())
+	for value := range f.Unseen.Numbers {
+		f.Unseen.Numbers[value] = int(next())
+	}
+	f.Unseen.Modifiers, f.Unseen.Actions = int(next()), int(next())
+	f.BustProbability = next()
+
+	full := FullDeck()
+	points, modifiers := full.ModifierPoints, full.Modifiers
+	for _, card := range f.Hand {
+		if card.Kind == Modifier {
+			points -= card.Value
+			modifiers--
+		}
+	}
+	if modifiers > 0 {
+		f.Unseen.ModifierPoints = f.Unseen.Modifiers * points / modifiers
+	}
+	return f, nil
+}
+
+// Encode writes the features as an observation, in ObservationNames order
+func (f Features) Encode() []float64 {
+	bit := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	obs := make([]float64, 0, len(ObservationNames))
+	held := f.Hand.Held()
+	for value := range 13 {
+		obs = append(obs, bit(held&(1<<value) != 0))
+	}
+	for _, m := range modifierNames {
+		has := false
+		for _, card := range f.Hand {
+			has = has || card.Name == m
+		}
+		obs = append(obs, bit(has))
+	}
+	obs = append(obs, bit(f.Hand.SecondChance()), float64(f.RoundScore), float64(f.TotalScore),
+		float64(f.BestOpponent), float64(f.WinningScore), float64(f.ActiveOpponents), float64(f.Unseen.Total))
+	for _, n := range f.Unseen.Numbers {
+		obs = append(obs, float64(n))
+	}
+	return append(obs, float64(f.Unseen.Modifiers), float64(f.Unseen.Actions), f.BustProbability)
+}
+
+// Normalize writes the features as Encode does, with every entry scaled
+// to between 0 and 1 for a model to learn from: scores as a share of the
+// winning score, which is then 1, opponents of the most there can be, and
+// unseen cards as a share of their copies in the full card set
+func (f Features) Normalize() []float64 {
+	obs := f.Encode()
+	full := FullDeck()
+	scale := func(name string, by float64) {
+		for i, n := range ObservationNames {
+			if n == name && by > 0 {
+				obs[i] = min(obs[i]/by, 1)
+			}
+		}
+	}
+	winning := float64(max(f.WinningScore, 1))
+	for _, name := range []string{"round_score", "total_score", "best_opponent_score"} {
+		scale(name, winning)
+	}
+	scale("winning_score", winning)
+	scale("active_opponents", 17)
+	scale("cards_left", float64(full.Total))
+	for value, n := range full.Numbers {
+		scale("left_"+strconv.Itoa(value), float64(n))
+	}
+	scale("left_modifiers", float64(full.Modifiers))
+	scale("left_actions", float64(full.Actions))
+	return obs
+}
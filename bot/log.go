@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// DecisionLog writes a bot's decisions as CSV, one row per decision with
+// its observation, the action and whether the bot went on to win the
+// game, for training a model on what won. A game's rows are written when
+// it's over.
+type DecisionLog struct {
+	w       *csv.Writer
+	game    int
+	pending [][]string
+}
+
+// NewDecisionLog starts a log on w with the header row
+func NewDecisionLog(w io.Writer) (*DecisionLog, error) {
+	l := &DecisionLog{w: csv.NewWriter(w), game: 1}
+	header := append([]string{"game", "round"}, ObservationNames...)
+	if err := l.w.Write(append(header, "action", "won")); err != nil {
+		return nil, err
+	}
+	l.w.Flush()
+	return l, l.w.Error()
+}
+
+// Decision logs a decision in the game being played
+func (l *DecisionLog) Decision(round int, observation []float64, action int) {
+	row := []string{strconv.Itoa(l.game), strconv.Itoa(round)}
+	for _, v := range observation {
+		row = append(row, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	l.pending = append(l.pending, append(row, strconv.Itoa(action)))
+}
+
+// GameOver writes the game's decisions with its outcome
+func (l *DecisionLog) GameOver(won bool) error {
+	for _, row := range l.pending {
+		if err := l.w.Write(append(row, strconv.FormatBool(won))); err != nil {
+			return err
+		}
+	}
+	l.pending = l.pending[:0]
+	l.game++
+	l.w.Flush()
+	return l.w.Error()
+}
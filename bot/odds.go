@@ -0,0 +1,83 @@
+package bot
+
+// BustProbability is the chance that the next card busts the hand: the
+// share of the unseen cards that are numbers it already holds. It's what
+// the engine's bust-probability strategies compare with their threshold.
+func BustProbability(h Hand, unseen Counts) float64 {
+	return HeldBustProbability(h.Held(), unseen)
+}
+
+// HeldBustProbability is BustProbability for the numbers in held, a mask
+// as Hand.Held returns
+func HeldBustProbability(held uint16, unseen Counts) float64 {
+	if unseen.Total <= 0 {
+		return 0
+	}
+	bust := 0
+	for value, n := range unseen.Numbers {
+		if held&(1<<value) != 0 {
+			bust += n
+		}
+	}
+	return float64(bust) / float64(unseen.Total)
+}
+
+// Flip7Chance is the chance that the next card makes a Flip 7: none until
+// the hand holds six different numbers, then the share of the unseen
+// cards that are a seventh
+func Flip7Chance(h Hand, unseen Counts) float64 {
+	if h.Numbers() != 6 || unseen.Total <= 0 {
+		return 0
+	}
+	seventh := 0
+	held := h.Held()
+	for value, n := range unseen.Numbers {
+		if held&(1<<value) == 0 {
+			seventh += n
+		}
+	}
+	return float64(seventh) / float64(unseen.Total)
+}
+
+// ExpectedPointsFromHit is what the next card is worth on average if it
+// doesn't bust the hand, as the engine's expected-value strategies count
+// it: its number or modifier points, and 5 for an action card
+func ExpectedPointsFromHit(h Hand, unseen Counts) float64 {
+	return HeldExpectedPoints(h.Held(), unseen)
+}
+
+// HeldExpectedPoints is ExpectedPointsFromHit for the numbers in held, a
+// mask as Hand.Held returns
+func HeldExpectedPoints(held uint16, unseen Counts) float64 {
+	points, cards := 0, 0
+	for value, n := range unseen.Numbers {
+		if held&(1<<value) == 0 {
+			points += value * n
+			cards += n
+		}
+	}
+	points += unseen.ModifierPoints + 5*unseen.Actions
+	cards += unseen.Modifiers + unseen.Actions
+	if cards == 0 {
+		return 0
+	}
+	return float64(points) / float64(cards)
+}
+
+// HitValue is how much hitting once adds to the round score on average:
+// what the next card is worth, doubled by x2, if it doesn't bust the hand,
+// plus the Flip 7 bonus when it completes one, less the round score lost
+// when it busts. A Second Chance saves the round score. Above zero,
+// hitting once is worth more than staying.
+func HitValue(h Hand, unseen Counts) float64 {
+	bust := BustProbability(h, unseen)
+	gain := ExpectedPointsFromHit(h, unseen)
+	if h.Doubled() {
+		gain *= 2
+	}
+	value := gain*(1-bust) + Flip7Bonus*Flip7Chance(h, unseen)
+	if !h.SecondChance() {
+		value -= bust * float64(h.Score())
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"math"
+	"testing"
+)
+
+// fewCards is a small unseen tally worked through by hand: two 5s, a 7, a
+// +4 and a Freeze
+var fewCards = Counts{
+	Numbers:        [13]int{5: 2, 7: 1},
+	Modifiers:      1,
+	ModifierPoints: 4,
+	Actions:        1,
+	Total:          5,
+}
+
+func TestOdds(t *testing.T) {
+	tests := []struct {
+		hand     string
+		unseen   Counts
+		bust     float64
+		flip7    float64
+		points   float64
+		hitValue float64
+	}{
+		// busts on either 5; otherwise the 7, the +4 or the Freeze at 5
+		{"5", fewCards, 0.4, 0, 16.0 / 3, 16.0/3*0.6 - 0.4*5},
+		// x2 doubles what the card adds and what a bust loses
+		{"5 x2", fewCards, 0.4, 0, 16.0 / 3, 32.0/3*0.6 - 0.4*10},
+		// a Second Chance loses nothing on a bust
+		{"5 chance", fewCards, 0.4, 0, 16.0 / 3, 16.0 / 3 * 0.6},
+		// six numbers: any 5 or 7 is a seventh
+		{"1 2 3 4 6 8", fewCards, 0, 0.6, 26.0 / 5, 26.0/5 + 15*0.6},
+		// nothing left to draw
+		{"5", Counts{}, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		h, err := ParseHand(tt.hand)
+		if err != nil {
+			t.Fatalf("ParseHand(%q): %v", tt.hand, err)
+		}
+		check := func(what string, got, want float64) {
+			t.Helper()
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s of %q = %.4f, want %.4f", what, tt.hand, got, want)
+			}
+		}
+		check("BustProbability", BustProbability(h, tt.unseen), tt.bust)
+		check("HeldBustProbability", HeldBustProbability(h.Held(), tt.unseen), tt.bust)
+		check("Flip7Chance", Flip7Chance(h, tt.unseen), tt.flip7)
+		check("ExpectedPointsFromHit", ExpectedPointsFromHit(h, tt.unseen), tt.points)
+		check("HeldExpectedPoints", HeldExpectedPoints(h.Held(), tt.unseen), tt.points)
+		check("HitValue", HitValue(h, tt.unseen), tt.hitValue)
+	}
+}
+
+func TestBustProbabilityFromTheFullDeck(t *testing.T) {
+	h, err := ParseHand("12 11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unseen := FullDeck()
+	unseen.Remove(h...)
+	// 11 more 12s and 10 more 11s among the 92 cards left
+	if got, want := BustProbability(h, unseen), 21.0/92; math.Abs(got-want) > 1e-9 {
+		t.Errorf("BustProbability = %.4f, want %.4f", got, want)
+	}
+}
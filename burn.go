@@ -0,0 +1,55 @@
+package main
+
+// maxBurnCards is the most cards the burn house rule may burn a round
+const maxBurnCards = 10
+
+// SetBurnCards turns on the house rule that burns the top n cards of the
+// deck at the start of every round, before the deal. Burned cards go
+// face up onto the discard pile, so everyone can count them.
+func (g *Game) SetBurnCards(n int) {
+	g.burnCards = n
+}
+
+// BurnCard burns the next card onto the discard pile and announces it,
+// and the reshuffle if burning set one off. When there's no card to burn
+// it announces that, with a CardBurned event without a card, and
+// returns nil.
+func (g *Game) BurnCard() *Card {
+	if d, ok := g.deck.(drawerSetter); ok {
+		d.setDrawer(nil)
+	}
+	left := g.deck.CardsLeft()
+	card := g.deck.BurnCard()
+	if card == nil {
+		g.narrate(func() { g.println(T("   🔥 No card is left to burn")) })
+		g.emit(Event{Type: CardBurned})
+		return nil
+	}
+	g.narrate(func() { g.printf(T("   🔥 Burned: %s\n"), g.renderer.Card(card)) })
+	g.emit(Event{Type: CardBurned, Card: card})
+	if g.deck.CardsLeft() != left-1 {
+		g.announceReshuffle()
+	}
+	return card
+}
+
+// PeekTop returns the next n cards to be drawn, next first, and tells
+// the listeners how many were looked at: none for n below 1, and in
+// debug mode only the debug deck's, as the rest are picked by hand.
+// Peeking doesn't change the deck; it's for tools, tests and variants
+// that show the top card.
+func (g *Game) PeekTop(n int) []*Card {
+	cards := g.deck.PeekTop(n)
+	g.emit(Event{Type: DeckPeeked, Score: len(cards)})
+	return cards
+}
+
+// burnForRound burns the cards the house rule calls for before a round
+// is dealt, stopping early if the deck runs out and can't be reshuffled
+func (g *Game) burnForRound() {
+	for range g.burnCards {
+		if g.BurnCard() == nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+// deckEvents has the game record the deck events it sends
+func deckEvents(g *Game) *[]Event {
+	var events []Event
+	g.Subscribe(func(e Event) {
+		if e.Type == CardBurned || e.Type == DeckPeeked {
+			events = append(events, e)
+		}
+	})
+	return &events
+}
+
+func TestPeekTop(t *testing.T) {
+	shuffled := NewDeck()
+	shuffled.Restore(mustParseCards("3 2 1"), nil)
+	for name, d := range map[string]DeckInterface{"stacked": NewStackedDeck(mustParseCards("1 2 3")...), "shuffled": shuffled} {
+		tests := []struct {
+			n    int
+			want string
+		}{
+			{-1, ""},
+			{0, ""},
+			{2, "1 2"},
+			{10, "1 2 3"},
+		}
+		for _, tt := range tests {
+			if got := formatCards(d.PeekTop(tt.n)); got != tt.want {
+				t.Errorf("%s PeekTop(%d) = %q, want %q", name, tt.n, got, tt.want)
+			}
+		}
+		if d.CardsLeft() != 3 {
+			t.Errorf("peeking at the %s deck drew cards", name)
+		}
+	}
+
+	g := NewGame()
+	events := deckEvents(g)
+	g.PeekTop(-3)
+	g.PeekTop(2)
+	if len(*events) != 2 || (*events)[0].Score != 0 || (*events)[1].Score != 2 {
+		t.Errorf("peeking sent %+v, want DeckPeeked events for 0 and 2 cards", *events)
+	}
+}
+
+func TestBurnCard(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetDeck(NewStackedDeck(mustParseCards("1 2")...))
+	g.SetReshufflePolicy(ReshuffleBetweenRounds)
+	events := deckEvents(g)
+
+	for range 3 {
+		g.BurnCard()
+	}
+	if got := formatCards(g.deck.Discards()); got != "1 2" {
+		t.Errorf("burned %q onto the discards, want %q", got, "1 2")
+	}
+	if len(*events) != 3 || formatCard((*events)[1].Card) != "2" || (*events)[2].Card != nil {
+		t.Errorf("burning sent %+v, want the 1, the 2 and one without a card", *events)
+	}
+	if got := describeEvent((*events)[2]); got != "No card was left to burn" {
+		t.Errorf("the empty burn reads %q", got)
+	}
+}
+
+// TestDebugModePeekAndBurn checks the debug deck's cards are peeked at
+// and burned in order, and the card picker takes over after them
+func TestDebugModePeekAndBurn(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.scanner = bufio.NewScanner(strings.NewReader("7\n"))
+	g.SetDebugMode(true)
+	if err := g.SetDebugDeck(mustParseCards("3 +4")); err != nil {
+		t.Fatal(err)
+	}
+	events := deckEvents(g)
+
+	if got := formatCards(g.PeekTop(5)); got != "3 +4" {
+		t.Errorf("peeked at %q, want only the debug deck's %q", got, "3 +4")
+	}
+	g.BurnCard()
+	g.BurnCard()
+	if got := g.PeekTop(1); len(got) != 0 {
+		t.Errorf("peeked at %s past the debug deck, where cards are picked by hand", formatCards(got))
+	}
+	if card := g.BurnCard(); card == nil || formatCard(card) != "7" {
+		t.Fatalf("burned %v, want the 7 typed in", card)
+	}
+	if got := formatCards(g.deck.Discards()); got != "3 +4 7" {
+		t.Errorf("burned %q onto the discards, want %q", got, "3 +4 7")
+	}
+	if got := g.deck.Counts().Numbers[7]; got != 6 {
+		t.Errorf("%d 7s left to draw, want 6", got)
+	}
+	if len(*events) != 5 {
+		t.Errorf("sent %d deck events, want 2 peeks and 3 burns", len(*events))
+	}
+}
@@ -72,11 +72,11 @@ func (c *Card) String() string {
 	case ActionCard:
 		switch c.Action {
 		case Freeze:
-			return "[❄️ FREEZE]"
+			return "[❄️ " + T("FREEZE") + "]"
 		case FlipThree:
-			return "[🎲 FLIP 3]"
+			return "[🎲 " + T("FLIP 3") + "]"
 		case SecondChance:
-			return "[🆘 2ND CHANCE]"
+			return "[🆘 " + T("2ND CHANCE") + "]"
 		}
 	case ModifierCard:
 		switch c.Modifier {
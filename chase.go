@@ -0,0 +1,103 @@
+package main
+
+// Flip7Chase is what chasing Flip 7 is worth to a hand of five or six
+// unique numbers that keeps hitting until it has seven or busts
+type Flip7Chase struct {
+	// Needed is how many more unique numbers complete Flip 7: 1 or 2
+	Needed int
+	// Complete and Bust are the chances of completing Flip 7 and of
+	// drawing a duplicate first; they add up to 1
+	Complete float64
+	Bust     float64
+	// Score is the round score expected if Flip 7 is completed, with
+	// the player's scorer's bonus. Completing ends the round, so it's banked as it stands.
+	Score float64
+}
+
+// Value is the round score chasing is expected to bank, counting a bust
+// as nothing
+func (c Flip7Chase) Value() float64 {
+	return c.Complete * c.Score
+}
+
+// CalculateFlip7Chase works out the exact chance that a hand of five or
+// six unique numbers completes Flip 7 before it busts, from the cards no
+// player can see. Modifiers and action cards drawn along the way don't
+// change which number comes next, so only the number cards count: the
+// first number drawn is equally likely to be any unseen number card. The
+// second result is false for any other hand, or when no number card is
+// left unseen.
+func CalculateFlip7Chase(player PlayerInterface, gameState *GameState) (Flip7Chase, bool) {
+	needed := 7 - player.NumberOfNumberCards()
+	if needed < 1 || needed > 2 {
+		return Flip7Chase{}, false
+	}
+	remaining := gameState.Unseen()
+	held := player.HeldNumbers()
+	fresh, duplicates := 0, 0
+	for value, copies := range remaining.Numbers {
+		if held&(1<<value) != 0 {
+			duplicates += copies
+		} else {
+			fresh += copies
+		}
+	}
+	numbers := float64(fresh + duplicates)
+	if numbers == 0 {
+		return Flip7Chase{}, false
+	}
+
+	multiplier := 1.0
+	if hasMultiplier(player) {
+		multiplier = 2
+	}
+	chase := Flip7Chase{Needed: needed}
+	added := 0.0 // number points expected, weighted by the chance of completing
+	if needed == 1 {
+		chase.Complete = float64(fresh) / numbers
+		for value, copies := range remaining.Numbers {
+			if held&(1<<value) == 0 {
+				added += float64(value*copies) / numbers
+			}
+		}
+	} else if numbers > 1 {
+		// The first new number takes one value; the second has to be a
+		// different one, from the cards left after the first
+		for first, copies := range remaining.Numbers {
+			if held&(1<<first) != 0 || copies == 0 {
+				continue
+			}
+			pFirst := float64(copies) / numbers
+			for second, others := range remaining.Numbers {
+				if held&(1<<second) != 0 || second == first || others == 0 {
+					continue
+				}
+				p := pFirst * float64(others) / (numbers - 1)
+				chase.Complete += p
+				added += p * float64(first+second)
+			}
+		}
+	}
+	chase.Bust = 1 - chase.Complete
+	if chase.Complete > 0 {
+		chase.Score = float64(player.CalculateRoundScore()) + added/chase.Complete*multiplier + float64(flip7BonusOf(player))
+	}
+	return chase, true
+}
+
+// chaseFlip7 decides a hand within reach of Flip 7 by what chasing it is
+// worth. At six numbers the seventh card is all or nothing, so the hand
+// hits exactly when chasing is expected to bank more than staying. At
+// five it hits when chasing both cards is worth it, and otherwise leaves
+// the decision to the strategy, which may still take one more card. The
+// second result is false when the hand isn't within reach.
+func chaseFlip7(self PlayerInterface, gameState *GameState) (hit, decided bool) {
+	chase, ok := CalculateFlip7Chase(self, gameState)
+	if !ok {
+		return false, false
+	}
+	if chase.Value() > float64(self.CalculateRoundScore()) {
+		return true, true
+	}
+	return false, chase.Needed == 1
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChatConfig sets up a seat played by a stream's chat
+type ChatConfig struct {
+	Source   string `yaml:"source" toml:"source"`     // twitch:CHANNEL or lines:PATH
+	Window   string `yaml:"window" toml:"window"`     // how long each vote stays open
+	Cooldown string `yaml:"cooldown" toml:"cooldown"` // how often each viewer may vote
+	Overlay  string `yaml:"overlay" toml:"overlay"`   // JSON file for stream overlays
+}
+
+// ChatMessage is one message in the stream's chat
+type ChatMessage struct {
+	User string
+	Text string
+	Time time.Time
+}
+
+// ChatOption is a choice chat can vote for, and its votes so far
+type ChatOption struct {
+	Command string `json:"command"`
+	Label   string `json:"label"`
+	Votes   int    `json:"votes"`
+}
+
+// ChatOverlay is the vote in progress or last decided, written for
+// stream overlays to show
+type ChatOverlay struct {
+	Seat     string         `json:"seat"`
+	Question string         `json:"question"`
+	Options  []ChatOption   `json:"options"`
+	Open     bool           `json:"open"`
+	EndsAt   time.Time      `json:"ends_at"`
+	Voters   int            `json:"voters"`
+	Result   string         `json:"result,omitempty"`
+	Scores   map[string]int `json:"scores"`
+}
+
+// ChatVotes runs chat's votes: each stays open for Window, each viewer's
+// last vote counts, and a viewer may vote again only after Cooldown
+type ChatVotes struct {
+	Window   time.Duration
+	Cooldown time.Duration
+	Overlay  string
+
+	messages <-chan ChatMessage
+	voted    map[string]time.Time
+	out      io.Writer
+}
+
+// NewChatVotes connects to the configured chat
+func NewChatVotes(c ChatConfig, out io.Writer) (*ChatVotes, error) {
+	v := &ChatVotes{Window: 20 * time.Second, Cooldown: 2 * time.Second, Overlay: c.Overlay, voted: make(map[string]time.Time), out: out}
+	for _, d := range []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{{"window", c.Window, &v.Window}, {"cooldown", c.Cooldown, &v.Cooldown}} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("chat.%s: %q is not a duration such as 20s", d.name, d.value)
+		}
+		*d.into = parsed
+	}
+
+	kind, target, _ := strings.Cut(c.Source, ":")
+	var err error
+	switch kind {
+	case "twitch":
+		v.messages, err = readTwitchChat(target)
+	case "lines":
+		v.messages, err = readChatLines(target)
+	default:
+		err = fmt.Errorf("chat.source: %q is not twitch:CHANNEL or lines:PATH", c.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// chatBuffer is how many messages wait between votes; older ones are
+// dropped rather than holding up the connection
+const chatBuffer = 256
+
+// readTwitchChat reads a Twitch channel's chat anonymously over IRC
+func readTwitchChat(channel string) (<-chan ChatMessage, error) {
+	channel = strings.ToLower(strings.TrimPrefix(channel, "#"))
+	if channel == "" {
+		return nil, fmt.Errorf("chat.source: twitch needs a channel, e.g. twitch:mychannel")
+	}
+	conn, err := net.DialTimeout("tcp", "irc.chat.twitch.tv:6667", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	// Anonymous justinfan logins can read any channel's chat
+	fmt.Fprintf(conn, "NICK justinfan%d\r\nJOIN #%s\r\n", 10000+rand.Intn(90000), channel)
+
+	messages := make(chan ChatMessage, chatBuffer)
+	go func() {
+		defer close(messages)
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if rest, ok := strings.CutPrefix(line, "PING "); ok {
+				fmt.Fprintf(conn, "PONG %s\r\n", rest)
+				continue
+			}
+			// :user!user@user.tmi.twitch.tv PRIVMSG #channel :text
+			prefix, rest, ok := strings.Cut(line, " PRIVMSG ")
+			if !ok {
+				continue
+			}
+			_, text, ok := strings.Cut(rest, " :")
+			if !ok {
+				continue
+			}
+			user, _, _ := strings.Cut(strings.TrimPrefix(prefix, ":"), "!")
+			sendChat(messages, ChatMessage{User: user, Text: text, Time: time.Now()})
+		}
+	}()
+	return messages, nil
+}
+
+// readChatLines reads "user: message" lines from a file or named pipe,
+// for chats other programs relay, such as YouTube's
+func readChatLines(path string) (<-chan ChatMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	messages := make(chan ChatMessage, chatBuffer)
+	go func() {
+		defer close(messages)
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			user, text, ok := strings.Cut(scanner.Text(), ":")
+			if ok {
+				sendChat(messages, ChatMessage{User: strings.TrimSpace(user), Text: strings.TrimSpace(text), Time: time.Now()})
+			}
+		}
+	}()
+	return messages, nil
+}
+
+// sendChat queues a message unless the queue is full
+func sendChat(messages chan<- ChatMessage, m ChatMessage) {
+	select {
+	case messages <- m:
+	default:
+	}
+}
+
+// vote opens a vote on options and returns the winner, or false if no
+// one voted or the vote was tied
+func (v *ChatVotes) vote(seat, question string, options []ChatOption, state *GameState) (int, bool) {
+	opened := time.Now()
+	overlay := ChatOverlay{Seat: seat, Question: question, Options: options, Open: true, EndsAt: opened.Add(v.Window), Scores: make(map[string]int)}
+	for _, p := range state.Players {
+		overlay.Scores[p.GetName()] = p.GetTotalScore()
+	}
+	commands := make([]string, len(options))
+	for i, o := range options {
+		commands[i] = o.Command
+	}
+	fmt.Fprintf(v.out, T("💬 Chat vote: %s Type %s (%ds)\n"), question, strings.Join(commands, " "), int(v.Window.Seconds()))
+	v.writeOverlay(overlay)
+
+	votes := make(map[string]int)
+	timer := time.NewTimer(v.Window)
+	defer timer.Stop()
+	messages := v.messages
+	for {
+		select {
+		case m, ok := <-messages:
+			if !ok {
+				messages = nil // the chat closed; wait out the vote
+				continue
+			}
+			choice := chatChoice(m.Text, options)
+			if choice < 0 || m.Time.Before(opened) {
+				continue
+			}
+			if last, ok := v.voted[m.User]; ok && m.Time.Sub(last) < v.Cooldown {
+				continue
+			}
+			v.voted[m.User] = m.Time
+			votes[m.User] = choice
+			for i := range overlay.Options {
+				overlay.Options[i].Votes = 0
+			}
+			for _, c := range votes {
+				overlay.Options[c].Votes++
+			}
+			overlay.Voters = len(votes)
+			v.writeOverlay(overlay)
+		case <-timer.C:
+			best, tied := -1, false
+			for i, o := range overlay.Options {
+				switch {
+				case o.Votes == 0:
+				case best < 0 || o.Votes > overlay.Options[best].Votes:
+					best, tied = i, false
+				case o.Votes == overlay.Options[best].Votes:
+					tied = true
+				}
+			}
+			overlay.Open = false
+			if best < 0 || tied {
+				fmt.Fprintf(v.out, T("💬 No clear winner from chat; %s plays its own strategy\n"), seat)
+				v.writeOverlay(overlay)
+				return 0, false
+			}
+			overlay.Result = overlay.Options[best].Command
+			fmt.Fprintf(v.out, T("💬 Chat chose %s (%d of %d votes)\n"), overlay.Options[best].Label, overlay.Options[best].Votes, len(votes))
+			v.writeOverlay(overlay)
+			return best, true
+		}
+	}
+}
+
+// chatChoice finds the option a message votes for, by its command or
+// its number, or -1 if it isn't a vote
+func chatChoice(text string, options []ChatOption) int {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) == 0 {
+		return -1
+	}
+	for i, o := range options {
+		if fields[0] == o.Command || fields[0] == "!"+strconv.Itoa(i+1) {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeOverlay replaces the overlay file, so overlays never read half
+// of it
+func (v *ChatVotes) writeOverlay(overlay ChatOverlay) {
+	if v.Overlay == "" {
+		return
+	}
+	data, _ := json.MarshalIndent(overlay, "", "  ")
+	tmp := filepath.Join(filepath.Dir(v.Overlay), "."+filepath.Base(v.Overlay)+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err == nil {
+		os.Rename(tmp, v.Overlay)
+	}
+}
+
+// ChatPlayer is a seat whose decisions chat votes on. When chat doesn't
+// agree, the seat's computer strategy decides.
+type ChatPlayer struct {
+	*ComputerPlayer
+	chat *ChatVotes
+}
+
+// NewChatPlayer creates a seat for chat, falling back on spec's strategy
+func NewChatPlayer(name string, spec StrategySpec, chat *ChatVotes) *ChatPlayer {
+	return &ChatPlayer{ComputerPlayer: NewComputerPlayerFromSpec(name, spec), chat: chat}
+}
+
+// GetPlayerIcon shows the seat belongs to chat
+func (p *ChatPlayer) GetPlayerIcon() string {
+	return "💬"
+}
+
+// MakeHitStayDecision puts hit or stay to a vote
+func (p *ChatPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	options := []ChatOption{{Command: "!hit", Label: T("HIT")}, {Command: "!stay", Label: T("STAY")}}
+	question := fmt.Sprintf(T("%s has %d points this round. Hit or stay?"), p.Name, p.CalculateRoundScore())
+	if choice, ok := p.chat.vote(p.Name, question, options, gameState); ok {
+		return choice == 0, nil
+	}
+	if p.HasSecondChance() {
+		return true, nil
+	}
+	return p.HitOrStayStrategy(p, gameState), nil
+}
+
+// DecideAction puts who gets an action card to a vote
+func (p *ChatPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	if target, ok := p.voteTarget(gameState, decision); ok {
+		return target, nil
+	}
+	return p.targetStrategy(decision.Action)(p, gameState, decision.Action), nil
+}
+
+// voteTarget puts the choice of a legal target to a vote
+func (p *ChatPlayer) voteTarget(gameState *GameState, decision ActionDecision) (PlayerInterface, bool) {
+	targets, question := decision.Targets, decision.Question()
+	options := make([]ChatOption, len(targets))
+	for i, player := range targets {
+		options[i] = ChatOption{Command: "!" + strconv.Itoa(i+1), Label: player.GetName()}
+	}
+	choice, ok := p.chat.vote(p.Name, question, options, gameState)
+	if !ok {
+		return nil, false
+	}
+	return targets[choice], true
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// Clone copies the game at this moment so a strategy can play out
+// hypothetical futures without touching the live game. The clone is
+// silent, has no listeners, records no replay and writes no files, reads
+// no input, and takes its shuffles and computer choices from seed, so
+// the live game's random sources are left alone and lookahead is
+// reproducible. Its deck keeps the live order; lookahead that shouldn't
+// know the hidden cards shuffles clone.deck first. Humans and remote
+// seats are copied too, and would still ask their players if the clone
+// asked them to decide, so lookahead decides for them.
+func (g *Game) Clone(seed int64) *Game {
+	clone := *g
+	clone.players = make([]PlayerInterface, len(g.players))
+	for i, p := range g.players {
+		clone.players[i] = p.Clone()
+	}
+	clone.deck = g.deck.Clone(seed)
+	clone.history = slices.Clone(g.history)
+	clone.progression = g.progression.clone(g.players, clone.players)
+	clone.winChances = slices.Clone(g.winChances)
+	clone.roster = slices.Clone(g.roster)
+	clone.namePool = slices.Clone(g.namePool)
+	clone.cardSet = maps.Clone(g.cardSet)
+	clone.scanner = bufio.NewScanner(strings.NewReader(""))
+	clone.stopRule = nil
+	clone.rng = rand.New(rand.NewSource(seed + 1))
+	clone.banter = rand.New(rand.NewSource(seed + 2))
+	clone.listeners = nil
+	clone.undoEnabled = false
+	clone.undoStack = nil
+	clone.pooled = nil
+	clone.metrics = nil
+	clone.spans = nil
+	clone.decisions = nil
+	clone.replay = nil
+	clone.recordFile = ""
+	clone.checkpoint = ""
+	clone.results = ""
+	clone.decisionsFile = ""
+	clone.scoresFile = ""
+	clone.report = ""
+	clone.commentary = false
+	clone.silentMode = true
+	clone.SetOutput(io.Discard)
+	return &clone
+}
+
+// Clone copies the deck, in the same order, with its own random source
+// seeded from seed
+func (d *Deck) Clone(seed int64) DeckInterface {
+	clone := *d
+	clone.cards = slices.Clone(d.cards)
+	clone.discards = slices.Clone(d.discards)
+	clone.rng = rand.New(rand.NewSource(seed))
+	clone.logger = slog.New(slog.DiscardHandler)
+	// Debug mode asks for each card, which would read the live input
+	clone.debugMode, clone.scanner, clone.scripted = false, nil, 0
+	clone.out = io.Discard
+	return &clone
+}
+
+// clone copies the progression for a game whose players are copies of
+// players, in the same seats
+func (sp ScoreProgression) clone(players, copies []PlayerInterface) ScoreProgression {
+	clone := ScoreProgression{Rounds: slices.Clone(sp.Rounds)}
+	for _, p := range sp.Players {
+		if i := slices.Index(players, p); i >= 0 {
+			p = copies[i]
+		}
+		clone.Players = append(clone.Players, p)
+	}
+	for _, totals := range sp.Totals {
+		clone.Totals = append(clone.Totals, slices.Clone(totals))
+	}
+	return clone
+}
+
+// Clone copies the stacked deck and the sequence it deals
+func (d *StackedDeck) Clone(seed int64) DeckInterface {
+	return &StackedDeck{Deck: d.Deck.Clone(seed).(*Deck), stack: d.stack}
+}
+
+// Clone copies the player's hand and score; strategies are shared, but
+// a learner that can copy itself is copied, so lookahead doesn't teach it
+func (p *ComputerPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.BasePlayer = cloneBasePlayer(&p.BasePlayer)
+	if l, ok := p.Learning.(learningCloner); ok {
+		clone.Learning = l.cloneLearning()
+	}
+	return &clone
+}
+
+// Clone copies the player's hand and score
+func (p *HumanPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.BasePlayer = cloneBasePlayer(&p.BasePlayer)
+	return &clone
+}
+
+// Clone copies the seat's hand and score
+func (p *SlackPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.BasePlayer = cloneBasePlayer(&p.BasePlayer)
+	return &clone
+}
+
+// Clone copies the seat's hand and score
+func (p *ChatPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.ComputerPlayer = p.ComputerPlayer.Clone().(*ComputerPlayer)
+	return &clone
+}
+
+// Clone copies the seat's hand and score
+func (p *LadderPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.ComputerPlayer = p.ComputerPlayer.Clone().(*ComputerPlayer)
+	clone.events = slices.Clone(p.events)
+	return &clone
+}
+
+// Clone copies the seat's hand and score
+func (p *ArenaPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.ComputerPlayer = p.ComputerPlayer.Clone().(*ComputerPlayer)
+	clone.events = slices.Clone(p.events)
+	return &clone
+}
+
+// Clone copies the agent's hand and score
+func (p *AgentPlayer) Clone() PlayerInterface {
+	clone := *p
+	clone.ComputerPlayer = p.ComputerPlayer.Clone().(*ComputerPlayer)
+	return &clone
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// snapshot describes everything a lookahead could disturb in a game: the
+// round, every hand and total, the history, the deck in draw order and
+// the discards
+func snapshot(g *Game) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "round %d, dealer %d, %d events\n", g.round, g.dealerIdx, len(g.history))
+	for _, p := range g.players {
+		fmt.Fprintf(&sb, "%s: %s (%d) %v\n", p.GetName(), formatCards(p.GetHand()), p.GetTotalScore(), p.GetState())
+	}
+	fmt.Fprintf(&sb, "deck: %s\n", formatCards(g.deck.PeekTop(g.deck.CardsLeft())))
+	fmt.Fprintf(&sb, "discards: %s\n", formatCards(g.deck.Discards()))
+	return sb.String()
+}
+
+func TestClonePlaysOutWithoutTouchingTheGame(t *testing.T) {
+	dir := t.TempDir()
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSeed(11)
+	g.SetRecord(filepath.Join(dir, "replay.json"))
+	g.SetCheckpoint(filepath.Join(dir, "checkpoint.json"))
+	g.SetResults(filepath.Join(dir, "results.jsonl"))
+	g.players = []PlayerInterface{
+		NewComputerPlayer("Ada", ExpectedValueStrategy, TargetLeaderStrategy, TargetLeaderStrategy),
+		NewComputerPlayer("Bo", ExpectedValueStrategy, TargetLeaderStrategy, TargetLeaderStrategy),
+	}
+	for _, p := range g.players {
+		p.AddCard(g.deck.DrawCard())
+	}
+	g.replay = &Replay{}
+	before := snapshot(g)
+
+	clone := g.Clone(3)
+	if err := clone.runSingleGame(context.Background()); err != nil {
+		t.Fatalf("playing out the clone: %v", err)
+	}
+	if !clone.hasWinner() {
+		t.Fatal("the clone stopped before anyone won")
+	}
+
+	if after := snapshot(g); after != before {
+		t.Errorf("playing out a clone changed the game\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+	if n := len(g.replay.Decisions); n != 0 {
+		t.Errorf("the clone recorded %d decisions in the game's replay", n)
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		t.Errorf("the clone wrote %s", f.Name())
+	}
+}
+
+func TestCloneSharesNothingMutable(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSeed(5)
+	g.scanner = bufio.NewScanner(strings.NewReader("7\n"))
+	g.deck.SetDebugMode(true, g.scanner)
+	g.players = table("Ada 40", "Bo 25")
+	g.progression = ScoreProgression{Players: g.players, Rounds: []int{1}, Totals: [][]int{{40, 25}}}
+	g.winChances = []float64{0.6, 0.4}
+	g.namePool = []RosterEntry{{Name: "Cy"}}
+	g.stopRule = &StopRule{Separated: true}
+
+	clone := g.Clone(1)
+	clone.progression.Totals[0][0] = 0
+	clone.progression.Rounds[0] = 9
+	clone.winChances[0] = 0
+	clone.namePool[0].Name = "Di"
+	if g.progression.Totals[0][0] != 40 || g.progression.Rounds[0] != 1 || g.winChances[0] != 0.6 || g.namePool[0].Name != "Cy" {
+		t.Error("changing the clone's progression, win chances or name pool changed the game's")
+	}
+	for i, p := range clone.progression.Players {
+		if p != clone.players[i] {
+			t.Errorf("the clone's progression charts %s from the live game", p.GetName())
+		}
+	}
+	if clone.stopRule != nil {
+		t.Error("the clone keeps the simulation's stopping rule")
+	}
+	if clone.banter == g.banter {
+		t.Error("the clone picks banter from the game's source")
+	}
+
+	if card := clone.deck.DrawCard(); card == nil {
+		t.Fatal("the clone's deck drew nothing")
+	}
+	if clone.scanner.Scan() {
+		t.Errorf("the clone read %q", clone.scanner.Text())
+	}
+	if !g.scanner.Scan() || g.scanner.Text() != "7" {
+		t.Error("the clone used up the game's input")
+	}
+}
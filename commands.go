@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ErrQuit is returned when a human quits the game from the command palette
+var ErrQuit = errors.New("player quit the game")
+
+// defaultSavePath is offered when saving from the command palette
+const defaultSavePath = "flip7-save.json"
+
+// CommandHandler runs a command typed at a human's hit/stay prompt. It
+// reports false if the input is not a command.
+type CommandHandler func(ctx context.Context, player PlayerInterface, input string) (bool, error)
+
+// handleCommand dispatches the command palette available to humans
+func (g *Game) handleCommand(ctx context.Context, player PlayerInterface, input string) (bool, error) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "scores":
+		g.show(g.drawScores)
+	case "standings":
+		g.prompt(g.standingsText())
+	case "hands":
+		g.show(g.drawAllHands)
+	case "discards":
+		g.showDiscards()
+	case "odds":
+		g.showOdds(player)
+	case "count":
+		g.showCardCount(player)
+	case "history":
+		g.showHistory()
+	case "undo":
+		if !g.canUndo() {
+			return false, nil
+		}
+		if !g.undo(player) {
+			g.promptln(T("Nothing to undo this round"))
+			return true, nil
+		}
+		g.promptln(T("↩️ Undone: back to your previous decision"))
+		g.show(func(w io.Writer) { g.renderer.RenderHand(w, player) })
+	case ":edit":
+		if !g.canEdit() {
+			return false, nil
+		}
+		return true, g.runEditor(ctx, player)
+	case "help", "?":
+		g.showCommandHelp()
+	case "quit":
+		return true, g.confirmQuit(ctx, fields[1:])
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (g *Game) showCommandHelp() {
+	g.promptln(T("Commands:"))
+	g.promptln(T("  scores    - show the scoreboard"))
+	g.promptln(T("  standings - show the standings with the points in play"))
+	g.promptln(T("  hands     - show every player's hand"))
+	g.promptln(T("  discards  - show the discard pile"))
+	g.promptln(T("  odds      - show your chance of busting if you hit"))
+	g.promptln(T("  count     - show unseen cards and your bust chance"))
+	g.promptln(T("  history   - show what has happened this round"))
+	if g.canUndo() {
+		g.promptln(T("  undo      - take back your last decision this round"))
+	}
+	if g.canEdit() {
+		g.promptln(T("  :edit     - set scores, hands, states and the round (debug mode)"))
+	}
+	g.promptln(T("  quit      - quit the game, optionally saving it"))
+}
+
+// showDiscards prints the discard pile grouped by card
+func (g *Game) showDiscards() {
+	if len(g.deck.Discards()) == 0 {
+		g.promptln(T("The discard pile is empty"))
+		return
+	}
+	g.promptf(T("Discard pile (%d cards): %s\n"), len(g.deck.Discards()), summarizeCards(g.renderer, g.deck.Discards()))
+}
+
+// summarizeCards renders cards grouped by value, e.g. "[3]x2 [7] [+4]"
+func summarizeCards(r Renderer, cards []*Card) string {
+	counts := make(map[string]int)
+	order := make([]*Card, 0)
+	for _, card := range cards {
+		if counts[card.String()] == 0 {
+			order = append(order, card)
+		}
+		counts[card.String()]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].Type != order[j].Type {
+			return order[i].Type < order[j].Type
+		}
+		switch order[i].Type {
+		case NumberCard:
+			return order[i].Value < order[j].Value
+		case ActionCard:
+			return order[i].Action < order[j].Action
+		}
+		return order[i].Modifier < order[j].Modifier
+	})
+
+	parts := make([]string, len(order))
+	for i, card := range order {
+		parts[i] = r.Card(card)
+		if n := counts[card.String()]; n > 1 {
+			parts[i] += fmt.Sprintf("x%d", n)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// showOdds prints the player's chance of busting on the next card
+func (g *Game) showOdds(player PlayerInterface) {
+	if g.deck.CardsLeft() == 0 {
+		g.promptln(T("The deck is empty"))
+		return
+	}
+	gameState := g.buildGameState()
+	bustProb := CalculateBustProbability(player, gameState)
+	g.promptf(T("Chance to bust if you hit: %.1f%% (%d cards in the deck)\n"), bustProb*100, g.deck.CardsLeft())
+	var left []string
+	for value, n := range g.deck.RemainingByValue() {
+		if n > 0 {
+			left = append(left, fmt.Sprintf("%d×%d", value, n))
+		}
+	}
+	g.promptf(T("Numbers left in the deck: %s\n"), strings.Join(left, " "))
+	if threat := gameState.FreezeThreat(player); threat > 0 {
+		g.promptf(T("Chance of being frozen before your next turn: %.1f%%\n"), threat*100)
+		for _, t := range gameState.FreezeThreats(player) {
+			if t.Chance > 0 {
+				g.promptf("   %s: %.1f%%\n", t.Opponent.GetName(), t.Chance*100)
+			}
+		}
+	}
+	switch {
+	case !gameState.LeaderWillWinIfRoundEnds:
+	case gameState.CurrentLeader == player:
+		g.promptln(T("Final round: you win if it ends now"))
+	default:
+		g.promptf(T("Final round: %s wins if it ends now; you need %d more points to overtake\n"),
+			gameState.CurrentLeader.GetName(), gameState.PointsNeededToOvertake(player))
+	}
+}
+
+// recordHistory keeps the events of the current round for the history command
+func (g *Game) recordHistory(event Event) {
+	if event.Type == RoundStarted {
+		g.history = g.history[:0]
+	}
+	g.history = append(g.history, event)
+}
+
+func (g *Game) showHistory() {
+	if len(g.history) == 0 {
+		g.promptln(T("Nothing has happened yet"))
+		return
+	}
+	for _, event := range g.history {
+		g.promptf("   %s\n", describeEvent(event))
+	}
+}
+
+// confirmQuit asks the human to confirm quitting and offers to save first.
+// It returns ErrQuit if the game should end.
+func (g *Game) confirmQuit(ctx context.Context, args []string) error {
+	g.prompt(T("Really quit? (y/n): "))
+	if !g.readYes(ctx) {
+		return nil
+	}
+
+	g.offerSave(ctx, args)
+	return ErrQuit
+}
+
+// interrupted winds up an interactive game stopped by Ctrl-C: it shows
+// the standings and offers to save the game, so it can be resumed from
+// the start of the round. It returns ErrQuit.
+func (g *Game) interrupted() error {
+	g.promptln(T("\n\n🛑 Interrupted"))
+	g.show(g.drawScores)
+	g.offerSave(context.Background(), nil)
+	return ErrQuit
+}
+
+// offerSave asks whether to save the game before quitting, to the path
+// in args or one the human types
+func (g *Game) offerSave(ctx context.Context, args []string) {
+	g.prompt(T("Save the game before quitting? (y/n): "))
+	if g.readYes(ctx) {
+		path := defaultSavePath
+		if len(args) > 0 {
+			path = args[0]
+		} else {
+			g.promptf(T("Save file [%s]: "), defaultSavePath)
+			if input, err := g.getStringInput(ctx); err == nil && input != "" {
+				path = input
+			}
+		}
+
+		if err := g.SaveGame(path); err != nil {
+			g.promptf(T("Could not save the game: %v\n"), err)
+		} else {
+			g.promptf(T("Game saved to %s. Resume with: flip7 -resume %s\n"), path, path)
+		}
+	}
+}
+
+// hasHumans reports whether anyone at the table is playing at the
+// terminal
+func (g *Game) hasHumans() bool {
+	for _, player := range g.players {
+		if _, ok := player.(*HumanPlayer); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// readYes reads a yes/no answer, treating anything but yes as no
+func (g *Game) readYes(ctx context.Context) bool {
+	input, err := g.getStringInput(ctx)
+	if err != nil {
+		return false
+	}
+	answer := strings.ToLower(input)
+	return answer == "y" || answer == "yes" || answer == T("y") || answer == T("yes")
+}
@@ -0,0 +1,149 @@
+package main
+
+import "fmt"
+
+// Remark is a situation a computer player comments on
+type Remark int
+
+const (
+	RemarkBusted Remark = iota
+	RemarkFrozeLeader
+	RemarkFlipThree
+	RemarkFlip7
+	RemarkWon
+	// Replies from the target of an action card, naming who played it
+	RemarkGotFrozen
+	RemarkGotFlipThree
+	RemarkGotSecondChance
+)
+
+// Personality is the voice a computer player comments in
+type Personality struct {
+	Name  string
+	Lines map[Remark][]string
+}
+
+var (
+	cautiousPersonality = &Personality{
+		Name: "cautious",
+		Lines: map[Remark][]string{
+			RemarkBusted:          {"The odds were in my favor. They were!", "I knew I should have stayed."},
+			RemarkFrozeLeader:     {"Safety first, %s. Especially yours.", "Let's keep things calm, %s."},
+			RemarkFlipThree:       {"Careful now, %s.", "Three cards, %s. Count them carefully."},
+			RemarkFlip7:           {"Seven, one careful step at a time."},
+			RemarkWon:             {"Slow and steady."},
+			RemarkGotFrozen:       {"Frozen? Fine by me, %s. I'll keep what I have."},
+			RemarkGotFlipThree:    {"Three cards, %s? That's not how I play."},
+			RemarkGotSecondChance: {"Thank you, %s. I'll put it somewhere safe."},
+		},
+	}
+	recklessPersonality = &Personality{
+		Name: "reckless",
+		Lines: map[Remark][]string{
+			RemarkBusted:          {"Worth it!", "Again! Deal me in again!"},
+			RemarkFrozeLeader:     {"Chill out, %s!", "Ice to meet you, %s."},
+			RemarkFlipThree:       {"Go big, %s!", "Flip 'em, %s! Flip 'em all!"},
+			RemarkFlip7:           {"FLIP SEVEN! Who needs odds?"},
+			RemarkWon:             {"Fortune favors the bold!"},
+			RemarkGotFrozen:       {"Hey! I was just getting started, %s!"},
+			RemarkGotFlipThree:    {"Three more? Thanks, %s, I love it!"},
+			RemarkGotSecondChance: {"A safety net, %s? Now I can really go for it!"},
+		},
+	}
+	analystPersonality = &Personality{
+		Name: "analyst",
+		Lines: map[Remark][]string{
+			RemarkBusted:          {"A statistically acceptable outcome.", "Recalculating..."},
+			RemarkFrozeLeader:     {"Freezing the leader maximizes my equity, %s.", "Nothing personal, %s. Just math."},
+			RemarkFlipThree:       {"Your expected value just dropped, %s."},
+			RemarkFlip7:           {"Flip 7, exactly as projected."},
+			RemarkWon:             {"The numbers never lie."},
+			RemarkGotFrozen:       {"Noted, %s. Adjusting my model."},
+			RemarkGotFlipThree:    {"Variance introduced, %s. Noted."},
+			RemarkGotSecondChance: {"A free option, %s. Accepted."},
+		},
+	}
+	competitorPersonality = &Personality{
+		Name: "competitor",
+		Lines: map[Remark][]string{
+			RemarkBusted:          {"Argh! Not again!", "That one hurt."},
+			RemarkFrozeLeader:     {"Not so fast, %s!", "Your lead ends here, %s."},
+			RemarkFlipThree:       {"Let's see you handle this, %s!"},
+			RemarkFlip7:           {"Seven! Catch me if you can!"},
+			RemarkWon:             {"Never in doubt!"},
+			RemarkGotFrozen:       {"You'll pay for that, %s!"},
+			RemarkGotFlipThree:    {"Bring it on, %s!"},
+			RemarkGotSecondChance: {"You'll regret helping me, %s."},
+		},
+	}
+)
+
+// personalityFor picks a computer player's voice from its strategy
+func personalityFor(spec StrategySpec) *Personality {
+	switch spec.Choice {
+	case 3, 4:
+		return recklessPersonality
+	case 2, 5, 9:
+		return cautiousPersonality
+	case 6, 7, 10, 13, 14:
+		return analystPersonality
+	}
+	return competitorPersonality
+}
+
+// SetCommentary turns computer player banter on or off
+func (g *Game) SetCommentary(enabled bool) {
+	g.commentary = enabled
+}
+
+// commentate is an EventListener that has computer players remark on
+// key events
+func (g *Game) commentate(event Event) {
+	if !g.commentary || g.silentMode {
+		return
+	}
+
+	switch event.Type {
+	case PlayerBusted:
+		g.remark(event.Player, RemarkBusted, nil)
+	case ActionPlayed:
+		switch {
+		case event.Card.Action == Freeze && event.Target != event.Player && event.Target == g.buildGameState().CurrentLeader:
+			g.remark(event.Player, RemarkFrozeLeader, event.Target)
+		case event.Card.Action == FlipThree && event.Target != event.Player:
+			g.remark(event.Player, RemarkFlipThree, event.Target)
+		}
+		if reply, ok := replies[event.Card.Action]; ok && event.Target != event.Player {
+			g.remark(event.Target, reply, event.Player)
+		}
+	case Flip7Achieved:
+		g.remark(event.Player, RemarkFlip7, nil)
+	case GameEnded:
+		g.remark(event.Player, RemarkWon, nil)
+	}
+}
+
+// replies are what the target of each action card says back
+var replies = map[ActionType]Remark{
+	Freeze:       RemarkGotFrozen,
+	FlipThree:    RemarkGotFlipThree,
+	SecondChance: RemarkGotSecondChance,
+}
+
+// remark prints a line from a computer player, naming the target if any
+func (g *Game) remark(speaker PlayerInterface, remark Remark, target PlayerInterface) {
+	computer, ok := speaker.(*ComputerPlayer)
+	if !ok {
+		return
+	}
+
+	lines := personalityFor(computer.Spec).Lines[remark]
+	if len(lines) == 0 {
+		return
+	}
+	line := T(lines[g.banter.Intn(len(lines))])
+	if target != nil {
+		line = fmt.Sprintf(line, target.GetName())
+	}
+	g.printf(T("   💬 %s: \"%s\"\n"), speaker.GetName(), line)
+}
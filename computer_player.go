@@ -1,8 +1,10 @@
 package main
 
 import (
-	"math"
+	"context"
 	"math/rand"
+
+	"flip7/bot"
 )
 
 // GameState provides context for AI decision making
@@ -11,17 +13,135 @@ type GameState struct {
 	Players       []PlayerInterface
 	ActivePlayers []PlayerInterface
 	CurrentLeader PlayerInterface
-	CardsInDeck   []*Card
+	// WinningScore is the total that ends the game, for Standing.Needs
+	WinningScore int
+	// LeaderWillWinIfRoundEnds is set when the round as it stands would
+	// end the game with CurrentLeader winning: anyone else has to beat
+	// them this round or lose
+	LeaderWillWinIfRoundEnds bool
+	// DiscardedCards is the discard pile, face up for everyone to count,
+	// and Discarded tallies it; it's counted from them if nil
+	DiscardedCards []*Card
+	Discarded      *DeckCounts
+	// Remaining tallies the undrawn cards from the deck's own card set.
+	// Every card off the deck is face up, so the tally is public and
+	// Unseen returns it; the order the cards will be drawn in is not,
+	// and isn't handed to strategies.
+	Remaining *DeckCounts
+	// Rand makes the random choices; the shared source is used if nil
+	Rand *rand.Rand
+
+	hands     [][]*Card
+	states    []PlayerState
+	ranking   []Standing
+	unseen    DeckCounts
+	counted   bool
+	discarded DeckCounts
+	remaining DeckCounts
+}
+
+// Hands returns the cards each of Players has on the table, in the same
+// order
+func (s *GameState) Hands() [][]*Card {
+	if s.hands == nil {
+		s.hands = make([][]*Card, len(s.Players))
+		for i, player := range s.Players {
+			s.hands[i] = player.GetHand()
+		}
+	}
+	return s.hands
+}
+
+// States returns whether each of Players is still in the round, stayed,
+// was frozen or busted, in the same order, so a strategy can tell a
+// rival who chose to stop from one a Freeze stopped
+func (s *GameState) States() []PlayerState {
+	if s.states == nil {
+		s.states = make([]PlayerState, len(s.Players))
+		for i, player := range s.Players {
+			s.states[i] = player.GetState()
+		}
+	}
+	return s.states
+}
+
+// random returns the source for the decision's random choices
+func (s *GameState) random() *rand.Rand {
+	if s.Rand == nil {
+		return rng
+	}
+	return s.Rand
+}
+
+// standardCounts tallies the full card set, which the rules make public
+var standardCounts = countDeck(standardCards)
+
+// Unseen tallies the cards no player can see, which is what a player
+// counting cards knows about the deck. Every card off the deck is face
+// up, in a hand, on the discard pile or being played, so in a game it's
+// Remaining, the deck's tally of its own cards. A state without one, set
+// up by hand, counts the full card set less the cards in Players' hands
+// and the discard pile.
+func (s *GameState) Unseen() *DeckCounts {
+	if s.Remaining != nil {
+		return s.Remaining
+	}
+	if s.counted {
+		return &s.unseen
+	}
+	counts := standardCounts
+	seen := func(cards []*Card) {
+		for _, card := range cards {
+			counts.add(card, -1)
+		}
+	}
+	for _, player := range s.Players {
+		// Players' own slices are read where possible, since this runs
+		// for every decision in simulations
+		if b, ok := player.(baser); ok {
+			p := b.base()
+			seen(p.NumberCards)
+			seen(p.ModifierCards)
+			seen(p.ActionCards)
+			continue
+		}
+		seen(player.GetHand())
+	}
+	if s.Discarded == nil {
+		discarded := countDeck(s.DiscardedCards)
+		s.Discarded = &discarded
+	}
+	for value, n := range s.Discarded.Numbers {
+		counts.Numbers[value] -= n
+	}
+	counts.Modifiers -= s.Discarded.Modifiers
+	counts.ModifierPoints -= s.Discarded.ModifierPoints
+	counts.Actions -= s.Discarded.Actions
+	counts.Freezes -= s.Discarded.Freezes
+	counts.Total -= s.Discarded.Total
+	s.unseen, s.counted = counts, true
+	return &s.unseen
 }
 
 type HitOrStayStrategy func(self PlayerInterface, gameState *GameState) bool
 type ActionTargetStrategy func(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface
 
+// ComputerPlayer plays by its strategies: one for hit or stay, one for
+// who gets a Freeze or Flip Three, one for who gets a spare Second
+// Chance, and optionally its own for Flip Three
 type ComputerPlayer struct {
 	BasePlayer
 	HitOrStayStrategy            HitOrStayStrategy
 	ActionTargetStrategy         ActionTargetStrategy
 	PositiveActionTargetStrategy ActionTargetStrategy
+	// FlipThreeTargetStrategy picks Flip Three targets in place of
+	// ActionTargetStrategy when set
+	FlipThreeTargetStrategy ActionTargetStrategy
+	// Learning is the state of a strategy that learns across games, if
+	// it has any
+	Learning Learner
+	Spec     StrategySpec
+	Icon     string
 }
 
 // NewComputerPlayer creates a new computer player with specified strategy
@@ -37,11 +157,41 @@ func NewComputerPlayer(name string, strategy HitOrStayStrategy, actionTargetStra
 	return p
 }
 
+// NewComputerPlayerFromSpec creates a computer player from a strategy spec
+func NewComputerPlayerFromSpec(name string, spec StrategySpec) *ComputerPlayer {
+	p := NewComputerPlayer(name, nil, nil, nil)
+	p.setSpec(spec)
+	return p
+}
+
+// setSpec makes the player play the strategy the spec describes. A
+// learning strategy keeps what it has learned if it already had one.
+func (p *ComputerPlayer) setSpec(spec StrategySpec) {
+	_, p.HitOrStayStrategy, p.ActionTargetStrategy, p.PositiveActionTargetStrategy = buildStrategy(spec)
+	p.FlipThreeTargetStrategy = nil
+	if target, ok := targetStrategies[spec.FlipThree]; ok {
+		p.FlipThreeTargetStrategy = target
+		if spec.Noise > 0 {
+			p.FlipThreeTargetStrategy = NoisyTargetStrategy(target, spec.Noise)
+		}
+	}
+	switch {
+	case spec.Choice != 13:
+		p.Learning = nil
+	case p.Learning == nil:
+		p.Learning = NewBandit()
+	}
+	p.Spec = spec
+}
+
 func (p *ComputerPlayer) GetPlayerIcon() string {
+	if p.Icon != "" {
+		return p.Icon
+	}
 	return "🤖"
 }
 
-func (p *ComputerPlayer) MakeHitStayDecision(gameState *GameState) (bool, error) {
+func (p *ComputerPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
 	// Always hit if you have a second chance
 	if p.HasSecondChance() {
 		return true, nil
@@ -50,12 +200,19 @@ func (p *ComputerPlayer) MakeHitStayDecision(gameState *GameState) (bool, error)
 	return p.HitOrStayStrategy(p, gameState), nil
 }
 
-func (p *ComputerPlayer) ChooseActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error) {
-	return p.ActionTargetStrategy(p, gameState, actionType), nil
+// targetStrategy returns the strategy that picks who gets an action card
+func (p *ComputerPlayer) targetStrategy(action ActionType) ActionTargetStrategy {
+	switch {
+	case action == SecondChance:
+		return p.PositiveActionTargetStrategy
+	case action == FlipThree && p.FlipThreeTargetStrategy != nil:
+		return p.FlipThreeTargetStrategy
+	}
+	return p.ActionTargetStrategy
 }
 
-func (p *ComputerPlayer) ChoosePositiveActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error) {
-	return p.PositiveActionTargetStrategy(p, gameState, actionType), nil
+func (p *ComputerPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	return p.targetStrategy(decision.Action)(p, gameState, decision.Action), nil
 }
 
 func PlayRoundTo(n int) HitOrStayStrategy {
@@ -74,7 +231,7 @@ func BayesianGainStrategy(self PlayerInterface, gameState *GameState) bool {
 	baseNextCardValue := 8.33
 
 	if self.NumberOfNumberCards() > 6 {
-		baseNextCardValue += 15
+		baseNextCardValue += flip7Bonus
 	}
 
 	if hasMultiplier(self) {
@@ -105,28 +262,27 @@ func CalculateLimitedKnowledgeBustProbability(player PlayerInterface) float64 {
 }
 
 // Helper functions for advanced strategies
-func CalculateBustProbability(player PlayerInterface, gameState *GameState) float64 {
-	numberCards := make(map[int]bool)
-	for _, card := range player.GetHand() {
-		if card.Type == NumberCard {
-			numberCards[card.Value] = true
-		}
-	}
 
-	// Count available cards that would cause a bust
-	bustCards := 0
-	for _, card := range gameState.CardsInDeck {
-		if card.Type == NumberCard && numberCards[card.Value] {
-			bustCards += 1
-		}
+// botCounts is the tally as the bot package counts it, so the engine's
+// computer players and Go bots work out their odds with the same code
+func (c *DeckCounts) botCounts() bot.Counts {
+	return bot.Counts{
+		Numbers:        c.Numbers,
+		Modifiers:      c.Modifiers,
+		ModifierPoints: c.ModifierPoints,
+		Actions:        c.Actions,
+		Total:          c.Total,
 	}
+}
 
-	totalCards := len(gameState.CardsInDeck)
-	if totalCards == 0 {
+// CalculateBustProbability is the chance the player busts on the next
+// card, counted from the cards they can't see; see bot.BustProbability
+func CalculateBustProbability(player PlayerInterface, gameState *GameState) float64 {
+	remaining := gameState.Unseen()
+	if remaining.Total == 0 {
 		panic("no cards left in deck can't calculate bust probability")
 	}
-
-	return float64(bustCards) / float64(totalCards)
+	return bot.HeldBustProbability(player.HeldNumbers(), remaining.botCounts())
 }
 
 func HitUntilAheadBy(n int) HitOrStayStrategy {
@@ -140,7 +296,29 @@ func AlwaysHitStrategy(self PlayerInterface, gameState *GameState) bool {
 }
 
 func RandomHitOrStayStrategy(self PlayerInterface, gameState *GameState) bool {
-	return rand.Intn(2) == 0
+	return gameState.random().Intn(2) == 0
+}
+
+// NoisyStrategy makes the opposite hit/stay decision with probability
+// noise, to weaken a strategy for easier opponents
+func NoisyStrategy(strategy HitOrStayStrategy, noise float64) HitOrStayStrategy {
+	return func(self PlayerInterface, gameState *GameState) bool {
+		hit := strategy(self, gameState)
+		if gameState.random().Float64() < noise {
+			return !hit
+		}
+		return hit
+	}
+}
+
+// NoisyTargetStrategy picks a random target with probability noise
+func NoisyTargetStrategy(strategy ActionTargetStrategy, noise float64) ActionTargetStrategy {
+	return func(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface {
+		if gameState.random().Float64() < noise {
+			return TargetRandomStrategy(self, gameState, actionType)
+		}
+		return strategy(self, gameState, actionType)
+	}
 }
 
 // Advanced strategies that could beat bust probability < 0.3
@@ -180,8 +358,12 @@ func AdaptiveBustProbabilityStrategy(baseProbability float64) HitOrStayStrategy
 	}
 }
 
-// ExpectedValueStrategy considers the expected points gained vs risk
+// ExpectedValueStrategy considers the expected points gained vs risk,
+// and chases Flip 7 when it's within reach and worth it
 func ExpectedValueStrategy(self PlayerInterface, gameState *GameState) bool {
+	if hit, ok := chaseFlip7(self, gameState); ok {
+		return hit
+	}
 	bustProb := CalculateBustProbability(self, gameState)
 	expectedPoints := CalculateExpectedPointsFromHit(self, gameState)
 	currentScore := self.CalculateRoundScore()
@@ -296,8 +478,12 @@ func GapBasedStrategy(self PlayerInterface, gameState *GameState) bool {
 	return CalculateBustProbability(self, gameState) < bustThreshold
 }
 
-// OptimalStrategy - combines best elements of gap-based and bust probability
+// OptimalStrategy - combines best elements of gap-based and bust probability,
+// and chases Flip 7 when it is within reach and worth it
 func OptimalStrategy(self PlayerInterface, gameState *GameState) bool {
+	if hit, ok := chaseFlip7(self, gameState); ok {
+		return hit
+	}
 	bustProb := CalculateBustProbability(self, gameState)
 	currentScore := self.CalculateRoundScore()
 
@@ -350,6 +536,9 @@ func OptimalStrategy(self PlayerInterface, gameState *GameState) bool {
 		baseThreshold += 0.04 // More aggressive with multiplier at low scores
 	}
 
+	// Push when likely to be frozen before the next turn, hold back when not
+	baseThreshold += freezeThreatAdjustment(self, gameState)
+
 	// Apply minimum and maximum bounds
 	if baseThreshold > 0.5 {
 		baseThreshold = 0.5
@@ -360,37 +549,10 @@ func OptimalStrategy(self PlayerInterface, gameState *GameState) bool {
 	return bustProb < baseThreshold
 }
 
+// CalculateExpectedPointsFromHit is what the player's next card is worth
+// on average if it doesn't bust them; see bot.ExpectedPointsFromHit
 func CalculateExpectedPointsFromHit(player PlayerInterface, gameState *GameState) float64 {
-	numberCards := make(map[int]bool)
-	for _, card := range player.GetHand() {
-		if card.Type == NumberCard {
-			numberCards[card.Value] = true
-		}
-	}
-
-	totalPoints := 0.0
-	validCards := 0
-
-	for _, card := range gameState.CardsInDeck {
-		if card.Type == NumberCard && !numberCards[card.Value] {
-			totalPoints += float64(card.Value)
-			validCards++
-		} else if card.Type == ModifierCard {
-			totalPoints += float64(card.GetPoints())
-			validCards++
-		}
-		// Action cards have variable value, approximate as 5 points
-		if card.Type == ActionCard {
-			totalPoints += 5.0
-			validCards++
-		}
-	}
-
-	if validCards == 0 {
-		return 0
-	}
-
-	return totalPoints / float64(validCards)
+	return bot.HeldExpectedPoints(player.HeldNumbers(), gameState.Unseen().botCounts())
 }
 
 func hasMultiplier(player PlayerInterface) bool {
@@ -402,54 +564,22 @@ func hasMultiplier(player PlayerInterface) bool {
 	return false
 }
 
+// TargetLeaderStrategy targets the rival heading the standings, or
+// itself when no rival can take the card
 func TargetLeaderStrategy(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface {
-	var leader PlayerInterface
-	leaderScore := 0
-	for _, player := range gameState.ActivePlayers {
-		if actionType == SecondChance && player.HasSecondChance() {
-			continue
-		}
-
-		if player != self {
-			playerScore := player.GetTotalScore() + player.CalculateRoundScore()
-			if playerScore > leaderScore {
-				leader = player
-				leaderScore = playerScore
-			}
-		}
+	if leader := leaderOf(gameState.rivals(self, actionType)); leader != nil {
+		return leader
 	}
-
-	// Must target self if no other player is active
-	if leader == nil {
-		return self
-	}
-
-	return leader
+	return self
 }
 
+// TargetLastPlaceStrategy targets the rival trailing the standings, or
+// itself when no rival can take the card
 func TargetLastPlaceStrategy(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface {
-	var last PlayerInterface
-	lastScore := math.MaxInt
-	for _, player := range gameState.ActivePlayers {
-		if actionType == SecondChance && player.HasSecondChance() {
-			continue
-		}
-
-		if player != self {
-			playerScore := player.GetTotalScore() + player.CalculateRoundScore()
-			if playerScore < lastScore {
-				last = player
-				lastScore = playerScore
-			}
-		}
+	if last := lastPlaceOf(gameState.rivals(self, actionType)); last != nil {
+		return last
 	}
-
-	// Must target self if no other player is active
-	if last == nil {
-		return self
-	}
-
-	return last
+	return self
 }
 
 func TargetRandomStrategy(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface {
@@ -469,5 +599,5 @@ func TargetRandomStrategy(self PlayerInterface, gameState *GameState, actionType
 		return self
 	}
 
-	return activePlayers[rand.Intn(len(activePlayers))]
+	return activePlayers[gameState.random().Intn(len(activePlayers))]
 }
@@ -0,0 +1,816 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a game setup read from a YAML or TOML file. It covers
+// everything setupPlayers asks for, plus rules and output options.
+type Config struct {
+	Players    []PlayerConfig `yaml:"players" toml:"players"`
+	Games      int            `yaml:"games" toml:"games"`
+	Duplicate  bool           `yaml:"duplicate" toml:"duplicate"`
+	Seats      bool           `yaml:"seats" toml:"seats"`
+	Actions    bool           `yaml:"actions" toml:"actions"`
+	Histograms bool           `yaml:"histograms" toml:"histograms"`
+	Lengths    bool           `yaml:"lengths" toml:"lengths"`
+	Decisions  string         `yaml:"decisions" toml:"decisions"`
+	Scores     string         `yaml:"export_scores" toml:"export_scores"`
+	Rotate     bool           `yaml:"rotate" toml:"rotate"`
+	Fresh      bool           `yaml:"fresh" toml:"fresh"`
+	Fast       bool           `yaml:"fast" toml:"fast"`
+	Dealer     string         `yaml:"dealer" toml:"dealer"`
+	Duration   string         `yaml:"duration" toml:"duration"`
+	Checkpoint string         `yaml:"checkpoint" toml:"checkpoint"`
+	Results    string         `yaml:"results" toml:"results"`
+	Report     string         `yaml:"report" toml:"report"`
+	Params     string         `yaml:"params" toml:"params"`
+	StopWhen   string         `yaml:"stop_when" toml:"stop_when"`
+	Seed       int64          `yaml:"seed" toml:"seed"`
+	Rules      RulesConfig    `yaml:"rules" toml:"rules"`
+	Chat       ChatConfig     `yaml:"chat" toml:"chat"`
+	Output     OutputConfig   `yaml:"output" toml:"output"`
+}
+
+// PlayerConfig is one player in a config file. Computer players name a
+// strategy, or a difficulty preset, and its parameters. A chat player's
+// strategy decides when chat doesn't.
+type PlayerConfig struct {
+	Name            string  `yaml:"name" toml:"name"`
+	Human           bool    `yaml:"human" toml:"human"`
+	Chat            bool    `yaml:"chat" toml:"chat"`
+	Strategy        string  `yaml:"strategy" toml:"strategy"`
+	TargetScore     int     `yaml:"target_score" toml:"target_score"`
+	BustProbability float64 `yaml:"bust_probability" toml:"bust_probability"`
+	GapTolerance    int     `yaml:"gap_tolerance" toml:"gap_tolerance"`
+	SlackFactor     int     `yaml:"slack_factor" toml:"slack_factor"`
+	Icon            string  `yaml:"icon" toml:"icon"`
+	Table           string  `yaml:"table" toml:"table"`
+	Team            string  `yaml:"team" toml:"team"`
+	Target          string  `yaml:"target" toml:"target"`
+	Gift            string  `yaml:"gift" toml:"gift"`
+	FlipThree       string  `yaml:"flip_three" toml:"flip_three"`
+	Noise           float64 `yaml:"noise" toml:"noise"`
+	RiskAversion    float64 `yaml:"risk_aversion" toml:"risk_aversion"`
+	Corpus          string  `yaml:"corpus" toml:"corpus"`
+}
+
+// RulesConfig holds the adjustable game rules. Win is how the game is
+// won: points (the default), rounds, elimination or team, with teams
+// given by each player's team. Reshuffle is when the discards go back
+// into the deck: mid-round (the default), between-rounds or when-spent.
+// Burn is how many cards are burned before every round, and Cards
+// changes the copies of the cards it names in the deck.
+type RulesConfig struct {
+	WinningScore int            `yaml:"winning_score" toml:"winning_score"`
+	HoldActions  bool           `yaml:"hold_actions" toml:"hold_actions"`
+	Reshuffle    string         `yaml:"reshuffle" toml:"reshuffle"`
+	Burn         int            `yaml:"burn" toml:"burn"`
+	Win          string         `yaml:"win" toml:"win"`
+	Rounds       int            `yaml:"rounds" toml:"rounds"`
+	Scoring      *VariantScorer `yaml:"scoring" toml:"scoring"`
+	Cards        CardSet        `yaml:"cards" toml:"cards"`
+}
+
+// applyDeckRules sets the game's reshuffle policy, if the rules name
+// one, the cards it burns before every round and the cards it's dealt
+// from
+func (r RulesConfig) applyDeckRules(g *Game) error {
+	if r.Burn > 0 {
+		g.SetBurnCards(r.Burn)
+	}
+	if len(r.Cards) > 0 {
+		if err := g.SetCardSet(r.Cards); err != nil {
+			return err
+		}
+	}
+	if r.Reshuffle == "" {
+		return nil
+	}
+	policy, err := ParseReshufflePolicy(r.Reshuffle)
+	if err != nil {
+		return err
+	}
+	g.SetReshufflePolicy(policy)
+	return nil
+}
+
+// winSpec returns the win condition the rules and players' teams name
+func (r RulesConfig) winSpec(players []PlayerConfig, names []string) WinSpec {
+	spec := WinSpec{Kind: r.Win, Rounds: r.Rounds}
+	if r.Win == "team" {
+		spec.Teams = make(map[string]string)
+		for i, p := range players {
+			if p.Team != "" {
+				spec.Teams[names[i]] = p.Team
+			}
+		}
+	}
+	return spec
+}
+
+// OutputConfig holds defaults for the output flags. Flags given on the
+// command line take precedence.
+type OutputConfig struct {
+	Theme     string `yaml:"theme" toml:"theme"`
+	Render    string `yaml:"render" toml:"render"`
+	Symbols   string `yaml:"symbols" toml:"symbols"`
+	Verbosity string `yaml:"verbosity" toml:"verbosity"`
+	Lang      string `yaml:"lang" toml:"lang"`
+	Speed     string `yaml:"speed" toml:"speed"`
+	Banter    *bool  `yaml:"banter" toml:"banter"`
+	Count     *bool  `yaml:"count" toml:"count"`
+	Standings *bool  `yaml:"standings" toml:"standings"`
+	Eval      *bool  `yaml:"eval" toml:"eval"`
+	TUI       *bool  `yaml:"tui" toml:"tui"`
+}
+
+// strategyNames maps config strategy names to the setup menu choices
+var strategyNames = map[string]int{
+	"play-to":          1,
+	"bust-probability": 2,
+	"always-hit":       3,
+	"random":           4,
+	"adaptive-bust":    5,
+	"expected-value":   6,
+	"hybrid":           7,
+	"gap-based":        8,
+	"optimal":          9,
+	"bayesian":         10,
+	"gap-aware":        11,
+	"table":            12,
+	"bandit":           13,
+	"risk-utility":     14,
+	"imitate":          15,
+}
+
+// LoadConfig reads and validates a config file. The format is chosen by
+// the extension: .toml for TOML, anything else is read as YAML.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate reports every problem with the config at once
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.Players) > 0 && (len(c.Players) < 2 || len(c.Players) > 18) {
+		errs = append(errs, fmt.Errorf("  players: need 2-18 players, got %d", len(c.Players)))
+	}
+
+	humans, chats := 0, 0
+	names := make(map[string]bool)
+	for i, p := range c.Players {
+		where := fmt.Sprintf("  players[%d]", i)
+		if p.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", where))
+		} else if names[p.Name] {
+			errs = append(errs, fmt.Errorf("%s: name %q is used twice", where, p.Name))
+		}
+		names[p.Name] = true
+
+		if p.Human && p.Chat {
+			errs = append(errs, fmt.Errorf("%s: a player can't be both human and chat", where))
+		}
+		if p.Human {
+			humans++
+			if p.Strategy != "" {
+				errs = append(errs, fmt.Errorf("%s: human players don't have a strategy", where))
+			}
+			continue
+		}
+		if p.Chat {
+			humans++
+			chats++
+			p = p.chatFallback()
+		}
+		if _, err := p.Spec(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", where, err))
+		}
+	}
+	if chats > 1 {
+		errs = append(errs, fmt.Errorf("  players: only one player can be played by chat"))
+	}
+	if chats > 0 && c.Chat.Source == "" {
+		errs = append(errs, fmt.Errorf("  chat.source: needed for the chat player, e.g. twitch:mychannel"))
+	}
+
+	if c.Games < 0 {
+		errs = append(errs, fmt.Errorf("  games: must not be negative"))
+	}
+	if c.Games > 1 && humans > 0 {
+		errs = append(errs, fmt.Errorf("  games: only AI-only setups can simulate several games"))
+	}
+	if c.Dealer != "" {
+		if _, err := ParseDealerMode(c.Dealer); err != nil {
+			errs = append(errs, fmt.Errorf("  dealer: %w", err))
+		}
+	}
+	if c.Duration != "" {
+		if d, err := time.ParseDuration(c.Duration); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("  duration: %q is not a positive duration such as 10m", c.Duration))
+		} else if humans > 0 {
+			errs = append(errs, fmt.Errorf("  duration: only AI-only setups can simulate several games"))
+		}
+	}
+	if c.StopWhen != "" {
+		if _, err := ParseStopRule(c.StopWhen); err != nil {
+			errs = append(errs, fmt.Errorf("  stop_when: %w", err))
+		} else if humans > 0 {
+			errs = append(errs, fmt.Errorf("  stop_when: only AI-only setups can simulate several games"))
+		}
+	}
+	if c.Rules.WinningScore < 0 {
+		errs = append(errs, fmt.Errorf("  rules.winning_score: must be positive"))
+	}
+	if _, err := c.Rules.winSpec(nil, nil).Condition(); err != nil {
+		errs = append(errs, fmt.Errorf("  rules.win: %w", err))
+	}
+	if c.Rules.Burn < 0 || c.Rules.Burn > maxBurnCards {
+		errs = append(errs, fmt.Errorf("  rules.burn: must be between 0 and %d", maxBurnCards))
+	}
+	if c.Rules.Reshuffle != "" {
+		if _, err := ParseReshufflePolicy(c.Rules.Reshuffle); err != nil {
+			errs = append(errs, fmt.Errorf("  rules.reshuffle: %w", err))
+		}
+	}
+	if _, err := c.Rules.Cards.cards(); err != nil {
+		errs = append(errs, fmt.Errorf("  rules.cards: %w", err))
+	}
+	if c.Rules.Scoring != nil {
+		if err := c.Rules.Scoring.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("  rules.scoring: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// chatFallback is the player with the strategy a chat player falls back
+// on, expert unless one is given
+func (p PlayerConfig) chatFallback() PlayerConfig {
+	if p.Strategy == "" {
+		p.Strategy = "expert"
+	}
+	return p
+}
+
+// Spec returns the strategy spec for a computer player, filling in the
+// same parameter defaults as interactive setup
+func (p PlayerConfig) Spec() (StrategySpec, error) {
+	spec, err := p.strategySpec()
+	if err != nil {
+		return spec, err
+	}
+	for _, t := range []struct{ field, name string }{{"target", p.Target}, {"gift", p.Gift}, {"flip_three", p.FlipThree}} {
+		if _, ok := targetStrategies[t.name]; t.name != "" && !ok {
+			return spec, fmt.Errorf("%s must be one of %s, not %q", t.field, strings.Join(targetNames(), ", "), t.name)
+		}
+	}
+	spec.Target, spec.Gift, spec.FlipThree = p.Target, p.Gift, p.FlipThree
+	if p.Noise < 0 || p.Noise > 1 {
+		return spec, fmt.Errorf("noise must be between 0 and 1")
+	}
+	if p.Noise > 0 {
+		spec.Noise = p.Noise
+	}
+	return spec, nil
+}
+
+// strategySpec returns the spec for the named strategy and its parameters
+func (p PlayerConfig) strategySpec() (StrategySpec, error) {
+	for _, d := range difficulties {
+		if p.Strategy == d.Name {
+			return d.Spec, nil
+		}
+	}
+
+	choice, ok := strategyNames[p.Strategy]
+	if !ok {
+		names := make([]string, 0, len(strategyNames)+len(difficulties))
+		for name := range strategyNames {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, d := range difficulties {
+			names = append(names, d.Name)
+		}
+		return StrategySpec{}, fmt.Errorf("unknown strategy %q (available: %s)", p.Strategy, strings.Join(names, ", "))
+	}
+
+	spec := StrategySpec{Choice: choice}
+	switch choice {
+	case 1, 11:
+		spec.TargetScore = orDefault(p.TargetScore, 30)
+		if spec.TargetScore < 1 || spec.TargetScore > 100 {
+			return spec, fmt.Errorf("target_score must be between 1 and 100")
+		}
+	case 2:
+		spec.BustProbability = p.BustProbability
+		if spec.BustProbability == 0 {
+			spec.BustProbability = 0.33
+		}
+		if spec.BustProbability < 0.1 || spec.BustProbability > 0.5 {
+			return spec, fmt.Errorf("bust_probability must be between 0.1 and 0.5")
+		}
+	}
+	if choice == 11 {
+		spec.GapTolerance = orDefault(p.GapTolerance, 20)
+		spec.SlackFactor = orDefault(p.SlackFactor, 5)
+	}
+	if choice == 12 {
+		spec.Table = p.Table
+		if _, err := solvedTable(spec.Table); err != nil {
+			return spec, fmt.Errorf("table: %w", err)
+		}
+	}
+	if choice == 14 {
+		spec.RiskAversion = p.RiskAversion
+		if spec.RiskAversion == 0 {
+			spec.RiskAversion = 2
+		}
+		if spec.RiskAversion < 0 || spec.RiskAversion > 10 {
+			return spec, fmt.Errorf("risk_aversion must be between 0 and 10")
+		}
+	}
+	if choice == 15 {
+		spec.Corpus = p.Corpus
+		if _, err := imitationCorpus(spec.Corpus); err != nil {
+			return spec, fmt.Errorf("corpus: %w", err)
+		}
+	}
+	return spec, nil
+}
+
+// Name returns the strategy's config name, with its parameter after a
+// colon and any other settings after commas, as parseStrategy reads it,
+// e.g. "play-to:25" or "bust-probability:0.28,target=leader"
+func (s StrategySpec) Name() string {
+	name := s.strategyName()
+	if s.Target != "" {
+		name += ",target=" + s.Target
+	}
+	if s.Gift != "" {
+		name += ",gift=" + s.Gift
+	}
+	if s.FlipThree != "" {
+		name += ",flip_three=" + s.FlipThree
+	}
+	if s.Noise > 0 && s.Difficulty == "" {
+		name += ",noise=" + strconv.FormatFloat(s.Noise, 'f', -1, 64)
+	}
+	return name
+}
+
+// strategyName returns the name of the spec's strategy and its parameter
+func (s StrategySpec) strategyName() string {
+	if s.Difficulty != "" {
+		return s.Difficulty
+	}
+	for name, choice := range strategyNames {
+		if choice != s.Choice {
+			continue
+		}
+		switch choice {
+		case 1, 11:
+			return fmt.Sprintf("%s:%d", name, s.TargetScore)
+		case 2:
+			return fmt.Sprintf("%s:%.2f", name, s.BustProbability)
+		case 12:
+			if s.Table != "" {
+				return name + ":" + s.Table
+			}
+		case 14:
+			return name + ":" + strconv.FormatFloat(s.RiskAversion, 'f', -1, 64)
+		case 15:
+			return name + ":" + s.Corpus
+		}
+		return name
+	}
+	return "custom"
+}
+
+// strategyAliases are the short names parseStrategy also accepts
+var strategyAliases = map[string]string{
+	"bustprob": "bust-probability",
+	"bust":     "bust-probability",
+	"playto":   "play-to",
+	"hit":      "always-hit",
+	"ev":       "expected-value",
+	"opt":      "optimal",
+	"gap":      "gap-based",
+	"gapaware": "gap-aware",
+	"risk":     "risk-utility",
+}
+
+// parseStrategy reads a strategy as a string on the command line: a
+// config file strategy name or alias, an optional parameter after a
+// colon (the target score for play-to and gap-aware, the bust
+// probability for bust-probability, the risk aversion for risk-utility,
+// the file for table, the corpus of recorded games for imitate), then any
+// player settings as key=value after commas, e.g. "play-to:25" or
+// "bustprob:0.28,target=leader,gift=last"
+func parseStrategy(name, s string) (PlayerConfig, error) {
+	p := PlayerConfig{Name: name}
+	fields := strings.Split(s, ",")
+	strategy, param, hasParam := strings.Cut(fields[0], ":")
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if alias, ok := strategyAliases[strategy]; ok {
+		strategy = alias
+	}
+	p.Strategy = strategy
+	if hasParam {
+		switch strategy {
+		case "play-to", "gap-aware":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				return p, fmt.Errorf("%s: target score %q is not a number", s, param)
+			}
+			p.TargetScore = n
+		case "bust-probability":
+			f, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return p, fmt.Errorf("%s: bust probability %q is not a number", s, param)
+			}
+			p.BustProbability = f
+		case "risk-utility":
+			f, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return p, fmt.Errorf("%s: risk aversion %q is not a number", s, param)
+			}
+			p.RiskAversion = f
+		case "table":
+			p.Table = param
+		case "imitate":
+			p.Corpus = param
+		default:
+			return p, fmt.Errorf("%s: %s doesn't take a parameter", s, strategy)
+		}
+	}
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return p, fmt.Errorf("%s: %q should be key=value", s, field)
+		}
+		if err := p.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return p, fmt.Errorf("%s: %w", s, err)
+		}
+	}
+	if _, err := p.Spec(); err != nil {
+		return p, fmt.Errorf("%s: %w", s, err)
+	}
+	return p, nil
+}
+
+// set sets one of the player's settings by its config file key
+func (p *PlayerConfig) set(key, value string) error {
+	number := func() (int, error) {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s %q is not a number", key, value)
+		}
+		return n, nil
+	}
+	var err error
+	switch key {
+	case "name":
+		p.Name = value
+	case "icon":
+		p.Icon = value
+	case "team":
+		p.Team = value
+	case "target":
+		p.Target = value
+	case "gift":
+		p.Gift = value
+	case "flip_three":
+		p.FlipThree = value
+	case "table":
+		p.Table = value
+	case "corpus":
+		p.Corpus = value
+	case "target_score":
+		p.TargetScore, err = number()
+	case "gap_tolerance":
+		p.GapTolerance, err = number()
+	case "slack_factor":
+		p.SlackFactor, err = number()
+	case "bust_probability", "noise", "risk_aversion":
+		f, perr := strconv.ParseFloat(value, 64)
+		if perr != nil {
+			return fmt.Errorf("%s %q is not a number", key, value)
+		}
+		switch key {
+		case "noise":
+			p.Noise = f
+		case "risk_aversion":
+			p.RiskAversion = f
+		default:
+			p.BustProbability = f
+		}
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return err
+}
+
+// newStrategyPlayer creates a computer player from a strategy as
+// parseStrategy reads it
+func newStrategyPlayer(name, s string) (PlayerInterface, error) {
+	p, err := parseStrategy(name, s)
+	if err != nil {
+		return nil, err
+	}
+	spec, _ := p.Spec()
+	suffix, _, _, _ := buildStrategy(spec)
+	return NewComputerPlayerFromSpec(p.Name+suffix, spec), nil
+}
+
+// strategyFlags collects the strategies given by repeating a flag
+type strategyFlags []string
+
+func (f *strategyFlags) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *strategyFlags) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+// UseStrategies replaces the config's players with computer players
+// playing the given strategies, as parseStrategy reads them, named AI 1,
+// AI 2 and so on unless a strategy names its player
+func (c *Config) UseStrategies(strategies []string) error {
+	c.Players = c.Players[:0]
+	for i, s := range strategies {
+		p, err := parseStrategy(fmt.Sprintf("AI %d", i+1), s)
+		if err != nil {
+			return err
+		}
+		c.Players = append(c.Players, p)
+	}
+	return c.Validate()
+}
+
+// orDefault returns value, or def if value is unset
+func orDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+// ApplyFlags uses the config's output options as defaults for any flag
+// that wasn't given on the command line
+func (c *Config) ApplyFlags(flags *flag.FlagSet) error {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	values := map[string]string{
+		"theme":     c.Output.Theme,
+		"render":    c.Output.Render,
+		"symbols":   c.Output.Symbols,
+		"verbosity": c.Output.Verbosity,
+		"lang":      c.Output.Lang,
+		"speed":     c.Output.Speed,
+	}
+	for name, b := range map[string]*bool{"banter": c.Output.Banter, "count": c.Output.Count, "standings": c.Output.Standings, "eval": c.Output.Eval, "tui": c.Output.TUI} {
+		if b != nil {
+			values[name] = strconv.FormatBool(*b)
+		}
+	}
+
+	for name, value := range values {
+		if value == "" || set[name] {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("config output.%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Apply sets up the game's players and rules from the config. The game's
+// input, output and speed must already be set.
+func (c *Config) Apply(g *Game) error {
+	if c.Rules.WinningScore > 0 {
+		g.SetWinningScore(c.Rules.WinningScore)
+	}
+	if c.Rules.HoldActions {
+		g.SetHoldActions(true)
+	}
+	if err := c.Rules.applyDeckRules(g); err != nil {
+		return err
+	}
+	if c.Rules.Scoring != nil {
+		g.SetScorer(*c.Rules.Scoring)
+	}
+	if c.Seed != 0 && !g.seeded {
+		SeedRandom(c.Seed)
+		g.SetSeed(c.Seed)
+	}
+	if c.Duplicate {
+		g.SetDuplicate(true)
+	}
+	if c.Seats {
+		g.SetSeatStats(true)
+	}
+	if c.Actions {
+		g.SetActionStats(true)
+	}
+	if c.Histograms {
+		g.SetHistograms(true)
+	}
+	if c.Lengths {
+		g.SetLengthStats(true)
+	}
+	if c.Decisions != "" && g.decisionsFile == "" {
+		g.SetDecisions(c.Decisions)
+	}
+	if c.Scores != "" && g.scoresFile == "" {
+		g.SetScoresFile(c.Scores)
+	}
+	if c.Rotate {
+		g.SetRotateSeats(true)
+	}
+	if c.Fresh {
+		g.SetFreshLearning(true)
+	}
+	if c.Fast {
+		g.SetFastStrategies(true)
+	}
+	if c.Dealer != "" && g.dealerMode == DealerFixed {
+		mode, err := ParseDealerMode(c.Dealer)
+		if err != nil {
+			return err
+		}
+		g.SetDealerMode(mode)
+	}
+	if c.Duration != "" && g.budget == 0 {
+		d, err := time.ParseDuration(c.Duration)
+		if err != nil {
+			return err
+		}
+		g.SetTimeBudget(d)
+	}
+	if c.Checkpoint != "" && g.checkpoint == "" {
+		g.SetCheckpoint(c.Checkpoint)
+	}
+	if c.Results != "" && g.results == "" {
+		g.SetResults(c.Results)
+	}
+	if c.Report != "" && g.report == "" {
+		g.SetReport(c.Report)
+	}
+	if c.Params != "" && g.params == "" {
+		g.SetParams(c.Params)
+	}
+	if c.StopWhen != "" && g.stopRule == nil {
+		rule, err := ParseStopRule(c.StopWhen)
+		if err != nil {
+			return err
+		}
+		g.SetStopRule(rule)
+	}
+	if len(c.Players) == 0 {
+		if c.Rules.Win != "" {
+			return g.SetWinCondition(c.Rules.winSpec(nil, nil))
+		}
+		return nil
+	}
+
+	humans := 0
+	g.players = g.players[:0]
+	for _, p := range c.Players {
+		if p.Human {
+			humans++
+			human := NewHumanPlayer(p.Name, g.scanner, g.out)
+			human.SetCommandHandler(g.handleCommand)
+			g.players = append(g.players, human)
+			continue
+		}
+		if p.Chat {
+			humans++
+			chat, err := NewChatVotes(c.Chat, g.out)
+			if err != nil {
+				return err
+			}
+			spec, err := p.chatFallback().Spec()
+			if err != nil {
+				return err
+			}
+			g.players = append(g.players, NewChatPlayer(p.Name, spec, chat))
+			continue
+		}
+
+		spec, err := p.Spec()
+		if err != nil {
+			return err
+		}
+		suffix, _, _, _ := buildStrategy(spec)
+		computer := NewComputerPlayerFromSpec(p.Name+suffix, spec)
+		computer.Icon = p.Icon
+		g.players = append(g.players, computer)
+	}
+	if c.Rules.Win != "" {
+		names := make([]string, len(g.players))
+		for i, p := range g.players {
+			names[i] = p.GetName()
+		}
+		if err := g.SetWinCondition(c.Rules.winSpec(c.Players, names)); err != nil {
+			return err
+		}
+	}
+
+	// A single AI-only game is being watched, so pace it
+	if humans == 0 && c.Games <= 1 && g.budget == 0 && g.stopRule == nil {
+		g.Subscribe(NewPacer(g.speed).OnEvent)
+	}
+	return nil
+}
+
+// RunGames plays the configured number of games until ctx ends. A time
+// budget or a stopping rule makes it a simulation even without a number
+// of games.
+func (c *Config) RunGames(ctx context.Context, g *Game) error {
+	switch {
+	case c.Games > 1:
+		g.SetSilentMode(true)
+		return g.runMultipleGames(ctx, c.Games)
+	case g.budget > 0 || g.stopRule != nil:
+		g.SetSilentMode(true)
+		return g.runMultipleGames(ctx, 0)
+	}
+	return g.Run(ctx)
+}
+
+// envPrefix starts the environment variables that set flag defaults
+const envPrefix = "FLIP7_"
+
+// ApplyEnv sets each flag that wasn't given on the command line from its
+// environment variable, e.g. FLIP7_SYMBOLS for -symbols
+func ApplyEnv(flags *flag.FlagSet) error {
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		env := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(env)
+		if !ok || set[f.Name] || err != nil {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("%s: %w", env, setErr)
+		}
+	})
+	return err
+}
+
+// DefaultConfigPath returns the user's config file,
+// $XDG_CONFIG_HOME/flip7/config.yaml, if it exists
+func DefaultConfigPath() (string, bool) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	path := filepath.Join(dir, "flip7", "config.yaml")
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CardCount is what any player can work out about the unseen cards from
+// the cards on the table and in the discard pile
+type CardCount struct {
+	Unseen      map[int]int
+	UnseenTotal int
+}
+
+// SetCardCounting shows the card-counting panel before every human decision
+func (g *Game) SetCardCounting(enabled bool) {
+	g.cardCounting = enabled
+}
+
+// countCards counts the cards not visible in any hand or the discard pile
+func (g *Game) countCards() CardCount {
+	count := CardCount{Unseen: make(map[int]int), UnseenTotal: g.deck.Size()}
+	for value := 0; value <= 12; value++ {
+		count.Unseen[value] = numberCardCopies(value)
+	}
+
+	seen := func(cards []*Card) {
+		for _, card := range cards {
+			count.UnseenTotal--
+			if card.Type == NumberCard {
+				count.Unseen[card.Value]--
+			}
+		}
+	}
+	for _, player := range g.players {
+		seen(player.GetHand())
+	}
+	seen(g.deck.Discards())
+
+	return count
+}
+
+// BustProbability returns the chance the next unseen card busts the player
+func (c CardCount) BustProbability(player PlayerInterface) float64 {
+	if c.UnseenTotal <= 0 {
+		return 0
+	}
+	bustCards := 0
+	for _, card := range player.GetHand() {
+		if card.Type == NumberCard {
+			bustCards += c.Unseen[card.Value]
+		}
+	}
+	return float64(bustCards) / float64(c.UnseenTotal)
+}
+
+// showCardCount prints how many of each number remain unseen and the
+// player's chance of busting on the next card
+func (g *Game) showCardCount(player PlayerInterface) {
+	count := g.countCards()
+
+	var sb strings.Builder
+	sb.WriteString(T("🔢 Unseen cards:"))
+	sb.WriteString("\n ")
+	for value := 0; value <= 12; value++ {
+		fmt.Fprintf(&sb, " %s:%d", g.renderer.Card(NewNumberCard(value)), count.Unseen[value])
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, T("  %d unseen cards, your bust chance: %.1f%%\n"), count.UnseenTotal, count.BustProbability(player)*100)
+	g.print(sb.String())
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dailyLineup is the fixed set of computer players every daily challenge
+// is played against. None of them make random choices, so the same deck
+// plays out the same way for everyone who makes the same decisions.
+var dailyLineup = []struct {
+	Name string
+	Spec StrategySpec
+}{
+	{"Cortana (opt)", StrategySpec{Choice: 9}},
+	{"Jeeves (hybrid)", StrategySpec{Choice: 7}},
+	{"HAL (exp)", StrategySpec{Choice: 6}},
+}
+
+// DailyResult is a finished daily challenge as recorded locally
+type DailyResult struct {
+	Date     string
+	Name     string
+	Score    int
+	Rounds   int
+	Won      bool
+	PlayedAt time.Time
+}
+
+// DailySeed derives the deck seed for a date, so everyone playing the
+// daily challenge on the same UTC day gets the same deck
+func DailySeed(date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("flip7-daily-" + date))
+	return int64(h.Sum64())
+}
+
+// dailyResultsPath is where daily challenge results are kept
+func dailyResultsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flip7", "daily.json"), nil
+}
+
+// loadDailyResults reads the recorded daily results, if any
+func loadDailyResults(path string) ([]DailyResult, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []DailyResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("reading daily results %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// saveDailyResults writes the recorded daily results
+func saveDailyResults(path string, results []DailyResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// RunDaily runs the daily subcommand: one human against the fixed lineup
+// on a deck seeded from the date, with the final score recorded locally.
+// Undo and manual card selection are turned off so results are comparable.
+func RunDaily(ctx context.Context, g *Game, args []string) error {
+	fs := flag.NewFlagSet("daily", flag.ContinueOnError)
+	name := fs.String("name", "You", "Your name")
+	date := fs.String("date", time.Now().UTC().Format(time.DateOnly), "Challenge date (YYYY-MM-DD)")
+	resultsFile := fs.String("results", "", "Daily results file (defaults to the user config directory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := time.Parse(time.DateOnly, *date); err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", *date)
+	}
+
+	path := *resultsFile
+	if path == "" {
+		var err error
+		if path, err = dailyResultsPath(); err != nil {
+			return err
+		}
+	}
+	results, err := loadDailyResults(path)
+	if err != nil {
+		return err
+	}
+
+	g.SetDebugMode(false)
+	g.SetUndoEnabled(false)
+	g.SetSeed(DailySeed(*date))
+
+	human := NewHumanPlayer(*name, g.scanner, g.out)
+	human.SetCommandHandler(g.handleCommand)
+	g.players = []PlayerInterface{human}
+	for _, ai := range dailyLineup {
+		g.players = append(g.players, NewComputerPlayerFromSpec(ai.Name, ai.Spec))
+	}
+
+	g.resultf(T("📅 Daily challenge for %s\n"), *date)
+	if err := g.Run(ctx); err != nil {
+		return err
+	}
+
+	result := DailyResult{
+		Date:     *date,
+		Name:     *name,
+		Score:    human.GetTotalScore(),
+		Rounds:   g.round - 1,
+		Won:      g.getWinner() == human,
+		PlayedAt: time.Now(),
+	}
+	results = append(results, result)
+	if err := saveDailyResults(path, results); err != nil {
+		return err
+	}
+
+	g.resultf(T("📅 Flip 7 daily %s: %s scored %d in %d rounds\n"), result.Date, result.Name, result.Score, result.Rounds)
+	for _, r := range results {
+		if r.Date == result.Date && r.PlayedAt != result.PlayedAt {
+			g.resultf(T("   Also played: %s scored %d in %d rounds\n"), r.Name, r.Score, r.Rounds)
+		}
+	}
+	return nil
+}
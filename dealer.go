@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// DealerMode chooses who deals the first round of each simulated game
+type DealerMode int
+
+const (
+	// DealerFixed has the first seat deal every game
+	DealerFixed DealerMode = iota
+	// DealerRotate passes the first deal one seat along after each game
+	DealerRotate
+	// DealerRandom picks the first dealer at random for each game
+	DealerRandom
+)
+
+// dealerModes are the names of the dealer modes, in order
+var dealerModes = []string{"fixed", "rotate", "random"}
+
+// ParseDealerMode parses a dealer mode name
+func ParseDealerMode(name string) (DealerMode, error) {
+	for i, mode := range dealerModes {
+		if mode == strings.ToLower(name) {
+			return DealerMode(i), nil
+		}
+	}
+	return DealerFixed, fmt.Errorf("unknown dealer mode %q (available: %s)", name, strings.Join(dealerModes, ", "))
+}
+
+// String returns the mode's name
+func (m DealerMode) String() string {
+	if m < 0 || int(m) >= len(dealerModes) {
+		return "unknown"
+	}
+	return dealerModes[m]
+}
+
+// SetDealerMode chooses who deals first in each simulated game
+func (g *Game) SetDealerMode(mode DealerMode) {
+	g.dealerMode = mode
+}
+
+// firstDealer returns the seat that deals the first round of the given
+// game or board. A random dealer comes from the game's seed rather than
+// the game's random source, so it doesn't change the rest of the game.
+func firstDealer(mode DealerMode, game int, seed int64, seats int) int {
+	switch mode {
+	case DealerRotate:
+		return game % seats
+	case DealerRandom:
+		return rand.New(rand.NewSource(seed)).Intn(seats)
+	}
+	return 0
+}
+
+// DealerStats tallies simulation results by who dealt the first round.
+// Games and Wins are keyed by the first dealer's name; Wins holds each
+// player's wins in those games. FirstDealer and FirstToAct count the games
+// won by the first dealer and by the player on their left.
+type DealerStats struct {
+	Games       map[string]int
+	Wins        map[string]map[string]int
+	FirstDealer int
+	FirstToAct  int
+}
+
+// NewDealerStats creates an empty tally
+func NewDealerStats() *DealerStats {
+	return &DealerStats{
+		Games: make(map[string]int),
+		Wins:  make(map[string]map[string]int),
+	}
+}
+
+// recordGame counts a finished game that the given seat dealt first
+func (d *DealerStats) recordGame(g *Game, dealer int, winner PlayerInterface) {
+	name := g.players[dealer].GetName()
+	d.Games[name]++
+	if d.Wins[name] == nil {
+		d.Wins[name] = make(map[string]int)
+	}
+	d.Wins[name][winner.GetName()]++
+
+	switch winner {
+	case g.players[dealer]:
+		d.FirstDealer++
+	case g.players[(dealer+1)%len(g.players)]:
+		d.FirstToAct++
+	}
+}
+
+// displayDealerStatistics shows every player's win rate by who dealt the
+// first round, and how often the first dealer and first to act won
+func (g *Game) displayDealerStatistics(d *DealerStats, mode DealerMode, names []string) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🎴 RESULTS BY FIRST DEALER (%s)\n"), mode)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-14s %7s", T("FIRST DEALER"), T("GAMES"))
+	for _, name := range names {
+		g.resultf(" %9.9s", name)
+	}
+	g.resultf("\n%s\n", strings.Repeat("-", 60))
+
+	total := 0
+	for _, dealer := range names {
+		games := d.Games[dealer]
+		total += games
+		if games == 0 {
+			continue
+		}
+		g.resultf("%-14.14s %7d", dealer, games)
+		for _, name := range names {
+			g.resultf(" %8.1f%%", 100*float64(d.Wins[dealer][name])/float64(games))
+		}
+		g.resultf("\n")
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+
+	if total > 0 {
+		g.resultf(T("The first dealer won %.1f%% of games and the first to act %.1f%% (an even share is %.1f%%)\n"),
+			100*float64(d.FirstDealer)/float64(total), 100*float64(d.FirstToAct)/float64(total), 100/float64(len(names)))
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// LoadDebugDeck reads a debug deck file: the cards to deal, one per line,
+// in the order they're drawn. Cards are written as the debug card picker
+// takes them, such as "7", "f3", "+10" or "x2". Blank lines and lines
+// starting with # are skipped.
+func LoadDebugDeck(path string) ([]*Card, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cards []*Card
+	var errs []error
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		card, err := parseDebugCard(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %w", path, line, err))
+			continue
+		}
+		cards = append(cards, card)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := takeCards(standardCards, cards); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", path, err))
+	}
+	return cards, errors.Join(errs...)
+}
+
+// SetDebugDeck stacks cards on top of a fresh deck, to be drawn in order,
+// with the rest of the card set shuffled under them. In debug mode the
+// card picker takes over once they've all been drawn. A reshuffle mixes
+// any that are left back in.
+func (g *Game) SetDebugDeck(cards []*Card) error {
+	if err := g.stackDeck(cards, nil, nil); err != nil {
+		return err
+	}
+	if d, ok := g.deck.(scriptedDeck); ok {
+		d.script(len(cards))
+	}
+	return nil
+}
+
+// stackDeck puts top on the deck in order, next first, with the rest of
+// the card set shuffled under it. The cards in out are in hands or the
+// discards and are left out of the deck.
+func (g *Game) stackDeck(top, discards, out []*Card) error {
+	rest, err := takeCards(g.cards(), append(slices.Clone(out), top...))
+	if err != nil {
+		return err
+	}
+	g.deck.Restore(rest, discards)
+	g.deck.Shuffle()
+	top = slices.Clone(top)
+	slices.Reverse(top)
+	g.deck.Restore(append(g.deck.Cards(), top...), discards)
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/csv"
+	"strconv"
+	"strings"
+)
+
+// decisionColumns is the header of a decisions file
+var decisionColumns = func() []string {
+	columns := []string{"game", "seed", "round", "player", "strategy",
+		"numbers", "modifier_points", "x2", "second_chance", "round_score",
+		"total_score", "leader_score", "gap", "active_players", "cards_left"}
+	for value := range 13 {
+		columns = append(columns, "left_"+strconv.Itoa(value))
+	}
+	return append(columns, "left_modifiers", "left_actions", "bust_probability",
+		"decision", "round_result", "busted", "flip7", "won", "final_score",
+		"frozen")
+}()
+
+// decision is one computer hit/stay decision, waiting for the round and
+// game to finish
+type decision struct {
+	player   PlayerInterface
+	features []string
+	hit      bool
+	result   int
+	busted   bool
+	flip7    bool
+	frozen   bool
+}
+
+// DecisionLog writes every computer player's hit/stay decision in a
+// simulation to a CSV file, one row per decision with what the player
+// could see and how the round and the game turned out. Rows are kept
+// until their game ends; the current round's start at roundStart.
+type DecisionLog struct {
+	rw         *resultsWriter
+	w          *csv.Writer
+	pending    []*decision
+	roundStart int
+}
+
+// SetDecisions makes simulations write every computer decision to a CSV
+// file at path, for training strategies offline
+func (g *Game) SetDecisions(path string) {
+	g.decisionsFile = path
+}
+
+// openDecisions opens a decisions file, cut back to size bytes as
+// results files are. A new file starts with the header.
+func openDecisions(path string, size int64) (*DecisionLog, error) {
+	rw, err := openResults(path, size)
+	if err != nil {
+		return nil, err
+	}
+	d := &DecisionLog{rw: rw, w: csv.NewWriter(rw.w)}
+	if size == 0 {
+		d.w.Write(decisionColumns)
+	}
+	return d, nil
+}
+
+// trackDecisions logs the game's computer decisions and their outcomes
+func (g *Game) trackDecisions(d *DecisionLog) {
+	g.decisions = d
+	g.Subscribe(func(event Event) {
+		switch event.Type {
+		case RoundStarted:
+			d.roundStart = len(d.pending)
+		case PlayerBusted:
+			d.update(event.Player, func(dec *decision) { dec.busted = true })
+		case Flip7Achieved:
+			d.update(event.Player, func(dec *decision) { dec.flip7 = true })
+		case PlayerScored:
+			frozen := event.Player.GetState() == Frozen
+			d.update(event.Player, func(dec *decision) { dec.result, dec.frozen = event.Score, frozen })
+		}
+	})
+}
+
+// update applies f to the player's decisions in the current round
+func (d *DecisionLog) update(player PlayerInterface, f func(*decision)) {
+	for _, dec := range d.pending[d.roundStart:] {
+		if dec.player == player {
+			f(dec)
+		}
+	}
+}
+
+// record notes a decision with the features the player decided on
+func (d *DecisionLog) record(g *Game, player PlayerInterface, state *GameState, hit bool) {
+	computer, ok := player.(*ComputerPlayer)
+	if !ok {
+		return
+	}
+
+	var numbers []string
+	modifiers, x2 := 0, false
+	for _, card := range player.GetHand() {
+		switch {
+		case card.Type == NumberCard:
+			numbers = append(numbers, strconv.Itoa(card.Value))
+		case card.Type == ModifierCard && card.Modifier == Multiply2:
+			x2 = true
+		case card.Type == ModifierCard:
+			modifiers += card.GetPoints()
+		}
+	}
+	leader := state.CurrentLeader.GetTotalScore()
+	remaining := state.Unseen()
+	bust := 0.0
+	if remaining.Total > 0 {
+		bust = CalculateBustProbability(player, state)
+	}
+
+	features := []string{
+		strconv.Itoa(g.round),
+		player.GetName(),
+		computer.Spec.Name(),
+		strings.Join(numbers, " "),
+		strconv.Itoa(modifiers),
+		strconv.FormatBool(x2),
+		strconv.FormatBool(player.HasSecondChance()),
+		strconv.Itoa(player.CalculateRoundScore()),
+		strconv.Itoa(player.GetTotalScore()),
+		strconv.Itoa(leader),
+		strconv.Itoa(leader - player.GetTotalScore()),
+		strconv.Itoa(len(state.ActivePlayers)),
+		strconv.Itoa(remaining.Total),
+	}
+	for _, n := range remaining.Numbers {
+		features = append(features, strconv.Itoa(n))
+	}
+	features = append(features,
+		strconv.Itoa(remaining.Modifiers),
+		strconv.Itoa(remaining.Actions),
+		strconv.FormatFloat(bust, 'f', 4, 64))
+	d.pending = append(d.pending, &decision{player: player, features: features, hit: hit})
+}
+
+// endGame writes the game's decisions now that its outcome is known
+func (d *DecisionLog) endGame(game int, seed int64, winner PlayerInterface) error {
+	for _, dec := range d.pending {
+		choice := "stay"
+		if dec.hit {
+			choice = "hit"
+		}
+		row := append([]string{strconv.Itoa(game), strconv.FormatInt(seed, 10)}, dec.features...)
+		row = append(row, choice, strconv.Itoa(dec.result), strconv.FormatBool(dec.busted),
+			strconv.FormatBool(dec.flip7), strconv.FormatBool(dec.player == winner),
+			strconv.Itoa(dec.player.GetTotalScore()), strconv.FormatBool(dec.frozen))
+		if err := d.w.Write(row); err != nil {
+			return err
+		}
+	}
+	d.pending = d.pending[:0]
+	d.roundStart = 0
+	return nil
+}
+
+// flush writes out buffered rows and returns the file's size
+func (d *DecisionLog) flush() (int64, error) {
+	d.w.Flush()
+	if err := d.w.Error(); err != nil {
+		return 0, err
+	}
+	return d.rw.flush()
+}
+
+// close flushes and closes the file
+func (d *DecisionLog) close() error {
+	d.w.Flush()
+	if err := d.w.Error(); err != nil {
+		d.rw.close()
+		return err
+	}
+	return d.rw.close()
+}
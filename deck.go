@@ -2,21 +2,112 @@ package main
 
 import (
 	"bufio"
+	"cmp"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"math/rand"
-	"strconv"
+	"os"
+	"slices"
 	"strings"
 	"time"
 )
 
+// DeckInterface is the deck as the game uses it. Game.SetDeck swaps in a
+// different implementation, such as a StackedDeck for tests.
+type DeckInterface interface {
+	DrawCard() *Card
+	DiscardCard(card *Card)
+	CardsLeft() int
+	// Cards returns the undrawn cards, the next card to be drawn last
+	Cards() []*Card
+	// PeekTop returns the next n cards to be drawn, next first, without
+	// drawing them; none for n below 1
+	PeekTop(n int) []*Card
+	// BurnCard draws the next card straight onto the discard pile
+	BurnCard() *Card
+	// RemainingByValue counts the undrawn number cards by value
+	RemainingByValue() [13]int
+	Discards() []*Card
+	// Restore replaces the undrawn cards and the discard pile
+	Restore(cards, discards []*Card)
+	// Counts tallies the undrawn cards, kept up to date on every change
+	Counts() *DeckCounts
+	// DiscardCounts tallies the discard pile the same way
+	DiscardCounts() *DeckCounts
+	// Size returns how many cards the deck was made with
+	Size() int
+	Shuffle()
+	// Reshuffle shuffles the discard pile back into the deck
+	Reshuffle()
+	// SetReshufflePolicy sets whether the deck reshuffles itself when
+	// it runs out mid-round
+	SetReshufflePolicy(policy ReshufflePolicy)
+	Reset()
+	// Clone copies the deck with its own random source, seeded from seed
+	Clone(seed int64) DeckInterface
+	Seed(seed int64)
+	SetDebugMode(debug bool, scanner *bufio.Scanner)
+	SetOutput(w io.Writer)
+	SetLogger(logger *slog.Logger)
+}
+
+// DeckCounts tallies the undrawn cards by kind, so strategies can ask
+// about the deck without scanning it
+type DeckCounts struct {
+	Numbers        [13]int // copies of each number card
+	Modifiers      int
+	ModifierPoints int // points on the +N modifiers; x2 counts as 0
+	Actions        int
+	Freezes        int // Freeze cards, counted in Actions too
+	Total          int
+}
+
+// add counts n more copies of a card; n is negative for cards drawn
+func (c *DeckCounts) add(card *Card, n int) {
+	switch card.Type {
+	case NumberCard:
+		c.Numbers[card.Value] += n
+	case ModifierCard:
+		c.Modifiers += n
+		c.ModifierPoints += n * card.GetPoints()
+	case ActionCard:
+		c.Actions += n
+		if card.Action == Freeze {
+			c.Freezes += n
+		}
+	}
+	c.Total += n
+}
+
+// countDeck tallies a set of cards
+func countDeck(cards []*Card) DeckCounts {
+	var c DeckCounts
+	for _, card := range cards {
+		c.add(card, 1)
+	}
+	return c
+}
+
 // Deck represents the game deck
 type Deck struct {
 	cards         []*Card
+	counts        DeckCounts
 	discards      []*Card
+	discardCounts DeckCounts
 	rng           *rand.Rand
 	debugMode     bool
 	scanner       *bufio.Scanner
+	out           io.Writer
+	logger        *slog.Logger
+	reshuffle     ReshufflePolicy
+	drawer        PlayerInterface
+	scripted      int
 	OriginalTotal int
+	// set is the card set the deck is made from, the full one if nil
+	set []*Card
 }
 
 // NewDeck creates a new deck with the correct card distribution for Flip 7
@@ -25,85 +116,208 @@ func NewDeck() *Deck {
 		cards:    make([]*Card, 0),
 		discards: make([]*Card, 0),
 		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		out:      os.Stdout,
+		logger:   slog.New(slog.DiscardHandler),
 	}
 
-	deck.createCards()
-	deck.Shuffle()
-	deck.OriginalTotal = len(deck.cards)
+	deck.Reset()
 
 	return deck
 }
 
-// createCards creates all cards with the correct distributions
-func (d *Deck) createCards() {
+// standardCards is the full Flip 7 card set. Cards are never modified, so
+// every deck deals these same cards instead of allocating its own.
+var standardCards = newStandardCards()
+
+// newStandardCards creates all cards with the correct distributions
+func newStandardCards() []*Card {
+	cards := make([]*Card, 0, 94)
+
 	// Number cards: each number has as many cards as its value
 	// 12 has 12 copies, 11 has 11 copies, etc., down to 0 which has 1 copy
 	for value := 0; value <= 12; value++ {
-		count := value
-		if value == 0 {
-			count = 1
-		}
-		for i := 0; i < count; i++ {
-			d.cards = append(d.cards, NewNumberCard(value))
+		for i := 0; i < numberCardCopies(value); i++ {
+			cards = append(cards, NewNumberCard(value))
 		}
 	}
 
 	// Score Modifier Cards (6 total)
-	d.cards = append(d.cards, NewModifierCard(Plus2))
-	d.cards = append(d.cards, NewModifierCard(Plus4))
-	d.cards = append(d.cards, NewModifierCard(Plus6))
-	d.cards = append(d.cards, NewModifierCard(Plus8))
-	d.cards = append(d.cards, NewModifierCard(Plus10))
-	d.cards = append(d.cards, NewModifierCard(Multiply2))
+	cards = append(cards, NewModifierCard(Plus2))
+	cards = append(cards, NewModifierCard(Plus4))
+	cards = append(cards, NewModifierCard(Plus6))
+	cards = append(cards, NewModifierCard(Plus8))
+	cards = append(cards, NewModifierCard(Plus10))
+	cards = append(cards, NewModifierCard(Multiply2))
 
 	// Action Cards (3 of each type = 9 total)
 	for i := 0; i < 3; i++ {
-		d.cards = append(d.cards, NewActionCard(Freeze))
-		d.cards = append(d.cards, NewActionCard(FlipThree))
-		d.cards = append(d.cards, NewActionCard(SecondChance))
+		cards = append(cards, NewActionCard(Freeze))
+		cards = append(cards, NewActionCard(FlipThree))
+		cards = append(cards, NewActionCard(SecondChance))
+	}
+
+	return cards
+}
+
+// CardSet changes the cards a game is dealt from, for house decks. It
+// gives the copies of each card it names, written as scenarios write
+// cards: cards it leaves out keep their usual copies, and 0 takes a card
+// out of the deck.
+type CardSet map[string]int
+
+// cards builds the deck the set gives: the full card set in its usual
+// order without the cards the set names, then those in name order
+func (s CardSet) cards() ([]*Card, error) {
+	copies := make(map[string]int, len(s))
+	for _, name := range slices.Sorted(maps.Keys(s)) {
+		card, err := parseCard(name)
+		if err != nil {
+			return nil, err
+		}
+		if s[name] < 0 {
+			return nil, fmt.Errorf("%s: copies must not be negative", name)
+		}
+		if _, ok := copies[formatCard(card)]; ok {
+			return nil, fmt.Errorf("%s is named twice", formatCard(card))
+		}
+		copies[formatCard(card)] = s[name]
+	}
+
+	var cards []*Card
+	for _, card := range standardCards {
+		if _, ok := copies[formatCard(card)]; !ok {
+			cards = append(cards, card)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(copies)) {
+		for range copies[name] {
+			card, _ := parseCard(name)
+			cards = append(cards, card)
+		}
+	}
+	if !slices.ContainsFunc(cards, func(c *Card) bool { return c.Type == NumberCard && c.Value > 0 }) {
+		return nil, fmt.Errorf("no number card above 0 is left to score with")
+	}
+	return cards, nil
+}
+
+// SetCardSet deals the game from the full card set with the copies set
+// changes. A StackedDeck keeps dealing its own cards.
+func (g *Game) SetCardSet(set CardSet) error {
+	cards, err := set.cards()
+	if err != nil {
+		return err
+	}
+	g.cardSet = set
+	if d, ok := g.deck.(*Deck); ok {
+		d.set = cards
+		d.Reset()
+	}
+	return nil
+}
+
+// cards returns the cards the game's deck is made from
+func (g *Game) cards() []*Card {
+	if d, ok := g.deck.(*Deck); ok && d.set != nil {
+		return d.set
+	}
+	return standardCards
+}
+
+// createCards adds the deck's card set to the deck
+func (d *Deck) createCards() {
+	if d.set != nil {
+		d.cards = append(d.cards, d.set...)
+		return
 	}
+	d.cards = append(d.cards, standardCards...)
 }
 
 // Shuffle shuffles the deck
 func (d *Deck) Shuffle() {
+	d.scripted = 0
 	d.rng.Shuffle(len(d.cards), func(i, j int) {
 		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
 	})
 }
 
-// DrawCard draws the top card from the deck
+// DrawCard draws the top card from the deck. The discards are shuffled
+// back in as soon as the last card is drawn, unless the reshuffle policy
+// keeps them out until the round is over. Drawing from an empty deck
+// returns nil.
 func (d *Deck) DrawCard() *Card {
 	if len(d.cards) == 0 {
-		panic("All cards disappeared!	")
+		return nil
 	}
-	if d.debugMode {
-		return d.drawCardDebug()
+	card := d.next()
+	d.reshuffleIfEmpty()
+	return card
+}
+
+// next takes the next card off the deck, chosen by hand in debug mode
+// once the debug deck's cards are dealt, without reshuffling
+func (d *Deck) next() *Card {
+	if d.debugMode && d.scripted == 0 {
+		card := d.drawCardDebug()
+		if card != nil {
+			d.counts.add(card, -1)
+		}
+		return card
 	}
 
+	d.scripted = max(d.scripted-1, 0)
 	card := d.cards[len(d.cards)-1]
 	d.cards = d.cards[:len(d.cards)-1]
+	d.counts.add(card, -1)
+	if d.tracing() {
+		d.trace("deck draw", "card", card.String(), "cards_left", len(d.cards))
+	}
+	return card
+}
 
-	if len(d.cards) == 0 {
+// reshuffleIfEmpty shuffles the discards back into a deck that has just
+// run out, if the policy allows it mid-round. Debug mode leaves the
+// deck as it is.
+func (d *Deck) reshuffleIfEmpty() {
+	if len(d.cards) == 0 && d.reshuffle == ReshuffleMidRound && !d.debugMode {
 		d.Reshuffle()
 	}
-
-	return card
 }
 
 // DiscardCard adds a card to the discard pile
 func (d *Deck) DiscardCard(card *Card) {
 	if card != nil {
 		d.discards = append(d.discards, card)
+		d.discardCounts.add(card, 1)
+		if d.tracing() {
+			d.trace("deck discard", "card", card.String(), "discards", len(d.discards))
+		}
+	}
+}
+
+// numberCardCopies returns how many copies of a number card are in the deck
+func numberCardCopies(value int) int {
+	if value == 0 {
+		return 1
 	}
+	return value
 }
 
 // Reshuffle reshuffles the discard pile back into the deck
 func (d *Deck) Reshuffle() {
+	d.logger.Debug("deck reshuffle", "discards", len(d.discards), "cards_left", len(d.cards))
 	d.cards = append(d.cards, d.discards...)
-	d.discards = make([]*Card, 0)
+	d.discards = d.discards[:0]
+	d.discardCounts = DeckCounts{}
+	d.counts = countDeck(d.cards)
 	d.Shuffle()
 }
 
+// SetReshufflePolicy sets when the deck may shuffle its discards back in
+func (d *Deck) SetReshufflePolicy(policy ReshufflePolicy) {
+	d.reshuffle = policy
+}
+
 // CardsLeft returns the number of cards remaining in the deck
 func (d *Deck) CardsLeft() int {
 	return len(d.cards)
@@ -114,89 +328,248 @@ func (d *Deck) TotalCards() int {
 	return len(d.cards) + len(d.discards)
 }
 
+// Cards returns the undrawn cards, the next card to be drawn last. The
+// slice belongs to the deck and must not be modified.
+func (d *Deck) Cards() []*Card {
+	return d.cards
+}
+
+// PeekTop returns the next n cards to be drawn, next first, or as many
+// as are left. In debug mode the next card is whichever is chosen, so
+// only the debug deck's cards still to come can be peeked at.
+func (d *Deck) PeekTop(n int) []*Card {
+	if d.debugMode {
+		n = min(n, d.scripted)
+	}
+	n = max(min(n, len(d.cards)), 0)
+	top := slices.Clone(d.cards[len(d.cards)-n:])
+	slices.Reverse(top)
+	return top
+}
+
+// BurnCard takes the next card, chosen by hand in debug mode, and
+// discards it face up. It reshuffles as drawing does, once the card is
+// on the discard pile, and returns nil if there's no card to burn.
+func (d *Deck) BurnCard() *Card {
+	if len(d.cards) == 0 {
+		return nil
+	}
+	card := d.next()
+	if card == nil {
+		return nil
+	}
+	d.DiscardCard(card)
+	d.reshuffleIfEmpty()
+	return card
+}
+
+// RemainingByValue counts the undrawn number cards by value, from the
+// running tally
+func (d *Deck) RemainingByValue() [13]int {
+	return d.counts.Numbers
+}
+
+// Discards returns the discard pile. The slice belongs to the deck and
+// must not be modified.
+func (d *Deck) Discards() []*Card {
+	return d.discards
+}
+
+// Restore replaces the undrawn cards and the discard pile
+func (d *Deck) Restore(cards, discards []*Card) {
+	d.cards = cards
+	d.discards = discards
+	d.counts = countDeck(cards)
+	d.discardCounts = countDeck(discards)
+}
+
+// Counts tallies the undrawn cards. The deck keeps it up to date as cards
+// are drawn and reshuffled, so reading it is O(1).
+func (d *Deck) Counts() *DeckCounts {
+	return &d.counts
+}
+
+// DiscardCounts tallies the discard pile, kept up to date like Counts
+func (d *Deck) DiscardCounts() *DeckCounts {
+	return &d.discardCounts
+}
+
+// Size returns how many cards the deck was made with
+func (d *Deck) Size() int {
+	return d.OriginalTotal
+}
+
+// Reset gathers every card back into a freshly shuffled deck. The deck's
+// slices are reused, so simulations don't allocate a deck per game.
+func (d *Deck) Reset() {
+	d.cards = d.cards[:0]
+	d.discards = d.discards[:0]
+	d.discardCounts = DeckCounts{}
+	d.createCards()
+	d.counts = countDeck(d.cards)
+	d.Shuffle()
+	d.OriginalTotal = len(d.cards)
+}
+
+// Seed replaces the deck with a fresh one shuffled from a fixed seed, so
+// the same seed always deals the same cards
+func (d *Deck) Seed(seed int64) {
+	if d.rng == nil {
+		d.rng = rand.New(rand.NewSource(seed))
+	} else {
+		d.rng.Seed(seed)
+	}
+	d.Reset()
+}
+
 // SetDebugMode enables or disables debug mode for manual card selection
 func (d *Deck) SetDebugMode(debug bool, scanner *bufio.Scanner) {
 	d.debugMode = debug
 	d.scanner = scanner
 }
 
-// drawCardDebug allows manual selection of cards in debug mode
-func (d *Deck) drawCardDebug() *Card {
-	if len(d.cards) == 0 {
-		return nil
+// SetLogger sets the logger used to trace deck operations
+func (d *Deck) SetLogger(logger *slog.Logger) {
+	d.logger = logger
+}
+
+// tracing reports whether deck operations are being traced
+func (d *Deck) tracing() bool {
+	return d.logger.Enabled(context.Background(), LevelTrace)
+}
+
+// trace logs a deck operation at trace level
+func (d *Deck) trace(msg string, args ...any) {
+	d.logger.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// SetOutput sets where debug card selection menus are written
+func (d *Deck) SetOutput(w io.Writer) {
+	d.out = w
+}
+
+// debugShortcuts are the short names the debug card picker takes for
+// cards, besides the names parseCard reads
+var debugShortcuts = map[string]string{
+	"f":  "freeze",
+	"fz": "freeze",
+	"f3": "flip3",
+	"sc": "chance",
+	"2c": "chance",
+	"×2": "x2",
+}
+
+// debugCardNames are the names the debug card picker shows for action
+// cards, by action
+var debugCardNames = []string{"f", "f3", "sc"}
+
+// parseDebugCard reads a card typed at the debug card picker: a number,
+// "+10", "x2", "f3" and the like
+func parseDebugCard(s string) (*Card, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if name, ok := debugShortcuts[s]; ok {
+		s = name
 	}
+	return parseCard(s)
+}
 
-	fmt.Println("\n🐛 DEBUG: Choose a card to draw:")
-	fmt.Printf("Available cards (%d total):\n", len(d.cards))
+// scriptedDeck is a deck that can deal a debug deck in order even in
+// debug mode
+type scriptedDeck interface {
+	script(n int)
+}
 
-	// Group cards by type for easier selection
-	numberCards := make([]*Card, 0)
-	actionCards := make([]*Card, 0)
-	modifierCards := make([]*Card, 0)
+// script marks the top n cards as a debug deck, dealt in order even in
+// debug mode. Shuffling the deck forgets them.
+func (d *Deck) script(n int) {
+	d.scripted = min(n, len(d.cards))
+}
 
-	for _, card := range d.cards {
-		switch card.Type {
-		case NumberCard:
-			numberCards = append(numberCards, card)
-		case ActionCard:
-			actionCards = append(actionCards, card)
-		case ModifierCard:
-			modifierCards = append(modifierCards, card)
-		}
+// drawerSetter is a deck whose debug card picker wants to know who each
+// card is for
+type drawerSetter interface {
+	setDrawer(player PlayerInterface)
+}
+
+// setDrawer tells the debug card picker who the next card is for, so it
+// can warn which cards would bust them
+func (d *Deck) setDrawer(player PlayerInterface) {
+	d.drawer = player
+}
+
+// debugWarning says what a number card would do to the player drawing
+// it, or nothing if it's safe
+func (d *Deck) debugWarning(value int) string {
+	if d.drawer == nil || d.drawer.HeldNumbers()&(1<<value) == 0 {
+		return ""
+	}
+	if d.drawer.HasSecondChance() {
+		return fmt.Sprintf(T("  🆘 uses %s's Second Chance"), d.drawer.GetName())
 	}
+	return fmt.Sprintf(T("  💥 busts %s"), d.drawer.GetName())
+}
 
-	// Display cards by category
-	cardOptions := make([]*Card, 0)
-	optionIndex := 1
+// drawCardDebug lets the card to draw be picked by hand in debug mode.
+// Cards are typed as scenarios write them, with shortcuts such as "f3",
+// and "r" takes the top card as if debug mode were off. Numbers the
+// drawing player already holds are marked with what they would do.
+func (d *Deck) drawCardDebug() *Card {
+	if len(d.cards) == 0 {
+		return nil
+	}
 
-	if len(numberCards) > 0 {
-		fmt.Println("\nNumber Cards:")
-		cardCounts := make(map[int]int)
-		for _, card := range numberCards {
-			cardCounts[card.Value]++
-		}
-		for value := 0; value <= 12; value++ {
-			if count := cardCounts[value]; count > 0 {
-				fmt.Printf("  %d) [%d] (%d available)\n", optionIndex, value, count)
-				cardOptions = append(cardOptions, NewNumberCard(value))
-				optionIndex++
+	if d.drawer != nil {
+		fmt.Fprintf(d.out, T("\n🐛 DEBUG: Choose a card for %s to draw:\n"), d.drawer.GetName())
+	} else {
+		fmt.Fprintln(d.out, T("\n🐛 DEBUG: Choose a card to draw:"))
+	}
+	fmt.Fprintf(d.out, T("Available cards (%d total):\n"), len(d.cards))
+
+	counts := countDeck(d.cards)
+	if counts.Total-counts.Modifiers-counts.Actions > 0 {
+		fmt.Fprintln(d.out, T("\nNumber Cards:"))
+		for value, n := range counts.Numbers {
+			if n > 0 {
+				fmt.Fprintf(d.out, T("  %-4d [%d] (%d available)%s\n"), value, value, n, d.debugWarning(value))
 			}
 		}
 	}
 
-	if len(actionCards) > 0 {
-		fmt.Println("\nAction Cards:")
-		actionCounts := make(map[ActionType]int)
-		for _, card := range actionCards {
-			actionCounts[card.Action]++
+	byKind := func(match func(*Card) bool) []*Card {
+		var kinds []*Card
+		for _, card := range d.cards {
+			if match(card) && !slices.ContainsFunc(kinds, func(c *Card) bool { return d.cardsEqual(c, card) }) {
+				kinds = append(kinds, card)
+			}
 		}
-		actionNames := []string{"❄️ FREEZE", "🎲 FLIP 3", "🆘 2ND CHANCE"}
-		for i, count := range []int{actionCounts[Freeze], actionCounts[FlipThree], actionCounts[SecondChance]} {
-			if count > 0 {
-				fmt.Printf("  %d) %s (%d available)\n", optionIndex, actionNames[i], count)
-				cardOptions = append(cardOptions, NewActionCard(ActionType(i)))
-				optionIndex++
+		slices.SortFunc(kinds, func(a, b *Card) int {
+			return cmp.Or(cmp.Compare(a.Action, b.Action), cmp.Compare(a.Modifier, b.Modifier))
+		})
+		return kinds
+	}
+	available := func(kind *Card) int {
+		n := 0
+		for _, card := range d.cards {
+			if d.cardsEqual(card, kind) {
+				n++
 			}
 		}
+		return n
 	}
-
-	if len(modifierCards) > 0 {
-		fmt.Println("\nModifier Cards:")
-		modifierCounts := make(map[ModifierType]int)
-		for _, card := range modifierCards {
-			modifierCounts[card.Modifier]++
+	if actions := byKind((*Card).IsActionCard); len(actions) > 0 {
+		fmt.Fprintln(d.out, T("\nAction Cards:"))
+		for _, card := range actions {
+			fmt.Fprintf(d.out, T("  %-4s %s (%d available)\n"), debugCardNames[card.Action], card, available(card))
 		}
-		modifierNames := []string{"+2", "+4", "+6", "+8", "+10", "×2"}
-		for i, count := range []int{modifierCounts[Plus2], modifierCounts[Plus4], modifierCounts[Plus6], modifierCounts[Plus8], modifierCounts[Plus10], modifierCounts[Multiply2]} {
-			if count > 0 {
-				fmt.Printf("  %d) [%s] (%d available)\n", optionIndex, modifierNames[i], count)
-				cardOptions = append(cardOptions, NewModifierCard(ModifierType(i)))
-				optionIndex++
-			}
+	}
+	if modifiers := byKind(func(c *Card) bool { return c.Type == ModifierCard }); len(modifiers) > 0 {
+		fmt.Fprintln(d.out, T("\nModifier Cards:"))
+		for _, card := range modifiers {
+			fmt.Fprintf(d.out, T("  %-4s %s (%d available)\n"), formatCard(card), card, available(card))
 		}
 	}
 
-	fmt.Printf("\nEnter choice (1-%d): ", len(cardOptions))
+	fmt.Fprint(d.out, T("\nType a card (e.g. 7, f3, +10, x2), or r for a random one: "))
 
 	for {
 		if !d.scanner.Scan() {
@@ -205,26 +578,23 @@ func (d *Deck) drawCardDebug() *Card {
 		}
 
 		input := strings.TrimSpace(d.scanner.Text())
-		choice, err := strconv.Atoi(input)
-		if err != nil || choice < 1 || choice > len(cardOptions) {
-			fmt.Printf("Please enter a number between 1 and %d: ", len(cardOptions))
+		if strings.EqualFold(input, "r") {
+			return d.drawRandomCard()
+		}
+		selectedCard, err := parseDebugCard(input)
+		if err != nil {
+			fmt.Fprintf(d.out, T("Unknown card %q; type a card listed above, or r: "), input)
 			continue
 		}
 
-		selectedCard := cardOptions[choice-1]
-
 		// Find and remove the actual card from the deck
 		for i, card := range d.cards {
 			if d.cardsEqual(card, selectedCard) {
-				// Remove this card from the deck
 				d.cards = append(d.cards[:i], d.cards[i+1:]...)
 				return card
 			}
 		}
-
-		// Fallback if card not found (shouldn't happen)
-		fmt.Println("Card not found, drawing random card instead...")
-		return d.drawRandomCard()
+		fmt.Fprintf(d.out, T("No %s left in the deck; type another card: "), selectedCard)
 	}
 }
 
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"flip7/bot"
+)
+
+// drawAll draws from the deck until it runs out, and lists the cards as
+// scenarios write them
+func drawAll(d DeckInterface) string {
+	var drawn []*Card
+	for card := d.DrawCard(); card != nil; card = d.DrawCard() {
+		drawn = append(drawn, card)
+	}
+	return formatCards(drawn)
+}
+
+func TestStackedDeckDealsInOrder(t *testing.T) {
+	d := NewStackedDeck(mustParseCards("7 x2 freeze 12")...)
+	if got := d.Counts().Total; got != 4 {
+		t.Fatalf("tally of 4 stacked cards: got %d", got)
+	}
+	if got, want := drawAll(d), "7 x2 freeze 12"; got != want {
+		t.Fatalf("drew %q, want %q", got, want)
+	}
+	if got := d.Counts().Total; got != 0 {
+		t.Errorf("tally after drawing every card: got %d, want 0", got)
+	}
+}
+
+func TestStackedDeckRedealsDiscardsInOrder(t *testing.T) {
+	d := NewStackedDeck(mustParseCards("1 2 3")...)
+	one, two := d.DrawCard(), d.DrawCard()
+	d.DiscardCard(two)
+	d.DiscardCard(one)
+	d.Reshuffle()
+	if got, want := drawAll(d), "3 2 1"; got != want {
+		t.Fatalf("drew %q after reshuffling, want %q", got, want)
+	}
+
+	d.Reset()
+	if got, want := drawAll(d), "1 2 3"; got != want {
+		t.Fatalf("drew %q after a reset, want %q", got, want)
+	}
+}
+
+func TestUnseenCountsOnlyTheStackedCards(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetDeck(NewStackedDeck(mustParseCards("5 5 9 +4 freeze")...))
+	ada := NewComputerPlayer("Ada", AlwaysHitStrategy, nil, nil)
+	g.players = []PlayerInterface{ada}
+
+	ada.AddCard(g.deck.DrawCard())
+	g.deck.DiscardCard(g.deck.DrawCard())
+
+	unseen := g.buildGameState().Unseen()
+	if unseen.Total != 3 {
+		t.Fatalf("unseen cards: got %d, want the 3 stacked cards not yet dealt", unseen.Total)
+	}
+	if unseen.Numbers[5] != 0 || unseen.Numbers[9] != 1 || unseen.Modifiers != 1 || unseen.Freezes != 1 {
+		t.Errorf("unseen tally %+v, want a 9, a +4 and a Freeze", *unseen)
+	}
+}
+
+// The bot package keeps its own tally of the full card set, since bots
+// can't import the engine; the two must agree
+func TestStandardCountsMatchTheBotPackage(t *testing.T) {
+	if got, want := standardCounts.botCounts(), bot.FullDeck(); got != want {
+		t.Errorf("engine card set %+v, bot package %+v", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// banterOf plays a game seeded as a simulation seeds it, with banter on,
+// and returns the lines its computer players said. With yield set it
+// lets other goroutines run after every event.
+func banterOf(t *testing.T, seed int64, yield bool) string {
+	var out bytes.Buffer
+	g := NewGame()
+	g.SetOutput(&out)
+	g.SetCommentary(true)
+	g.SetSeed(seed)
+	g.seedRandom(seed)
+	g.SetWinningScore(100)
+	for _, name := range []string{"Ada", "Bo", "Cy", "Di"} {
+		g.players = append(g.players, NewComputerPlayerFromSpec(name, randomSpec(g.rng)))
+	}
+	if yield {
+		g.Subscribe(func(Event) { runtime.Gosched() })
+	}
+	if err := g.Run(context.Background()); err != nil {
+		t.Errorf("seed %d: %v", seed, err)
+	}
+
+	var said []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.Contains(line, "💬") {
+			said = append(said, line)
+		}
+	}
+	return strings.Join(said, "\n")
+}
+
+func TestBanterReplaysSideBySide(t *testing.T) {
+	const games = 8
+	serial := make([]string, games)
+	for i := range serial {
+		serial[i] = banterOf(t, int64(i+1), false)
+	}
+	if strings.Join(serial, "") == "" {
+		t.Fatal("no computer player said anything")
+	}
+
+	parallel := make([]string, games)
+	var wg sync.WaitGroup
+	for i := range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parallel[i] = banterOf(t, int64(i+1), true)
+		}()
+	}
+	wg.Wait()
+
+	for i := range serial {
+		if serial[i] != parallel[i] {
+			t.Errorf("seed %d says different banter played side by side:\n%s\nwant\n%s", i+1, parallel[i], serial[i])
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// Difficulty is a preset computer strength offered to casual players
+// instead of the full list of strategies
+type Difficulty struct {
+	Name string
+	Spec StrategySpec
+}
+
+// difficulties are the presets, easiest first. The lower tiers play a
+// simple strategy and make a share of their decisions at random.
+var difficulties = []Difficulty{
+	{Name: "easy", Spec: StrategySpec{Choice: 1, TargetScore: 15, Noise: 0.25, Difficulty: "easy"}},
+	{Name: "medium", Spec: StrategySpec{Choice: 2, BustProbability: 0.25, Noise: 0.1, Difficulty: "medium"}},
+	{Name: "hard", Spec: StrategySpec{Choice: 7, Difficulty: "hard"}},
+	{Name: "expert", Spec: StrategySpec{Choice: 9, Difficulty: "expert"}},
+}
+
+// chooseDifficulty asks for one difficulty for all computer players. It
+// returns false if the human wants to pick each computer's strategy.
+// Adaptive difficulty follows the first human's profile.
+func (g *Game) chooseDifficulty(ctx context.Context) (StrategySpec, bool, error) {
+	g.promptln(T("\nChoose computer difficulty:"))
+	for i, d := range difficulties {
+		g.promptf("  %d) %s\n", i+1, T(strings.ToUpper(d.Name[:1])+d.Name[1:]))
+	}
+	adaptive := len(difficulties) + 1
+	g.promptf(T("  %d) Adaptive (adjusts to your win rate between games)\n"), adaptive)
+	g.promptf(T("  %d) Custom (choose each computer's strategy)\n"), adaptive+1)
+	g.promptf(T("Enter choice (1-%d): "), adaptive+1)
+
+	choice, err := g.getIntInput(ctx, 1, adaptive+1)
+	if err != nil {
+		return StrategySpec{}, false, err
+	}
+	switch {
+	case choice == adaptive:
+		spec, err := g.adaptiveSpec(g.players[0])
+		return spec, err == nil, err
+	case choice > adaptive:
+		return StrategySpec{}, false, nil
+	}
+	return difficulties[choice-1].Spec, true, nil
+}
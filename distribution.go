@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// histogramRows is the most rows a score histogram is drawn with
+	histogramRows = 12
+	// histogramWidth is the length of the longest bar in a histogram
+	histogramWidth = 30
+)
+
+// histogramSteps are the bucket widths a histogram may use, narrowest
+// first
+var histogramSteps = []int{1, 2, 5, 10, 20, 25, 50, 100, 200, 500}
+
+// ScoreDistribution counts how many times each score came up
+type ScoreDistribution map[int]int
+
+// Add counts a score
+func (d ScoreDistribution) Add(score int) {
+	d[score]++
+}
+
+// Count is the number of scores counted
+func (d ScoreDistribution) Count() int {
+	n := 0
+	for _, c := range d {
+		n += c
+	}
+	return n
+}
+
+// Percentile returns the smallest score that at least p percent of the
+// scores are no higher than
+func (d ScoreDistribution) Percentile(p float64) int {
+	rank := int(math.Ceil(p / 100 * float64(d.Count())))
+	seen := 0
+	for _, score := range slices.Sorted(maps.Keys(d)) {
+		seen += d[score]
+		if seen >= rank {
+			return score
+		}
+	}
+	return 0
+}
+
+// ScoreDistributions holds every player's final and round scores in a
+// simulation, by player name
+type ScoreDistributions struct {
+	Final map[string]ScoreDistribution
+	Round map[string]ScoreDistribution
+}
+
+// NewScoreDistributions creates empty distributions
+func NewScoreDistributions() *ScoreDistributions {
+	return &ScoreDistributions{
+		Final: make(map[string]ScoreDistribution),
+		Round: make(map[string]ScoreDistribution),
+	}
+}
+
+// SetHistograms turns on score histograms in the simulation results
+func (g *Game) SetHistograms(enabled bool) {
+	g.histograms = enabled
+}
+
+// SetScoresFile makes simulations write every player's score
+// distributions to a CSV file for plotting
+func (g *Game) SetScoresFile(path string) {
+	g.scoresFile = path
+}
+
+// add counts a score for a player
+func add(dists map[string]ScoreDistribution, name string, score int) {
+	if dists[name] == nil {
+		dists[name] = make(ScoreDistribution)
+	}
+	dists[name].Add(score)
+}
+
+// trackScores counts every round score the game's players make
+func (g *Game) trackScores(s *ScoreDistributions) {
+	g.Subscribe(func(event Event) {
+		if event.Type == PlayerScored {
+			add(s.Round, event.Player.GetName(), event.Score)
+		}
+	})
+}
+
+// recordGame counts the players' final scores
+func (s *ScoreDistributions) recordGame(g *Game) {
+	for _, p := range g.players {
+		add(s.Final, p.GetName(), p.GetTotalScore())
+	}
+}
+
+// export writes the distributions as CSV, one row per player, kind and
+// score with the number of times it came up
+func (s *ScoreDistributions) export(path string, names []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"player", "kind", "score", "count"})
+	for _, name := range names {
+		for _, kind := range []struct {
+			name  string
+			dists map[string]ScoreDistribution
+		}{{"final", s.Final}, {"round", s.Round}} {
+			d := kind.dists[name]
+			for _, score := range slices.Sorted(maps.Keys(d)) {
+				w.Write([]string{name, kind.name, strconv.Itoa(score), strconv.Itoa(d[score])})
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// displayScoreDistributions draws every player's final and round score
+// histograms with their percentiles
+func (g *Game) displayScoreDistributions(s *ScoreDistributions, names []string) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf("%s\n", T("📊 SCORE DISTRIBUTIONS"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	for i, name := range names {
+		if i > 0 {
+			g.resultf("%s\n", strings.Repeat("-", 60))
+		}
+		g.displayHistogram(fmt.Sprintf(T("%s, final score"), name), s.Final[name])
+		g.displayHistogram(fmt.Sprintf(T("%s, round score"), name), s.Round[name])
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
+
+// buckets groups the scores into at most histogramRows buckets of a
+// round width, the first holding low
+func (d ScoreDistribution) buckets() (low, step int, buckets []int) {
+	scores := slices.Sorted(maps.Keys(d))
+	low, high := scores[0], scores[len(scores)-1]
+	step = histogramSteps[len(histogramSteps)-1]
+	for _, s := range histogramSteps {
+		if (high/s)-(low/s) < histogramRows {
+			step = s
+			break
+		}
+	}
+
+	buckets = make([]int, high/step-low/step+1)
+	for score, n := range d {
+		buckets[score/step-low/step] += n
+	}
+	return low, step, buckets
+}
+
+// displayHistogram draws one distribution with its P50, P90 and P99
+func (g *Game) displayHistogram(title string, d ScoreDistribution) {
+	if d.Count() == 0 {
+		return
+	}
+	g.resultf(T("%s: P50 %d, P90 %d, P99 %d\n"), title, d.Percentile(50), d.Percentile(90), d.Percentile(99))
+
+	low, step, buckets := d.buckets()
+	tallest := slices.Max(buckets)
+	total := d.Count()
+	for i, n := range buckets {
+		from := (low/step + i) * step
+		label := strconv.Itoa(from)
+		if step > 1 {
+			label = fmt.Sprintf("%d-%d", from, from+step-1)
+		}
+		bar := strings.Repeat("▓", (n*histogramWidth+tallest-1)/tallest)
+		g.resultf("  %9s %6.1f%% %s\n", label, 100*float64(n)/float64(total), bar)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// roundBuckets are the upper bounds of the round score ranges in a duel
+// report. Busted rounds get a row of their own.
+var roundBuckets = []int{0, 14, 24, 34, 49, math.MaxInt}
+
+// DuelStats is one side's record in a duel
+type DuelStats struct {
+	Wins      int
+	Total     int // final scores, summed over games
+	Margin    int // winning margins, summed over games won
+	Rounds    int
+	Busts     int
+	Flip7s    int
+	Points    int // round scores, summed over rounds
+	Histogram []int
+}
+
+// Duel is a heads-up match between two strategies. Every deck is dealt
+// twice with the seats swapped, so neither side gets the better cards or
+// the better seat.
+type Duel struct {
+	Players []PlayerInterface
+	Stats   []*DuelStats
+	Games   int
+	// Notables, when set, keeps the most notable games, by the seed
+	// their deck was dealt from
+	Notables *NotableGames
+
+	busted []bool // this round
+}
+
+// RunDuel handles the duel subcommand: two strategies play each other
+// heads-up and the report shows why one of them wins
+func RunDuel(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("duel", flag.ContinueOnError)
+	a := fs.String("a", "", "First strategy, e.g. expert or play-to:25")
+	b := fs.String("b", "", "Second strategy")
+	games := fs.Int("n", 1000, "Number of games; each deck is dealt twice with the seats swapped")
+	seed := fs.Int64("seed", 0, "Seed for the first deck (0 uses the clock)")
+	score := fs.Int("score", 200, "Points needed to win")
+	markdown := fs.String("markdown", "", "Also write the report as Markdown to this file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *a == "" || *b == "" {
+		return fmt.Errorf("usage: flip7 duel -a STRATEGY -b STRATEGY [-n GAMES] [-seed N] [-score N] [-markdown FILE]")
+	}
+	if *games < 2 {
+		return fmt.Errorf("-n must be at least 2")
+	}
+	if *score < 1 {
+		return fmt.Errorf("-score must be positive")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	var players []PlayerInterface
+	for i, s := range []string{*a, *b} {
+		p, err := newStrategyPlayer(string(rune('A'+i)), s)
+		if err != nil {
+			return err
+		}
+		players = append(players, p)
+	}
+
+	d := NewDuel(players)
+	if *markdown != "" {
+		d.Notables = NewNotableGames()
+	}
+	if err := d.Play(*games, *seed, *score); err != nil {
+		return err
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	report.displayDuel(d, *seed)
+	if *markdown != "" {
+		if err := writeMarkdown(*markdown, func(w io.Writer) { d.writeMarkdown(w, *seed, *a, *b, *score) }); err != nil {
+			return err
+		}
+		report.printf(T("📝 Markdown report written to %s\n"), *markdown)
+	}
+	return nil
+}
+
+// NewDuel sets up a duel between two players
+func NewDuel(players []PlayerInterface) *Duel {
+	d := &Duel{Players: players, busted: make([]bool, len(players))}
+	for range players {
+		d.Stats = append(d.Stats, &DuelStats{Histogram: make([]int, len(roundBuckets)+1)})
+	}
+	return d
+}
+
+// Play plays the given number of games, dealing the deck from seed for
+// the first pair of games, seed+1 for the next and so on
+func (d *Duel) Play(games int, seed int64, winningScore int) error {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetRandom(rand.New(rand.NewSource(seed)))
+	g.SetWinningScore(winningScore)
+	g.Subscribe(d.observe)
+	if d.Notables != nil {
+		g.trackNotables(d.Notables)
+	}
+
+	for i := 0; i < games; i++ {
+		deal := seed + int64(i/2)
+		g.players = []PlayerInterface{d.Players[i%2], d.Players[1-i%2]}
+		g.resetGameState()
+		g.deck.Seed(deal)
+		g.seedRandom(deal)
+		if d.Notables != nil {
+			d.Notables.startGame(deal, true)
+		}
+		if err := g.runSingleGame(context.Background()); err != nil {
+			return fmt.Errorf("game %d: %w", i+1, err)
+		}
+
+		d.Games++
+		winner := g.getWinner()
+		if d.Notables != nil {
+			d.Notables.endGame(winner)
+		}
+		for side, p := range d.Players {
+			stats := d.Stats[side]
+			stats.Total += p.GetTotalScore()
+			if p == winner {
+				stats.Wins++
+				stats.Margin += p.GetTotalScore() - d.Players[1-side].GetTotalScore()
+			}
+		}
+	}
+	return nil
+}
+
+// observe counts each side's rounds, busts, Flip 7s and round scores
+func (d *Duel) observe(event Event) {
+	side := -1
+	for i, p := range d.Players {
+		if event.Player == p {
+			side = i
+		}
+	}
+
+	switch event.Type {
+	case RoundStarted:
+		for i, stats := range d.Stats {
+			stats.Rounds++
+			d.busted[i] = false
+		}
+	case PlayerBusted:
+		d.busted[side] = true
+		d.Stats[side].Busts++
+		d.Stats[side].Histogram[0]++
+	case Flip7Achieved:
+		d.Stats[side].Flip7s++
+	case PlayerScored:
+		stats := d.Stats[side]
+		stats.Points += event.Score
+		if d.busted[side] {
+			return
+		}
+		for i, bound := range roundBuckets {
+			if event.Score <= bound {
+				stats.Histogram[i+1]++
+				break
+			}
+		}
+	}
+}
+
+// duelRow is a line of the duel report: what's compared and each side's
+// figure
+type duelRow struct {
+	Label string
+	A, B  string
+}
+
+// pct is n as a percentage of of, or 0 when of is
+func pct(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return 100 * float64(n) / float64(of)
+}
+
+// avg is n shared between of, or 0 when of is
+func avg(n, of int) float64 {
+	if of == 0 {
+		return 0
+	}
+	return float64(n) / float64(of)
+}
+
+// rows compares the two sides' records
+func (d *Duel) rows() []duelRow {
+	a, b := d.Stats[0], d.Stats[1]
+	row := func(label string, format string, va, vb any) duelRow {
+		return duelRow{label, fmt.Sprintf(format, va), fmt.Sprintf(format, vb)}
+	}
+	return []duelRow{
+		row(T("Wins"), "%d", a.Wins, b.Wins),
+		row(T("Win rate"), "%.1f%%", pct(a.Wins, d.Games), pct(b.Wins, d.Games)),
+		row(T("Average final score"), "%.1f", avg(a.Total, d.Games), avg(b.Total, d.Games)),
+		row(T("Average winning margin"), "%.1f", avg(a.Margin, a.Wins), avg(b.Margin, b.Wins)),
+		row(T("Rounds busted"), "%.1f%%", pct(a.Busts, a.Rounds), pct(b.Busts, b.Rounds)),
+		row(T("Rounds with Flip 7"), "%.1f%%", pct(a.Flip7s, a.Rounds), pct(b.Flip7s, b.Rounds)),
+		row(T("Average round score"), "%.1f", avg(a.Points, a.Rounds), avg(b.Points, b.Rounds)),
+		row(T("Average banked round"), "%.1f", avg(a.Points, a.Rounds-a.Busts), avg(b.Points, b.Rounds-b.Busts)),
+	}
+}
+
+// roundScores compares how the two sides' round scores are spread
+func (d *Duel) roundScores() []duelRow {
+	a, b := d.Stats[0], d.Stats[1]
+	labels := []string{T("busted"), "0"}
+	for i := 1; i < len(roundBuckets); i++ {
+		if roundBuckets[i] == math.MaxInt {
+			labels = append(labels, fmt.Sprintf("%d+", roundBuckets[i-1]+1))
+		} else {
+			labels = append(labels, fmt.Sprintf("%d-%d", roundBuckets[i-1]+1, roundBuckets[i]))
+		}
+	}
+	var rows []duelRow
+	for i, label := range labels {
+		rows = append(rows, duelRow{label, fmt.Sprintf("%.1f%%", pct(a.Histogram[i], a.Rounds)), fmt.Sprintf("%.1f%%", pct(b.Histogram[i], b.Rounds))})
+	}
+	return rows
+}
+
+// verdict sums the duel up: who wins how often, with two standard errors
+// either side, and how the leader plays differently
+func (d *Duel) verdict() (result, comparison string) {
+	a, b := d.Stats[0], d.Stats[1]
+	games := float64(d.Games)
+	rate := float64(a.Wins) / games
+	margin := 200 * math.Sqrt(rate*(1-rate)/games)
+	leader, trailer, ls, ts := d.Players[0], d.Players[1], a, b
+	if b.Wins > a.Wins {
+		leader, trailer, ls, ts = d.Players[1], d.Players[0], b, a
+	}
+	if math.Abs(pct(a.Wins, d.Games)-50) <= margin {
+		result = fmt.Sprintf(T("Too close to call: %.1f%% ± %.1f%% for %s\n"), pct(a.Wins, d.Games), margin, d.Players[0].GetName())
+	} else {
+		result = fmt.Sprintf(T("%s wins %.1f%% ± %.1f%% of games against %s\n"), leader.GetName(), pct(ls.Wins, d.Games), margin, trailer.GetName())
+	}
+	comparison = fmt.Sprintf(T("Compared with %s: bust rate %+.1f%%, Flip 7 rate %+.1f%%, round score %+.1f\n"), trailer.GetName(),
+		pct(ls.Busts, ls.Rounds)-pct(ts.Busts, ts.Rounds), pct(ls.Flip7s, ls.Rounds)-pct(ts.Flip7s, ts.Rounds),
+		avg(ls.Points, ls.Rounds)-avg(ts.Points, ts.Rounds))
+	return result, comparison
+}
+
+// displayDuel shows the matchup report
+func (g *Game) displayDuel(d *Duel, seed int64) {
+	row := func(prefix string, r duelRow) {
+		g.resultf("%-28s %15s %15s\n", prefix+r.Label, r.A, r.B)
+	}
+
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("⚔️ DUEL - %d games from seed %d\n"), d.Games, seed)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-28s %15s %15s\n", "", d.Players[0].GetName(), d.Players[1].GetName())
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	for _, r := range d.rows() {
+		row("", r)
+	}
+
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%s\n", T("Round scores:"))
+	for _, r := range d.roundScores() {
+		row("  ", r)
+	}
+
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	result, comparison := d.verdict()
+	g.resultf("%s", result)
+	g.resultf("%s", comparison)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetDuplicate makes simulations deal each board once per seating, like
+// duplicate bridge
+func (g *Game) SetDuplicate(enabled bool) {
+	g.duplicate = enabled
+}
+
+// playBoard deals the simulation's next board once for every rotation of
+// the seats, so each player plays each seat against the same cards.
+// Comparing strategies this way cancels out most of the deck luck, so
+// results settle after far fewer games.
+func (g *Game) playBoard(ctx context.Context, sim *Simulation, lineup []PlayerInterface) error {
+	boardSeed := sim.Seed + int64(sim.Played)
+	for rotation := range lineup {
+		g.players = rotated(lineup, rotation)
+		g.resetGameState()
+		g.deck.Seed(boardSeed)
+		g.seedRandom(boardSeed)
+		g.dealerIdx = firstDealer(sim.Dealer, sim.Played, boardSeed, len(lineup))
+
+		g.SetSilentMode(true)
+		err := g.runSingleGame(ctx)
+		g.SetSilentMode(false)
+		if err != nil {
+			return fmt.Errorf("error in board %d, seating %d: %v", sim.Played+1, rotation+1, err)
+		}
+		if err := g.recordSimulatedGame(sim, boardSeed, rotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// canEdit reports whether the state editor is available in this game
+func (g *Game) canEdit() bool {
+	return g.debugMode && !g.silentMode
+}
+
+// runEditor is the debug-mode state editor, opened with :edit at a
+// human's prompt. It sets scores, deals cards from the deck into hands,
+// forces player states and moves to another round, so an edge case can
+// be set up in place instead of played into. Edits aren't announced to
+// listeners; the game carries on from the edited position.
+func (g *Game) runEditor(ctx context.Context, player PlayerInterface) error {
+	g.promptln(T("🛠️ State editor: type help for commands, done to go back to the game"))
+	for {
+		g.prompt(T("edit> "))
+		input, err := g.getStringInput(ctx)
+		if err != nil {
+			return err
+		}
+		fields := strings.Fields(input)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "done", "exit", ":q":
+			g.show(func(w io.Writer) { g.renderer.RenderHand(w, player) })
+			return nil
+		case "help", "?":
+			g.showEditorHelp()
+			continue
+		case "hands":
+			g.show(g.drawAllHands)
+			g.show(g.drawScores)
+			continue
+		}
+		if err := g.editCommand(fields); err != nil {
+			g.promptf(T("Can't do that: %v\n"), err)
+		}
+	}
+}
+
+// editCommand carries out one state editor command
+func (g *Game) editCommand(fields []string) error {
+	command, args := strings.ToLower(fields[0]), fields[1:]
+	if command == "round" {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: round <n>")
+		}
+		round, err := strconv.Atoi(args[0])
+		if err != nil || round < 1 {
+			return fmt.Errorf("%q isn't a round number", args[0])
+		}
+		g.round = round
+		g.promptf(T("Now in round %d\n"), round)
+		return nil
+	}
+
+	var usage string
+	switch command {
+	case "score":
+		usage = "score <player> <points>"
+	case "give":
+		usage = "give <player> <card>..."
+	case "state":
+		usage = "state <player> <active|stayed|frozen|busted>"
+	default:
+		return fmt.Errorf("unknown command %q (type help)", fields[0])
+	}
+	if len(args) < 2 || command != "give" && len(args) != 2 {
+		return fmt.Errorf("usage: %s", usage)
+	}
+	target, err := g.findPlayer(args[0])
+	if err != nil {
+		return err
+	}
+	p := target.(baser).base()
+
+	switch command {
+	case "score":
+		score, err := strconv.Atoi(args[1])
+		if err != nil || score < 0 {
+			return fmt.Errorf("%q isn't a score", args[1])
+		}
+		p.TotalScore = score
+		g.promptf(T("%s now has %d points\n"), p.Name, score)
+	case "give":
+		for _, arg := range args[1:] {
+			if err := g.giveCard(p, arg); err != nil {
+				return err
+			}
+		}
+		g.render(func(w io.Writer) { g.renderer.RenderHand(w, target) })
+	case "state":
+		state, ok := scenarioStates[strings.ToLower(args[1])]
+		if !ok {
+			return fmt.Errorf("unknown state %q (active, stayed, frozen or busted)", args[1])
+		}
+		p.State = state
+		if state != Busted {
+			p.BustedBy = nil
+		}
+		g.promptf(T("%s is now %s\n"), p.Name, state)
+	}
+	return nil
+}
+
+// giveCard takes a card from the deck and puts it in the player's hand,
+// as long as the hand can hold it without busting
+func (g *Game) giveCard(p *BasePlayer, name string) error {
+	card, err := parseDebugCard(name)
+	if err != nil {
+		return err
+	}
+	switch {
+	case card.Type == NumberCard && p.Holds(card.Value):
+		return fmt.Errorf("%s already has a %s; use state to bust them", p.Name, card)
+	case card.Type == ActionCard && card.Action == SecondChance && p.HasSecondChance():
+		return fmt.Errorf("%s already has a Second Chance", p.Name)
+	case card.Type == ActionCard && card.Action != SecondChance && !g.holdActions:
+		return fmt.Errorf("only Second Chance stays in a hand unless action cards are held")
+	}
+
+	deck := g.deck.Cards()
+	for i := len(deck) - 1; i >= 0; i-- {
+		if *deck[i] == *card {
+			card = deck[i]
+			rest := append(deck[:i:i], deck[i+1:]...)
+			g.deck.Restore(rest, g.deck.Discards())
+			if err := p.AddCard(card); err != nil && err.Error() != "flip7" {
+				return err
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("the deck doesn't have a %s", card)
+}
+
+// findPlayer finds a player by seat number, counting from 1, or by the
+// start of their name
+func (g *Game) findPlayer(name string) (PlayerInterface, error) {
+	if seat, err := strconv.Atoi(name); err == nil {
+		if seat < 1 || seat > len(g.players) {
+			return nil, fmt.Errorf("there's no seat %d", seat)
+		}
+		return g.players[seat-1], nil
+	}
+	var found PlayerInterface
+	for _, player := range g.players {
+		if strings.HasPrefix(strings.ToLower(player.GetName()), strings.ToLower(name)) {
+			if found != nil {
+				return nil, fmt.Errorf("%q could be %s or %s; use a seat number", name, found.GetName(), player.GetName())
+			}
+			found = player
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no player called %q", name)
+	}
+	return found, nil
+}
+
+func (g *Game) showEditorHelp() {
+	g.promptln(T("Editor commands (players by seat number or the start of their name):"))
+	g.promptln(T("  score <player> <points>  - set a player's total score"))
+	g.promptln(T("  give <player> <card>...  - move cards from the deck into a hand, e.g. give 2 7 x2"))
+	g.promptln(T("  state <player> <state>   - make a player active, stayed, frozen or busted"))
+	g.promptln(T("  round <n>                - set the round number"))
+	g.promptln(T("  hands                    - show every hand and the scores"))
+	g.promptln(T("  done                     - go back to the game"))
+}
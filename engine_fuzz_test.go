@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+// FuzzEngine plays a randomly configured game for each seed and fails on
+// the first broken invariant: cards created or lost, a negative score, a
+// round or game that doesn't end, or a panic. go test runs the seeds
+// below; go test -fuzz FuzzEngine searches for more and keeps any that
+// fail under testdata/fuzz/FuzzEngine.
+func FuzzEngine(f *testing.F) {
+	for _, seed := range []int64{1, 2, 3, 42, 7919, 1792121167455725534} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		if err := fuzzGame(seed); err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+	})
+}
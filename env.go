@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"flip7/bot"
+)
+
+// envProtocolVersion is bumped whenever the env protocol's messages
+// change incompatibly
+const envProtocolVersion = 4
+
+// envActions are the actions an agent can take, by index
+var envActions = []string{"stay", "hit"}
+
+// observationNames name the entries of an observation, in order. The bot
+// package decodes observations, so it holds the names.
+var observationNames = bot.ObservationNames
+
+// errEnvReset stops a game the agent abandoned by resetting or closing
+var errEnvReset = errors.New("environment reset")
+
+// TimeStep is what the agent sees after a reset or a step: the
+// observation to decide on, the actions it may take, and at the end of
+// the game its reward, 1 for a win and 0 otherwise. Events are what
+// happened since the last time step and State the table as it stands.
+type TimeStep struct {
+	Observation  []float64      `json:"observation"`
+	LegalActions []int          `json:"legal_actions"`
+	Reward       float64        `json:"reward"`
+	Done         bool           `json:"done"`
+	Round        int            `json:"round"`
+	Scores       map[string]int `json:"scores"`
+	Events       []EventMessage `json:"events"`
+	State        StateSnapshot  `json:"state"`
+}
+
+// StateSnapshot is the table as every player can see it
+type StateSnapshot struct {
+	Round        int              `json:"round"`
+	Dealer       string           `json:"dealer"`
+	WinningScore int              `json:"winning_score"`
+	CardsLeft    int              `json:"cards_left"`
+	Players      []PlayerSnapshot `json:"players"`
+}
+
+// PlayerSnapshot is a player's hand and scores, the hand written as
+// parseCards reads it. State is active, stayed, frozen or busted, and
+// BustedBy the card a busted player drew.
+type PlayerSnapshot struct {
+	Name         string `json:"name"`
+	Hand         string `json:"hand"`
+	RoundScore   int    `json:"round_score"`
+	TotalScore   int    `json:"total_score"`
+	Active       bool   `json:"active"`
+	SecondChance bool   `json:"second_chance"`
+	State        string `json:"state,omitempty"`
+	BustedBy     string `json:"busted_by,omitempty"`
+}
+
+// envTurn is a time step handed from the game to the agent
+type envTurn struct {
+	step TimeStep
+	err  error
+}
+
+// Env runs games between an agent and computer opponents one decision
+// at a time, for training agents against the engine's rules. The game
+// runs in its own goroutine and stops at each of the agent's hit/stay
+// decisions until the agent steps; the agent's action cards go to the
+// leader and last place as the computer players' do.
+type Env struct {
+	Opponents []string
+	Score     int
+	Name      string // the agent's name, "Agent" if empty
+
+	game     *Game
+	agent    *AgentPlayer
+	turns    chan envTurn
+	actions  chan bool
+	cancel   context.CancelCauseFunc
+	finished chan struct{}
+	done     bool
+	events   []EventMessage
+}
+
+// AgentPlayer is a computer player whose hit/stay decisions come from
+// the environment's agent
+type AgentPlayer struct {
+	*ComputerPlayer
+	env *Env
+}
+
+// NewEnv creates an environment against opponents named as duel
+// strategies are, playing to score points
+func NewEnv(opponents []string, score int) (*Env, error) {
+	if len(opponents) == 0 {
+		return nil, fmt.Errorf("no opponents")
+	}
+	for i, s := range opponents {
+		if _, err := parseStrategy(fmt.Sprintf("Opponent %d", i+1), s); err != nil {
+			return nil, err
+		}
+	}
+	if score < 1 {
+		return nil, fmt.Errorf("the winning score must be positive")
+	}
+	return &Env{Opponents: opponents, Score: score, done: true}, nil
+}
+
+// Reset abandons any game in progress and deals a new one from seed,
+// returning the agent's first decision. The seed also picks the first
+// dealer, so the agent doesn't always act last.
+func (e *Env) Reset(seed int64) (TimeStep, error) {
+	e.Close()
+
+	players := []PlayerInterface{}
+	name := e.Name
+	if name == "" {
+		name = "Agent"
+	}
+	e.agent = &AgentPlayer{ComputerPlayer: NewComputerPlayerFromSpec(name, StrategySpec{Choice: 6}), env: e}
+	players = append(players, e.agent)
+	for i, s := range e.Opponents {
+		p, err := newStrategyPlayer(fmt.Sprintf("Opponent %d", i+1), s)
+		if err != nil {
+			return TimeStep{}, err
+		}
+		players = append(players, p)
+	}
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetRandom(rand.New(rand.NewSource(seed)))
+	g.SetWinningScore(e.Score)
+	g.players = players
+	g.resetGameState()
+	g.deck.Seed(seed)
+	g.seedRandom(seed)
+	g.dealerIdx = firstDealer(DealerRandom, 0, seed, len(players))
+	e.game = g
+	e.events = nil
+	g.Subscribe(func(event Event) {
+		e.events = append(e.events, event.Message())
+	})
+
+	e.turns = make(chan envTurn)
+	e.actions = make(chan bool)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	e.cancel = cancel
+	e.finished = make(chan struct{})
+	e.done = false
+	go func() {
+		defer close(e.finished)
+		err := g.runSingleGame(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		step := e.observe(g.buildGameState())
+		step.Done = true
+		step.LegalActions = []int{}
+		if err == nil && g.getWinner() == e.agent {
+			step.Reward = 1
+		}
+		e.turns <- envTurn{step, err}
+	}()
+	return e.wait()
+}
+
+// Step takes an action by its index in envActions and plays on to the
+// agent's next decision or the end of the game
+func (e *Env) Step(action int) (TimeStep, error) {
+	if e.done {
+		return TimeStep{}, fmt.Errorf("no game in progress; reset first")
+	}
+	if action < 0 || action >= len(envActions) {
+		return TimeStep{}, fmt.Errorf("unknown action %d (available: 0 stay, 1 hit)", action)
+	}
+	e.actions <- action == 1
+	return e.wait()
+}
+
+// wait returns the game's next time step
+func (e *Env) wait() (TimeStep, error) {
+	turn := <-e.turns
+	if turn.step.Done || turn.err != nil {
+		e.done = true
+		<-e.finished
+	}
+	return turn.step, turn.err
+}
+
+// Close stops the game in progress, if any
+func (e *Env) Close() {
+	if e.done {
+		return
+	}
+	e.cancel(errEnvReset)
+	<-e.finished
+	e.done = true
+}
+
+// MakeHitStayDecision hands the decision to the agent and waits for it
+func (p *AgentPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	step := p.env.observe(gameState)
+	step.LegalActions = actionIndexes(p.env.game.LegalActions(p))
+	p.env.turns <- envTurn{step: step}
+	select {
+	case hit := <-p.env.actions:
+		return hit, nil
+	case <-ctx.Done():
+		return false, context.Cause(ctx)
+	}
+}
+
+// DecideAction plays Freeze and Flip Three on the leader and gives a
+// spare Second Chance to last place. The strategy is given the agent
+// itself, so it knows not to pick the agent.
+func (p *AgentPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	return p.targetStrategy(decision.Action)(p, gameState, decision.Action), nil
+}
+
+// observe builds the agent's observation of the game, with the events
+// since the last one
+func (e *Env) observe(state *GameState) TimeStep {
+	step := observe(e.game, e.agent, state)
+	step.Events = e.events
+	e.events = nil
+	return step
+}
+
+// observe builds a player's observation of a game and the table as it
+// stands
+func observe(g *Game, agent PlayerInterface, state *GameState) TimeStep {
+	obs := make([]float64, 0, len(observationNames))
+	bit := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	for value := range 13 {
+		obs = append(obs, bit(agent.HeldNumbers()&(1<<value) != 0))
+	}
+	var modifiers uint8
+	for _, card := range agent.GetHand() {
+		if card.Type == ModifierCard {
+			modifiers |= 1 << card.Modifier
+		}
+	}
+	for m := Plus2; m <= Multiply2; m++ {
+		obs = append(obs, bit(modifiers&(1<<m) != 0))
+	}
+
+	best, active := 0, 0
+	scores := make(map[string]int)
+	for _, p := range g.players {
+		scores[p.GetName()] = p.GetTotalScore()
+		if p == agent {
+			continue
+		}
+		best = max(best, p.GetTotalScore())
+		if p.IsActive() {
+			active++
+		}
+	}
+	remaining := state.Unseen()
+	obs = append(obs, bit(agent.HasSecondChance()),
+		float64(agent.CalculateRoundScore()), float64(agent.GetTotalScore()),
+		float64(best), float64(g.winningScore), float64(active), float64(remaining.Total))
+	for _, n := range remaining.Numbers {
+		obs = append(obs, float64(n))
+	}
+	bust := 0.0
+	if remaining.Total > 0 {
+		bust = CalculateBustProbability(agent, state)
+	}
+	obs = append(obs, float64(remaining.Modifiers), float64(remaining.Actions), bust)
+
+	snapshot := StateSnapshot{
+		Round:        g.round,
+		Dealer:       g.players[g.dealerIdx].GetName(),
+		WinningScore: g.winningScore,
+		CardsLeft:    remaining.Total,
+	}
+	for _, p := range g.players {
+		var hand []string
+		for _, card := range p.GetHand() {
+			hand = append(hand, formatCard(card))
+		}
+		player := PlayerSnapshot{
+			Name:         p.GetName(),
+			Hand:         strings.Join(hand, " "),
+			RoundScore:   p.CalculateRoundScore(),
+			TotalScore:   p.GetTotalScore(),
+			Active:       p.IsActive(),
+			SecondChance: p.HasSecondChance(),
+			State:        p.GetState().String(),
+		}
+		if card := p.GetBustedBy(); card != nil {
+			player.BustedBy = formatCard(card)
+		}
+		snapshot.Players = append(snapshot.Players, player)
+	}
+	return TimeStep{Observation: obs, Round: g.round, Scores: scores, State: snapshot}
+}
+
+// envRequest is a request to an environment: "spec", "reset" with a
+// seed, or "step" with an action. A session may open with "hello" to
+// choose the encoding of the rest of it.
+type envRequest struct {
+	Op       string `json:"op"`
+	Seed     int64  `json:"seed"`
+	Action   int    `json:"action"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// envSpec describes the environment's actions and observations
+type envSpec struct {
+	Version     int      `json:"version"`
+	Actions     []string `json:"actions"`
+	Observation []string `json:"observation"`
+	Players     int      `json:"players"`
+}
+
+// envHello answers a hello with the encoding the session continues in
+type envHello struct {
+	Version  int    `json:"version"`
+	Encoding string `json:"encoding"`
+}
+
+// respond answers a request
+func (e *Env) respond(req envRequest) (any, error) {
+	switch req.Op {
+	case "spec":
+		return envSpec{Version: envProtocolVersion, Actions: envActions, Observation: observationNames, Players: len(e.Opponents) + 1}, nil
+	case "reset":
+		return e.Reset(req.Seed)
+	case "step":
+		return e.Step(req.Action)
+	case "hello":
+		return nil, fmt.Errorf("hello must be the first request")
+	}
+	return nil, fmt.Errorf("unknown op %q (available: hello, spec, reset, step)", req.Op)
+}
+
+// handle answers one JSON request with a JSON response
+func (e *Env) handle(line []byte) []byte {
+	var req envRequest
+	var resp any
+	err := json.Unmarshal(line, &req)
+	if err == nil {
+		resp, err = e.respond(req)
+	}
+	if err != nil {
+		resp = map[string]string{"error": err.Error()}
+	}
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+// handleProto answers requests in length-delimited protobuf frames, as
+// described in proto/flip7.proto, until in closes
+func (e *Env) handleProto(r *bufio.Reader, w *bufio.Writer) error {
+	for {
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if size > maxProtoFrame {
+			return fmt.Errorf("request of %d bytes is too large", size)
+		}
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return err
+		}
+		var resp any
+		req, err := decodeProtoRequest(frame)
+		if err == nil {
+			resp, err = e.respond(req)
+		}
+		msg := appendProtoResponse(nil, resp, err)
+		w.Write(binary.AppendUvarint(nil, uint64(len(msg))))
+		w.Write(msg)
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// RunEnv handles the env subcommand: it serves an environment over
+// standard input and output, one JSON request and response per line, or
+// in protobuf frames after a hello asking for them
+func RunEnv(args []string, in io.Reader, out io.Writer) error {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	opponents := fs.String("opponents", "expert", "Comma-separated opponent strategies, e.g. expert,play-to:25")
+	score := fs.Int("score", 200, "Points needed to win")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	e, err := NewEnv(strings.Split(*opponents, ","), *score)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	r := bufio.NewReader(in)
+	w := bufio.NewWriter(out)
+	first := true
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var req envRequest
+			if first && json.Unmarshal(line, &req) == nil && req.Op == "hello" {
+				encoding := req.Encoding
+				if encoding == "" {
+					encoding = "json"
+				}
+				var data []byte
+				if encoding == "json" || encoding == "protobuf" {
+					data, _ = json.Marshal(envHello{Version: envProtocolVersion, Encoding: encoding})
+				} else {
+					data, _ = json.Marshal(map[string]string{"error": fmt.Sprintf("unknown encoding %q (available: json, protobuf)", encoding)})
+					encoding = "json"
+				}
+				w.Write(data)
+				w.WriteByte('\n')
+				if err := w.Flush(); err != nil {
+					return err
+				}
+				if encoding == "protobuf" {
+					return e.handleProto(r, w)
+				}
+			} else {
+				w.Write(e.handle(line))
+				w.WriteByte('\n')
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+			first = false
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
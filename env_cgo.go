@@ -0,0 +1,69 @@
+//go:build cgo && flip7env
+
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// cgoEnvs holds the environments a C caller has opened, by handle
+var cgoEnvs = struct {
+	sync.Mutex
+	next int
+	envs map[int]*Env
+}{envs: make(map[int]*Env)}
+
+// Flip7EnvNew opens an environment against comma-separated opponent
+// strategies, returning its handle, or -1 if the opponents are invalid
+//
+//export Flip7EnvNew
+func Flip7EnvNew(opponents *C.char, score C.int) C.int {
+	e, err := NewEnv(strings.Split(C.GoString(opponents), ","), int(score))
+	if err != nil {
+		return -1
+	}
+	cgoEnvs.Lock()
+	defer cgoEnvs.Unlock()
+	cgoEnvs.next++
+	cgoEnvs.envs[cgoEnvs.next] = e
+	return C.int(cgoEnvs.next)
+}
+
+// Flip7EnvRequest answers a JSON request as the env subcommand does. The
+// caller frees the response with Flip7Free.
+//
+//export Flip7EnvRequest
+func Flip7EnvRequest(handle C.int, request *C.char) *C.char {
+	cgoEnvs.Lock()
+	e := cgoEnvs.envs[int(handle)]
+	cgoEnvs.Unlock()
+	if e == nil {
+		return C.CString(`{"error":"unknown environment"}`)
+	}
+	return C.CString(string(e.handle([]byte(C.GoString(request)))))
+}
+
+// Flip7EnvClose stops an environment's game and forgets its handle
+//
+//export Flip7EnvClose
+func Flip7EnvClose(handle C.int) {
+	cgoEnvs.Lock()
+	e := cgoEnvs.envs[int(handle)]
+	delete(cgoEnvs.envs, int(handle))
+	cgoEnvs.Unlock()
+	if e != nil {
+		e.Close()
+	}
+}
+
+// Flip7Free frees a response from Flip7EnvRequest
+//
+//export Flip7Free
+func Flip7Free(p *C.char) {
+	C.free(unsafe.Pointer(p))
+}
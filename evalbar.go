@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// evalRollouts is how many games the evaluation bar plays out from each
+// position
+const evalRollouts = 200
+
+// evalBarWidth is how many columns a 100% chance of winning fills
+const evalBarWidth = 20
+
+// SetEvalBar shows every player's chance of winning, worked out again
+// before every turn, for watching a game
+func (g *Game) SetEvalBar(enabled bool) {
+	g.evalBar = enabled
+}
+
+// WinChances estimates every player's chance of winning, in seat order,
+// from the game as it stands with player about to act. The rest of the
+// game is played out from fresh shuffles of the unseen cards, as analyze
+// plays out a recorded decision, several games at once.
+func (g *Game) WinChances(player PlayerInterface, rollouts int) ([]float64, error) {
+	replay, err := g.newReplay()
+	if err != nil {
+		return nil, err
+	}
+	d := g.position(player)
+	// Seeded from the position, so a seeded game shows the same chances
+	// every time it's played
+	base := streamSeed(g.seed + int64(g.round)<<20 + int64(g.turns))
+
+	type finished struct {
+		winner int
+		err    error
+	}
+	done := make(chan finished)
+	slots := make(chan struct{}, runtime.NumCPU())
+	for i := range rollouts {
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			winner, err := replay.playOut(d, nil, base+int64(i))
+			done <- finished{winner, err}
+		}()
+	}
+
+	wins := make([]int, len(g.players))
+	var firstErr error
+	for range rollouts {
+		f := <-done
+		if f.err != nil {
+			firstErr = f.err
+			continue
+		}
+		if f.winner >= 0 {
+			wins[f.winner]++
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	chances := make([]float64, len(wins))
+	for i, w := range wins {
+		chances[i] = float64(w) / float64(rollouts)
+	}
+	return chances, nil
+}
+
+// updateEvalBar works out the chances of winning before player's turn
+// and shows them, unless the TUI draws them in a panel of its own. A game
+// the chances can't be worked out for stops trying.
+func (g *Game) updateEvalBar(player PlayerInterface) {
+	chances, err := g.WinChances(player, evalRollouts)
+	if err != nil {
+		g.printf(T("Not showing the chances of winning: %v\n"), err)
+		g.evalBar = false
+		return
+	}
+	g.winChances = chances
+	if !g.evalPanel {
+		g.print(writeEvalBar(g.players, chances))
+	}
+}
+
+// writeEvalBar draws a bar per player of their chance of winning
+func writeEvalBar(players []PlayerInterface, chances []float64) string {
+	var sb strings.Builder
+	sb.WriteString(T("⚖️ Chances of winning:"))
+	sb.WriteString("\n")
+	for i, player := range players {
+		fmt.Fprintf(&sb, "  %-20s %s %3.0f%%\n", player.GetName(), evalBar(chances[i]), 100*chances[i])
+	}
+	return sb.String()
+}
+
+// evalBar draws a chance of winning as a bar evalBarWidth columns wide
+func evalBar(chance float64) string {
+	filled := min(int(chance*evalBarWidth+0.5), evalBarWidth)
+	return strings.Repeat("▓", filled) + strings.Repeat("░", evalBarWidth-filled)
+}
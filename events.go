@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// EventType represents the different things that can happen during a game
+type EventType int
+
+const (
+	GameStarted EventType = iota
+	RoundStarted
+	CardDrawn
+	ActionPlayed
+	PlayerStayed
+	PlayerBusted
+	SecondChanceUsed
+	Flip7Achieved
+	PlayerScored
+	RoundEnded
+	GameEnded
+	DeckReshuffled
+	CardBurned
+	DeckPeeked
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case GameStarted:
+		return "GameStarted"
+	case RoundStarted:
+		return "RoundStarted"
+	case CardDrawn:
+		return "CardDrawn"
+	case ActionPlayed:
+		return "ActionPlayed"
+	case PlayerStayed:
+		return "PlayerStayed"
+	case PlayerBusted:
+		return "PlayerBusted"
+	case SecondChanceUsed:
+		return "SecondChanceUsed"
+	case Flip7Achieved:
+		return "Flip7Achieved"
+	case PlayerScored:
+		return "PlayerScored"
+	case RoundEnded:
+		return "RoundEnded"
+	case GameEnded:
+		return "GameEnded"
+	case DeckReshuffled:
+		return "DeckReshuffled"
+	case CardBurned:
+		return "CardBurned"
+	case DeckPeeked:
+		return "DeckPeeked"
+	}
+	return "Unknown"
+}
+
+// parseEventType looks up an event type by the name String gives it
+func parseEventType(name string) (EventType, bool) {
+	for t := GameStarted; t <= DeckPeeked; t++ {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
+
+// Event describes a single thing that happened in the game.
+// Player is the player the event is about (the drawer, the stayer, the
+// dealer for RoundStarted, the winner for GameEnded). Score is the round
+// score for PlayerStayed and PlayerScored, the total for GameEnded, the
+// cards in the deck after a DeckReshuffled and the cards looked at for
+// DeckPeeked. Target is set for action cards played on another player.
+// A CardBurned without a Card found no card left to burn.
+type Event struct {
+	Type   EventType
+	Round  int
+	Player PlayerInterface
+	Target PlayerInterface
+	Card   *Card
+	Score  int
+}
+
+// EventMessage is an event as the env protocol sends it, with players
+// named and the card written as parseCards reads it
+type EventMessage struct {
+	Type   string `json:"type"`
+	Round  int    `json:"round"`
+	Player string `json:"player,omitempty"`
+	Target string `json:"target,omitempty"`
+	Card   string `json:"card,omitempty"`
+	Score  int    `json:"score,omitempty"`
+}
+
+// Message returns the event as the env protocol sends it
+func (e Event) Message() EventMessage {
+	m := EventMessage{Type: e.Type.String(), Round: e.Round, Score: e.Score}
+	if e.Player != nil {
+		m.Player = e.Player.GetName()
+	}
+	if e.Target != nil {
+		m.Target = e.Target.GetName()
+	}
+	if e.Card != nil {
+		m.Card = formatCard(e.Card)
+	}
+	return m
+}
+
+// EventListener is called synchronously for every event the game emits
+type EventListener func(event Event)
+
+// Subscribe registers a listener on the game's event bus
+func (g *Game) Subscribe(listener EventListener) {
+	g.listeners = append(g.listeners, listener)
+}
+
+// emit sends an event to every subscribed listener
+func (g *Game) emit(event Event) {
+	event.Round = g.round
+	if !g.silentMode {
+		g.render(func(w io.Writer) { g.renderer.RenderEvent(w, event) })
+	}
+	for _, listener := range g.listeners {
+		listener(event)
+	}
+}
+
+// describeEvent returns a one-line description of an event
+func describeEvent(event Event) string {
+	name := func(p PlayerInterface) string {
+		if p == nil {
+			return "?"
+		}
+		return p.GetName()
+	}
+
+	switch event.Type {
+	case GameStarted:
+		return T("Game started")
+	case RoundStarted:
+		return fmt.Sprintf(T("Round %d started, %s deals"), event.Round, name(event.Player))
+	case CardDrawn:
+		return fmt.Sprintf(T("%s drew %s"), name(event.Player), event.Card)
+	case ActionPlayed:
+		return fmt.Sprintf(T("%s played %s on %s"), name(event.Player), event.Card, name(event.Target))
+	case PlayerStayed:
+		return fmt.Sprintf(T("%s stayed with %d"), name(event.Player), event.Score)
+	case PlayerBusted:
+		return fmt.Sprintf(T("%s busted on %s"), name(event.Player), event.Card)
+	case SecondChanceUsed:
+		return fmt.Sprintf(T("%s used Second Chance"), name(event.Player))
+	case Flip7Achieved:
+		return fmt.Sprintf(T("%s achieved FLIP 7!"), name(event.Player))
+	case PlayerScored:
+		return fmt.Sprintf(T("%s scored %d"), name(event.Player), event.Score)
+	case RoundEnded:
+		return fmt.Sprintf(T("Round %d ended"), event.Round)
+	case GameEnded:
+		return fmt.Sprintf(T("%s wins with %d points!"), name(event.Player), event.Score)
+	case DeckReshuffled:
+		return fmt.Sprintf(T("The discards were shuffled back into the deck (%d cards)"), event.Score)
+	case CardBurned:
+		if event.Card == nil {
+			return T("No card was left to burn")
+		}
+		return fmt.Sprintf(T("%s was burned"), event.Card)
+	case DeckPeeked:
+		return fmt.Sprintf(T("The top %d cards were peeked at"), event.Score)
+	}
+	return event.Type.String()
+}
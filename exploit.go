@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// counterFamily is a strategy with a parameter that an exploit search
+// tunes: first over a coarse grid, then in fine steps around the best
+// point of the grid
+type counterFamily struct {
+	name           string
+	from, to, step float64
+	fine           float64
+	format         string
+}
+
+// counterFamilies are the tunable counter-strategies, within the ranges
+// their config parameters allow
+var counterFamilies = []counterFamily{
+	{"play-to", 10, 60, 5, 1, "%.0f"},
+	{"gap-aware", 10, 60, 5, 1, "%.0f"},
+	{"bust-probability", 0.10, 0.50, 0.05, 0.01, "%.2f"},
+	{"risk-utility", 0.5, 5, 0.5, 0.1, "%.1f"},
+}
+
+// counterStrategies are the strategies without parameters that an
+// exploit search also tries
+var counterStrategies = []string{"expected-value", "hybrid", "gap-based", "optimal", "bayesian", "adaptive-bust"}
+
+// Counter is a counter-strategy and how it fared against the target
+type Counter struct {
+	Strategy string
+	Wins     int
+	Games    int
+}
+
+// rate is the counter's win rate and two standard errors around it
+func (c Counter) rate() (rate, margin float64) {
+	rate = float64(c.Wins) / float64(c.Games)
+	return rate, 2 * math.Sqrt(rate*(1-rate)/float64(c.Games))
+}
+
+// Exploit is a search for the best response to a target strategy.
+// Candidates are played against the target on the same deals, and the
+// best one is played again on fresh deals, since the best of many
+// candidates is flattered by luck.
+type Exploit struct {
+	Target     string
+	Games      int
+	Seed       int64
+	Score      int
+	Parallel   int
+	Candidates []Counter
+	Best       Counter
+}
+
+// RunExploit handles the exploit subcommand: it searches for the counter-
+// strategy that does best against a target strategy heads-up, to see how
+// exploitable the target is
+func RunExploit(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("exploit", flag.ContinueOnError)
+	target := fs.String("target", "", "Strategy to exploit, e.g. expert or play-to:25")
+	games := fs.Int("n", 500, "Games per candidate counter-strategy")
+	seed := fs.Int64("seed", 0, "Seed for the first deck (0 uses the clock)")
+	score := fs.Int("score", 200, "Points needed to win")
+	parallel := fs.Int("parallel", 1, "How many candidates to play at once (0 uses every CPU)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *target == "" {
+		return fmt.Errorf("usage: flip7 exploit -target STRATEGY [-n GAMES] [-seed N] [-score N] [-parallel N]")
+	}
+	if _, err := parseStrategy("target", *target); err != nil {
+		return err
+	}
+	if *games < 2 {
+		return fmt.Errorf("-n must be at least 2")
+	}
+	if *score < 1 {
+		return fmt.Errorf("-score must be positive")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	if *parallel <= 0 {
+		*parallel = runtime.NumCPU()
+	}
+
+	e := &Exploit{Target: *target, Games: *games, Seed: *seed, Score: *score, Parallel: *parallel}
+	report := NewGame()
+	report.SetOutput(out)
+	start := time.Now()
+	if err := e.Search(report); err != nil {
+		return err
+	}
+	report.printf(T("🔍 Searched %d counter-strategies in %.1fs\n"), len(e.Candidates), time.Since(start).Seconds())
+	report.displayExploit(e)
+	return nil
+}
+
+// Search plays the coarse grid and the fixed strategies, refines each
+// family around its best point, and replays the best counter on fresh
+// deals
+func (e *Exploit) Search(report *Game) error {
+	var grid []string
+	for _, f := range counterFamilies {
+		for v := f.from; v <= f.to+f.fine/2; v += f.step {
+			grid = append(grid, f.strategy(v))
+		}
+	}
+	grid = append(grid, counterStrategies...)
+	report.printf(T("🔍 Playing %d counter-strategies against %s, %d games each...\n"), len(grid), e.Target, e.Games)
+	if err := e.play(grid); err != nil {
+		return err
+	}
+
+	var refine []string
+	tried := make(map[string]bool)
+	for _, c := range e.Candidates {
+		tried[c.Strategy] = true
+	}
+	for _, f := range counterFamilies {
+		best, bestValue := -1.0, 0.0
+		for _, c := range e.Candidates {
+			value, ok := f.value(c.Strategy)
+			if rate, _ := c.rate(); ok && rate > best {
+				best, bestValue = rate, value
+			}
+		}
+		for v := bestValue - f.step + f.fine; v < bestValue+f.step-f.fine/2; v += f.fine {
+			s := f.strategy(v)
+			if v >= f.from-f.fine/2 && v <= f.to+f.fine/2 && !tried[s] {
+				refine = append(refine, s)
+				tried[s] = true
+			}
+		}
+	}
+	report.printf(T("🔍 Refining around the best of each family: %d more...\n"), len(refine))
+	if err := e.play(refine); err != nil {
+		return err
+	}
+
+	sort.SliceStable(e.Candidates, func(i, j int) bool { return e.Candidates[i].Wins > e.Candidates[j].Wins })
+	best := e.Candidates[0].Strategy
+	report.printf(T("🔍 Replaying %s on fresh deals...\n"), best)
+	fresh, err := e.duel(best, e.Seed+int64(e.Games))
+	if err != nil {
+		return err
+	}
+	e.Best = fresh
+	return nil
+}
+
+// strategy names the family's strategy with the given parameter
+func (f counterFamily) strategy(v float64) string {
+	return f.name + ":" + fmt.Sprintf(f.format, v)
+}
+
+// value returns the parameter of one of the family's strategies
+func (f counterFamily) value(s string) (float64, bool) {
+	name, param, ok := strings.Cut(s, ":")
+	if !ok || name != f.name {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(param, 64)
+	return v, err == nil
+}
+
+// play duels every strategy against the target, several at once
+func (e *Exploit) play(strategies []string) error {
+	type finished struct {
+		counter Counter
+		err     error
+	}
+	done := make(chan finished)
+	slots := make(chan struct{}, e.Parallel)
+	for _, s := range strategies {
+		go func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			c, err := e.duel(s, e.Seed)
+			done <- finished{c, err}
+		}()
+	}
+
+	var errs []error
+	for range strategies {
+		f := <-done
+		if f.err != nil {
+			errs = append(errs, f.err)
+			continue
+		}
+		e.Candidates = append(e.Candidates, f.counter)
+	}
+	// Finishing order depends on scheduling; the report shouldn't
+	sort.SliceStable(e.Candidates, func(i, j int) bool { return e.Candidates[i].Strategy < e.Candidates[j].Strategy })
+	return errors.Join(errs...)
+}
+
+// duel plays one counter-strategy against the target from the given seed
+func (e *Exploit) duel(strategy string, seed int64) (Counter, error) {
+	counter, err := newStrategyPlayer("C", strategy)
+	if err != nil {
+		return Counter{}, err
+	}
+	target, err := newStrategyPlayer("T", e.Target)
+	if err != nil {
+		return Counter{}, err
+	}
+	d := NewDuel([]PlayerInterface{counter, target})
+	if err := d.Play(e.Games, seed, e.Score); err != nil {
+		return Counter{}, fmt.Errorf("%s: %w", strategy, err)
+	}
+	return Counter{Strategy: strategy, Wins: d.Stats[0].Wins, Games: d.Games}, nil
+}
+
+// displayExploit shows the best counter-strategies and whether the best
+// one still beats the target on fresh deals
+func (g *Game) displayExploit(e *Exploit) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🔍 BEST RESPONSES TO %s - %d games each from seed %d\n"), e.Target, e.Games, e.Seed)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-30s %8s %10s %9s\n", T("COUNTER-STRATEGY"), T("WINS"), T("WIN RATE"), "± 2 SE")
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	for i, c := range e.Candidates {
+		if i == 10 {
+			g.resultf(T("... and %d more\n"), len(e.Candidates)-i)
+			break
+		}
+		rate, margin := c.rate()
+		g.resultf("%-30s %8d %9.1f%% %8.1f%%\n", c.Strategy, c.Wins, 100*rate, 100*margin)
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+
+	rate, margin := e.Best.rate()
+	g.resultf(T("On fresh deals %s won %.1f%% ± %.1f%% against %s\n"), e.Best.Strategy, 100*rate, 100*margin, e.Target)
+	switch {
+	case rate-margin > 0.5:
+		g.resultf(T("%s is exploitable: %s beats it %.1f%% to %.1f%%\n"), e.Target, e.Best.Strategy, 100*rate, 100*(1-rate))
+	default:
+		g.resultf(T("No counter-strategy searched beats %s; it looks near optimal against these\n"), e.Target)
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// castWidth and castHeight are the terminal size casts are played in
+const (
+	castWidth  = 80
+	castHeight = 30
+)
+
+// clearScreen clears the terminal and moves to the top left, between the
+// frames of a summary
+const clearScreen = "\x1b[2J\x1b[H"
+
+// Cast is an asciinema (v2) recording being built: text written at times
+// since the start, in a profile's symbols
+type Cast struct {
+	Title   string
+	profile *SymbolProfile
+	now     float64
+	frames  []castFrame
+}
+
+type castFrame struct {
+	at   float64
+	text string
+}
+
+// write adds text at the current time
+func (c *Cast) write(text string) {
+	text = strings.ReplaceAll(c.profile.Apply(text), "\n", "\r\n")
+	c.frames = append(c.frames, castFrame{c.now, text})
+}
+
+// pause moves the current time on
+func (c *Cast) pause(d time.Duration) {
+	c.now += d.Seconds()
+}
+
+// WriteTo writes the cast as asciinema reads it: a header line, then a
+// line per frame
+func (c *Cast) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	header, err := json.Marshal(struct {
+		Version int    `json:"version"`
+		Width   int    `json:"width"`
+		Height  int    `json:"height"`
+		Title   string `json:"title,omitempty"`
+	}{2, castWidth, castHeight, c.Title})
+	if err != nil {
+		return 0, err
+	}
+	buf.Write(header)
+	buf.WriteByte('\n')
+	for _, frame := range c.frames {
+		line, err := json.Marshal([]any{math.Round(frame.at*1000) / 1000, "o", frame.text})
+		if err != nil {
+			return 0, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return buf.WriteTo(w)
+}
+
+// RunExport handles the export subcommand. It turns a replay into an
+// asciinema cast for sharing: the whole game event by event, paced like
+// a watched game, or with -format summary a scoreboard per round.
+func RunExport(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "cast", "What to export: cast (the game event by event) or summary (a frame per round)")
+	output := fs.String("o", "", "File to write (default: the replay's name ending in .cast)")
+	speedName := fs.String("speed", "normal", "Pacing of the cast (instant, fast, normal, dramatic)")
+	themeName := fs.String("theme", "classic", "Color theme ("+strings.Join(append([]string{"none"}, ThemeNames()...), ", ")+")")
+	symbols := fs.String("symbols", "emoji", "Symbol profile (emoji, unicode, ascii)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: flip7 export [-format cast|summary] [-o FILE] [-speed S] [-theme T] [-symbols P] REPLAY.f7r")
+	}
+	speed, err := ParseSpeed(*speedName)
+	if err != nil {
+		return err
+	}
+	var renderer Renderer = PlainRenderer{}
+	if *themeName != "none" {
+		theme, ok := themes[*themeName]
+		if !ok {
+			return fmt.Errorf("unknown theme %q (available: none, %s)", *themeName, strings.Join(ThemeNames(), ", "))
+		}
+		renderer = NewANSIRenderer(theme)
+	}
+	profile, err := LookupSymbolProfile(*symbols)
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	replay, err := LoadReplay(path)
+	if err != nil {
+		return err
+	}
+	if len(replay.Events) == 0 {
+		return fmt.Errorf("replay %s has no events to export; record the game again", path)
+	}
+	players, err := NewGame().restorePlayers(replay.Players)
+	if err != nil {
+		return fmt.Errorf("replay %s %w", path, err)
+	}
+	events, err := replay.events(players)
+	if err != nil {
+		return fmt.Errorf("replay %s: %w", path, err)
+	}
+
+	names := make([]string, len(players))
+	for i, player := range players {
+		names[i] = player.GetName()
+	}
+	cast := &Cast{Title: "Flip 7: " + strings.Join(names, " vs "), profile: profile}
+	switch *format {
+	case "cast":
+		cast.playByPlay(replay, players, events, renderer, speed)
+	case "summary":
+		cast.summary(replay, players, events, renderer, speed)
+	default:
+		return fmt.Errorf("unknown format %q (available: cast, summary)", *format)
+	}
+
+	if *output == "" {
+		*output = strings.TrimSuffix(path, filepath.Ext(path)) + ".cast"
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if _, err := cast.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	report.printf(T("🎬 Wrote %s: %d rounds in %.0fs. Play it with: asciinema play %s\n"), *output, replay.rounds(), cast.now, *output)
+	return nil
+}
+
+// rounds returns how many rounds the recorded game lasted
+func (r *Replay) rounds() int {
+	rounds := 0
+	for _, m := range r.Events {
+		if m.Type == RoundEnded.String() {
+			rounds++
+		}
+	}
+	return rounds
+}
+
+// playByPlay tells the whole game the way it was narrated, pausing after
+// each event as long as a watched game would, and ends with the score
+// chart
+func (c *Cast) playByPlay(replay *Replay, players []PlayerInterface, events []Event, r Renderer, speed Speed) {
+	progression := ScoreProgression{Players: players}
+	for _, event := range events {
+		var sb strings.Builder
+		name := ""
+		if event.Player != nil {
+			name = event.Player.GetName()
+		}
+		switch event.Type {
+		case GameStarted:
+			fmt.Fprintf(&sb, T("\n🎮 Starting Flip 7! %s\n"), fmt.Sprintf(T("First to %d points wins!"), replay.WinningScore))
+		case RoundStarted:
+			sb.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+			sb.WriteString(r.Heading(fmt.Sprintf(T("🎯 ROUND %d"), event.Round)) + "\n")
+			sb.WriteString(strings.Repeat("=", 50) + "\n")
+			fmt.Fprintf(&sb, T("Dealer: %s\n\n"), name)
+		case CardDrawn:
+			fmt.Fprintf(&sb, T("   %s draws %s\n"), name, r.Card(event.Card))
+		case ActionPlayed:
+			target := name
+			if event.Target != nil {
+				target = event.Target.GetName()
+			}
+			sb.WriteString("   " + fmt.Sprintf(T("%s played %s on %s"), name, r.Card(event.Card), target) + "\n")
+		case PlayerStayed:
+			fmt.Fprintf(&sb, T("   %s stays with %d points\n"), name, event.Score)
+		case PlayerBusted:
+			sb.WriteString(r.Bust(fmt.Sprintf(T("   💥 %s busts and is out of the round!"), name)) + "\n")
+		case Flip7Achieved:
+			sb.WriteString(r.Flip7(fmt.Sprintf(T("   🎉 %s achieved FLIP 7!"), name)) + "\n")
+		case PlayerScored:
+			event.Player.(baser).base().TotalScore += event.Score
+		case RoundEnded:
+			r.RenderScoreboard(&sb, players)
+			progression.Rounds = append(progression.Rounds, event.Round)
+			progression.Totals = append(progression.Totals, totals(players))
+		case GameEnded:
+			gameOver := fmt.Sprintf(T("🎉 GAME OVER! %s wins with %d points! 🎉"), name, event.Score)
+			sb.WriteString("\n" + r.Heading(gameOver) + "\n")
+			if len(progression.Rounds) > 0 {
+				r.RenderProgression(&sb, &progression)
+			}
+		default:
+			sb.WriteString("   " + describeEvent(event) + "\n")
+		}
+		if sb.Len() > 0 {
+			c.write(sb.String())
+		}
+		c.pause(speed.delay(event))
+	}
+}
+
+// summary shows a frame per round: every player's total as a bar towards
+// the winning score, with what they made of the round
+func (c *Cast) summary(replay *Replay, players []PlayerInterface, events []Event, r Renderer, speed Speed) {
+	outcomes := make(map[PlayerInterface]string, len(players))
+	for _, event := range events {
+		switch event.Type {
+		case RoundStarted:
+			clear(outcomes)
+		case PlayerStayed:
+			outcomes[event.Player] = T("stayed")
+		case PlayerBusted:
+			outcomes[event.Player] = r.Bust(fmt.Sprintf(T("busted on %s"), event.Card))
+		case Flip7Achieved:
+			outcomes[event.Player] = r.Flip7(T("FLIP 7!"))
+		case PlayerScored:
+			p := event.Player.(baser).base()
+			p.TotalScore += event.Score
+			outcomes[event.Player] = strings.TrimSpace(fmt.Sprintf("+%-3d %s", event.Score, outcomes[event.Player]))
+		case RoundEnded:
+			var sb strings.Builder
+			sb.WriteString(clearScreen)
+			sb.WriteString(r.Heading(fmt.Sprintf(T("🎮 Flip 7 - %s"), fmt.Sprintf(T("First to %d points wins!"), replay.WinningScore))) + "\n\n")
+			sb.WriteString(r.Heading(fmt.Sprintf(T("🎯 ROUND %d"), event.Round)) + "\n\n")
+			for _, player := range players {
+				progress := min(float64(player.GetTotalScore())/float64(replay.WinningScore), 1)
+				fmt.Fprintf(&sb, "   %-20s %s %3d  %s\n", player.GetName(), evalBar(progress), player.GetTotalScore(), outcomes[player])
+			}
+			c.write(sb.String())
+			c.pause(max(speed.RoundEnd, speed.Flip7))
+		case GameEnded:
+			gameOver := fmt.Sprintf(T("🎉 GAME OVER! %s wins with %d points! 🎉"), event.Player.GetName(), event.Score)
+			c.write("\n" + r.Heading(gameOver) + "\n")
+			c.pause(speed.Flip7)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import "math/bits"
+
+// fastOuts is, for every hand of numbers as a bitmask, how many other
+// copies of its numbers the full card set has: the cards that would bust
+// it from a fresh deck
+var fastOuts = func() (outs [1 << 13]int) {
+	for mask := range outs {
+		for held := uint16(mask); held != 0; held &= held - 1 {
+			outs[mask] += numberCardCopies(bits.TrailingZeros16(held)) - 1
+		}
+	}
+	return outs
+}()
+
+// bustLimits is a bust-probability threshold precomputed for every count
+// of unseen cards: with n cards unseen, the hand hits while fewer than
+// limits[n] of them would bust it. It makes the same decisions as
+// comparing the bust probability to the threshold.
+type bustLimits [96]int
+
+// newBustLimits precomputes the limits for threshold p
+func newBustLimits(p float64) *bustLimits {
+	var limits bustLimits
+	for unseen := 1; unseen < len(limits); unseen++ {
+		for limits[unseen] <= unseen && float64(limits[unseen])/float64(unseen) < p {
+			limits[unseen]++
+		}
+	}
+	return &limits
+}
+
+// fastBustCards counts the unseen cards that would bust player, and the
+// unseen cards, without tallying everything unseen. A game's state
+// counts the cards left in its deck, whatever the deck holds, so the
+// hand's numbers are read from those. Without them the deck is taken to
+// be the full card set: the hand's outs in it, less the copies of its
+// numbers in the discard pile and in other players' hands.
+func fastBustCards(player PlayerInterface, gameState *GameState) (bust, unseen int) {
+	held := player.HeldNumbers()
+	if remaining := gameState.Remaining; remaining != nil {
+		for h := held; h != 0; h &= h - 1 {
+			bust += remaining.Numbers[bits.TrailingZeros16(h)]
+		}
+		return bust, remaining.Total
+	}
+	discarded := gameState.Discarded
+	if discarded == nil {
+		counts := countDeck(gameState.DiscardedCards)
+		discarded = &counts
+	}
+	bust = fastOuts[held&(1<<13-1)]
+	for h := held; h != 0; h &= h - 1 {
+		bust -= discarded.Numbers[bits.TrailingZeros16(h)]
+	}
+	unseen = standardCounts.Total - discarded.Total
+	for _, other := range gameState.Players {
+		numbers := other.GetHand()
+		if b, ok := other.(baser); ok {
+			p := b.base()
+			numbers = p.NumberCards
+			unseen -= len(p.NumberCards) + len(p.ModifierCards) + len(p.ActionCards)
+		} else {
+			unseen -= len(numbers)
+		}
+		if other == player {
+			continue
+		}
+		for _, card := range numbers {
+			if card.Type == NumberCard && held&(1<<card.Value) != 0 {
+				bust--
+			}
+		}
+	}
+	return bust, unseen
+}
+
+// FastBustProbability is PlayToBustProbability(p) played from
+// precomputed tables: the hand's outs by its bitmask, and the most of
+// them that may be left by the count of unseen cards. It decides exactly
+// as PlayToBustProbability does.
+func FastBustProbability(p float64) HitOrStayStrategy {
+	limits := newBustLimits(p)
+	return func(self PlayerInterface, gameState *GameState) bool {
+		bust, unseen := fastBustCards(self, gameState)
+		if unseen <= 0 || unseen >= len(limits) {
+			return PlayToBustProbability(p)(self, gameState)
+		}
+		return bust < limits[unseen]
+	}
+}
+
+// SetFastStrategies makes simulations play threshold strategies from
+// tables precomputed when the simulation starts, instead of working out
+// the bust probability for every decision. They decide exactly as
+// before, only faster.
+func (g *Game) SetFastStrategies(enabled bool) {
+	g.fastStrategies = enabled
+}
+
+// useFastStrategies switches the players with a bust-probability
+// strategy to its table-driven fast path
+func (g *Game) useFastStrategies(players []PlayerInterface) {
+	for _, player := range players {
+		p, ok := player.(*ComputerPlayer)
+		if !ok || p.Spec.Choice != 2 {
+			continue
+		}
+		p.HitOrStayStrategy = FastBustProbability(p.Spec.BustProbability)
+		if p.Spec.Noise > 0 {
+			p.HitOrStayStrategy = NoisyStrategy(p.HitOrStayStrategy, p.Spec.Noise)
+		}
+	}
+}
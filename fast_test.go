@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+// TestFastBustProbabilityOnAStackedDeck checks the fast path decides as
+// the exact one does when the deck isn't the full card set
+func TestFastBustProbabilityOnAStackedDeck(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetDeck(NewStackedDeck(mustParseCards("5 9 5 12 12 3 +4 freeze 7")...))
+	ada := NewComputerPlayer("Ada", AlwaysHitStrategy, nil, nil)
+	bo := NewComputerPlayer("Bo", AlwaysHitStrategy, nil, nil)
+	g.players = []PlayerInterface{ada, bo}
+	ada.AddCard(g.deck.DrawCard())
+	bo.AddCard(g.deck.DrawCard())
+	state := g.buildGameState()
+
+	// the other 5 busts Ada, among the 7 cards left
+	if bust, unseen := fastBustCards(ada, state); bust != 1 || unseen != 7 {
+		t.Fatalf("fastBustCards = %d of %d, want 1 of 7", bust, unseen)
+	}
+	for p := 0.05; p < 1; p += 0.05 {
+		fast, exact := FastBustProbability(p)(ada, state), PlayToBustProbability(p)(ada, state)
+		if fast != exact {
+			t.Errorf("at a threshold of %.2f the fast path decides to %s, the exact one to %s", p, hitOrStay(fast), hitOrStay(exact))
+		}
+	}
+}
+
+// TestFastBustProbabilityWithoutDeckCounts checks a state built by hand,
+// without the deck's counts, is read as the full card set
+func TestFastBustProbabilityWithoutDeckCounts(t *testing.T) {
+	players := table("Ada 0 12 11 10", "Bo 0 12 9")
+	state := &GameState{Players: players, DiscardedCards: mustParseCards("12 12 11 10 10 10")}
+	for p := 0.05; p < 1; p += 0.05 {
+		t.Run(fmt.Sprintf("%.2f", p), func(t *testing.T) {
+			fast, exact := FastBustProbability(p)(players[0], state), PlayToBustProbability(p)(players[0], state)
+			if fast != exact {
+				t.Errorf("the fast path decides to %s, the exact one to %s", hitOrStay(fast), hitOrStay(exact))
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixtureDir is where the strategy fixtures are kept
+const fixtureDir = "testdata/fixtures"
+
+// Fixture is a canned position for checking what a strategy decides,
+// without playing a game up to it. The position is written as in a
+// scenario: every player's total and hand, the discard pile, the cards
+// left in the deck if they matter, and the rules. Decides names the
+// computer player whose hit or stay decision is checked, the first one
+// if it's left out, and Strategy plays them with another strategy, as
+// -ai takes it. Players without a strategy play play-to, since the
+// others' strategies don't change the decision. Want is hit or stay.
+type Fixture struct {
+	Scenario `yaml:",inline"`
+	Name     string `yaml:"name"`
+	Decides  string `yaml:"decides"`
+	Strategy string `yaml:"strategy"`
+	Want     string `yaml:"want"`
+}
+
+// FixtureFile is a file of fixtures
+type FixtureFile struct {
+	Fixtures []Fixture `yaml:"fixtures"`
+}
+
+// decider returns the index of the player whose decision is checked
+func (f *Fixture) decider() (int, error) {
+	if f.Decides == "" {
+		if len(f.Players) == 0 {
+			return 0, fmt.Errorf("no players")
+		}
+		return 0, nil
+	}
+	i := slices.IndexFunc(f.Players, func(p ScenarioPlayer) bool { return p.Name == f.Decides })
+	if i < 0 {
+		return 0, fmt.Errorf("decides: no player named %q", f.Decides)
+	}
+	return i, nil
+}
+
+// Game sets up a silent game at the fixture's position, without
+// playing any of it, and returns it with the player who decides
+func (f *Fixture) Game() (*Game, PlayerInterface, error) {
+	i, err := f.decider()
+	if err != nil {
+		return nil, nil, err
+	}
+	s := f.Scenario
+	s.Players = slices.Clone(f.Players)
+	for j := range s.Players {
+		if s.Players[j].Strategy == "" && !s.Players[j].Human {
+			s.Players[j].Strategy = "play-to"
+		}
+	}
+	if f.Strategy != "" {
+		p, err := parseStrategy(s.Players[i].Name, f.Strategy)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.Players[i].PlayerConfig = p
+	}
+	if s.Players[i].Human {
+		return nil, nil, fmt.Errorf("%s is human; a fixture checks a computer player", s.Players[i].Name)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetSeed(1)
+	if err := s.Apply(g); err != nil {
+		return nil, nil, err
+	}
+	g.useScorer()
+	return g, g.players[i], nil
+}
+
+// GameState builds the state the deciding player sees at the fixture's
+// position, for checking a strategy by hand
+func (f *Fixture) GameState() (*GameState, PlayerInterface, error) {
+	g, player, err := f.Game()
+	if err != nil {
+		return nil, nil, err
+	}
+	return g.buildGameState(), player, nil
+}
+
+// Decide returns whether the deciding player hits at the fixture's
+// position
+func (f *Fixture) Decide() (bool, error) {
+	state, player, err := f.GameState()
+	if err != nil {
+		return false, err
+	}
+	if !player.IsActive() {
+		return false, fmt.Errorf("%s isn't active, so has nothing to decide", player.GetName())
+	}
+	return player.MakeHitStayDecision(context.Background(), state)
+}
+
+// Check reports whether the deciding player decides as the fixture
+// wants
+func (f *Fixture) Check() error {
+	var want bool
+	switch f.Want {
+	case "hit":
+		want = true
+	case "stay":
+	default:
+		return fmt.Errorf("want must be hit or stay, not %q", f.Want)
+	}
+	hit, err := f.Decide()
+	if err != nil {
+		return err
+	}
+	if hit != want {
+		return fmt.Errorf("want %s, got %s", f.Want, hitOrStay(hit))
+	}
+	return nil
+}
+
+// hitOrStay names a hit or stay decision
+func hitOrStay(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "stay"
+}
+
+// LoadFixtures reads a file of fixtures
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file FixtureFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading fixtures %s: %w", path, err)
+	}
+	return file.Fixtures, nil
+}
+
+// RunFixtures checks every strategy fixture in a directory, or the
+// files given, and reports each one that decides differently
+func RunFixtures(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("fixtures", flag.ContinueOnError)
+	dir := fs.String("dir", fixtureDir, "Directory holding the fixture files")
+	verbose := fs.Bool("v", false, "List the fixtures that pass too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		var err error
+		paths, err = filepath.Glob(filepath.Join(*dir, "*.yaml"))
+		if err != nil {
+			return err
+		}
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no fixture files in %s", *dir)
+	}
+
+	checked, failures := 0, 0
+	for _, path := range paths {
+		fixtures, err := LoadFixtures(path)
+		if err != nil {
+			return err
+		}
+		for i, f := range fixtures {
+			name := f.Name
+			if name == "" {
+				name = fmt.Sprintf("fixture %d", i+1)
+			}
+			checked++
+			if err := f.Check(); err != nil {
+				failures++
+				fmt.Fprintf(out, "FAIL %s: %s: %v\n", path, name, err)
+				continue
+			}
+			if *verbose {
+				fmt.Fprintf(out, "ok   %s: %s\n", path, name)
+			}
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d fixtures failed", failures, checked)
+	}
+	fmt.Fprintf(out, "ok   %d fixtures\n", checked)
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// position writes a fixture as strategies.yaml would, one player to a
+// string: "name total hand...". The first player decides, playing
+// strategy as -ai takes it; "name=strategy" gives a player their own.
+func position(t testing.TB, strategy string, players ...string) Fixture {
+	t.Helper()
+	f := Fixture{Strategy: strategy}
+	for _, player := range players {
+		fields := strings.Fields(player)
+		if len(fields) < 2 {
+			t.Fatalf("player %q: want a name and a total", player)
+		}
+		name, spec, _ := strings.Cut(fields[0], "=")
+		config := PlayerConfig{Name: name}
+		if spec != "" {
+			var err error
+			if config, err = parseStrategy(name, spec); err != nil {
+				t.Fatalf("player %q: %v", player, err)
+			}
+		}
+		total, err := strconv.Atoi(fields[1])
+		if err != nil {
+			t.Fatalf("player %q: %v", player, err)
+		}
+		f.Players = append(f.Players, ScenarioPlayer{
+			PlayerConfig: config,
+			Total:        total,
+			Hand:         strings.Join(fields[2:], " "),
+		})
+	}
+	return f
+}
+
+// fixtureState sets up the fixture's position and returns the state the
+// deciding player sees, with that player
+func fixtureState(t testing.TB, f Fixture) (*GameState, PlayerInterface) {
+	t.Helper()
+	state, player, err := f.GameState()
+	if err != nil {
+		t.Fatalf("setting up the position: %v", err)
+	}
+	return state, player
+}
+
+// assertDecision checks that player decides want, hit or stay, at state
+func assertDecision(t testing.TB, player PlayerInterface, state *GameState, want string) {
+	t.Helper()
+	hit, err := player.MakeHitStayDecision(context.Background(), state)
+	if err != nil {
+		t.Fatalf("%s deciding: %v", player.GetName(), err)
+	}
+	if got := hitOrStay(hit); got != want {
+		t.Errorf("%s decided to %s, want %s", player.GetName(), got, want)
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+const (
+	// fuzzMaxRounds is how many rounds a fuzzed game may take before it's
+	// considered stuck
+	fuzzMaxRounds = 1000
+	// fuzzMaxDraws is how many cards a single round may draw before it's
+	// considered stuck
+	fuzzMaxDraws = 1000
+)
+
+// invariantError is raised from inside a fuzzed game when a rule invariant
+// breaks, so the game stops at the first bad event
+type invariantError struct {
+	msg string
+}
+
+func (e invariantError) Error() string {
+	return e.msg
+}
+
+// RunFuzz plays many games between randomly configured computer players,
+// with some random hit/stay noise, and checks the engine's invariants
+// after every event: cards are never created or lost, the deck's tally
+// matches its cards, the standings rank everyone in order, scores are
+// never negative, and every round and game ends. Each failure prints the
+// seed that reproduces it. FuzzEngine plays the same games under go test
+// -fuzz.
+func RunFuzz(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("fuzz", flag.ContinueOnError)
+	count := fs.Int("n", 1000, "Number of games to play")
+	seed := fs.Int64("seed", 0, "Seed for the first game; game i uses seed+i (0 uses the clock)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	failures := 0
+	for i := 0; i < *count; i++ {
+		gameSeed := *seed + int64(i)
+		if err := fuzzGame(gameSeed); err != nil {
+			failures++
+			fmt.Fprintf(out, "FAIL seed %d: %v\n", gameSeed, err)
+			fmt.Fprintf(out, "     reproduce with: flip7 fuzz -seed %d -n 1\n", gameSeed)
+		}
+	}
+
+	fmt.Fprintf(out, "%d games, %d failures (first seed %d)\n", *count, failures, *seed)
+	if failures > 0 {
+		return fmt.Errorf("%d of %d games broke an invariant", failures, *count)
+	}
+	return nil
+}
+
+// fuzzGame plays one randomly configured game and reports the first
+// broken invariant, engine error or panic
+func fuzzGame(seed int64) (err error) {
+	r := rand.New(rand.NewSource(seed))
+	SeedRandom(seed)
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetSeed(seed)
+	g.SetWinningScore(50 + r.Intn(251))
+
+	numPlayers := 2 + r.Intn(17)
+	for i := 0; i < numPlayers; i++ {
+		spec := randomSpec(r)
+		suffix, _, _, _ := buildStrategy(spec)
+		g.players = append(g.players, NewComputerPlayerFromSpec(fmt.Sprintf("P%d%s", i+1, suffix), spec))
+	}
+	g.Subscribe(fuzzInvariants(g))
+
+	defer func() {
+		switch p := recover().(type) {
+		case nil:
+		case invariantError:
+			err = fmt.Errorf("%d players: %w", numPlayers, p)
+		default:
+			err = fmt.Errorf("%d players, round %d: panic: %v", numPlayers, g.round, p)
+		}
+	}()
+	if err := g.Run(context.Background()); err != nil {
+		return fmt.Errorf("%d players, round %d: %w", numPlayers, g.round, err)
+	}
+	return nil
+}
+
+// randomSpec picks a strategy with random parameters, sometimes with noise
+// so decisions stray from what the strategy would do
+func randomSpec(r *rand.Rand) StrategySpec {
+	spec := StrategySpec{
+		Choice:          1 + r.Intn(11),
+		TargetScore:     1 + r.Intn(100),
+		BustProbability: 0.1 + 0.4*r.Float64(),
+		GapTolerance:    r.Intn(50),
+		SlackFactor:     r.Intn(10),
+	}
+	if r.Intn(2) == 0 {
+		spec.Noise = r.Float64()
+	}
+	return spec
+}
+
+// fuzzInvariants returns a listener that panics with an invariantError as
+// soon as the game breaks one of its rules
+func fuzzInvariants(g *Game) EventListener {
+	draws := 0
+	totals := make(map[PlayerInterface]int)
+
+	fail := func(format string, args ...any) {
+		panic(invariantError{fmt.Sprintf(format, args...)})
+	}
+	cardsInPlay := func() int {
+		n := g.deck.CardsLeft() + len(g.deck.Discards())
+		for _, p := range g.players {
+			n += len(p.GetHand())
+		}
+		return n
+	}
+
+	return func(event Event) {
+		switch event.Type {
+		case RoundStarted:
+			draws = 0
+			if event.Round > fuzzMaxRounds {
+				fail("game still going after %d rounds", fuzzMaxRounds)
+			}
+			if n := cardsInPlay(); n != g.deck.Size() {
+				fail("round %d started with %d cards, deck has %d", event.Round, n, g.deck.Size())
+			}
+		case CardDrawn:
+			draws++
+			if draws > fuzzMaxDraws {
+				fail("round %d still going after %d draws", event.Round, draws)
+			}
+			if counts := countDeck(g.deck.Cards()); counts != *g.deck.Counts() {
+				fail("deck tally %+v doesn't match its cards %+v", *g.deck.Counts(), counts)
+			}
+			if err := checkStandings(g.players); err != nil {
+				fail("round %d: %v", event.Round, err)
+			}
+		case DeckReshuffled:
+			if n := len(g.deck.Discards()); n > 0 {
+				fail("round %d reshuffled with %d cards left in the discards", event.Round, n)
+			}
+			if g.reshuffle != ReshuffleMidRound {
+				for _, p := range g.players {
+					if len(p.GetHand()) > 0 {
+						fail("round %d reshuffled mid-round under %s", event.Round, g.reshuffle)
+					}
+				}
+			}
+		case CardBurned:
+			if event.Card == nil {
+				if g.deck.CardsLeft() > 0 {
+					fail("round %d found no card to burn with %d left", event.Round, g.deck.CardsLeft())
+				}
+				break
+			}
+			// Burning the last card reshuffles it straight back in
+			discards := g.deck.Discards()
+			if len(discards) == 0 && !slices.Contains(g.deck.Cards(), event.Card) ||
+				len(discards) > 0 && discards[len(discards)-1] != event.Card {
+				fail("round %d burned %s but it isn't on top of the discards", event.Round, event.Card)
+			}
+		case PlayerScored:
+			if event.Score < 0 {
+				fail("%s scored %d", event.Player.GetName(), event.Score)
+			}
+		case RoundEnded:
+			if n := cardsInPlay(); n != g.deck.Size() {
+				fail("round %d ended with %d cards, deck has %d", event.Round, n, g.deck.Size())
+			}
+			for _, p := range g.players {
+				if p.IsActive() {
+					fail("round %d ended with %s still active", event.Round, p.GetName())
+				}
+				var held uint16
+				for _, card := range p.(baser).base().NumberCards {
+					held |= 1 << card.Value
+				}
+				if held != p.HeldNumbers() {
+					fail("%s holds numbers %013b but its mask says %013b", p.GetName(), held, p.HeldNumbers())
+				}
+				if p.GetTotalScore() < totals[p] {
+					fail("%s's total went down from %d to %d", p.GetName(), totals[p], p.GetTotalScore())
+				}
+				totals[p] = p.GetTotalScore()
+			}
+		case GameEnded:
+			if _, points := g.condition().(FirstToPoints); points && event.Score < g.winningScore {
+				fail("game ended with %s on %d, below %d", event.Player.GetName(), event.Score, g.winningScore)
+			}
+		}
+	}
+}
+
+// checkStandings checks the standings rank every player once, best
+// first, with places that only tie on level scores, and that the leader
+// and last place shortcuts agree with them
+func checkStandings(players []PlayerInterface) error {
+	standings := Standings(players, true)
+	if len(standings) != len(players) {
+		return fmt.Errorf("standings rank %d of %d players", len(standings), len(players))
+	}
+	seen := make(map[PlayerInterface]bool, len(players))
+	for i, s := range standings {
+		name := s.Player.GetName()
+		if seen[s.Player] {
+			return fmt.Errorf("standings rank %s twice", name)
+		}
+		seen[s.Player] = true
+		if s.Projected != s.Total+s.Round {
+			return fmt.Errorf("%s projected %d, want %d+%d", name, s.Projected, s.Total, s.Round)
+		}
+		want := 1
+		if i > 0 {
+			switch c := compareStandings(standings[i-1], s); {
+			case c > 0:
+				return fmt.Errorf("%s ranked below %s", standings[i-1].Player.GetName(), name)
+			case c == 0:
+				want = standings[i-1].Place
+			default:
+				want = i + 1
+			}
+		}
+		if s.Place != want {
+			return fmt.Errorf("%s placed %d, want %d", name, s.Place, want)
+		}
+	}
+	if len(standings) == 0 {
+		return nil
+	}
+	if leader := leaderOf(players); leader != standings[0].Player {
+		return fmt.Errorf("leader is %s, standings have %s first", leader.GetName(), standings[0].Player.GetName())
+	}
+	if last := lastPlaceOf(players); last != standings[len(standings)-1].Player {
+		return fmt.Errorf("last place is %s, standings have %s last", last.GetName(), standings[len(standings)-1].Player.GetName())
+	}
+	return nil
+}
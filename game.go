@@ -2,11 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"os"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,29 +20,202 @@ import (
 // Game represents the main game state
 type Game struct {
 	players    []PlayerInterface
-	deck       *Deck
+	deck       DeckInterface
 	round      int
 	dealerIdx  int
 	scanner    *bufio.Scanner
 	debugMode  bool
 	silentMode bool
+	out        io.Writer
+	renderer   Renderer
+	listeners  []EventListener
+	verbosity  Verbosity
+	logger     *slog.Logger
+	speed      Speed
+	history    []Event
+	// progression is every player's total after each round this game
+	progression ScoreProgression
+	// replay records the game for analysis when recordFile is set
+	recordFile string
+	replay     *Replay
+	// winChances are every player's chances of winning before the latest
+	// turn, shown when evalBar is set; evalPanel means the TUI draws them
+	winChances []float64
+	evalPanel  bool
+
+	roster         []RosterEntry
+	namePool       []RosterEntry
+	extraComputers int
+
+	winningScore int
+	winSpec      WinSpec
+	winCondition WinCondition
+	scorer       Scorer
+	cardSet      CardSet
+	seed         int64
+	seeded       bool
+
+	now func() time.Time
+
+	cardCounting  bool
+	liveStandings bool
+	evalBar       bool
+	commentary    bool
+	holdActions   bool
+	reshuffle     ReshufflePolicy
+	burnCards     int
+	undoEnabled   bool
+	undoStack     []engineState
+
+	// Set by a scenario that starts part way through a round
+	midRound  bool
+	startTurn int
+
+	// pooled is the GameState every decision shares in a simulation
+	pooled *GameState
+	// deciding is the decision being waited on, for LegalActions
+	deciding pendingDecision
+
+	// turns counts the turns taken so far this round
+	turns int
+
+	// rng makes the computer players' random choices, and rngSeed is
+	// the seed seedRandom derived it from
+	rng     *rand.Rand
+	rngSeed int64
+	// banter picks banter lines and computer names, apart from rng so
+	// neither changes the computer players' choices
+	banter *rand.Rand
+
+	// metrics counts the game for monitoring, when it's served
+	metrics *Metrics
+	// spans records the game's trace, when tracing is on
+	spans *SpanRecorder
+
+	// Simulation options
+	duplicate      bool
+	seatStats      bool
+	actionStats    bool
+	histograms     bool
+	decisions      *DecisionLog
+	decisionsFile  string
+	lengthStats    bool
+	scoresFile     string
+	rotateSeats    bool
+	freshLearning  bool
+	fastStrategies bool
+	dealerMode     DealerMode
+	budget         time.Duration
+	checkpoint     string
+	results        string
+	report         string
+	params         string
+	stopRule       *StopRule
 }
 
 // NewGame creates a new Flip 7 game instance
 func NewGame() *Game {
-	return &Game{
-		players:   make([]PlayerInterface, 0),
-		deck:      NewDeck(),
-		round:     1,
-		scanner:   bufio.NewScanner(os.Stdin),
-		debugMode: false,
+	g := &Game{
+		players:      make([]PlayerInterface, 0),
+		deck:         NewDeck(),
+		round:        1,
+		scanner:      bufio.NewScanner(os.Stdin),
+		debugMode:    false,
+		out:          os.Stdout,
+		renderer:     PlainRenderer{},
+		verbosity:    Normal,
+		logger:       NewConsoleLogger(os.Stdout, Normal),
+		commentary:   true,
+		winningScore: 200,
+		now:          time.Now,
+		rng:          rng,
+		banter:       banterRng,
+		metrics:      gameMetrics,
 	}
+	if gameTracer != nil {
+		g.spans = &SpanRecorder{tracer: gameTracer}
+	}
+	g.deck.SetLogger(g.logger)
+	g.Subscribe(g.logEvent)
+	g.Subscribe(g.recordHistory)
+	g.Subscribe(g.clearUndo)
+	g.Subscribe(g.recapRound)
+	g.Subscribe(g.trackProgression)
+	g.Subscribe(g.recordReplay)
+	g.Subscribe(g.react)
+	g.Subscribe(g.commentate)
+	return g
+}
+
+// SetSpeed sets the pacing used when spectating an AI-only game
+func (g *Game) SetSpeed(speed Speed) {
+	g.speed = speed
+}
+
+// SetRenderer sets how cards and highlights are decorated in the output
+func (g *Game) SetRenderer(r Renderer) {
+	g.renderer = r
+}
+
+// SetInput sets where the game reads interactive input from
+func (g *Game) SetInput(r io.Reader) {
+	g.scanner = bufio.NewScanner(r)
+	g.deck.SetDebugMode(g.debugMode, g.scanner)
+	for _, player := range g.players {
+		if human, ok := player.(*HumanPlayer); ok {
+			human.scanner = g.scanner
+		}
+	}
+}
+
+// SetOutput sets where the game writes its output
+func (g *Game) SetOutput(w io.Writer) {
+	g.out = w
+	g.logger = NewConsoleLogger(w, g.verbosity)
+	g.deck.SetOutput(w)
+	for _, player := range g.players {
+		if human, ok := player.(*HumanPlayer); ok {
+			human.out = w
+		}
+	}
+	g.deck.SetLogger(g.logger)
+}
+
+// SetVerbosity sets how much output the game produces
+func (g *Game) SetVerbosity(v Verbosity) {
+	g.verbosity = v
+	g.logger = NewConsoleLogger(g.out, v)
+	g.deck.SetLogger(g.logger)
 }
 
 // SetDebugMode enables or disables debug mode
 func (g *Game) SetDebugMode(debug bool) {
 	g.debugMode = debug
 	g.deck.SetDebugMode(debug, g.scanner)
+	g.deck.SetOutput(g.out)
+}
+
+// SetSeed makes the game deal from a deck shuffled with a fixed seed.
+// Each further simulated game deals from the next seed.
+func (g *Game) SetSeed(seed int64) {
+	g.seed = seed
+	g.seeded = true
+	g.deck.Seed(seed)
+}
+
+// SetDeck replaces the game's deck, e.g. with a StackedDeck that deals a
+// known sequence of cards
+func (g *Game) SetDeck(d DeckInterface) {
+	g.deck = d
+	g.deck.SetDebugMode(g.debugMode, g.scanner)
+	g.deck.SetOutput(g.out)
+	g.deck.SetLogger(g.logger)
+	g.deck.SetReshufflePolicy(g.reshuffle)
+}
+
+// SetWinningScore sets the total a player needs to win the game
+func (g *Game) SetWinningScore(score int) {
+	g.winningScore = score
 }
 
 // SetSilentMode enables or disables silent mode (no output)
@@ -45,43 +223,107 @@ func (g *Game) SetSilentMode(silent bool) {
 	g.silentMode = silent
 }
 
-// printf prints formatted output only when not in silent mode
+// printf logs formatted narration only when not in silent mode
 func (g *Game) printf(format string, args ...interface{}) {
-	if !g.silentMode {
-		fmt.Printf(format, args...)
+	if g.narrating() {
+		g.logger.Info(fmt.Sprintf(format, args...))
 	}
 }
 
-// println prints output only when not in silent mode
+// println logs narration only when not in silent mode
 func (g *Game) println(args ...interface{}) {
-	if !g.silentMode {
-		fmt.Println(args...)
+	if g.narrating() {
+		g.logger.Info(fmt.Sprintln(args...))
 	}
 }
 
-// print prints output only when not in silent mode
+// promptf writes interactive output, such as setup questions, menus and
+// answers to commands, straight to the console. It isn't narration, so
+// no verbosity hides it: a human has to see what they're answering.
+func (g *Game) promptf(format string, args ...any) {
+	fmt.Fprintf(g.out, format, args...)
+}
+
+// promptln writes an interactive line, as promptf does
+func (g *Game) promptln(args ...any) {
+	fmt.Fprintln(g.out, args...)
+}
+
+// prompt writes interactive output, as promptf does
+func (g *Game) prompt(args ...any) {
+	fmt.Fprint(g.out, args...)
+}
+
+// narrating reports whether narration is written at all
+func (g *Game) narrating() bool {
+	return !g.silentMode && g.renderer.Narrates()
+}
+
+// narrate runs write only when narration is written. Narration on the
+// hot paths is written inside it, so simulations neither format nor box
+// its arguments; the closure stays on the stack, so skipping it costs
+// nothing either.
+func (g *Game) narrate(write func()) {
+	if g.narrating() {
+		write()
+	}
+}
+
+// print logs narration only when not in silent mode
 func (g *Game) print(args ...interface{}) {
-	if !g.silentMode {
-		fmt.Print(args...)
+	if g.narrating() {
+		g.logger.Info(fmt.Sprint(args...))
+	}
+}
+
+// render has the renderer draw something, outside silent mode, and
+// writes it as one piece of narration
+func (g *Game) render(draw func(w io.Writer)) {
+	if g.silentMode {
+		return
+	}
+	var b strings.Builder
+	draw(&b)
+	if b.Len() > 0 {
+		g.logger.Info(b.String())
 	}
 }
 
+// show has the renderer draw interactive output, such as a scoreboard a
+// human asked for, straight to the console as promptf writes it
+func (g *Game) show(draw func(w io.Writer)) {
+	draw(g.out)
+}
+
 // Run starts the main game loop
-func (g *Game) Run() error {
-	// Setup players
-	if err := g.setupPlayers(); err != nil {
-		return err
+func (g *Game) Run(ctx context.Context) (err error) {
+	// Setup players unless they were restored from a save file
+	if len(g.players) == 0 {
+		if err := g.setupPlayers(ctx); err != nil {
+			return err
+		}
 	}
+	if g.metrics != nil {
+		finished := g.metrics.trackGame(g)
+		defer func() { finished(err) }()
+	}
+	g.spans.startGame(g)
+	defer func() { g.spans.endGame(g, err) }()
+	g.useScorer()
 
-	g.println("\n🎮 Starting Flip 7! First to 200 points wins!")
+	g.printf(T("\n🎮 Starting Flip 7! %s\n"), g.condition().Goal(g))
+	g.emit(Event{Type: GameStarted})
 
 	// Main game loop
 	for !g.hasWinner() {
-		g.printf("\n" + strings.Repeat("=", 50))
-		g.printf("\n🎯 ROUND %d\n", g.round)
-		g.printf(strings.Repeat("=", 50) + "\n")
+		g.println("\n" + strings.Repeat("=", 50))
+		g.println(g.renderer.Heading(fmt.Sprintf(T("🎯 ROUND %d"), g.round)))
+		g.println(strings.Repeat("=", 50))
 
-		if err := g.playRound(); err != nil {
+		if err := g.playRound(ctx); err != nil {
+			if errors.Is(err, context.Canceled) && g.hasHumans() {
+				return g.interrupted()
+			}
 			return err
 		}
 
@@ -90,27 +332,32 @@ func (g *Game) Run() error {
 	}
 
 	winner := g.getWinner()
-	g.printf("\n🎉 GAME OVER! %s wins with %d points! 🎉\n", winner.GetName(), winner.GetTotalScore())
+	gameOver := fmt.Sprintf(T("🎉 GAME OVER! %s wins with %d points! 🎉"), winner.GetName(), winner.GetTotalScore())
+	if a, ok := g.condition().(announcer); ok {
+		gameOver = a.announce(g, winner)
+	}
+	g.resultf("\n%s\n", g.renderer.Heading(gameOver))
+	g.emit(Event{Type: GameEnded, Player: winner, Score: winner.GetTotalScore()})
+	g.showProgression()
 
 	return nil
 }
 
 // Helper methods for input handling
-func (g *Game) getIntInput(min, max int) (int, error) {
+func (g *Game) getIntInput(ctx context.Context, min, max int) (int, error) {
 	for {
-		if !g.scanner.Scan() {
-			return 0, fmt.Errorf("failed to read input")
+		input, err := g.getStringInput(ctx)
+		if err != nil {
+			return 0, err
 		}
-
-		input := strings.TrimSpace(g.scanner.Text())
 		num, err := strconv.Atoi(input)
 		if err != nil {
-			g.printf("Please enter a valid number between %d and %d: ", min, max)
+			g.promptf(T("Please enter a valid number between %d and %d: "), min, max)
 			continue
 		}
 
 		if num < min || num > max {
-			g.printf("Please enter a number between %d and %d: ", min, max)
+			g.promptf(T("Please enter a number between %d and %d: "), min, max)
 			continue
 		}
 
@@ -118,49 +365,59 @@ func (g *Game) getIntInput(min, max int) (int, error) {
 	}
 }
 
-func (g *Game) getStringInput() (string, error) {
-	if !g.scanner.Scan() {
+func (g *Game) getStringInput(ctx context.Context) (string, error) {
+	ok, err := scanContext(ctx, g.scanner)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
 		return "", fmt.Errorf("failed to read input")
 	}
 	return strings.TrimSpace(g.scanner.Text()), nil
 }
 
+// hasWinner reports whether the win condition has ended the game
 func (g *Game) hasWinner() bool {
-	for _, player := range g.players {
-		if player.GetTotalScore() >= 200 {
-			return true
-		}
-	}
-	return false
+	return g.condition().Over(g)
 }
 
+// getWinner returns the winner of a finished game
 func (g *Game) getWinner() PlayerInterface {
-	var winner PlayerInterface
-	maxScore := -1
+	return g.condition().Winner(g)
+}
 
-	for _, player := range g.players {
-		if player.GetTotalScore() > maxScore {
-			maxScore = player.GetTotalScore()
-			winner = player
-		}
-	}
+// showScores has the renderer draw the scoreboard
+func (g *Game) showScores() {
+	g.render(g.drawScores)
+}
 
-	return winner
+// drawScores draws the scoreboard
+func (g *Game) drawScores(w io.Writer) {
+	g.renderer.RenderScoreboard(w, g.players)
 }
 
-func (g *Game) showScores() {
-	g.println("\n📊 Current Scores:")
-	g.println(strings.Repeat("-", 40))
-	for _, player := range g.players {
-		icon := player.GetPlayerIcon()
-		g.printf("%s %-20s: %3d points\n", icon, player.GetName(), player.GetTotalScore())
+// nextDealer returns the seat after the dealer's, passing over players
+// knocked out of the game
+func (g *Game) nextDealer() int {
+	next := g.dealerIdx
+	for range g.players {
+		next = (next + 1) % len(g.players)
+		if !g.players[next].(baser).base().Eliminated {
+			break
+		}
 	}
-	g.println(strings.Repeat("-", 40))
+	return next
 }
 
 func (g *Game) nextRound() {
 	g.round++
-	g.dealerIdx = (g.dealerIdx + 1) % len(g.players)
+	if e, ok := g.condition().(eliminator); ok {
+		if out := e.eliminate(g); out != nil {
+			out.(baser).base().Eliminated = true
+			g.printf(T("❌ %s is knocked out with %d points\n"), out.GetName(), out.GetTotalScore())
+		}
+	}
+	g.dealerIdx = g.nextDealer()
 
 	// Reset players for new round
 	for _, player := range g.players {
@@ -170,17 +427,17 @@ func (g *Game) nextRound() {
 		}
 	}
 
-	totalCards := g.deck.CardsLeft() + len(g.deck.discards)
+	totalCards := g.deck.CardsLeft() + len(g.deck.Discards())
 	for _, player := range g.players {
 		totalCards += len(player.GetHand())
 	}
 
-	if totalCards != g.deck.OriginalTotal {
+	if totalCards != g.deck.Size() {
 		totals := map[string]int{}
-		for _, card := range g.deck.cards {
+		for _, card := range g.deck.Cards() {
 			totals[card.String()]++
 		}
-		for _, card := range g.deck.discards {
+		for _, card := range g.deck.Discards() {
 			totals[card.String()]++
 		}
 		for _, player := range g.players {
@@ -189,31 +446,43 @@ func (g *Game) nextRound() {
 			}
 		}
 		g.println(totals)
-		panic(fmt.Sprintf("Total cards is not the original total. Cards are disappearing! found: %d != excpected: %d", totalCards, g.deck.OriginalTotal))
+		panic(fmt.Sprintf("Total cards is not the original total. Cards are disappearing! found: %d != excpected: %d", totalCards, g.deck.Size()))
 	}
 }
 
-func (g *Game) playRound() error {
-	g.printf("Dealer: %s\n\n", g.players[g.dealerIdx].GetName())
-
-	// Deal initial cards
-	if err := g.dealInitialCards(); err != nil {
-		return err
+func (g *Game) playRound(ctx context.Context) (err error) {
+	g.turns = 0
+	g.spans.startRound(g)
+	defer func() { g.spans.endRound(g, err) }()
+	g.narrate(func() { g.printf(T("Dealer: %s\n\n"), g.players[g.dealerIdx].GetName()) })
+	g.emit(Event{Type: RoundStarted, Player: g.players[g.dealerIdx]})
+
+	// Deal initial cards, unless a scenario has already dealt them
+	if g.midRound {
+		g.midRound = false
+		g.showAllHands()
+	} else {
+		g.reshuffleForRound()
+		g.burnForRound()
+		if err := g.dealInitialCards(ctx); err != nil {
+			return err
+		}
 	}
 
 	// Play turns until round ends
-	if err := g.playTurns(); err != nil {
+	if err := g.playTurns(ctx); err != nil {
 		return err
 	}
 
 	// Calculate scores
 	g.calculateRoundScores()
+	g.emit(Event{Type: RoundEnded})
 
 	return nil
 }
 
-func (g *Game) dealInitialCards() error {
-	g.println("🃏 Dealing initial cards...")
+func (g *Game) dealInitialCards(ctx context.Context) error {
+	g.narrate(func() { g.println(T("🃏 Dealing initial cards...")) })
 
 	// Deal one card to each player
 	for i := 0; i < len(g.players); i++ {
@@ -225,21 +494,25 @@ func (g *Game) dealInitialCards() error {
 			continue
 		}
 
-		card := g.deck.DrawCard()
+		card, err := g.drawCard(player)
 		if card == nil {
-			return fmt.Errorf("deck is empty")
+			if err != nil {
+				return err
+			}
+			break
 		}
 
-		g.printf("   %s draws %s\n", player.GetName(), card.String())
+		g.narrate(func() { g.printf(T("   %s draws %s\n"), player.GetName(), g.renderer.Card(card)) })
+		g.emit(Event{Type: CardDrawn, Player: player, Card: card})
 
 		// Handle action cards immediately
 		if card.IsActionCard() {
-			if err := g.handleActionCard(player, card); err != nil {
+			if err := g.handleActionCard(ctx, player, card); err != nil {
 				return err
 			}
 		} else {
 			if err := player.AddCard(card); err != nil {
-				return g.handleCardAddError(player, card, err)
+				return g.handleCardAddError(ctx, player, card, err)
 			}
 		}
 	}
@@ -249,60 +522,101 @@ func (g *Game) dealInitialCards() error {
 	return nil
 }
 
-func (g *Game) playTurns() error {
+func (g *Game) playTurns(ctx context.Context) error {
+	start := g.startTurn
+	g.startTurn = 0
 	for g.hasActivePlayers() {
-		for i := 0; i < len(g.players); i++ {
+		for i := start; i < len(g.players); i++ {
 			playerIdx := (g.dealerIdx + 1 + i) % len(g.players)
 			player := g.players[playerIdx]
 
 			if !player.IsActive() {
 				continue
 			}
-
-			// Player must hit if they have no number cards
-			if !player.HasCards() {
-				g.printf("🎯 %s has no number cards and must HIT\n", player.GetName())
-				if err := g.playerHit(player); err != nil {
-					return err
-				}
-				continue
+			// A cancelled game stops between turns
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			g.turns++
+			if g.liveStandings {
+				g.narrate(g.showStandings)
+			}
+			if g.evalBar {
+				g.narrate(func() { g.updateEvalBar(player) })
 			}
 
-			// Ask player to hit or stay
-			choice, err := g.getPlayerChoice(player)
+			g.spans.startTurn(player)
+			err := g.playTurn(ctx, player)
+			g.spans.endTurn(player, err)
 			if err != nil {
 				return err
 			}
 
-			if choice == "h" {
-				if err := g.playerHit(player); err != nil {
-					return err
-				}
-			} else {
-				g.playerStay(player)
-			}
-
 			if !g.hasActivePlayers() {
 				break
 			}
 		}
+		start = 0
+	}
+
+	return nil
+}
+
+// playTurn plays one player's turn: a hit, or their hit/stay decision,
+// after playing a held action card if they choose to
+func (g *Game) playTurn(ctx context.Context, player PlayerInterface) error {
+	if g.deckSpent() {
+		return nil
+	}
+	if g.holdActions {
+		if err := g.offerHeldActions(ctx, player); err != nil {
+			return err
+		}
+		if !player.IsActive() || g.deckSpent() {
+			return nil
+		}
+	}
+
+	// Player must hit if they have no number cards
+	if !player.HasCards() {
+		g.narrate(func() { g.printf(T("🎯 %s has no number cards and must HIT\n"), player.GetName()) })
+		return g.playerHit(ctx, player)
+	}
+
+	// Ask player to hit or stay
+	choice, err := g.getPlayerChoice(ctx, player)
+	if err != nil {
+		return err
+	}
+	// The state editor may have ended their round while they decided
+	if !player.IsActive() {
+		return nil
 	}
 
+	if choice == "h" {
+		return g.playerHit(ctx, player)
+	}
+	g.playerStay(player)
 	return nil
 }
 
 func (g *Game) calculateRoundScores() {
-	g.println("📊 Calculating round scores...")
-	g.println(strings.Repeat("-", 40))
+	g.narrate(func() {
+		g.println(T("📊 Calculating round scores..."))
+		g.println(strings.Repeat("-", 40))
+	})
 
 	for _, player := range g.players {
 		roundScore := player.CalculateRoundScore()
 		player.AddToTotalScore()
+		g.emit(Event{Type: PlayerScored, Player: player, Score: roundScore})
 
-		g.printf("%s: %d points this round (Total: %d)\n",
-			player.GetName(), roundScore, player.GetTotalScore())
+		g.narrate(func() {
+			g.printf(T("%s: %d points this round (Total: %d)\n"),
+				player.GetName(), roundScore, player.GetTotalScore())
+		})
 	}
-	g.println(strings.Repeat("-", 40))
+	g.narrate(func() { g.println(strings.Repeat("-", 40)) })
 }
 
 // Helper methods for gameplay
@@ -316,22 +630,61 @@ func (g *Game) hasActivePlayers() bool {
 	return false
 }
 
+// showAllHands has the renderer draw every player's hand
 func (g *Game) showAllHands() {
-	if g.silentMode {
-		return
-	}
+	g.render(g.drawAllHands)
+}
 
+// drawAllHands draws every player's hand
+func (g *Game) drawAllHands(w io.Writer) {
 	for _, player := range g.players {
-		player.ShowHand()
+		g.renderer.RenderHand(w, player)
 	}
 }
 
-func (g *Game) getPlayerChoice(player PlayerInterface) (string, error) {
+func (g *Game) getPlayerChoice(ctx context.Context, player PlayerInterface) (string, error) {
+	if _, human := player.(*HumanPlayer); human {
+		if g.canUndo() {
+			g.pushUndoState(player)
+		}
+		if g.cardCounting {
+			g.showCardCount(player)
+		}
+	}
+
 	gameState := g.buildGameState()
-	shouldHit, err := player.MakeHitStayDecision(gameState)
+	var start time.Time
+	if g.metrics != nil || g.spans != nil {
+		start = time.Now()
+	}
+	done := g.decide(pendingDecision{player: player})
+	shouldHit, err := player.MakeHitStayDecision(ctx, gameState)
+	done()
 	if err != nil {
 		return "", err
 	}
+	if g.metrics != nil {
+		g.metrics.decided(time.Since(start))
+	}
+	g.spans.decision(player, gameState, start, shouldHit)
+	if g.decisions != nil {
+		g.decisions.record(g, player, gameState, shouldHit)
+	}
+	if g.replay != nil {
+		g.replay.record(g, player, shouldHit)
+	}
+
+	if g.tracing() {
+		g.trace("hit/stay decision",
+			"player", player.GetName(),
+			"hand", player.GetHandSummary(),
+			"round_score", player.CalculateRoundScore(),
+			"total", player.GetTotalScore(),
+			"leader", gameState.CurrentLeader.GetName(),
+			"bust_probability", fmt.Sprintf("%.3f", CalculateBustProbability(player, gameState)),
+			"cards_in_deck", g.deck.CardsLeft(),
+			"hit", shouldHit)
+	}
 
 	if shouldHit {
 		return "h", nil
@@ -340,20 +693,21 @@ func (g *Game) getPlayerChoice(player PlayerInterface) (string, error) {
 	}
 }
 
-func (g *Game) playerHit(player PlayerInterface) error {
-	card := g.deck.DrawCard()
+func (g *Game) playerHit(ctx context.Context, player PlayerInterface) error {
+	card, err := g.drawCard(player)
 	if card == nil {
-		return fmt.Errorf("deck is empty")
+		return err
 	}
 
-	g.printf("   %s draws %s\n", player.GetName(), card.String())
+	g.narrate(func() { g.printf(T("   %s draws %s\n"), player.GetName(), g.renderer.Card(card)) })
+	g.emit(Event{Type: CardDrawn, Player: player, Card: card})
 
 	if card.IsActionCard() {
-		return g.handleActionCard(player, card)
+		return g.handleActionCard(ctx, player, card)
 	}
 
 	if err := player.AddCard(card); err != nil {
-		return g.handleCardAddError(player, card, err)
+		return g.handleCardAddError(ctx, player, card, err)
 	}
 
 	return nil
@@ -362,65 +716,80 @@ func (g *Game) playerHit(player PlayerInterface) error {
 func (g *Game) playerStay(player PlayerInterface) {
 	player.Stay()
 	player.CalculateRoundScore()
-	g.printf("   %s stays with %d points\n", player.GetName(), player.CalculateRoundScore())
+	g.narrate(func() { g.printf(T("   %s stays with %d points\n"), player.GetName(), player.CalculateRoundScore()) })
+	g.emit(Event{Type: PlayerStayed, Player: player, Score: player.CalculateRoundScore()})
 }
 
-func (g *Game) handleActionCard(player PlayerInterface, card *Card) error {
-	g.printf("   🎲 Action card! %s\n", card.String())
+func (g *Game) handleActionCard(ctx context.Context, player PlayerInterface, card *Card) error {
+	g.narrate(func() { g.printf(T("   🎲 Action card! %s\n"), g.renderer.Card(card)) })
 
+	if g.holdActions && card.Action != SecondChance {
+		return g.holdActionCard(player, card)
+	}
+	return g.playActionCard(ctx, player, card)
+}
+
+// playActionCard carries out an action card for the player who played it
+func (g *Game) playActionCard(ctx context.Context, player PlayerInterface, card *Card) error {
 	switch card.Action {
 	case Freeze:
-		return g.handleFreezeCard(player, card)
+		return g.handleFreezeCard(ctx, player, card)
 	case FlipThree:
-		return g.handleFlipThreeCard(player, card)
+		return g.handleFlipThreeCard(ctx, player, card)
 	case SecondChance:
-		return g.handleSecondChanceCard(player, card)
+		return g.handleSecondChanceCard(ctx, player, card)
 	}
 
 	return nil
 }
 
-func (g *Game) handleFreezeCard(player PlayerInterface, card *Card) error {
-	target, err := g.chooseActionTarget(player, "Who should be frozen?", Freeze)
+func (g *Game) handleFreezeCard(ctx context.Context, player PlayerInterface, card *Card) error {
+	target, err := g.chooseActionTarget(ctx, player, Freeze)
 	if err != nil {
 		g.deck.DiscardCard(card) // Discard card even if target selection fails
 		return err
 	}
 
-	target.Stay()
-	target.CalculateRoundScore()
-	g.printf("   ❄️ %s is frozen and stays with %d points!\n", target.GetName(), target.CalculateRoundScore())
+	target.Freeze()
+	g.narrate(func() {
+		g.printf(T("   ❄️ %s is frozen and stays with %d points!\n"), target.GetName(), target.CalculateRoundScore())
+	})
+	g.emit(Event{Type: ActionPlayed, Player: player, Target: target, Card: card})
+	g.emit(Event{Type: PlayerStayed, Player: target, Score: target.CalculateRoundScore()})
 
 	g.deck.DiscardCard(card)
 	return nil
 }
 
-func (g *Game) handleFlipThreeCard(player PlayerInterface, card *Card) error {
-	target, err := g.chooseActionTarget(player, "Who should flip three cards?", FlipThree)
+func (g *Game) handleFlipThreeCard(ctx context.Context, player PlayerInterface, card *Card) error {
+	target, err := g.chooseActionTarget(ctx, player, FlipThree)
 	if err != nil {
 		g.deck.DiscardCard(card) // Discard card even if target selection fails
 		return err
 	}
 
-	g.printf("   🎲 %s must flip 3 cards!\n", target.GetName())
+	g.narrate(func() { g.printf(T("   🎲 %s must flip 3 cards!\n"), target.GetName()) })
+	g.emit(Event{Type: ActionPlayed, Player: player, Target: target, Card: card})
 
 	for i := 0; i < 3; i++ {
 		if !target.IsActive() {
 			break
 		}
 
-		drawnCard := g.deck.DrawCard()
+		drawnCard, _ := g.drawCard(target)
 		if drawnCard == nil {
 			break
 		}
 
-		g.printf("      Card %d: %s\n", i+1, drawnCard.String())
+		g.narrate(func() { g.printf(T("      Card %d: %s\n"), i+1, g.renderer.Card(drawnCard)) })
+		g.emit(Event{Type: CardDrawn, Player: target, Card: drawnCard})
 
 		if drawnCard.IsActionCard() {
 			// Handle nested action cards after all 3 cards are drawn
-			if err := g.handleActionCard(target, drawnCard); err != nil {
+			if err := g.handleActionCard(ctx, target, drawnCard); err != nil {
 				if strings.Contains(err.Error(), "flip7") {
-					g.printf("   🎉 %s achieved FLIP 7!\n", target.GetName())
+					g.println(g.renderer.Flip7(fmt.Sprintf(T("   🎉 %s achieved FLIP 7!"), target.GetName())))
+					g.emit(Event{Type: Flip7Achieved, Player: target})
 					g.endRoundForFlip7(target)
 					break // End the Flip Three loop
 				}
@@ -430,7 +799,7 @@ func (g *Game) handleFlipThreeCard(player PlayerInterface, card *Card) error {
 			}
 		} else {
 			if err := target.AddCard(drawnCard); err != nil {
-				if err := g.handleCardAddError(target, drawnCard, err); err != nil {
+				if err := g.handleCardAddError(ctx, target, drawnCard, err); err != nil {
 					return err
 				}
 				break
@@ -442,52 +811,87 @@ func (g *Game) handleFlipThreeCard(player PlayerInterface, card *Card) error {
 	return nil
 }
 
-func (g *Game) handleSecondChanceCard(player PlayerInterface, card *Card) error {
+func (g *Game) handleSecondChanceCard(ctx context.Context, player PlayerInterface, card *Card) error {
 	// Try to give it to the player who drew it first
 	if !player.HasSecondChance() {
-		g.printf("   🆘 %s receives a Second Chance card!\n", player.GetName())
+		g.narrate(func() { g.printf(T("   🆘 %s receives a Second Chance card!\n"), player.GetName()) })
 		if err := player.AddCard(card); err != nil {
 			g.deck.DiscardCard(card)
 			return err
 		}
+		g.emit(Event{Type: ActionPlayed, Player: player, Target: player, Card: card})
 		return nil
 	}
 
-	// Player already has second chance, need to give it to someone else
-	g.printf("   🆘 %s already has Second Chance, must give to another player\n", player.GetName())
-	target, err := player.ChoosePositiveActionTarget(g.buildGameState(), SecondChance)
-	if err != nil {
-		g.printf("   🆘 No one can take the Second Chance card, discarding\n")
+	return g.passSecondChance(ctx, player, card)
+}
+
+// passSecondChance has a player who already holds a Second Chance, and
+// can't hold two, pass the one they drew to someone still in the round
+// without one. The card is discarded when everyone left has one.
+func (g *Game) passSecondChance(ctx context.Context, player PlayerInterface, card *Card) error {
+	g.narrate(func() {
+		g.printf(T("   🆘 %s already has Second Chance, must give to another player\n"), player.GetName())
+	})
+	if len(g.buildGameState().LegalTargets(player, SecondChance)) == 0 {
+		g.narrate(func() { g.print(T("   🆘 Everyone left in the round has a Second Chance, so it's discarded\n")) })
 		g.deck.DiscardCard(card)
 		return nil
 	}
+	target, err := g.chooseActionTarget(ctx, player, SecondChance)
+	if err != nil {
+		g.deck.DiscardCard(card)
+		return err
+	}
 
 	if err := target.AddCard(card); err != nil {
 		g.deck.DiscardCard(card)
-		g.printf("   🆘 %s cannot take the Second Chance card, discarding\n", player.GetName())
+		g.narrate(func() { g.printf(T("   🆘 %s cannot take the Second Chance card, discarding\n"), target.GetName()) })
 		return nil
 	}
-
-	g.printf("   🆘 %s receives a Second Chance card!\n", target.GetName())
+	g.narrate(func() {
+		g.printf(T("   🆘 %s gives the Second Chance card to %s\n"), player.GetName(), target.GetName())
+	})
+	g.emit(Event{Type: ActionPlayed, Player: player, Target: target, Card: card})
 	return nil
 }
 
-func (g *Game) chooseActionTarget(player PlayerInterface, prompt string, actionType ActionType) (PlayerInterface, error) {
+// chooseActionTarget asks player who gets an action card, out of its
+// legal targets, and checks the answer is one of them
+func (g *Game) chooseActionTarget(ctx context.Context, player PlayerInterface, actionType ActionType) (PlayerInterface, error) {
+	done := g.decide(pendingDecision{player: player, target: true, action: actionType})
+	decision := ActionDecision{Action: actionType, Targets: moveTargets(g.LegalActions(player))}
 	gameState := g.buildGameState()
-	return player.ChooseActionTarget(gameState, actionType)
+	target, err := player.DecideAction(ctx, gameState, decision)
+	done()
+	if err == nil {
+		err = checkTarget(player, gameState, actionType, target)
+	}
+	if err == nil && g.tracing() {
+		g.trace("action target decision",
+			"player", player.GetName(),
+			"action", NewActionCard(actionType).String(),
+			"active_players", len(gameState.ActivePlayers),
+			"target", target.GetName())
+	}
+	return target, err
 }
 
-func (g *Game) handleCardAddError(player PlayerInterface, card *Card, err error) error {
+func (g *Game) handleCardAddError(ctx context.Context, player PlayerInterface, card *Card, err error) error {
 	if strings.Contains(err.Error(), "flip7") {
-		g.printf("   🎉 %s achieved FLIP 7 and wins the round!\n", player.GetName())
+		g.println(g.renderer.Flip7(fmt.Sprintf(T("   🎉 %s achieved FLIP 7 and wins the round!"), player.GetName())))
+		g.emit(Event{Type: Flip7Achieved, Player: player, Card: card})
 		// Mark all other players as non-active to end the round
 		g.endRoundForFlip7(player)
 		return nil // Don't propagate the error, just end the round
 	}
 
 	if strings.Contains(err.Error(), "duplicate_with_second_chance") {
-		g.printf("   💥 %s drew a duplicate %s but has Second Chance!\n", player.GetName(), card)
+		g.narrate(func() {
+			g.printf(T("   💥 %s drew a duplicate %s but has Second Chance!\n"), player.GetName(), g.renderer.Card(card))
+		})
 		secondChanceCard := player.UseSecondChance()
+		g.emit(Event{Type: SecondChanceUsed, Player: player, Card: card})
 		g.deck.DiscardCard(secondChanceCard) // Discard the second chance card
 		g.deck.DiscardCard(card)             // Discard the duplicate
 		return nil
@@ -495,41 +899,30 @@ func (g *Game) handleCardAddError(player PlayerInterface, card *Card, err error)
 
 	if strings.Contains(err.Error(), "bust") {
 		g.deck.DiscardCard(card) // Discard the duplicate
-		g.printf("   💥 %s busts and is out of the round!\n", player.GetName())
+		g.narrate(func() {
+			g.println(g.renderer.Bust(fmt.Sprintf(T("   💥 %s busts and is out of the round!"), player.GetName())))
+		})
+		g.emit(Event{Type: PlayerBusted, Player: player, Card: card})
 		return nil
 	}
 
 	if strings.Contains(err.Error(), "second_chance_duplicate") {
-		newTarget, err := player.ChoosePositiveActionTarget(g.buildGameState(), SecondChance)
-		if err != nil {
-			return err
-		}
-
-		if err := newTarget.AddCard(card); err != nil {
-			// Can't give second chance to anyone
-			g.printf("   🆘 %s cannot give the Second Chance card to anyone, discarding\n", player.GetName())
-			g.deck.DiscardCard(card)
-			return nil
-		} else {
-			g.printf("   🆘 %s gives the Second Chance card to %s\n", player.GetName(), newTarget.GetName())
-		}
-
-		return nil
+		return g.passSecondChance(ctx, player, card)
 	}
 
 	return err
 }
 
 // setupPlayers handles the initial player setup (human vs computer)
-func (g *Game) setupPlayers() error {
-	g.println("How many players total? (2-18): ")
-	numPlayers, err := g.getIntInput(2, 18)
+func (g *Game) setupPlayers(ctx context.Context) error {
+	g.promptln(T("How many players total? (2-18): "))
+	numPlayers, err := g.getIntInput(ctx, 2, 18)
 	if err != nil {
 		return err
 	}
 
-	g.printf("How many human players? (0-%d): ", numPlayers)
-	numHumans, err := g.getIntInput(0, numPlayers)
+	g.promptf(T("How many human players? (0-%d): "), numPlayers)
+	numHumans, err := g.getIntInput(ctx, 0, numPlayers)
 	if err != nil {
 		return err
 	}
@@ -538,46 +931,80 @@ func (g *Game) setupPlayers() error {
 
 	// Setup human players
 	for i := 0; i < numHumans; i++ {
-		g.printf("Enter name for Human Player %d: ", i+1)
-		name, err := g.getStringInput()
+		g.promptf(T("Enter name for Human Player %d: "), i+1)
+		name, err := g.getStringInput(ctx)
+		if err != nil {
+			return err
+		}
+		human := NewHumanPlayer(name, g.scanner, g.out)
+		human.SetCommandHandler(g.handleCommand)
+		g.players = append(g.players, human)
+	}
+
+	// Casual games pick one difficulty for every computer
+	var preset StrategySpec
+	usePreset := false
+	if numHumans > 0 && numComputers > 0 {
+		preset, usePreset, err = g.chooseDifficulty(ctx)
 		if err != nil {
 			return err
 		}
-		g.players = append(g.players, NewHumanPlayer(name, g.scanner))
 	}
 
 	// Setup computer players
+	g.resetNamePool()
 	for i := 0; i < numComputers; i++ {
-		name, strategy, actionTargetStrategy, positiveActionTargetStrategy, err := g.getComputerPlayerSetup(i + 1)
+		entry := g.nextComputer()
+		if usePreset {
+			suffix, _, _, _ := buildStrategy(preset)
+			computer := NewComputerPlayerFromSpec(entry.Name+suffix, preset)
+			computer.Icon = entry.Icon
+			g.players = append(g.players, computer)
+			continue
+		}
+
+		name, spec, err := g.getComputerPlayerSetup(ctx, i+1, entry)
 		if err != nil {
 			return err
 		}
-		g.players = append(g.players, NewComputerPlayer(name, strategy, actionTargetStrategy, positiveActionTargetStrategy))
-		g.printf("  → Added: %s (%s AI)\n", name, g.players[len(g.players)-1].GetName())
+		computer := NewComputerPlayerFromSpec(name, spec)
+		computer.Icon = entry.Icon
+		g.players = append(g.players, computer)
+		g.promptf(T("  → Added: %s (%s AI)\n"), name, g.players[len(g.players)-1].GetName())
 	}
 
 	if numHumans == 0 {
-		g.printf("\n🎮 Starting AI-only Flip 7 with %d computer players!\n", numComputers)
-		g.println("🍿 Sit back and watch the AIs battle it out!")
+		g.printf(T("\n🎮 Starting AI-only Flip 7 with %d computer players!\n"), numComputers)
+		g.println(T("🍿 Sit back and watch the AIs battle it out!"))
+
+		// A time budget or a stopping rule decides how many games to
+		// simulate
+		if g.budget > 0 || g.stopRule != nil {
+			g.SetSilentMode(true)
+			return g.runMultipleGames(ctx, 0)
+		}
 
 		// Ask for number of games to simulate
-		g.printf("\nHow many games would you like to simulate? ")
-		numGames, err := g.getIntInput(1, math.MaxInt)
+		g.prompt(T("\nHow many games would you like to simulate? "))
+		numGames, err := g.getIntInput(ctx, 1, math.MaxInt)
 		if err != nil {
 			return err
 		}
 
 		if numGames > 1 {
 			g.SetSilentMode(true)
-			return g.runMultipleGames(numGames)
+			return g.runMultipleGames(ctx, numGames)
 		}
+
+		// A single AI-only game is being watched, so pace it
+		g.Subscribe(NewPacer(g.speed).OnEvent)
 	} else {
-		g.printf("\n🎮 Starting Flip 7 with %d humans and %d computers!\n", numHumans, numComputers)
+		g.printf(T("\n🎮 Starting Flip 7 with %d humans and %d computers!\n"), numHumans, numComputers)
 	}
 	return nil
 }
 
-// getComputerPlayerSetup handles setup for a single computer player
+// computerNames are the default computer player names
 var computerNames = []string{
 	"HAL",
 	"Data",
@@ -599,53 +1026,54 @@ var computerNames = []string{
 	"Jeeves",
 }
 
-func (g *Game) getComputerPlayerSetup(computerNum int) (string, HitOrStayStrategy, ActionTargetStrategy, ActionTargetStrategy, error) {
-	nameIndex := rand.Intn(len(computerNames))
-	name := computerNames[nameIndex]
-	computerNames = slices.Delete(computerNames, nameIndex, nameIndex+1)
-
-	g.printf("\nComputer Player %d:\n", computerNum)
-	g.println("Choose AI strategy:")
-	g.println("  1) Plays to some score")
-	g.println("  2) Plays to against some bust probability threshold (counts cards)")
-	g.println("  3) FLIP 7 (always hits)")
-	g.println("  4) Random")
-	g.println("  5) Adaptive Bust Prob (0.3)")
-	g.println("  6) Expected Value")
-	g.println("  7) Hybrid Strategy")
-	g.println("  8) Gap-Based Strategy")
-	g.println("  9) Optimal Strategy")
-	g.println("  10) Bayesian Gain Strategy")
-	g.println("  11) Gap Aware Stragegy")
-
-	g.print("Enter choice (1-11): ")
-
-	choice, err := g.getIntInput(1, 11)
+// getComputerPlayerSetup handles setup for a single computer player. A
+// roster entry with a preferred strategy is used without asking.
+func (g *Game) getComputerPlayerSetup(ctx context.Context, computerNum int, entry RosterEntry) (string, StrategySpec, error) {
+	name := entry.Name
+	if entry.Strategy != nil {
+		suffix, _, _, _ := buildStrategy(*entry.Strategy)
+		return name + suffix, *entry.Strategy, nil
+	}
+
+	g.promptf(T("\nComputer Player %d:\n"), computerNum)
+	g.promptln(T("Choose AI strategy:"))
+	g.promptln(T("  1) Plays to some score"))
+	g.promptln(T("  2) Plays to against some bust probability threshold (counts cards)"))
+	g.promptln(T("  3) FLIP 7 (always hits)"))
+	g.promptln(T("  4) Random"))
+	g.promptln(T("  5) Adaptive Bust Prob (0.3)"))
+	g.promptln(T("  6) Expected Value"))
+	g.promptln(T("  7) Hybrid Strategy"))
+	g.promptln(T("  8) Gap-Based Strategy"))
+	g.promptln(T("  9) Optimal Strategy"))
+	g.promptln(T("  10) Bayesian Gain Strategy"))
+	g.promptln(T("  11) Gap Aware Stragegy"))
+	g.promptln(T("  12) Solved Table"))
+	g.promptln(T("  13) Bandit (learns which strategy to follow)"))
+	g.promptln(T("  14) Risk Utility (protects what it has)"))
+	g.promptln(T("  15) Imitate (plays like recorded human games)"))
+
+	g.prompt(T("Enter choice (1-15): "))
+
+	choice, err := g.getIntInput(ctx, 1, 15)
 	if err != nil {
 		choice = 6
 	}
 
-	var strategy HitOrStayStrategy
-	var actionTargetStrategy ActionTargetStrategy
-	var positiveActionTargetStrategy ActionTargetStrategy
-	var targetScore int
-	var bustProbabilityThreshold float64
-	var gapTolerance int
-	var slackFactor int
+	spec := StrategySpec{Choice: choice}
 
 	if choice == 1 {
-		g.print("Enter Target Score: ")
-		score, err := g.getIntInput(1, 100)
+		g.prompt(T("Enter Target Score: "))
+		score, err := g.getIntInput(ctx, 1, 100)
 		if err != nil {
 			score = 30
 		}
-		strategy = PlayRoundTo(score)
-		targetScore = score
+		spec.TargetScore = score
 	}
 
 	if choice == 2 {
-		g.print("Enter Bust Probability Threshold: ")
-		probInput, err := g.getStringInput()
+		g.prompt(T("Enter Bust Probability Threshold: "))
+		probInput, err := g.getStringInput(ctx)
 		if err != nil {
 			probInput = "0.33"
 		}
@@ -653,85 +1081,189 @@ func (g *Game) getComputerPlayerSetup(computerNum int) (string, HitOrStayStrateg
 		if err != nil || prob < 0.1 || prob > 0.5 {
 			prob = 0.33
 		}
-		bustProbabilityThreshold = prob
+		spec.BustProbability = prob
 	}
 	if choice == 11 {
-		g.print("Enter Target Score: ")
-		score, err := g.getIntInput(1, 100)
+		g.prompt(T("Enter Target Score: "))
+		score, err := g.getIntInput(ctx, 1, 100)
 		if err != nil {
 			score = 30
 		}
-		strategy = PlayRoundTo(score)
-		targetScore = score
+		spec.TargetScore = score
 
-		g.print("Enter Gap Tolerance (e.g., 5): ")
-		gapTol, err := g.getIntInput(1, 50)
+		g.prompt(T("Enter Gap Tolerance (e.g., 5): "))
+		gapTol, err := g.getIntInput(ctx, 1, 50)
 		if err != nil {
 			gapTol = 20
 		}
-		gapTolerance = gapTol
-		g.print("Enter Slack Factor (e.g., 5): ")
-		slack, err := g.getIntInput(1, 20)
+		spec.GapTolerance = gapTol
+		g.prompt(T("Enter Slack Factor (e.g., 5): "))
+		slack, err := g.getIntInput(ctx, 1, 20)
 		if err != nil {
 			slack = 5
 		}
-		slackFactor = slack
+		spec.SlackFactor = slack
 	}
+	if choice == 14 {
+		g.prompt(T("Enter Risk Aversion (0-10, e.g., 2): "))
+		aversionInput, err := g.getStringInput(ctx)
+		if err != nil {
+			aversionInput = "2"
+		}
+		aversion, err := strconv.ParseFloat(aversionInput, 64)
+		if err != nil || aversion <= 0 || aversion > 10 {
+			aversion = 2
+		}
+		spec.RiskAversion = aversion
+	}
+	if choice == 15 {
+		g.prompt(T("Enter Corpus of Recorded Games (JSONL): "))
+		path, err := g.getStringInput(ctx)
+		if err == nil {
+			_, err = imitationCorpus(path)
+		}
+		spec.Corpus = path
+		if err != nil {
+			g.promptf(T("⚠️ %v; playing Expected Value instead\n"), err)
+			spec = StrategySpec{Choice: 6}
+		}
+	}
+
+	suffix, _, _, _ := buildStrategy(spec)
+	return name + suffix, spec, nil
+}
+
+// StrategySpec records which AI strategy a computer player was set up
+// with, so the player can be rebuilt when a saved game is resumed
+type StrategySpec struct {
+	Choice          int
+	TargetScore     int     `json:",omitempty"`
+	BustProbability float64 `json:",omitempty"`
+	GapTolerance    int     `json:",omitempty"`
+	SlackFactor     int     `json:",omitempty"`
+	RiskAversion    float64 `json:",omitempty"`
+	Noise           float64 `json:",omitempty"`
+	Difficulty      string  `json:",omitempty"`
+	Table           string  `json:",omitempty"`
+	Corpus          string  `json:",omitempty"`
+	// Target and Gift name the target strategies for Freeze and Flip
+	// Three, and for Second Chance, in place of the strategy's own.
+	// FlipThree names one for Flip Three alone, in place of Target.
+	Target    string `json:",omitempty"`
+	Gift      string `json:",omitempty"`
+	FlipThree string `json:",omitempty"`
+}
+
+// targetStrategies are the action target strategies a spec can name
+var targetStrategies = map[string]ActionTargetStrategy{
+	"leader": TargetLeaderStrategy,
+	"last":   TargetLastPlaceStrategy,
+	"random": TargetRandomStrategy,
+}
+
+// targetNames returns the names of the target strategies, sorted
+func targetNames() []string {
+	names := make([]string, 0, len(targetStrategies))
+	for name := range targetStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildStrategy returns the name suffix and strategies for a spec
+func buildStrategy(spec StrategySpec) (string, HitOrStayStrategy, ActionTargetStrategy, ActionTargetStrategy) {
+	var suffix string
+	var strategy HitOrStayStrategy
+	var actionTargetStrategy ActionTargetStrategy
+	var positiveActionTargetStrategy ActionTargetStrategy
 
-	switch choice {
+	switch spec.Choice {
 	case 1:
-		name += " (" + strconv.Itoa(targetScore) + ")"
-		strategy = PlayRoundTo(targetScore)
+		suffix = " (" + strconv.Itoa(spec.TargetScore) + ")"
+		strategy = PlayRoundTo(spec.TargetScore)
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 2:
-		name += " p(" + fmt.Sprintf("%.2f", bustProbabilityThreshold) + ")"
-		strategy = PlayToBustProbability(bustProbabilityThreshold)
+		suffix = " p(" + fmt.Sprintf("%.2f", spec.BustProbability) + ")"
+		strategy = PlayToBustProbability(spec.BustProbability)
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 3:
-		name += " (hit)"
+		suffix = " (hit)"
 		strategy = AlwaysHitStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 4:
-		name += " (rand)"
+		suffix = " (rand)"
 		strategy = RandomHitOrStayStrategy
 		actionTargetStrategy = TargetRandomStrategy
 		positiveActionTargetStrategy = TargetRandomStrategy
 	case 5:
-		name += " (adapt0.3)"
+		suffix = " (adapt0.3)"
 		strategy = AdaptiveBustProbabilityStrategy(0.3)
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 6:
-		name += " (exp)"
+		suffix = " (exp)"
 		strategy = ExpectedValueStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 7:
-		name += " (hybrid)"
+		suffix = " (hybrid)"
 		strategy = HybridStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 8:
-		name += " (gap)"
+		suffix = " (gap)"
 		strategy = GapBasedStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 9:
-		name += " (opt)"
+		suffix = " (opt)"
 		strategy = OptimalStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 10:
-		name += " (bayes)"
+		suffix = " (bayes)"
 		strategy = BayesianGainStrategy
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 	case 11:
-		name += fmt.Sprintf(" (gap%d_slack%d)", gapTolerance, slackFactor)
-		strategy = GapAwareStrategy(gapTolerance, slackFactor)
+		suffix = fmt.Sprintf(" (gap%d_slack%d)", spec.GapTolerance, spec.SlackFactor)
+		strategy = GapAwareStrategy(spec.GapTolerance, spec.SlackFactor)
+		actionTargetStrategy = TargetLeaderStrategy
+		positiveActionTargetStrategy = TargetLastPlaceStrategy
+	case 12:
+		suffix = " (table)"
+		// A table file that has gone missing since the player was set up
+		// falls back to the fresh deck's table
+		table, err := solvedTable(spec.Table)
+		if err != nil {
+			table, _ = solvedTable("")
+		}
+		strategy = TableStrategy(table)
+		actionTargetStrategy = TargetLeaderStrategy
+		positiveActionTargetStrategy = TargetLastPlaceStrategy
+	case 13:
+		suffix = " (bandit)"
+		strategy = BanditStrategy
+		actionTargetStrategy = TargetLeaderStrategy
+		positiveActionTargetStrategy = TargetLastPlaceStrategy
+	case 14:
+		suffix = fmt.Sprintf(" (crra%g)", spec.RiskAversion)
+		strategy = RiskUtilityStrategy(spec.RiskAversion)
+		actionTargetStrategy = TargetLeaderStrategy
+		positiveActionTargetStrategy = TargetLastPlaceStrategy
+	case 15:
+		suffix = " (imitate)"
+		// A corpus that has gone missing since the player was set up falls
+		// back to the stand-in analyze plays humans with
+		if corpus, err := imitationCorpus(spec.Corpus); err == nil {
+			strategy = ImitationStrategy(corpus)
+		} else {
+			_, strategy, _, _ = buildStrategy(analysisStandIn)
+		}
 		actionTargetStrategy = TargetLeaderStrategy
 		positiveActionTargetStrategy = TargetLastPlaceStrategy
 
@@ -739,34 +1271,63 @@ func (g *Game) getComputerPlayerSetup(computerNum int) (string, HitOrStayStrateg
 		panic("invalid choice")
 	}
 
-	return name, strategy, actionTargetStrategy, positiveActionTargetStrategy, nil
+	if target, ok := targetStrategies[spec.Target]; ok {
+		actionTargetStrategy = target
+	}
+	if gift, ok := targetStrategies[spec.Gift]; ok {
+		positiveActionTargetStrategy = gift
+	}
+	if spec.Noise > 0 {
+		strategy = NoisyStrategy(strategy, spec.Noise)
+		actionTargetStrategy = NoisyTargetStrategy(actionTargetStrategy, spec.Noise)
+		positiveActionTargetStrategy = NoisyTargetStrategy(positiveActionTargetStrategy, spec.Noise)
+	}
+	if spec.Difficulty != "" {
+		suffix = " (" + spec.Difficulty + ")"
+	}
+
+	return suffix, strategy, actionTargetStrategy, positiveActionTargetStrategy
 }
 
-// buildGameState creates a GameState for AI decision making
+// buildGameState creates the GameState for a decision. Each decision gets
+// its own, with its own list of the active players and its own copies of
+// the deck's tallies, so a strategy may keep it and anything it returns
+// without the next decision changing them. Only the simulation loop
+// shares one between decisions; see poolState.
 func (g *Game) buildGameState() *GameState {
-	activePlayers := make([]PlayerInterface, 0)
+	state := g.pooled
+	if state == nil {
+		state = &GameState{}
+	}
+	activePlayers := state.ActivePlayers[:0]
 	for _, p := range g.players {
 		if p.IsActive() {
 			activePlayers = append(activePlayers, p)
 		}
 	}
 
-	var currentLeader PlayerInterface
-	maxScore := -1
-	for _, p := range g.players {
-		if p.GetTotalScore() > maxScore {
-			maxScore = p.GetTotalScore() + p.CalculateRoundScore()
-			currentLeader = p
-		}
+	*state = GameState{
+		Round:                    g.round,
+		Players:                  g.players,
+		ActivePlayers:            activePlayers,
+		CurrentLeader:            g.condition().Leader(g),
+		WinningScore:             g.winningScore,
+		LeaderWillWinIfRoundEnds: g.endsNow(),
+		DiscardedCards:           slices.Clip(g.deck.Discards()),
+		Rand:                     g.rng,
 	}
+	state.discarded, state.remaining = *g.deck.DiscardCounts(), *g.deck.Counts()
+	state.Discarded, state.Remaining = &state.discarded, &state.remaining
+	return state
+}
 
-	return &GameState{
-		Round:         g.round,
-		Players:       g.players,
-		ActivePlayers: activePlayers,
-		CurrentLeader: currentLeader,
-		CardsInDeck:   g.deck.cards,
-	}
+// poolState makes every decision share one GameState, rebuilt in place
+// for the next, until the returned function is called. Simulations use
+// it, since they decide millions of times and their computer players
+// keep nothing from one decision to the next.
+func (g *Game) poolState() func() {
+	g.pooled = &GameState{}
+	return func() { g.pooled = nil }
 }
 
 // endRoundForFlip7 marks all players except the Flip 7 achiever as non-active
@@ -779,57 +1340,30 @@ func (g *Game) endRoundForFlip7(flip7Player PlayerInterface) {
 	}
 }
 
-// runMultipleGames runs multiple AI-only games and tracks statistics
-func (g *Game) runMultipleGames(numGames int) error {
-	g.printf("\n🎲 Running %d games for statistical analysis...\n", numGames)
-
-	// Track wins for each player
-	playerWins := make(map[string]int)
-	playerNames := make([]string, len(g.players))
-
-	// Initialize player names and win counters
-	for i, player := range g.players {
-		playerNames[i] = player.GetName()
-		playerWins[player.GetName()] = 0
+// runMultipleGames runs multiple AI-only games and tracks statistics.
+// Zero games means as many as fit in the time budget.
+func (g *Game) runMultipleGames(ctx context.Context, numGames int) error {
+	sim, err := g.newSimulation(numGames)
+	if err != nil {
+		return err
 	}
 
-	// Track time for progress reporting
-	startTime := time.Now()
-	lastProgressTime := startTime
-
-	// Run the games
-	for gameNum := 1; gameNum <= numGames; gameNum++ {
-		// Show progress every 5 seconds or for first game
-		now := time.Now()
-		if gameNum == 1 || now.Sub(lastProgressTime) >= 5*time.Second {
-			elapsed := now.Sub(startTime)
-			g.printf("⚡ Game %d/%d... (%.1fs elapsed)\n", gameNum, numGames, elapsed.Seconds())
-			lastProgressTime = now
-		}
-
-		// Reset the game state
-		g.resetGameState()
-
-		// Enable silent mode for simulation
-		g.SetSilentMode(true)
-
-		// Run a single game using regular methods (now silent)
-		err := g.runSingleGame()
-		if err != nil {
-			return fmt.Errorf("error in game %d: %v", gameNum, err)
-		}
-
-		// Track the winner
-		winner := g.getWinner()
-		playerWins[winner.GetName()]++
-
-		// Disable silent mode to show progress
-		g.SetSilentMode(false)
+	seats := len(g.players)
+	switch {
+	case g.duplicate && numGames == 0 && g.budget == 0:
+		g.printf(T("\n🔁 Dealing boards to %d seatings each until %s...\n"), seats, g.stopRule)
+	case g.duplicate && numGames == 0:
+		g.printf(T("\n🔁 Dealing boards to %d seatings each for %s...\n"), seats, g.budget)
+	case g.duplicate:
+		g.printf(T("\n🔁 Dealing %d boards to %d seatings each (%d games)...\n"), numGames, seats, numGames*seats)
+	case numGames == 0 && g.budget == 0:
+		g.printf(T("\n🎲 Running games until %s for statistical analysis...\n"), g.stopRule)
+	case numGames == 0:
+		g.printf(T("\n🎲 Running games for %s for statistical analysis...\n"), g.budget)
+	default:
+		g.printf(T("\n🎲 Running %d games for statistical analysis...\n"), numGames)
 	}
-
-	// Display statistics
-	g.displayGameStatistics(numGames, playerWins, playerNames)
-	return nil
+	return g.simulate(ctx, sim)
 }
 
 // resetGameState resets the game for a new game
@@ -839,6 +1373,7 @@ func (g *Game) resetGameState() {
 
 	// Reset all players
 	for _, player := range g.players {
+		player.(baser).base().Eliminated = false
 		discardedCards := player.ResetForNewRound()
 		for _, card := range discardedCards {
 			g.deck.DiscardCard(card)
@@ -849,27 +1384,41 @@ func (g *Game) resetGameState() {
 		}
 	}
 
+	g.forgetLearning()
+
 	// Reset deck
-	g.deck = NewDeck()
+	g.deck.Reset()
+	if g.seeded {
+		g.seed++
+		g.deck.Seed(g.seed)
+	}
 }
 
 // runSingleGame runs a single game (output controlled by silentMode)
-func (g *Game) runSingleGame() error {
+func (g *Game) runSingleGame(ctx context.Context) (err error) {
+	if g.metrics != nil {
+		finished := g.metrics.trackGame(g)
+		defer func() { finished(err) }()
+	}
+	g.spans.startGame(g)
+	defer func() { g.spans.endGame(g, err) }()
+	g.useScorer()
 	// Main game loop
 	for !g.hasWinner() {
-		if err := g.playRound(); err != nil {
+		if err := g.playRound(ctx); err != nil {
 			return err
 		}
 		g.nextRound()
 	}
+	g.learnFromGame()
 	return nil
 }
 
 // displayGameStatistics shows the final win-rate statistics
 func (g *Game) displayGameStatistics(numGames int, playerWins map[string]int, playerNames []string) {
-	g.printf("\n" + strings.Repeat("=", 60) + "\n")
-	g.printf("🏆 SIMULATION RESULTS - %d GAMES COMPLETED\n", numGames)
-	g.printf(strings.Repeat("=", 60) + "\n")
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🏆 SIMULATION RESULTS - %d GAMES COMPLETED\n"), numGames)
+	g.resultf("%s\n", strings.Repeat("=", 60))
 
 	// Sort players by win count (descending)
 	type playerStat struct {
@@ -895,8 +1444,8 @@ func (g *Game) displayGameStatistics(numGames int, playerWins map[string]int, pl
 	}
 
 	// Display results
-	g.printf("%-20s %8s %10s %12s\n", "PLAYER", "WINS", "WIN RATE", "PERFORMANCE")
-	g.printf(strings.Repeat("-", 60) + "\n")
+	g.resultf("%-20s %8s %10s %12s\n", T("PLAYER"), T("WINS"), T("WIN RATE"), T("PERFORMANCE"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
 
 	for i, stat := range stats {
 		var medal string
@@ -913,30 +1462,30 @@ func (g *Game) displayGameStatistics(numGames int, playerWins map[string]int, pl
 
 		var performance string
 		if stat.rate >= 50 {
-			performance = "🔥 DOMINANT"
+			performance = T("🔥 DOMINANT")
 		} else if stat.rate >= 35 {
-			performance = "💪 STRONG"
+			performance = T("💪 STRONG")
 		} else if stat.rate >= 20 {
-			performance = "👍 DECENT"
+			performance = T("👍 DECENT")
 		} else {
-			performance = "😔 WEAK"
+			performance = T("😔 WEAK")
 		}
 
-		g.printf("%-20s %8d %9.1f%% %12s %s\n",
+		g.resultf("%-20s %8d %9.1f%% %12s %s\n",
 			stat.name, stat.wins, stat.rate, performance, medal)
 	}
 
-	g.printf(strings.Repeat("-", 60) + "\n")
-	g.printf("Total Games: %d\n", numGames)
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf(T("Total Games: %d\n"), numGames)
 
 	// Additional statistics
 	winner := stats[0]
 	if len(stats) > 1 {
 		runnerUp := stats[1]
 		margin := winner.rate - runnerUp.rate
-		g.printf("Victory Margin: %.1f%% (%s vs %s)\n",
+		g.resultf(T("Victory Margin: %.1f%% (%s vs %s)\n"),
 			margin, winner.name, runnerUp.name)
 	}
 
-	g.printf(strings.Repeat("=", 60) + "\n")
+	g.resultf("%s\n", strings.Repeat("=", 60))
 }
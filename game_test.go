@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestGameStateOutlivesTheNextDecision(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetSeed(3)
+	g.players = table("Ada 0", "Bo 0", "Cy 0")
+
+	kept := g.buildGameState()
+	targets := kept.LegalTargets(g.players[0], Freeze)
+	left, discards := kept.Remaining.Total, kept.Discarded.Total
+
+	g.players[1].Stay()
+	g.players[0].AddCard(g.deck.DrawCard())
+	g.deck.DiscardCard(g.deck.DrawCard())
+	next := g.buildGameState()
+
+	if next == kept {
+		t.Fatal("two decisions share a GameState outside a simulation")
+	}
+	if got := nameOf(targets[1]); len(targets) != 3 || got != "Bo" {
+		t.Errorf("the kept targets changed to %d players, second %s", len(targets), got)
+	}
+	if len(kept.ActivePlayers) != 3 {
+		t.Errorf("the kept state has %d active players, want the 3 it was built with", len(kept.ActivePlayers))
+	}
+	if kept.Remaining.Total != left || kept.Discarded.Total != discards {
+		t.Errorf("the kept tallies changed to %d left and %d discarded, want %d and %d",
+			kept.Remaining.Total, kept.Discarded.Total, left, discards)
+	}
+	if next.Remaining.Total != left-2 || len(next.ActivePlayers) != 2 {
+		t.Errorf("the next state has %d left and %d active, want %d and 2", next.Remaining.Total, len(next.ActivePlayers), left-2)
+	}
+}
+
+func TestSimulationsPoolTheGameState(t *testing.T) {
+	g := NewGame()
+	g.players = table("Ada 0", "Bo 0")
+	release := g.poolState()
+	if first, second := g.buildGameState(), g.buildGameState(); first != second {
+		t.Error("a simulation's decisions don't share a GameState")
+	}
+	release()
+	if first, second := g.buildGameState(), g.buildGameState(); first == second {
+		t.Error("decisions still share a GameState once the pool is released")
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// goldenDir is where the golden replays are kept
+const goldenDir = "testdata/golden"
+
+// GoldenPlayer is a computer player in a golden replay
+type GoldenPlayer struct {
+	Name     string
+	Strategy StrategySpec
+}
+
+// GoldenGame is a seeded AI-only game and the event stream it produced
+// when it was recorded. Replaying the setup must produce the same events.
+type GoldenGame struct {
+	Seed         int64
+	WinningScore int
+	Players      []GoldenPlayer
+	Events       []string
+}
+
+// goldenCorpus is the set of games recorded by golden -record. Between
+// them they cover every strategy, noisy decisions, and tables from
+// heads-up to a dozen players.
+var goldenCorpus = map[string]GoldenGame{
+	"heads-up": {Seed: 1, WinningScore: 200, Players: []GoldenPlayer{
+		{"Ada", StrategySpec{Choice: 9}},
+		{"Bo", StrategySpec{Choice: 7}},
+	}},
+	"thresholds": {Seed: 2, WinningScore: 200, Players: []GoldenPlayer{
+		{"Cy", StrategySpec{Choice: 1, TargetScore: 25}},
+		{"Di", StrategySpec{Choice: 2, BustProbability: 0.3}},
+		{"Ed", StrategySpec{Choice: 11, TargetScore: 30, GapTolerance: 20, SlackFactor: 5}},
+	}},
+	"models": {Seed: 3, WinningScore: 200, Players: []GoldenPlayer{
+		{"Fay", StrategySpec{Choice: 5}},
+		{"Gus", StrategySpec{Choice: 6}},
+		{"Hal", StrategySpec{Choice: 8}},
+		{"Ivy", StrategySpec{Choice: 10}},
+	}},
+	"reckless": {Seed: 4, WinningScore: 150, Players: []GoldenPlayer{
+		{"Jo", StrategySpec{Choice: 3}},
+		{"Kit", StrategySpec{Choice: 4}},
+		{"Lu", StrategySpec{Choice: 3}},
+	}},
+	"noisy": {Seed: 5, WinningScore: 200, Players: []GoldenPlayer{
+		{"Mo", StrategySpec{Choice: 1, TargetScore: 15, Noise: 0.25}},
+		{"Ned", StrategySpec{Choice: 2, BustProbability: 0.25, Noise: 0.1}},
+		{"Oz", StrategySpec{Choice: 9}},
+	}},
+	"crowded": {Seed: 6, WinningScore: 200, Players: []GoldenPlayer{
+		{"P1", StrategySpec{Choice: 1, TargetScore: 20}},
+		{"P2", StrategySpec{Choice: 2, BustProbability: 0.2}},
+		{"P3", StrategySpec{Choice: 3}},
+		{"P4", StrategySpec{Choice: 4}},
+		{"P5", StrategySpec{Choice: 5}},
+		{"P6", StrategySpec{Choice: 6}},
+		{"P7", StrategySpec{Choice: 7}},
+		{"P8", StrategySpec{Choice: 8}},
+		{"P9", StrategySpec{Choice: 9}},
+		{"P10", StrategySpec{Choice: 10}},
+		{"P11", StrategySpec{Choice: 11, TargetScore: 30, GapTolerance: 20, SlackFactor: 5}},
+		{"P12", StrategySpec{Choice: 2, BustProbability: 0.45, Noise: 0.3}},
+	}},
+}
+
+// RunGolden checks the recorded golden replays against the current engine,
+// or re-records them with -record. A replay whose events differ means the
+// engine changed how a game plays out; if the change was intended, record
+// again and review the diff.
+func RunGolden(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("golden", flag.ContinueOnError)
+	dir := fs.String("dir", goldenDir, "Directory holding the golden replays")
+	record := fs.Bool("record", false, "Record the corpus again instead of checking it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *record {
+		return recordGolden(*dir, out)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(*dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no golden replays in %s (record them with golden -record)", *dir)
+	}
+
+	failures := 0
+	for _, path := range paths {
+		if err := checkGolden(path, out); err != nil {
+			failures++
+			fmt.Fprintf(out, "FAIL %s: %v\n", path, err)
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d golden replays differ", failures, len(paths))
+	}
+	return nil
+}
+
+// recordGolden plays every game in the corpus and writes its replay
+func recordGolden(dir string, out io.Writer) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, name := range slices.Sorted(maps.Keys(goldenCorpus)) {
+		gg := goldenCorpus[name]
+		events, err := gg.play()
+		if err != nil {
+			return fmt.Errorf("recording %s: %w", name, err)
+		}
+		gg.Events = events
+
+		data, err := json.MarshalIndent(gg, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "recorded %s (%d events)\n", path, len(events))
+	}
+	return nil
+}
+
+// checkGolden replays one recorded game and reports the first event that
+// differs
+func checkGolden(path string, out io.Writer) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var gg GoldenGame
+	if err := json.Unmarshal(data, &gg); err != nil {
+		return fmt.Errorf("reading golden replay: %w", err)
+	}
+
+	events, err := gg.play()
+	if err != nil {
+		return err
+	}
+	if err := diffEvents(gg.Events, events); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "ok   %s (%d events)\n", path, len(events))
+	return nil
+}
+
+// diffEvents reports the first event where two event streams differ
+func diffEvents(want, got []string) error {
+	for i := range max(len(want), len(got)) {
+		w, g := "(end of game)", "(end of game)"
+		if i < len(want) {
+			w = want[i]
+		}
+		if i < len(got) {
+			g = got[i]
+		}
+		if w != g {
+			return fmt.Errorf("event %d differs\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return nil
+}
+
+// play runs the game silently and returns its event stream
+func (gg GoldenGame) play() ([]string, error) {
+	SeedRandom(gg.Seed)
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetSeed(gg.Seed)
+	g.SetWinningScore(gg.WinningScore)
+	for _, p := range gg.Players {
+		g.players = append(g.players, NewComputerPlayerFromSpec(p.Name, p.Strategy))
+	}
+
+	var events []string
+	g.Subscribe(func(event Event) {
+		events = append(events, replayLine(event))
+	})
+	if err := g.Run(context.Background()); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// replayLine writes an event in a stable form that doesn't depend on the
+// language or symbols in use, e.g. "3 ActionPlayed Ada on Bo freeze"
+func replayLine(event Event) string {
+	parts := []string{strconv.Itoa(event.Round), event.Type.String()}
+	if event.Player != nil {
+		parts = append(parts, event.Player.GetName())
+	}
+	if event.Target != nil {
+		parts = append(parts, "on", event.Target.GetName())
+	}
+	if event.Card != nil {
+		parts = append(parts, formatCard(event.Card))
+	}
+	switch event.Type {
+	case PlayerStayed, PlayerScored, GameEnded, DeckReshuffled, DeckPeeked:
+		parts = append(parts, "="+strconv.Itoa(event.Score))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Thresholds for a computer player to play a held action card on the
+// rival its target strategy picks
+const (
+	// heldFreezeScore is the most a rival can have this round for a
+	// Freeze to be worth playing on them, since it banks what they have
+	heldFreezeScore = 15
+	// heldFlipThreeBust is the chance of busting on one card that makes
+	// three of them worth forcing on a rival
+	heldFlipThreeBust = 0.25
+	// heldLastChance loosens the thresholds by this much on the turn the
+	// player means to stay, since held cards are lost with the round
+	heldLastChance = 0.5
+	// heldStayBust is the chance of busting on one card at which the
+	// player is taken to mean to stay
+	heldStayBust = 0.25
+)
+
+// SetHoldActions turns on the house rule that lets players keep the
+// Freeze and Flip Three cards they draw and play them at the start of a
+// later turn, instead of playing them at once. Held cards are discarded
+// with the rest of the hand at the end of the round.
+func (g *Game) SetHoldActions(enabled bool) {
+	g.holdActions = enabled
+}
+
+// HeldActions returns the Freeze and Flip Three cards the player is
+// holding to play later
+func (p *BasePlayer) HeldActions() []*Card {
+	var held []*Card
+	for _, card := range p.ActionCards {
+		if card.Action != SecondChance {
+			held = append(held, card)
+		}
+	}
+	return held
+}
+
+// removeActionCard takes a held card out of the player's hand
+func (p *BasePlayer) removeActionCard(card *Card) {
+	p.ActionCards = slices.DeleteFunc(p.ActionCards, func(c *Card) bool { return c == card })
+}
+
+// holdActionCard puts a drawn Freeze or Flip Three in the player's hand
+// for later
+func (g *Game) holdActionCard(player PlayerInterface, card *Card) error {
+	if err := player.AddCard(card); err != nil {
+		g.deck.DiscardCard(card)
+		return err
+	}
+	g.narrate(func() { g.printf(T("   🎴 %s keeps %s to play later\n"), player.GetName(), g.renderer.Card(card)) })
+	return nil
+}
+
+// offerHeldActions lets a player holding action cards play one of them
+// at the start of their turn
+func (g *Game) offerHeldActions(ctx context.Context, player PlayerInterface) error {
+	held := player.HeldActions()
+	if len(held) == 0 {
+		return nil
+	}
+
+	gameState := g.buildGameState()
+	done := g.decide(pendingDecision{player: player, play: true})
+	card, err := player.ChooseActionCardToPlay(ctx, gameState)
+	done()
+	if err != nil || card == nil {
+		return err
+	}
+	if !slices.Contains(held, card) {
+		return fmt.Errorf("%s played %s, which they are not holding", player.GetName(), card)
+	}
+
+	player.(baser).base().removeActionCard(card)
+	g.narrate(func() { g.printf(T("   🎴 %s plays %s from their hand\n"), player.GetName(), g.renderer.Card(card)) })
+	return g.playActionCard(ctx, player, card)
+}
+
+// ChooseActionCardToPlay plays a held Freeze on a rival who has little
+// to bank yet and a held Flip Three on a rival likely to bust, and
+// otherwise keeps them for a better moment. On a turn it likely means to
+// stay it won't get another chance, so it settles for less. That's judged
+// from the odds of busting, not by asking its strategy, which may learn
+// or draw random numbers as it decides. The rival weighed up is the one
+// its target strategy would pick.
+func (p *ComputerPlayer) ChooseActionCardToPlay(ctx context.Context, gameState *GameState) (*Card, error) {
+	freezeScore, flipThreeBust := float64(heldFreezeScore), heldFlipThreeBust
+	if p.lastChance(gameState) {
+		freezeScore /= heldLastChance
+		flipThreeBust *= heldLastChance
+	}
+	for _, card := range p.HeldActions() {
+		target := p.targetStrategy(card.Action)(p, gameState, card.Action)
+		if target == nil || target == PlayerInterface(p) {
+			continue
+		}
+		worth := false
+		switch card.Action {
+		case Freeze:
+			worth = float64(target.CalculateRoundScore()) <= freezeScore
+		case FlipThree:
+			worth = CalculateBustProbability(target, gameState) >= flipThreeBust
+		}
+		if worth {
+			return card, nil
+		}
+	}
+	return nil, nil
+}
+
+// lastChance reports whether the player is likely to stay this turn. A
+// player holding a Second Chance doesn't fear the bust it would take
+// back, so it's taken to keep hitting.
+func (p *ComputerPlayer) lastChance(gameState *GameState) bool {
+	if !p.HasCards() || p.HasSecondChance() || gameState.Unseen().Total == 0 {
+		return false
+	}
+	return CalculateBustProbability(p, gameState) >= heldStayBust
+}
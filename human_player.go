@@ -2,20 +2,26 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type HumanPlayer struct {
 	BasePlayer
-	scanner *bufio.Scanner
+	scanner  *bufio.Scanner
+	out      io.Writer
+	commands CommandHandler
 }
 
 // NewHumanPlayer creates a new human player
-func NewHumanPlayer(name string, scanner *bufio.Scanner) *HumanPlayer {
+func NewHumanPlayer(name string, scanner *bufio.Scanner, out io.Writer) *HumanPlayer {
 	p := &HumanPlayer{
 		scanner: scanner,
+		out:     out,
 	}
 
 	p.BasePlayer.Init(name)
@@ -23,84 +29,149 @@ func NewHumanPlayer(name string, scanner *bufio.Scanner) *HumanPlayer {
 	return p
 }
 
+// pendingScans are reads abandoned when their context ended, by
+// scanner. The next read from the scanner picks up the line, so nothing
+// typed is lost.
+var (
+	pendingScans   = make(map[*bufio.Scanner]chan bool)
+	pendingScansMu sync.Mutex
+)
+
+// scanContext reads the next line like scanner.Scan, but stops waiting
+// when ctx ends and returns ctx's error
+func scanContext(ctx context.Context, scanner *bufio.Scanner) (bool, error) {
+	pendingScansMu.Lock()
+	scanned, ok := pendingScans[scanner]
+	if !ok && ctx.Done() == nil {
+		pendingScansMu.Unlock()
+		return scanner.Scan(), nil
+	}
+	if !ok {
+		scanned = make(chan bool, 1)
+		pendingScans[scanner] = scanned
+		go func() { scanned <- scanner.Scan() }()
+	}
+	pendingScansMu.Unlock()
+
+	select {
+	case ok := <-scanned:
+		pendingScansMu.Lock()
+		delete(pendingScans, scanner)
+		pendingScansMu.Unlock()
+		return ok, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// readLine reads the player's next line of input
+func (p *HumanPlayer) readLine(ctx context.Context) (string, error) {
+	ok, err := scanContext(ctx, p.scanner)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("failed to read input")
+	}
+	return strings.TrimSpace(p.scanner.Text()), nil
+}
+
+// SetCommandHandler sets the handler for commands typed at the hit/stay prompt
+func (p *HumanPlayer) SetCommandHandler(commands CommandHandler) {
+	p.commands = commands
+}
+
 func (p *HumanPlayer) GetPlayerIcon() string {
 	return "👤"
 }
 
-func (p *HumanPlayer) MakeHitStayDecision(gameState *GameState) (bool, error) {
-	fmt.Printf("%s's hand, %v\n", p.Name, p.GetHand())
-	fmt.Printf("🎯 %s, do you want to (H)it or (S)tay? ", p.Name)
+func (p *HumanPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	fmt.Fprintf(p.out, T("%s's hand, %v\n"), p.Name, p.GetHand())
+	fmt.Fprintf(p.out, T("🎯 %s, do you want to (H)it or (S)tay? "), p.Name)
 	for {
-		if !p.scanner.Scan() {
-			return false, fmt.Errorf("failed to read input")
+		input, err := p.readLine(ctx)
+		if err != nil {
+			return false, err
 		}
-
-		choice := strings.ToLower(strings.TrimSpace(p.scanner.Text()))
-		if choice == "h" || choice == "hit" {
+		choice := strings.ToLower(input)
+		if choice == "h" || choice == "hit" || choice == T("h") || choice == T("hit") {
 			return true, nil
 		}
-		if choice == "s" || choice == "stay" {
+		if choice == "s" || choice == "stay" || choice == T("s") || choice == T("stay") {
 			return false, nil
 		}
 
-		fmt.Print("Please enter 'H' for Hit or 'S' for Stay: ")
-	}
-}
+		if p.commands != nil {
+			handled, err := p.commands(ctx, p, input)
+			if err != nil {
+				return false, err
+			}
+			if handled {
+				fmt.Fprintf(p.out, T("🎯 %s, do you want to (H)it or (S)tay? "), p.Name)
+				continue
+			}
+		}
 
-func (p *HumanPlayer) ChooseActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error) {
-	actionName := map[ActionType]string{
-		Freeze:       "Who should be frozen?",
-		FlipThree:    "Who should flip three cards?",
-		SecondChance: "Who should get the Second Chance card?",
+		fmt.Fprint(p.out, T("Please enter 'H' for Hit or 'S' for Stay (or 'help' for commands): "))
 	}
+}
 
-	fmt.Printf("   %s\n", actionName[actionType])
-	for i, player := range gameState.ActivePlayers {
-		fmt.Printf("   %d) %s\n", i+1, player.GetName())
+// DecideAction asks who gets an action card, saying what it does and
+// listing each legal target with their hand and scores
+func (p *HumanPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	targets := decision.Targets
+	fmt.Fprintf(p.out, "   %s\n", decision.Question())
+	fmt.Fprintf(p.out, "   (%s)\n", decision.Hint())
+	for i, player := range targets {
+		name := player.GetName()
+		if player == PlayerInterface(p) {
+			name = fmt.Sprintf(T("%s (you)"), name)
+		}
+		fmt.Fprintf(p.out, T("   %d) %s: %s - %d points this round, %d in total\n"),
+			i+1, name, player.GetHandSummary(), player.CalculateRoundScore(), player.GetTotalScore())
 	}
 
 	for {
-		fmt.Printf("Enter choice (1-%d): ", len(gameState.ActivePlayers))
-		if !p.scanner.Scan() {
-			return nil, fmt.Errorf("failed to read input")
+		fmt.Fprintf(p.out, T("Enter choice (1-%d): "), len(targets))
+		input, err := p.readLine(ctx)
+		if err != nil {
+			return nil, err
 		}
-
-		input := strings.TrimSpace(p.scanner.Text())
 		choice, err := strconv.Atoi(input)
-		if err != nil || choice < 1 || choice > len(gameState.ActivePlayers) {
-			fmt.Printf("Please enter a number between 1 and %d: ", len(gameState.ActivePlayers))
+		if err != nil || choice < 1 || choice > len(targets) {
+			fmt.Fprintf(p.out, T("Please enter a number between 1 and %d: "), len(targets))
 			continue
 		}
 
-		return gameState.ActivePlayers[choice-1], nil
+		return targets[choice-1], nil
 	}
 }
 
-func (p *HumanPlayer) ChoosePositiveActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error) {
-	actionName := map[ActionType]string{
-		Freeze:       "Who should be frozen?",
-		FlipThree:    "Who should flip three cards?",
-		SecondChance: "Who should get the Second Chance card?",
-	}
-
-	fmt.Printf("   %s\n", actionName[actionType])
-	for i, player := range gameState.ActivePlayers {
-		fmt.Printf("   %d) %s\n", i+1, player.GetName())
+// ChooseActionCardToPlay asks whether to play one of the held action
+// cards before deciding to hit or stay
+func (p *HumanPlayer) ChooseActionCardToPlay(ctx context.Context, gameState *GameState) (*Card, error) {
+	held := p.HeldActions()
+	fmt.Fprintf(p.out, "   %s\n", T("Play a held action card?"))
+	fmt.Fprintf(p.out, "   0) %s\n", T("Not now"))
+	for i, card := range held {
+		fmt.Fprintf(p.out, "   %d) %s\n", i+1, card)
 	}
 
 	for {
-		fmt.Printf("Enter choice (1-%d): ", len(gameState.ActivePlayers))
-		if !p.scanner.Scan() {
-			return nil, fmt.Errorf("failed to read input")
+		fmt.Fprintf(p.out, T("Enter choice (0-%d): "), len(held))
+		input, err := p.readLine(ctx)
+		if err != nil {
+			return nil, err
 		}
-
-		input := strings.TrimSpace(p.scanner.Text())
 		choice, err := strconv.Atoi(input)
-		if err != nil || choice < 1 || choice > len(gameState.ActivePlayers) {
-			fmt.Printf("Please enter a number between 1 and %d: ", len(gameState.ActivePlayers))
+		if err != nil || choice < 0 || choice > len(held) {
+			fmt.Fprintf(p.out, T("Please enter a number between 0 and %d: "), len(held))
 			continue
 		}
 
-		return gameState.ActivePlayers[choice-1], nil
+		if choice == 0 {
+			return nil, nil
+		}
+		return held[choice-1], nil
 	}
 }
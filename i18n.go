@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Catalog maps English message text (the message ID) to a translation.
+// Message IDs are the English strings themselves, so English needs no
+// catalog and a missing translation falls back to English.
+type Catalog map[string]string
+
+// catalogs holds the message catalog for every supported language
+var catalogs = map[string]Catalog{
+	"en": {},
+	"es": spanishCatalog,
+}
+
+// messages is the catalog for the current language
+var messages = catalogs["en"]
+
+// T returns the translation of an English message for the current language
+func T(id string) string {
+	if translated, ok := messages[id]; ok {
+		return translated
+	}
+	return id
+}
+
+// Languages returns the supported language codes
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// SetLanguage selects the message catalog. An empty language is detected
+// from the LC_ALL, LC_MESSAGES and LANG environment variables.
+func SetLanguage(lang string) error {
+	if lang == "" {
+		lang = DetectLanguage()
+	}
+
+	catalog, ok := catalogs[strings.ToLower(lang)]
+	if !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", lang, strings.Join(Languages(), ", "))
+	}
+
+	messages = catalog
+	return nil
+}
+
+// DetectLanguage returns the supported language named by the locale
+// environment, defaulting to English
+func DetectLanguage() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(env)
+		if locale == "" {
+			continue
+		}
+		lang := strings.ToLower(strings.SplitN(locale, "_", 2)[0])
+		lang = strings.SplitN(lang, ".", 2)[0]
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		return "en"
+	}
+	return "en"
+}
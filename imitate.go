@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// imitationNeighbours is how many of the nearest recorded decisions vote
+// on each of an imitating player's decisions
+const imitationNeighbours = 7
+
+// positionFeatures is what a position is matched on, each scaled to
+// about 0 to 1 so none outweighs the rest
+type positionFeatures [6]float64
+
+// imitatedDecision is one recorded human decision and its position
+type imitatedDecision struct {
+	features positionFeatures
+	hit      bool
+}
+
+// ImitationCorpus is every hit/stay decision humans made in a set of
+// recorded games, for the imitate strategy to match positions against
+type ImitationCorpus struct {
+	Path      string
+	Games     int
+	decisions []imitatedDecision
+}
+
+// LoadImitationCorpus reads recorded games, one replay per line as
+// -record writes them, and keeps the decisions human players made.
+// Replay files simply put one after another can be read as well.
+func LoadImitationCorpus(path string) (*ImitationCorpus, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &ImitationCorpus{Path: path}
+	decoder := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r Replay
+		err := decoder.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: game %d: %w", path, c.Games+1, err)
+		}
+		c.Games++
+		if r.Version != replayVersion {
+			return nil, fmt.Errorf("%s: game %d has version %d, expected %d", path, c.Games, r.Version, replayVersion)
+		}
+		for i, d := range r.Decisions {
+			self, state, err := r.decisionState(d)
+			if err != nil {
+				return nil, fmt.Errorf("%s: game %d: decision %d: %w", path, c.Games, i+1, err)
+			}
+			if r.Players[d.Seat].Human {
+				c.decisions = append(c.decisions, imitatedDecision{features: decisionFeatures(self, state), hit: d.Hit})
+			}
+		}
+	}
+	if len(c.decisions) == 0 {
+		return nil, fmt.Errorf("%s has no decisions by human players", path)
+	}
+	return c, nil
+}
+
+// decisionState rebuilds the table at a recorded decision as strategies
+// see it, and the player deciding
+func (r *Replay) decisionState(d ReplayDecision) (PlayerInterface, *GameState, error) {
+	n := len(r.Players)
+	if len(d.Totals) != n || len(d.Hands) != n || len(d.States) != n || d.Seat < 0 || d.Seat >= n {
+		return nil, nil, fmt.Errorf("doesn't match the %d players", n)
+	}
+	state := &GameState{Round: d.Round, WinningScore: r.WinningScore}
+	for i, sp := range r.Players {
+		p := &ComputerPlayer{}
+		p.Init(sp.Name)
+		if r.Scoring != nil {
+			p.scorer = *r.Scoring
+		}
+		p.TotalScore = d.Totals[i]
+		hand, err := parseCards(d.Hands[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, card := range hand {
+			p.AddCard(card)
+		}
+		playerState, ok := scenarioStates[d.States[i]]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s has unknown state %q", sp.Name, d.States[i])
+		}
+		p.State = playerState
+		state.Players = append(state.Players, p)
+	}
+	discards, err := parseCards(d.Discards)
+	if err != nil {
+		return nil, nil, err
+	}
+	state.DiscardedCards = discards
+	return state.Players[d.Seat], state, nil
+}
+
+// decisionFeatures describes a position for matching: the chance of
+// busting, the round score of 50, the numbers held of seven, the
+// player's total and the best opponent's as shares of the winning score,
+// and whether the player has a Second Chance
+func decisionFeatures(self PlayerInterface, state *GameState) positionFeatures {
+	bust := 0.0
+	if state.Unseen().Total > 0 {
+		bust = CalculateBustProbability(self, state)
+	}
+	best := 0
+	for _, p := range state.Players {
+		if p != self {
+			best = max(best, p.GetTotalScore())
+		}
+	}
+	winning := float64(max(state.WinningScore, 1))
+	secondChance := 0.0
+	if self.HasSecondChance() {
+		secondChance = 1
+	}
+	return positionFeatures{bust, float64(self.CalculateRoundScore()) / 50, float64(self.NumberOfNumberCards()) / 7,
+		float64(self.GetTotalScore()) / winning, float64(best) / winning, secondChance}
+}
+
+// ShouldHit decides as the humans did in the recorded positions nearest
+// this one: the nearest imitationNeighbours vote, and a tie goes to the
+// nearest
+func (c *ImitationCorpus) ShouldHit(self PlayerInterface, state *GameState) bool {
+	type neighbour struct {
+		distance float64
+		hit      bool
+	}
+	f := decisionFeatures(self, state)
+	nearest := make([]neighbour, 0, imitationNeighbours+1)
+	for _, d := range c.decisions {
+		distance := 0.0
+		for i := range f {
+			distance += (f[i] - d.features[i]) * (f[i] - d.features[i])
+		}
+		if len(nearest) == imitationNeighbours && distance >= nearest[len(nearest)-1].distance {
+			continue
+		}
+		i, _ := slices.BinarySearchFunc(nearest, distance, func(n neighbour, distance float64) int {
+			if n.distance <= distance {
+				return -1
+			}
+			return 1
+		})
+		nearest = slices.Insert(nearest, i, neighbour{distance, d.hit})
+		nearest = nearest[:min(len(nearest), imitationNeighbours)]
+	}
+	votes := 0
+	for _, n := range nearest {
+		if n.hit {
+			votes++
+		} else {
+			votes--
+		}
+	}
+	return votes > 0 || votes == 0 && nearest[0].hit
+}
+
+// imitationCorpora caches corpora by path, so every player imitating one
+// shares it
+var imitationCorpora = struct {
+	sync.Mutex
+	corpora map[string]*ImitationCorpus
+}{corpora: make(map[string]*ImitationCorpus)}
+
+// imitationCorpus returns the corpus at path
+func imitationCorpus(path string) (*ImitationCorpus, error) {
+	if path == "" {
+		return nil, fmt.Errorf("imitate needs a corpus of recorded games")
+	}
+	imitationCorpora.Lock()
+	defer imitationCorpora.Unlock()
+	if c := imitationCorpora.corpora[path]; c != nil {
+		return c, nil
+	}
+	c, err := LoadImitationCorpus(path)
+	if err != nil {
+		return nil, err
+	}
+	imitationCorpora.corpora[path] = c
+	return c, nil
+}
+
+// ImitationStrategy hits or stays as the corpus's humans did in the
+// positions most like this one
+func ImitationStrategy(corpus *ImitationCorpus) HitOrStayStrategy {
+	return func(self PlayerInterface, gameState *GameState) bool {
+		return corpus.ShouldHit(self, gameState)
+	}
+}
@@ -0,0 +1,619 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Ladder ratings start at ladderStartRating and move by up to ladderK
+// points a game, split between the other players at the table
+const (
+	ladderStartRating = 1500
+	ladderK           = 32
+)
+
+// Ladder is the ranked ladder of a served flip7: accounts with their
+// Elo ratings this season, and the standings of seasons past. It's saved
+// to a JSON file after every change.
+type Ladder struct {
+	Season      int              `json:"season"`
+	SeasonStart time.Time        `json:"season_start"`
+	Accounts    []*LadderAccount `json:"accounts"`
+	History     []LadderSeason   `json:"history"`
+
+	path   string
+	length time.Duration // of a season; 0 never ends one
+	table  int           // players per ranked game
+	score  int           // points needed to win
+	turn   TurnTimeout   // for each decision
+
+	mu      sync.Mutex
+	queue   []*ladderSeat
+	playing map[*LadderAccount]bool
+}
+
+// LadderAccount is a ladder player. Only a hash of their token is kept.
+// Their decisions are audited across seasons.
+type LadderAccount struct {
+	Name   string        `json:"name"`
+	Token  string        `json:"token_sha256"`
+	Rating float64       `json:"rating"`
+	Games  int           `json:"games"`
+	Wins   int           `json:"wins"`
+	Joined time.Time     `json:"joined"`
+	Audit  DecisionAudit `json:"audit"`
+}
+
+// LadderStanding is a player's place in a season. Flagged players'
+// decisions follow the hidden deck too closely to be honest.
+type LadderStanding struct {
+	Rank    int    `json:"rank"`
+	Name    string `json:"name"`
+	Rating  int    `json:"rating"`
+	Games   int    `json:"games"`
+	Wins    int    `json:"wins"`
+	Flagged bool   `json:"flagged,omitempty"`
+}
+
+// LadderSeason is a season's standings
+type LadderSeason struct {
+	Season    int              `json:"season"`
+	Started   time.Time        `json:"started"`
+	Ended     *time.Time       `json:"ended,omitempty"`
+	Standings []LadderStanding `json:"standings"`
+}
+
+// ladderSeat is a player waiting in the ranked queue or playing a
+// ranked game over their WebSocket. Legal is the actions they may take
+// while the game waits on them, and nil otherwise.
+type ladderSeat struct {
+	account *LadderAccount
+	ws      *WebSocket
+	actions chan int
+	gone    chan struct{}
+
+	mu    sync.Mutex
+	legal []int
+}
+
+// ladderMessage is what the server sends a ranked player: "queued",
+// "match" when a game starts, "turn" for a decision, "result" at the
+// end, or "error"
+type ladderMessage struct {
+	Type    string    `json:"type"`
+	Waiting int       `json:"waiting,omitempty"`
+	Players []string  `json:"players,omitempty"`
+	Step    *TimeStep `json:"step,omitempty"`
+	Rating  int       `json:"rating,omitempty"`
+	Change  int       `json:"change,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// OpenLadder loads the ladder saved at path, or starts one
+func OpenLadder(path string, length time.Duration, table, score int, turn TurnTimeout) (*Ladder, error) {
+	l := &Ladder{path: path, length: length, table: table, score: score, turn: turn, playing: make(map[*LadderAccount]bool)}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		l.Season, l.SeasonStart = 1, time.Now()
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, l); err != nil {
+			return nil, fmt.Errorf("reading ladder %s: %w", path, err)
+		}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollover(time.Now())
+	return l, l.save()
+}
+
+// save writes the ladder to its file. The caller holds l.mu.
+func (l *Ladder) save() error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(filepath.Dir(l.path), "."+filepath.Base(l.path)+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// rollover ends the season if it has run its length, keeping its
+// standings and starting every rating afresh. The caller holds l.mu.
+func (l *Ladder) rollover(now time.Time) {
+	if l.length <= 0 || now.Before(l.SeasonStart.Add(l.length)) {
+		return
+	}
+	ended := l.standings()
+	ended.Ended = &now
+	l.History = append(l.History, ended)
+	for _, a := range l.Accounts {
+		a.Rating, a.Games, a.Wins = ladderStartRating, 0, 0
+	}
+	l.Season++
+	l.SeasonStart = now
+}
+
+// standings ranks this season's players by rating. Players yet to play
+// a game aren't ranked. The caller holds l.mu.
+func (l *Ladder) standings() LadderSeason {
+	season := LadderSeason{Season: l.Season, Started: l.SeasonStart, Standings: []LadderStanding{}}
+	for _, a := range l.Accounts {
+		if a.Games > 0 {
+			season.Standings = append(season.Standings, LadderStanding{Name: a.Name, Rating: int(math.Round(a.Rating)),
+				Games: a.Games, Wins: a.Wins, Flagged: a.Audit.Flagged()})
+		}
+	}
+	slices.SortStableFunc(season.Standings, func(a, b LadderStanding) int { return b.Rating - a.Rating })
+	for i := range season.Standings {
+		season.Standings[i].Rank = i + 1
+	}
+	return season
+}
+
+// hashToken is how a token is kept
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Register opens an account and returns its token, which is shown only
+// this once
+func (l *Ladder) Register(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxWebName {
+		return "", fmt.Errorf("names must be 1 to %d characters", maxWebName)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, a := range l.Accounts {
+		if strings.EqualFold(a.Name, name) {
+			return "", fmt.Errorf("the name %q is taken", name)
+		}
+	}
+	l.Accounts = append(l.Accounts, &LadderAccount{Name: name, Token: hashToken(token), Rating: ladderStartRating, Joined: time.Now()})
+	return token, l.save()
+}
+
+// account finds the account a token belongs to
+func (l *Ladder) account(token string) *LadderAccount {
+	hash := hashToken(token)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, a := range l.Accounts {
+		if a.Token == hash {
+			return a
+		}
+	}
+	return nil
+}
+
+// eloChanges are the rating changes from a game. Every pair of players
+// is scored as a match: the winner beats everyone, and otherwise the
+// higher total beats the lower.
+func eloChanges(ratings []float64, totals []int, winner int) []float64 {
+	changes := make([]float64, len(ratings))
+	k := ladderK / float64(len(ratings)-1)
+	for i := range ratings {
+		for j := i + 1; j < len(ratings); j++ {
+			var score float64
+			switch {
+			case i == winner || (j != winner && totals[i] > totals[j]):
+				score = 1
+			case j == winner || totals[i] < totals[j]:
+				score = 0
+			default:
+				score = 0.5
+			}
+			expected := 1 / (1 + math.Pow(10, (ratings[j]-ratings[i])/400))
+			changes[i] += k * (score - expected)
+			changes[j] -= k * (score - expected)
+		}
+	}
+	return changes
+}
+
+// handleRegister opens an account: POST name=NAME
+func (l *Ladder) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST a name to register", http.StatusMethodNotAllowed)
+		return
+	}
+	token, err := l.Register(r.FormValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"name": strings.TrimSpace(r.FormValue("name")), "token": token})
+}
+
+// handleStandings returns this season's standings, or a past season's
+// with ?season=N
+func (l *Ladder) handleStandings(w http.ResponseWriter, r *http.Request) {
+	l.mu.Lock()
+	l.rollover(time.Now())
+	season := l.standings()
+	if s := r.URL.Query().Get("season"); s != "" {
+		n, _ := strconv.Atoi(s)
+		i := slices.IndexFunc(l.History, func(h LadderSeason) bool { return h.Season == n })
+		if i < 0 && n != l.Season {
+			l.mu.Unlock()
+			http.Error(w, fmt.Sprintf("no season %q", s), http.StatusNotFound)
+			return
+		}
+		if i >= 0 {
+			season = l.History[i]
+		}
+	}
+	l.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(season)
+}
+
+// handlePlay queues a player for a ranked game over a WebSocket,
+// ?token=TOKEN, and passes on their decisions: {"op":"step","action":N}.
+// Decisions sent out of turn or that aren't legal are refused and
+// counted against the player.
+func (l *Ladder) handlePlay(w http.ResponseWriter, r *http.Request) {
+	account := l.account(r.URL.Query().Get("token"))
+	if account == nil {
+		http.Error(w, "unknown token; register first", http.StatusUnauthorized)
+		return
+	}
+	ws, err := UpgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	seat := &ladderSeat{account: account, ws: ws, actions: make(chan int, 1), gone: make(chan struct{})}
+	defer close(seat.gone)
+	if !l.join(seat) {
+		seat.send(ladderMessage{Type: "error", Error: "already queued or playing"})
+		return
+	}
+	defer l.leave(seat)
+
+	for {
+		msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req envRequest
+		if json.Unmarshal(msg, &req) != nil || req.Op != "step" {
+			err = fmt.Errorf(`expected {"op":"step","action":N}`)
+		} else {
+			err = seat.offer(req.Action)
+		}
+		if err != nil {
+			l.mu.Lock()
+			account.Audit.Rejected++
+			l.mu.Unlock()
+			seat.send(ladderMessage{Type: "error", Error: err.Error()})
+		}
+	}
+}
+
+// offer passes an action to the seat's game, if it's waiting on the
+// player and the action is legal
+func (s *ladderSeat) offer(action int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.legal == nil {
+		return fmt.Errorf("it isn't your turn")
+	}
+	if !slices.Contains(s.legal, action) {
+		return fmt.Errorf("action %d isn't legal now (legal: %v)", action, s.legal)
+	}
+	s.legal = nil
+	s.actions <- action
+	return nil
+}
+
+// open starts waiting on the player for one of the legal actions
+func (s *ladderSeat) open(legal []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.legal = legal
+}
+
+// shut stops waiting on the player, dropping an action that arrived
+// too late
+func (s *ladderSeat) shut() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.legal = nil
+	select {
+	case <-s.actions:
+	default:
+	}
+}
+
+// send writes a message to the seat's player
+func (s *ladderSeat) send(m ladderMessage) {
+	data, _ := json.Marshal(m)
+	s.ws.WriteMessage(data)
+}
+
+// join queues a seat and starts a game if enough players are waiting.
+// An account plays one ranked game at a time.
+func (l *Ladder) join(seat *ladderSeat) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.playing[seat.account] || slices.ContainsFunc(l.queue, func(s *ladderSeat) bool { return s.account == seat.account }) {
+		return false
+	}
+	l.queue = append(l.queue, seat)
+	for _, s := range l.queue {
+		s.send(ladderMessage{Type: "queued", Waiting: len(l.queue)})
+	}
+	if len(l.queue) < l.table {
+		return true
+	}
+
+	// The longest waiting player meets those nearest their rating
+	first, rest := l.queue[0], slices.Clone(l.queue[1:])
+	slices.SortStableFunc(rest, func(a, b *ladderSeat) int {
+		return int(math.Abs(a.account.Rating-first.account.Rating) - math.Abs(b.account.Rating-first.account.Rating))
+	})
+	seats := append([]*ladderSeat{first}, rest[:l.table-1]...)
+	l.queue = slices.DeleteFunc(l.queue, func(s *ladderSeat) bool { return slices.Contains(seats, s) })
+	for _, s := range seats {
+		l.playing[s.account] = true
+	}
+	go l.play(seats)
+	return true
+}
+
+// leave takes a seat out of the queue when its connection closes
+func (l *Ladder) leave(seat *ladderSeat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue = slices.DeleteFunc(l.queue, func(s *ladderSeat) bool { return s == seat })
+}
+
+// LadderPlayer is a ranked player's seat. Their hit/stay decisions come
+// over their connection; action targets follow the expert strategy, as
+// they do for env agents. A player who lets a turn run out gets the
+// ladder's timeout fallback, and one who leaves stays from then on.
+type LadderPlayer struct {
+	*ComputerPlayer
+	seat   *ladderSeat
+	ladder *Ladder
+	game   *Game
+	events []EventMessage
+}
+
+// MakeHitStayDecision sends the player their observation and waits for
+// their decision, which is audited against the next card
+func (p *LadderPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	step := observe(p.game, p, gameState)
+	step.Events, p.events = p.events, nil
+	step.LegalActions = actionIndexes(p.game.LegalActions(p))
+	bust, duplicate, audited := auditDecision(p.game, p, gameState)
+	p.seat.open(step.LegalActions)
+	defer p.seat.shut()
+	p.seat.send(ladderMessage{Type: "turn", Step: &step})
+
+	turn, cancel := p.ladder.turn.Context(ctx)
+	defer cancel()
+	select {
+	case action := <-p.seat.actions:
+		hit := action == 1
+		if audited {
+			p.ladder.mu.Lock()
+			p.seat.account.Audit.record(bust, hit, duplicate)
+			p.ladder.mu.Unlock()
+		}
+		return hit, nil
+	case <-turn.Done():
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		p.ladder.mu.Lock()
+		p.seat.account.Audit.Timeouts++
+		p.ladder.mu.Unlock()
+		action := "stay"
+		if p.ladder.turn.Hit {
+			action = "hit"
+		}
+		p.seat.send(ladderMessage{Type: "error", Error: fmt.Sprintf("no decision within %v; you %s", p.ladder.turn.Limit, action)})
+		return p.ladder.turn.Hit, nil
+	case <-p.seat.gone:
+		return false, nil
+	}
+}
+
+// DecideAction plays Freeze and Flip Three on the leader and gives a
+// spare Second Chance to last place
+func (p *LadderPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	return p.targetStrategy(decision.Action)(p, gameState, decision.Action), nil
+}
+
+// play runs a ranked game and updates the players' ratings
+func (l *Ladder) play(seats []*ladderSeat) {
+	seed := time.Now().UnixNano()
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	g.SetRandom(mathrand.New(mathrand.NewSource(seed)))
+	g.SetWinningScore(l.score)
+	g.deck.Seed(seed)
+	var players []*LadderPlayer
+	var names []string
+	for _, seat := range seats {
+		p := &LadderPlayer{ComputerPlayer: NewComputerPlayerFromSpec(seat.account.Name, StrategySpec{Choice: 6}), seat: seat, ladder: l, game: g}
+		players = append(players, p)
+		g.players = append(g.players, p)
+		names = append(names, seat.account.Name)
+	}
+	g.Subscribe(func(event Event) {
+		for _, p := range players {
+			p.events = append(p.events, event.Message())
+		}
+	})
+	g.resetGameState()
+	g.dealerIdx = firstDealer(DealerRandom, 0, seed, len(g.players))
+	for _, seat := range seats {
+		seat.send(ladderMessage{Type: "match", Players: names})
+	}
+
+	err := g.runSingleGame(context.Background())
+	defer func() {
+		l.mu.Lock()
+		for _, seat := range seats {
+			delete(l.playing, seat.account)
+		}
+		l.mu.Unlock()
+	}()
+	if err != nil {
+		for _, seat := range seats {
+			seat.send(ladderMessage{Type: "error", Error: err.Error()})
+			seat.ws.Close()
+		}
+		return
+	}
+
+	winner := slices.IndexFunc(players, func(p *LadderPlayer) bool { return PlayerInterface(p) == g.getWinner() })
+	ratings := make([]float64, len(seats))
+	totals := make([]int, len(seats))
+	for i, seat := range seats {
+		ratings[i], totals[i] = seat.account.Rating, players[i].GetTotalScore()
+	}
+	changes := eloChanges(ratings, totals, winner)
+
+	l.mu.Lock()
+	l.rollover(time.Now())
+	for i, seat := range seats {
+		a := seat.account
+		a.Rating += changes[i]
+		a.Games++
+		if i == winner {
+			a.Wins++
+		}
+		if a.Audit.Flagged() {
+			fmt.Fprintf(os.Stderr, "⚠️  ladder audit: %s's decisions follow the hidden deck (%.1f standard deviations over %d decisions)\n",
+				a.Name, a.Audit.Suspicion(), a.Audit.Decisions)
+		}
+	}
+	if err := l.save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: saving ladder: %v\n", err)
+	}
+	l.mu.Unlock()
+
+	for i, seat := range seats {
+		step := observe(g, players[i], g.buildGameState())
+		step.Events = players[i].events
+		step.Done, step.LegalActions = true, []int{}
+		if i == winner {
+			step.Reward = 1
+		}
+		seat.send(ladderMessage{Type: "result", Step: &step, Rating: int(math.Round(seat.account.Rating)), Change: int(math.Round(changes[i]))})
+		seat.ws.Close()
+	}
+}
+
+// RunLadder handles the ladder subcommand: it shows a ladder server's
+// standings, or registers an account on it
+func RunLadder(args []string, out io.Writer) error {
+	fset := flag.NewFlagSet("ladder", flag.ContinueOnError)
+	server := fset.String("server", "http://localhost:8080", "Ladder server URL")
+	register := fset.String("register", "", "Register this name and print its token")
+	season := fset.Int("season", 0, "Show a past season's standings")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	base := strings.TrimSuffix(*server, "/")
+	report := NewGame()
+	report.SetOutput(out)
+
+	if *register != "" {
+		resp, err := http.PostForm(base+"/ladder/register", url.Values{"name": {*register}})
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		var account struct{ Name, Token string }
+		if err := ladderResponse(resp, &account); err != nil {
+			return err
+		}
+		report.printf(T("🏆 Registered %s. Your token, which won't be shown again: %s\n"), account.Name, account.Token)
+		report.printf(T("Play ranked games over a WebSocket at %s\n"), strings.Replace(base, "http", "ws", 1)+"/ladder/play?token="+account.Token)
+		return nil
+	}
+
+	standingsURL := base + "/ladder/standings"
+	if *season > 0 {
+		standingsURL += "?season=" + strconv.Itoa(*season)
+	}
+	resp, err := http.Get(standingsURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var s LadderSeason
+	if err := ladderResponse(resp, &s); err != nil {
+		return err
+	}
+	report.printf(T("🏆 LADDER - SEASON %d (from %s)\n"), s.Season, s.Started.Format("2006-01-02"))
+	if s.Ended != nil {
+		report.printf(T("Ended %s\n"), s.Ended.Format("2006-01-02"))
+	}
+	if len(s.Standings) == 0 {
+		report.println(T("No ranked games yet"))
+		return nil
+	}
+	report.printf("%4s  %-20s %6s %6s %6s\n", "#", T("Player"), T("Rating"), T("Games"), T("Wins"))
+	flagged := false
+	for _, st := range s.Standings {
+		mark := ""
+		if st.Flagged {
+			mark, flagged = " ⚠️", true
+		}
+		report.printf("%4d  %-20s %6d %6d %6d%s\n", st.Rank, st.Name, st.Rating, st.Games, st.Wins, mark)
+	}
+	if flagged {
+		report.println(T("⚠️  flagged: decisions follow the hidden deck too closely to be chance"))
+	}
+	return nil
+}
+
+// ladderResponse decodes a ladder server's JSON response, or its error
+func ladderResponse(resp *http.Response, v any) error {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ladder server: %s", strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
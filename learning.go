@@ -0,0 +1,92 @@
+package main
+
+// Learner is a strategy that learns as it plays: opponent models,
+// thresholds tuned by results and the like. What it has learned carries
+// from one game of a simulation to the next, so it improves over the
+// series, unless the simulation is set to start every game fresh.
+type Learner interface {
+	// GameOver is called at the end of every game with its winner
+	GameOver(self, winner PlayerInterface)
+	// Forget clears everything learned so far
+	Forget()
+}
+
+// SetFreshLearning makes every game start with learners that have
+// forgotten everything, so a simulation compares strategies as they
+// play a single game rather than as they improve over many
+func (g *Game) SetFreshLearning(enabled bool) {
+	g.freshLearning = enabled
+}
+
+// GameOver passes the game's result to the player's learning strategy
+func (p *ComputerPlayer) GameOver(self, winner PlayerInterface) {
+	if p.Learning != nil {
+		p.Learning.GameOver(self, winner)
+	}
+}
+
+// Forget clears what the player's learning strategy has learned
+func (p *ComputerPlayer) Forget() {
+	if p.Learning != nil {
+		p.Learning.Forget()
+	}
+}
+
+// learningPlayer is a player that may have a learning strategy
+type learningPlayer interface {
+	learning() Learner
+}
+
+// learning returns the player's learning strategy, nil if it has none
+func (p *ComputerPlayer) learning() Learner {
+	return p.Learning
+}
+
+// learningReporter is a Learner that can show what it learned at the end
+// of a simulation
+type learningReporter interface {
+	report(g *Game, name string)
+}
+
+// learningCloner is a Learner that can copy what it has learned, for a
+// cloned game to play with
+type learningCloner interface {
+	cloneLearning() Learner
+}
+
+// learnFromGame tells every learner how the game just played went
+func (g *Game) learnFromGame() {
+	winner := g.getWinner()
+	for _, player := range g.players {
+		if l, ok := player.(Learner); ok {
+			l.GameOver(player, winner)
+		}
+	}
+}
+
+// forgetLearning clears the learners before a game, when every game
+// starts fresh
+func (g *Game) forgetLearning() {
+	if !g.freshLearning {
+		return
+	}
+	for _, player := range g.players {
+		if l, ok := player.(Learner); ok {
+			l.Forget()
+		}
+	}
+}
+
+// displayLearning shows what each learner at the table learned over the
+// simulation, for those that can say
+func (g *Game) displayLearning() {
+	for _, player := range g.players {
+		p, ok := player.(learningPlayer)
+		if !ok {
+			continue
+		}
+		if r, ok := p.learning().(learningReporter); ok {
+			r.report(g, player.GetName())
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import "slices"
+
+// MoveKind is the kind of decision a move makes
+type MoveKind int
+
+const (
+	MoveStay MoveKind = iota
+	MoveHit
+	MoveTarget
+	MovePlay
+	MovePass
+)
+
+// Move is one legal decision: hitting, staying, giving an action card to
+// a target, or playing or keeping a held action card
+type Move struct {
+	Kind   MoveKind
+	Action ActionType      // the action card, for MoveTarget and MovePlay
+	Target PlayerInterface // who gets it, for MoveTarget
+}
+
+// pendingDecision is the decision the engine is waiting on, if any
+type pendingDecision struct {
+	player PlayerInterface
+	target bool // choosing an action card's target rather than hit or stay
+	play   bool // choosing whether to play a held action card
+	action ActionType
+}
+
+// LegalActions returns the moves open to player in the decision they
+// face now, so interfaces, bots and validators share the engine's rules.
+// While a player chooses an action card's target the moves are the
+// eligible targets, and while they choose whether to play a held action
+// card they may play any of them or pass; otherwise an active player may
+// hit, and stay once they hold a number card. A player out of the round
+// has none.
+func (g *Game) LegalActions(player PlayerInterface) []Move {
+	if g.deciding.player == player && g.deciding.play {
+		moves := []Move{{Kind: MovePass}}
+		for _, card := range player.HeldActions() {
+			moves = append(moves, Move{Kind: MovePlay, Action: card.Action})
+		}
+		return moves
+	}
+	if g.deciding.player == player && g.deciding.target {
+		var moves []Move
+		for _, target := range g.buildGameState().LegalTargets(player, g.deciding.action) {
+			moves = append(moves, Move{Kind: MoveTarget, Action: g.deciding.action, Target: target})
+		}
+		return moves
+	}
+	if !player.IsActive() {
+		return nil
+	}
+	if !player.HasCards() {
+		return []Move{{Kind: MoveHit}}
+	}
+	return []Move{{Kind: MoveStay}, {Kind: MoveHit}}
+}
+
+// ActionDecision is an action card a player must give to someone, and
+// who they may give it to. A Second Chance is only ever a decision when
+// the player already has one and must pass it on.
+type ActionDecision struct {
+	Action  ActionType
+	Targets []PlayerInterface // the legal targets, in seat order; never empty
+}
+
+// Question asks who gets the card
+func (d ActionDecision) Question() string {
+	switch d.Action {
+	case Freeze:
+		return T("Who should be frozen?")
+	case FlipThree:
+		return T("Who should flip three cards?")
+	}
+	return T("Who should get the Second Chance card?")
+}
+
+// Hint says what the card does to whoever gets it
+func (d ActionDecision) Hint() string {
+	switch d.Action {
+	case Freeze:
+		return T("They bank what they have and are out of the round")
+	case FlipThree:
+		return T("They must take the next three cards, and may bust")
+	}
+	return T("You can't hold two, so pass it to someone still in the round without one")
+}
+
+// moveTargets returns the targets of the MoveTarget moves, in order
+func moveTargets(moves []Move) []PlayerInterface {
+	var targets []PlayerInterface
+	for _, m := range moves {
+		if m.Kind == MoveTarget {
+			targets = append(targets, m.Target)
+		}
+	}
+	return targets
+}
+
+// LegalTargets returns who player may give an action card to: anyone
+// still in the round, themselves included, for Freeze and Flip Three,
+// and anyone in the round without one already for a Second Chance. The
+// slice may be the state's own ActivePlayers, so copy it to change it.
+func (s *GameState) LegalTargets(player PlayerInterface, actionType ActionType) []PlayerInterface {
+	if actionType != SecondChance {
+		return s.ActivePlayers
+	}
+	return slices.DeleteFunc(slices.Clone(s.ActivePlayers), PlayerInterface.HasSecondChance)
+}
+
+// decide records that the engine is waiting on a player's decision until
+// the returned function is called
+func (g *Game) decide(d pendingDecision) func() {
+	g.deciding = d
+	return func() { g.deciding = pendingDecision{} }
+}
+
+// actionIndexes returns hit/stay moves as indexes into envActions, as
+// the environment protocol sends them
+func actionIndexes(moves []Move) []int {
+	indexes := []int{}
+	for _, m := range moves {
+		switch m.Kind {
+		case MoveStay:
+			indexes = append(indexes, 0)
+		case MoveHit:
+			indexes = append(indexes, 1)
+		}
+	}
+	return indexes
+}
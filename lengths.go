@@ -0,0 +1,73 @@
+package main
+
+import "strings"
+
+// LengthStats tallies how long simulated games run: rounds per game,
+// turns per round and cards drawn per game, counting the cards dealt and
+// flipped by Flip Three
+type LengthStats struct {
+	Rounds ScoreDistribution
+	Turns  ScoreDistribution
+	Cards  ScoreDistribution
+
+	cards int // drawn so far in this game
+}
+
+// NewLengthStats creates an empty tally
+func NewLengthStats() *LengthStats {
+	return &LengthStats{
+		Rounds: make(ScoreDistribution),
+		Turns:  make(ScoreDistribution),
+		Cards:  make(ScoreDistribution),
+	}
+}
+
+// SetLengthStats turns on the game length report for simulations
+func (g *Game) SetLengthStats(enabled bool) {
+	g.lengthStats = enabled
+}
+
+// trackLengths counts the turns and cards of the game's rounds
+func (g *Game) trackLengths(l *LengthStats) {
+	g.Subscribe(func(event Event) {
+		switch event.Type {
+		case CardDrawn:
+			l.cards++
+		case RoundEnded:
+			l.Turns.Add(g.turns)
+		}
+	})
+}
+
+// recordGame counts the game just played
+func (l *LengthStats) recordGame(g *Game) {
+	l.Rounds.Add(g.round - 1)
+	l.Cards.Add(l.cards)
+	l.cards = 0
+}
+
+// mean is the average of the counted values
+func (d ScoreDistribution) mean() float64 {
+	total, n := 0, 0
+	for value, count := range d {
+		total += value * count
+		n += count
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(total) / float64(n)
+}
+
+// displayLengthStatistics shows how long the games and rounds ran
+func (g *Game) displayLengthStatistics(l *LengthStats) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf("%s\n", T("⏳ GAME LENGTH"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf(T("Average game: %.1f rounds, %.1f turns a round, %.1f cards drawn\n"), l.Rounds.mean(), l.Turns.mean(), l.Cards.mean())
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.displayHistogram(T("Rounds per game"), l.Rounds)
+	g.displayHistogram(T("Turns per round"), l.Turns)
+	g.displayHistogram(T("Cards drawn per game"), l.Cards)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Verbosity controls how much the game prints
+type Verbosity int
+
+const (
+	Quiet Verbosity = iota
+	Normal
+	Verbose
+	Trace
+)
+
+// LevelTrace is the slog level for every AI input/output and deck operation
+const LevelTrace = slog.LevelDebug - 4
+
+// LevelResult is the slog level for game and simulation results, which are
+// still shown in quiet mode
+const LevelResult = slog.LevelInfo + 2
+
+// ParseVerbosity parses a verbosity name
+func ParseVerbosity(name string) (Verbosity, error) {
+	switch strings.ToLower(name) {
+	case "quiet":
+		return Quiet, nil
+	case "normal":
+		return Normal, nil
+	case "verbose":
+		return Verbose, nil
+	case "trace":
+		return Trace, nil
+	}
+	return Normal, fmt.Errorf("unknown verbosity %q (available: quiet, normal, verbose, trace)", name)
+}
+
+// Level returns the minimum slog level shown at this verbosity
+func (v Verbosity) Level() slog.Level {
+	switch v {
+	case Quiet:
+		return LevelResult
+	case Verbose:
+		return slog.LevelDebug
+	case Trace:
+		return LevelTrace
+	}
+	return slog.LevelInfo
+}
+
+// consoleHandler is a slog.Handler for the game's console output. Info
+// records without attributes are game narration and are written verbatim;
+// everything else is written as a tagged key=value line.
+type consoleHandler struct {
+	w      io.Writer
+	level  slog.Leveler
+	attrs  []slog.Attr
+	prefix string
+	mu     *sync.Mutex
+}
+
+// newConsoleHandler creates a console handler writing to w
+func newConsoleHandler(w io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{w: w, level: level, mu: &sync.Mutex{}}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if r.Level >= slog.LevelInfo && r.Level < slog.LevelWarn && r.NumAttrs() == 0 && len(h.attrs) == 0 {
+		_, err := io.WriteString(h.w, r.Message)
+		return err
+	}
+
+	var b strings.Builder
+	switch {
+	case r.Level < slog.LevelDebug:
+		b.WriteString("   [trace] ")
+	case r.Level < slog.LevelInfo:
+		b.WriteString("   [verbose] ")
+	case r.Level >= slog.LevelError:
+		b.WriteString("[error] ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("[warn] ")
+	}
+	b.WriteString(r.Message)
+
+	writeAttr := func(a slog.Attr) {
+		if a.Equal(slog.Attr{}) {
+			return
+		}
+		fmt.Fprintf(&b, " %s%s=%v", h.prefix, a.Key, a.Value.Resolve())
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(a)
+		return true
+	})
+	b.WriteString("\n")
+
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// NewConsoleLogger creates the logger used for game output at a verbosity
+func NewConsoleLogger(w io.Writer, verbosity Verbosity) *slog.Logger {
+	return slog.New(newConsoleHandler(w, verbosity.Level()))
+}
+
+// logEvent writes every game event as a verbose record
+func (g *Game) logEvent(event Event) {
+	if !g.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
+	attrs := []any{"round", event.Round}
+	if event.Player != nil {
+		attrs = append(attrs, "player", event.Player.GetName())
+	}
+	if event.Target != nil {
+		attrs = append(attrs, "target", event.Target.GetName())
+	}
+	if event.Card != nil {
+		attrs = append(attrs, "card", event.Card.String())
+	}
+	if event.Score != 0 {
+		attrs = append(attrs, "score", event.Score)
+	}
+	g.logger.Debug(event.Type.String(), attrs...)
+}
+
+// resultf logs a formatted result line, shown even in quiet mode
+func (g *Game) resultf(format string, args ...any) {
+	if g.narrating() {
+		g.logger.Log(context.Background(), LevelResult, fmt.Sprintf(format, args...))
+	}
+}
+
+// trace logs a record at trace level
+func (g *Game) trace(msg string, args ...any) {
+	g.logger.Log(context.Background(), LevelTrace, msg, args...)
+}
+
+// tracing reports whether trace records are being written, so callers can
+// skip building expensive attributes
+func (g *Game) tracing() bool {
+	return g.logger.Enabled(context.Background(), LevelTrace)
+}
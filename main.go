@@ -1,26 +1,490 @@
+//go:build !js
+
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 )
 
 var debugMode = flag.Bool("debug", false, "Enable debug mode to manually choose cards")
+var debugDeck = flag.String("debug-deck", "", "Deal the cards listed in this file first, one per line, in order")
+var tuiMode = flag.Bool("tui", false, "Play in a full-screen terminal UI")
+var theme = flag.String("theme", "classic", "Color theme (none, classic, pastel, high-contrast)")
+var render = flag.String("render", "console", "How the game is drawn (console, json, silent)")
+var verbosity = flag.String("verbosity", "normal", "Output verbosity (quiet, normal, verbose, trace)")
+var lang = flag.String("lang", "", "Language for game text (en, es); defaults to the locale")
+var countCards = flag.Bool("count", false, "Show a card-counting panel before each of your decisions")
+var liveStandings = flag.Bool("standings", false, "Show the standings with the points in play before every turn")
+var showEval = flag.Bool("eval", false, "Show every player's chance of winning before every turn, for watching a game")
+var banter = flag.Bool("banter", true, "Let computer players comment on the game")
+var duplicate = flag.Bool("duplicate", false, "In simulations, deal each deck once per seating to cancel out deck luck")
+var seatStats = flag.Bool("seats", false, "After a simulation, report win rate and scores by seat")
+var actionStats = flag.Bool("actions", false, "After a simulation, report how much each action card changed outcomes")
+var histograms = flag.Bool("histograms", false, "After a simulation, show score histograms and percentiles for every player")
+var lengthStats = flag.Bool("lengths", false, "After a simulation, report rounds per game, turns per round and cards drawn per game")
+var decisions = flag.String("decisions", "", "Write every computer decision in simulations to this CSV file with its outcome")
+var exportScores = flag.String("export-scores", "", "Write every player's final and round score distributions to this CSV file")
+var freshLearning = flag.Bool("fresh", false, "In simulations, make learning strategies forget what they learned before every game")
+var fastStrategies = flag.Bool("fast", false, "In simulations, play bust-probability strategies from precomputed tables (same decisions, faster)")
+var rotateSeats = flag.Bool("rotate", false, "In simulations, move every player one seat along after each game")
+var dealer = flag.String("dealer", "fixed", "In simulations, who deals first in each game (fixed, rotate, random)")
+var duration = flag.Duration("duration", 0, "Simulate AI-only games for this long, e.g. 10m")
+var checkpoint = flag.String("checkpoint", "", "Save simulation progress to this file so it can be resumed")
+var record = flag.String("record", "", "Record the game to this replay file (.f7r) for flip7 analyze")
+var results = flag.String("results", "", "Write a line for every simulated game to this file")
+var htmlReport = flag.String("report", "", "Write the simulation results to this HTML report")
+var stopWhen = flag.String("stop-when", "", "In simulations, stop once win rates are this precise: ci<1% for every margin, separated for the top two, or both")
+var params = flag.String("params", "", "In simulations, read players' strategies from this YAML or TOML file and again whenever it changes")
+var casual = flag.Bool("casual", false, "Casual game: humans may undo their last decision")
+var roster = flag.String("roster", "", "Roster file of computer player names, preferred strategies and icons")
+var configFile = flag.String("config", "", "YAML or TOML file with players, strategies, rules and output options")
+var seed = flag.Int64("seed", 0, "Seed for the deck and computer players (0 picks one)")
+var script = flag.String("script", "", "Read every answer from a script file and make the output reproducible")
+var resume = flag.String("resume", "", "Resume a game from a save file, or a simulation from a checkpoint")
+var scenario = flag.String("scenario", "", "Start from a position described in a scenario file")
+var speed = flag.String("speed", "instant", "Pacing for watching AI-only games (instant, fast, normal, dramatic)")
+var metricsAddr = flag.String("metrics", "", "Serve Prometheus metrics for every game played at this address, e.g. :9090")
+var spansFile = flag.String("spans", "", "Write OpenTelemetry spans of every game, round, turn and decision to this OTLP/JSON file")
+var games = flag.Int("games", 0, "Number of AI-only games to simulate")
+var strategies strategyFlags
+
+func init() {
+	flag.Var(&strategies, "ai", "Add a computer player with this strategy, e.g. bustprob:0.28,target=leader; repeat for each player")
+}
+
+var symbols = flag.String("symbols", "auto", "Symbol profile for cards and icons (auto, ascii, unicode, emoji)")
 
 func main() {
 	flag.Parse()
+	if err := ApplyEnv(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Settings come from flags, then FLIP7_* variables, then the config file
+	if *configFile == "" {
+		if path, ok := DefaultConfigPath(); ok {
+			*configFile = path
+		}
+	}
+	var cfg *Config
+	if *configFile != "" {
+		var err error
+		if cfg, err = LoadConfig(*configFile); err == nil {
+			err = cfg.ApplyFlags(flag.CommandLine)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Players given with -ai replace the config file's
+	if len(strategies) > 0 || *games > 0 {
+		if cfg == nil {
+			cfg = &Config{}
+		}
+		if *games > 0 {
+			cfg.Games = *games
+		}
+		if len(strategies) > 0 {
+			if err := cfg.UseStrategies(strategies); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: -ai: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	if *metricsAddr != "" {
+		if err := StartMetrics(*metricsAddr); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *spansFile != "" {
+		if err := StartTracing(*spansFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := SetLanguage(*lang); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	profile, err := LookupSymbolProfile(*symbols)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	level, err := ParseVerbosity(*verbosity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	pace, err := ParseSpeed(*speed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	dealerMode, err := ParseDealerMode(*dealer)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var rosterEntries []RosterEntry
+	if *roster != "" {
+		rosterEntries, err = LoadRoster(*roster)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var debugCards []*Card
+	if *debugDeck != "" {
+		if *resume != "" || *scenario != "" {
+			fmt.Fprintln(os.Stderr, "Error: -debug-deck can't be used with -resume or -scenario; a scenario file lists its own deck")
+			os.Exit(1)
+		}
+		debugCards, err = LoadDebugDeck(*debugDeck)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *tuiMode {
+		game := NewGame()
+		game.SetVerbosity(level)
+		game.SetSpeed(pace)
+		game.SetDebugMode(*debugMode)
+		game.SetUndoEnabled(*debugMode || *casual)
+		game.SetCardCounting(*countCards)
+		game.SetLiveStandings(*liveStandings)
+		game.SetEvalBar(*showEval)
+		game.SetCommentary(*banter)
+		game.SetRoster(rosterEntries)
+		if cfg != nil {
+			if err := cfg.Apply(game); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if debugCards != nil {
+			if err := game.SetDebugDeck(debugCards); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *resume != "" {
+			if err := game.LoadGame(*resume); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *scenario != "" {
+			if err := loadScenario(game, *scenario); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := RunTUI(context.Background(), game, profile); err != nil && !errors.Is(err, ErrQuit) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	renderer, err := NewRenderer(*render, *theme, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := NewProfileWriter(os.Stdout, profile)
+	if flag.Arg(0) == "puzzle" {
+		if err := RunPuzzles(flag.Args()[1:], os.Stdin, out, renderer); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "golden" {
+		if err := RunGolden(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "determinism" {
+		if err := RunDeterminism(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "fixtures" {
+		if err := RunFixtures(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "simulate" {
+		if err := RunSimulate(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "duel" {
+		if err := RunDuel(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "exploit" {
+		if err := RunExploit(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "solve" {
+		if err := RunSolve(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "analyze" {
+		if err := RunAnalyze(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "export" {
+		if err := RunExport(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "tree" {
+		if err := RunTree(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	fmt.Println("🎴 Welcome to Flip 7!")
-	fmt.Println("Press your luck and flip your way to 200 points!")
-	if *debugMode {
-		fmt.Println("🐛 DEBUG MODE: You can choose cards manually!")
+	if flag.Arg(0) == "env" {
+		if err := RunEnv(flag.Args()[1:], os.Stdin, out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "schema" {
+		if err := RunSchema(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "serve" {
+		if err := RunServe(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "slack" {
+		if err := RunSlack(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "ladder" {
+		if err := RunLadder(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "arena" {
+		if err := RunArena(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "stats" {
+		if err := RunStats(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "soak" {
+		if err := RunSoak(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if flag.Arg(0) == "fuzz" {
+		if err := RunFuzz(flag.Args()[1:], out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if level != Quiet && renderer.Narrates() {
+		fmt.Fprintln(out, T("🎴 Welcome to Flip 7!"))
+		fmt.Fprintln(out, T("Press your luck and flip your way to 200 points!"))
+		if *debugMode {
+			fmt.Fprintln(out, T("🐛 DEBUG MODE: You can choose cards manually!"))
+		}
+		if debugCards != nil {
+			fmt.Fprintf(out, T("🐛 DEBUG DECK: The first %d cards come from %s\n"), len(debugCards), *debugDeck)
+		}
+		fmt.Fprintln(out)
 	}
-	fmt.Println()
 
 	game := NewGame()
+	game.SetOutput(out)
+	if *script != "" {
+		f, err := os.Open(*script)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		game.SetScript(f)
+		if *seed == 0 {
+			*seed = 1
+		}
+	}
+	if *seed != 0 {
+		SeedRandom(*seed)
+		game.SetSeed(*seed)
+	}
+	game.SetVerbosity(level)
+	game.SetSpeed(pace)
 	game.SetDebugMode(*debugMode)
-	if err := game.Run(); err != nil {
+	game.SetRenderer(renderer)
+	game.SetDuplicate(*duplicate)
+	game.SetSeatStats(*seatStats)
+	game.SetActionStats(*actionStats)
+	game.SetHistograms(*histograms)
+	game.SetLengthStats(*lengthStats)
+	game.SetScoresFile(*exportScores)
+	game.SetDecisions(*decisions)
+	game.SetRotateSeats(*rotateSeats)
+	game.SetFreshLearning(*freshLearning)
+	game.SetFastStrategies(*fastStrategies)
+	game.SetDealerMode(dealerMode)
+	game.SetTimeBudget(*duration)
+	game.SetCheckpoint(*checkpoint)
+	game.SetResults(*results)
+	game.SetReport(*htmlReport)
+	game.SetParams(*params)
+	if *stopWhen != "" {
+		rule, err := ParseStopRule(*stopWhen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -stop-when: %v\n", err)
+			os.Exit(1)
+		}
+		game.SetStopRule(rule)
+	}
+	game.SetRecord(*record)
+	game.SetUndoEnabled(*debugMode || *casual)
+	game.SetCardCounting(*countCards)
+	game.SetLiveStandings(*liveStandings)
+	game.SetEvalBar(*showEval)
+	game.SetCommentary(*banter)
+	game.SetRoster(rosterEntries)
+	// Ctrl-C cancels the game or simulation rather than killing the
+	// process; a second one kills it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if flag.Arg(0) == "daily" {
+		if err := RunDaily(ctx, game, flag.Args()[1:]); err != nil && !errors.Is(err, ErrQuit) && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	run := game.Run
+	if cfg != nil {
+		if err := cfg.Apply(game); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		run = func(ctx context.Context) error { return cfg.RunGames(ctx, game) }
+	}
+	if debugCards != nil {
+		if err := game.SetDebugDeck(debugCards); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *resume != "" && IsCheckpoint(*resume) {
+		run = func(ctx context.Context) error { return game.ResumeSimulation(ctx, *resume) }
+	} else if *resume != "" {
+		if err := game.LoadGame(*resume); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		run = game.Run
+	}
+	if *scenario != "" {
+		if err := loadScenario(game, *scenario); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		run = game.Run
+	}
+	if err := run(ctx); err != nil && !errors.Is(err, ErrQuit) && !errors.Is(err, context.Canceled) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
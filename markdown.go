@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// writeMarkdown writes a Markdown report to path
+func writeMarkdown(path string, write func(w io.Writer)) error {
+	var buf bytes.Buffer
+	write(&buf)
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// markdownTable writes a table as GitHub and most wikis draw it. Columns
+// of figures are right aligned and the rest left aligned.
+func markdownTable(w io.Writer, header []string, rows [][]string) {
+	line := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = markdownCell(cell)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+	}
+	line(header)
+	align := make([]string, len(header))
+	for i := range align {
+		align[i] = "---:"
+		for _, row := range rows {
+			if !isFigure(row[i]) {
+				align[i] = ":---"
+			}
+		}
+	}
+	fmt.Fprintf(w, "|%s|\n", strings.Join(align, "|"))
+	for _, row := range rows {
+		line(row)
+	}
+	fmt.Fprintln(w)
+}
+
+// isFigure reports whether a cell holds a number, or a dash for none
+func isFigure(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return s == "" || unicode.IsDigit(rune(s[0]))
+}
+
+// markdownCell escapes text for a table cell, which can't hold a pipe or
+// a line break
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// writeMarkdownNotables lists the best game of each kind with its seed, as
+// displayNotableGames does, and how to play one again
+func writeMarkdownNotables(w io.Writer, n *NotableGames, replay string) {
+	fmt.Fprintf(w, "### %s\n\n", T("Notable games"))
+	var rows [][]string
+	for kind := range numNotableKinds {
+		best := n.Best[kind]
+		if best == nil {
+			continue
+		}
+		count := "-"
+		if kind != NotableLongest {
+			count = fmt.Sprint(n.Count[kind])
+		}
+		rows = append(rows, []string{kind.String(), count, fmt.Sprint(best.Seed), best.Summary})
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(w, "%s\n\n", T("No notable games"))
+		return
+	}
+	markdownTable(w, []string{T("Kind"), T("Games"), T("Seed"), T("What happened")}, rows)
+	fmt.Fprintf(w, "%s\n\n", replay)
+}
+
+// writeMarkdown writes the duel's report as Markdown: the standings, each
+// side's record and round scores, the verdict and the notable games. a and
+// b are the strategies as given, for the command that replays a deal.
+func (d *Duel) writeMarkdown(w io.Writer, seed int64, a, b string, score int) {
+	names := []string{d.Players[0].GetName(), d.Players[1].GetName()}
+	fmt.Fprintf(w, "## %s\n\n", fmt.Sprintf(T("⚔️ Duel: %s vs %s"), names[0], names[1]))
+	fmt.Fprintf(w, "%s\n\n", fmt.Sprintf(T("%d games from seed %d, first to %d points. Every deck is dealt twice with the seats swapped."), d.Games, seed, score))
+
+	fmt.Fprintf(w, "### %s\n\n", T("Standings"))
+	order := []int{0, 1}
+	if d.Stats[1].Wins > d.Stats[0].Wins {
+		order = []int{1, 0}
+	}
+	rate := float64(d.Stats[0].Wins) / float64(d.Games)
+	margin := 200 * math.Sqrt(rate*(1-rate)/float64(d.Games))
+	var rows [][]string
+	for rank, side := range order {
+		stats := d.Stats[side]
+		rows = append(rows, []string{fmt.Sprint(rank + 1), names[side], fmt.Sprint(stats.Wins),
+			fmt.Sprintf("%.1f%% ± %.1f%%", pct(stats.Wins, d.Games), margin), fmt.Sprintf("%.1f", avg(stats.Total, d.Games))})
+	}
+	markdownTable(w, []string{"#", T("Player"), T("Wins"), T("Win rate"), T("Average final score")}, rows)
+	result, comparison := d.verdict()
+	fmt.Fprintf(w, "%s  \n%s\n", strings.TrimSpace(result), comparison)
+
+	fmt.Fprintf(w, "### %s\n\n", T("Per-strategy stats"))
+	rows = nil
+	for _, r := range d.rows() {
+		rows = append(rows, []string{r.Label, r.A, r.B})
+	}
+	markdownTable(w, append([]string{""}, names...), rows)
+
+	fmt.Fprintf(w, "### %s\n\n", T("Round scores"))
+	rows = nil
+	for _, r := range d.roundScores() {
+		rows = append(rows, []string{r.Label, r.A, r.B})
+	}
+	markdownTable(w, append([]string{""}, names...), rows)
+
+	if d.Notables != nil {
+		replay := fmt.Sprintf(T("Play a deal again both ways with `flip7 duel -a %s -b %s -score %d -n 2 -seed SEED`."), a, b, score)
+		writeMarkdownNotables(w, d.Notables, replay)
+	}
+}
+
+// writeMarkdown writes the tournament's report as Markdown: the
+// standings, each bot's rounds and the notable games
+func (r *ArenaResults) writeMarkdown(w io.Writer, bots, table int) {
+	fmt.Fprintf(w, "## %s\n\n", fmt.Sprintf(T("🏟️ Arena: %d bots"), bots))
+	fmt.Fprintf(w, "%s\n\n", fmt.Sprintf(T("%d games at tables of %d, first to %d points, played %s."), r.Games, table, r.Score, r.Finished.Format("2006-01-02")))
+
+	fmt.Fprintf(w, "### %s\n\n", T("Standings"))
+	header := []string{"#", T("Bot"), T("Games"), T("Wins"), T("Win rate"), T("Average final score"), T("Timeouts")}
+	disqualified := slices.ContainsFunc(r.Standings, func(s ArenaStanding) bool { return s.Disqualified != "" })
+	if disqualified {
+		header = append(header, T("Disqualified"))
+	}
+	var rows [][]string
+	for _, s := range r.Standings {
+		winRate := "-"
+		if s.Games > 0 {
+			rate := float64(s.Wins) / float64(s.Games)
+			winRate = fmt.Sprintf("%.1f%% ± %.1f%%", 100*rate, 200*math.Sqrt(rate*(1-rate)/float64(s.Games)))
+		}
+		row := []string{fmt.Sprint(s.Rank), s.Name, fmt.Sprint(s.Games), fmt.Sprint(s.Wins), winRate,
+			fmt.Sprintf("%.1f", s.AverageScore), fmt.Sprint(s.Timeouts)}
+		if disqualified {
+			row = append(row, s.Disqualified)
+		}
+		rows = append(rows, row)
+	}
+	markdownTable(w, header, rows)
+	if r.Skipped > 0 {
+		fmt.Fprintf(w, T("%d games skipped for disqualified bots\n"), r.Skipped)
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "### %s\n\n", T("Per-strategy stats"))
+	rows = nil
+	for _, s := range r.Standings {
+		rows = append(rows, []string{s.Name, fmt.Sprint(s.Rounds), fmt.Sprintf("%.1f%%", pct(s.Busts, s.Rounds)),
+			fmt.Sprintf("%.1f%%", pct(s.Flip7s, s.Rounds)), fmt.Sprintf("%.1f", avg(s.Points, s.Rounds)),
+			fmt.Sprintf("%.1f", avg(s.Points, s.Rounds-s.Busts))})
+	}
+	markdownTable(w, []string{T("Bot"), T("Rounds"), T("Rounds busted"), T("Rounds with Flip 7"), T("Average round score"), T("Average banked round")}, rows)
+
+	if r.Notables != nil {
+		writeMarkdownNotables(w, r.Notables, T("Every game is in `games.jsonl` with its seed."))
+	}
+}
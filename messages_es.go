@@ -0,0 +1,541 @@
+package main
+
+// spanishCatalog is the Spanish (es) message catalog
+var spanishCatalog = Catalog{
+	// Welcome and setup
+	"🎴 Welcome to Flip 7!":                                                 "🎴 ¡Bienvenido a Flip 7!",
+	"Press your luck and flip your way to 200 points!":                     "¡Tienta a la suerte y voltea cartas hasta llegar a 200 puntos!",
+	"🐛 DEBUG MODE: You can choose cards manually!":                         "🐛 MODO DEPURACIÓN: ¡Puedes elegir las cartas a mano!",
+	"🐛 DEBUG DECK: The first %d cards come from %s\n":                      "🐛 MAZO DE DEPURACIÓN: Las primeras %d cartas salen de %s\n",
+	"How many players total? (2-18): ":                                     "¿Cuántos jugadores en total? (2-18): ",
+	"How many human players? (0-%d): ":                                     "¿Cuántos jugadores humanos? (0-%d): ",
+	"Enter name for Human Player %d: ":                                     "Nombre del jugador humano %d: ",
+	"  → Added: %s (%s AI)\n":                                              "  → Añadido: %s (IA %s)\n",
+	"\n🎮 Starting AI-only Flip 7 with %d computer players!\n":              "\n🎮 ¡Empieza Flip 7 solo con IA y %d jugadores de computadora!\n",
+	"🍿 Sit back and watch the AIs battle it out!":                          "🍿 ¡Ponte cómodo y mira cómo se enfrentan las IA!",
+	"\nHow many games would you like to simulate? ":                        "\n¿Cuántas partidas quieres simular? ",
+	"\n🎮 Starting Flip 7 with %d humans and %d computers!\n":               "\n🎮 ¡Empieza Flip 7 con %d humanos y %d computadoras!\n",
+	"\nComputer Player %d:\n":                                              "\nJugador de computadora %d:\n",
+	"Choose AI strategy:":                                                  "Elige la estrategia de la IA:",
+	"  1) Plays to some score":                                             "  1) Juega hasta cierta puntuación",
+	"  2) Plays to against some bust probability threshold (counts cards)": "  2) Juega contra un umbral de probabilidad de pasarse (cuenta cartas)",
+	"  3) FLIP 7 (always hits)":                                            "  3) FLIP 7 (siempre pide)",
+	"  4) Random":                                                          "  4) Aleatoria",
+	"  5) Adaptive Bust Prob (0.3)":                                        "  5) Prob. de pasarse adaptativa (0.3)",
+	"  6) Expected Value":                                                  "  6) Valor esperado",
+	"  7) Hybrid Strategy":                                                 "  7) Estrategia híbrida",
+	"  8) Gap-Based Strategy":                                              "  8) Estrategia según la diferencia",
+	"  9) Optimal Strategy":                                                "  9) Estrategia óptima",
+	"  10) Bayesian Gain Strategy":                                         "  10) Estrategia de ganancia bayesiana",
+	"  11) Gap Aware Stragegy":                                             "  11) Estrategia consciente de la diferencia",
+	"  12) Solved Table":                                                   "  12) Tabla resuelta",
+	"  13) Bandit (learns which strategy to follow)":                       "  13) Bandido (aprende qué estrategia seguir)",
+	"  14) Risk Utility (protects what it has)":                            "  14) Utilidad con riesgo (protege lo que tiene)",
+	"  15) Imitate (plays like recorded human games)":                      "  15) Imitar (juega como las partidas humanas grabadas)",
+	"Enter choice (1-15): ":                                                "Elige una opción (1-15): ",
+	"Enter Corpus of Recorded Games (JSONL): ":                             "Introduce el corpus de partidas grabadas (JSONL): ",
+	"⚠️ %v; playing Expected Value instead\n":                              "⚠️ %v; se usa Valor esperado en su lugar\n",
+	"Enter Risk Aversion (0-10, e.g., 2): ":                                "Introduce la aversión al riesgo (0-10, p. ej., 2): ",
+	"🎰 BANDIT ARMS - %s\n":                                                 "🎰 BRAZOS DEL BANDIDO - %s\n",
+	"ARM":                                                                  "BRAZO",
+	"WEIGHT":                                                               "PESO",
+	"AVG PTS":                                                              "PTS MEDIOS",
+	"Enter Target Score: ":                                                 "Puntuación objetivo: ",
+	"Enter Bust Probability Threshold: ":                                   "Umbral de probabilidad de pasarse: ",
+	"Enter Gap Tolerance (e.g., 5): ":                                      "Tolerancia de diferencia (p. ej., 5): ",
+	"Enter Slack Factor (e.g., 5): ":                                       "Factor de holgura (p. ej., 5): ",
+	"Please enter a valid number between %d and %d: ":                      "Introduce un número válido entre %d y %d: ",
+	"Please enter a number between %d and %d: ":                            "Introduce un número entre %d y %d: ",
+	"Please enter a number between 1 and %d: ":                             "Introduce un número entre 1 y %d: ",
+	"Enter choice (1-%d): ":                                                "Elige una opción (1-%d): ",
+	"Play a held action card?":                                             "¿Jugar una carta de acción guardada?",
+	"Not now":                                                              "Ahora no",
+	"Enter choice (0-%d): ":                                                "Elige una opción (0-%d): ",
+	"Please enter a number between 0 and %d: ":                             "Introduce un número entre 0 y %d: ",
+	"   🎴 %s keeps %s to play later\n":                                     "   🎴 %s guarda %s para jugarla más tarde\n",
+	"   🎴 %s plays %s from their hand\n":                                   "   🎴 %s juega %s de su mano\n",
+
+	// Game flow
+	"\n🎮 Starting Flip 7! %s\n":                                          "\n🎮 ¡Empieza Flip 7! %s\n",
+	"First to %d points wins!":                                           "¡Gana el primero en llegar a %d puntos!",
+	"Most points after %d rounds wins!":                                  "¡Gana quien tenga más puntos tras %d rondas!",
+	"Lowest total is knocked out after each round; last player in wins!": "El total más bajo queda eliminado tras cada ronda; ¡gana el último en pie!",
+	"First team to %d points wins!":                                      "¡Gana el primer equipo en llegar a %d puntos!",
+	"🎉 GAME OVER! Team %s wins with %d points! 🎉":                        "🎉 ¡FIN DE LA PARTIDA! ¡El equipo %s gana con %d puntos! 🎉",
+	"❌ %s is knocked out with %d points\n":                               "❌ %s queda eliminado con %d puntos\n",
+	"   ❌ OUT":                                                           "   ❌ ELIMINADO",
+	"❌ OUT":                                                              "❌ ELIMINADO",
+	"🎯 ROUND %d":                                                         "🎯 RONDA %d",
+	"Dealer: %s\n\n":                                                     "Reparte: %s\n\n",
+	"🃏 Dealing initial cards...":                                         "🃏 Repartiendo las cartas iniciales...",
+	"   %s draws %s\n":                                                   "   %s saca %s\n",
+	"🎯 %s has no number cards and must HIT\n":                            "🎯 %s no tiene cartas numéricas y debe PEDIR\n",
+	"   %s stays with %d points\n":                                       "   %s se planta con %d puntos\n",
+	"\n📊 Current Scores:":                                                "\n📊 Puntuaciones actuales:",
+	"📈 Standings:":                                                       "📈 Clasificación:",
+	", needs %d":                                                         ", le faltan %d",
+	", enough to win":                                                    ", suficiente para ganar",
+	"%d. %s %-20s: %3d points\n":                                         "%d. %s %-20s: %3d puntos\n",
+	"📊 Calculating round scores...":                                      "📊 Calculando las puntuaciones de la ronda...",
+	"%s: %d points this round (Total: %d)\n":                             "%s: %d puntos esta ronda (Total: %d)\n",
+	"🎉 GAME OVER! %s wins with %d points! 🎉":                             "🎉 ¡FIN DE LA PARTIDA! ¡%s gana con %d puntos! 🎉",
+
+	// Action cards
+	"FREEZE":                 "CONGELAR",
+	"FLIP 3":                 "VOLTEA 3",
+	"2ND CHANCE":             "2ª OPORTUNIDAD",
+	"   🎲 Action card! %s\n": "   🎲 ¡Carta de acción! %s\n",
+	"   ❄️ %s is frozen and stays with %d points!\n":                           "   ❄️ ¡%s queda congelado y se planta con %d puntos!\n",
+	"   🎲 %s must flip 3 cards!\n":                                             "   🎲 ¡%s debe voltear 3 cartas!\n",
+	"      Card %d: %s\n":                                                      "      Carta %d: %s\n",
+	"   🆘 %s receives a Second Chance card!\n":                                 "   🆘 ¡%s recibe una carta de Segunda Oportunidad!\n",
+	"   🆘 %s already has Second Chance, must give to another player\n":         "   🆘 %s ya tiene Segunda Oportunidad, debe dársela a otro jugador\n",
+	"   🆘 Everyone left in the round has a Second Chance, so it's discarded\n": "   🆘 Todos los que quedan en la ronda tienen Segunda Oportunidad, así que se descarta\n",
+	"   🆘 %s cannot take the Second Chance card, discarding\n":                 "   🆘 %s no puede recibir la Segunda Oportunidad, se descarta\n",
+	"   🆘 %s gives the Second Chance card to %s\n":                             "   🆘 %s le da la Segunda Oportunidad a %s\n",
+	"Who should be frozen?":                                                    "¿A quién congelar?",
+	"Who should flip three cards?":                                             "¿Quién debe voltear tres cartas?",
+	"Who should get the Second Chance card?":                                   "¿Quién recibe la Segunda Oportunidad?",
+	"They bank what they have and are out of the round":                        "Se guarda lo que tiene y queda fuera de la ronda",
+	"They must take the next three cards, and may bust":                        "Debe tomar las tres cartas siguientes, y puede pasarse",
+	"You can't hold two, so pass it to someone still in the round without one": "No puedes tener dos, así que pásasela a alguien de la ronda que no tenga",
+	"%s (you)": "%s (tú)",
+	"   %d) %s: %s - %d points this round, %d in total\n": "   %d) %s: %s - %d puntos esta ronda, %d en total\n",
+
+	// Flip 7 and busts
+	"   🎉 %s achieved FLIP 7!":                             "   🎉 ¡%s consiguió FLIP 7!",
+	"   🎉 %s achieved FLIP 7 and wins the round!":          "   🎉 ¡%s consiguió FLIP 7 y gana la ronda!",
+	"   💥 %s drew a duplicate %s but has Second Chance!\n": "   💥 ¡%s sacó un %s repetido pero tiene Segunda Oportunidad!\n",
+	"   💥 %s busts and is out of the round!":               "   💥 ¡%s se pasa y queda fuera de la ronda!",
+
+	// Hands
+	"   No cards":                      "   Sin cartas",
+	"   Numbers: ":                     "   Números: ",
+	"   Modifiers: ":                   "   Modificadores: ",
+	"   Held: ":                        "   Guardadas: ",
+	"   🆘 Has Second Chance":           "   🆘 Tiene Segunda Oportunidad",
+	"   ✅ STAYED - Round Score: %d\n":  "   ✅ PLANTADO - Puntos de la ronda: %d\n",
+	"   💥 BUSTED":                      "   💥 SE PASÓ",
+	"💥 BUSTED":                         "💥 SE PASÓ",
+	"   ❄️ FROZEN - Round Score: %d\n": "   ❄️ CONGELADO - Puntos de la ronda: %d\n",
+	"   💥 BUSTED on %s":                "   💥 SE PASÓ con %s",
+	"💥 BUSTED on %d":                   "💥 SE PASÓ con %d",
+	"No cards":                         "Sin cartas",
+	" (STAYED: %d pts)":                " (PLANTADO: %d pts)",
+	" (FROZEN: %d pts)":                " (CONGELADO: %d pts)",
+
+	// Human prompts
+	"%s's hand, %v\n": "Mano de %s, %v\n",
+	"🎯 %s, do you want to (H)it or (S)tay? ":                              "🎯 %s, ¿quieres (P)edir o (Q)uedarte? ",
+	"Please enter 'H' for Hit or 'S' for Stay (or 'help' for commands): ": "Escribe 'P' para Pedir o 'Q' para Quedarte (o 'help' para ver los comandos): ",
+	"h":    "p",
+	"hit":  "pedir",
+	"s":    "q",
+	"stay": "quedarme",
+
+	// Command palette
+	"Commands:":                         "Comandos:",
+	"  scores    - show the scoreboard": "  scores    - muestra el marcador",
+	"  standings - show the standings with the points in play":           "  standings - muestra la clasificación con los puntos en juego",
+	"  hands     - show every player's hand":                             "  hands     - muestra la mano de cada jugador",
+	"  discards  - show the discard pile":                                "  discards  - muestra la pila de descartes",
+	"  odds      - show your chance of busting if you hit":               "  odds      - muestra tu probabilidad de pasarte si pides",
+	"  history   - show what has happened this round":                    "  history   - muestra lo ocurrido en esta ronda",
+	"  undo      - take back your last decision this round":              "  undo      - deshace tu última decisión de esta ronda",
+	"  :edit     - set scores, hands, states and the round (debug mode)": "  :edit     - cambia puntuaciones, manos, estados y la ronda (modo depuración)",
+	"  count     - show unseen cards and your bust chance":               "  count     - muestra las cartas no vistas y tu probabilidad de pasarte",
+	"🔢 Unseen cards:":                                    "🔢 Cartas no vistas:",
+	"  %d unseen cards, your bust chance: %.1f%%\n":      "  %d cartas no vistas, tu probabilidad de pasarte: %.1f%%\n",
+	"%s scored %d":                                       "%s anotó %d",
+	"📰 Round %d recap":                                   "📰 Resumen de la ronda %d",
+	"   Biggest hand: %s with %d points\n":               "   Mejor mano: %s con %d puntos\n",
+	"📈 Score by round (rounds %d-%d):":                   "📈 Puntuación por ronda (rondas %d-%d):",
+	"tied":                                               "empate",
+	"   Lead: %s\n":                                      "   En cabeza: %s\n",
+	"   Biggest round: %s, +%d in round %d\n":            "   Mejor ronda: %s, +%d en la ronda %d\n",
+	"   %s flipped 7!\n":                                 "   ¡%s consiguió Flip 7!\n",
+	"   %s busted on %s\n":                               "   %s se pasó con %s\n",
+	"   %s kept %s\n":                                    "   %s se quedó con %s\n",
+	"   %s played %s on %s\n":                            "   %s jugó %s sobre %s\n",
+	"   Standings:":                                      "   Clasificación:",
+	"   %d. %-20s %3d (+%d)%s\n":                         "   %d. %-20s %3d (+%d)%s\n",
+	"🧩 Puzzle %d: %s":                                    "🧩 Problema %d: %s",
+	"Your total: %d\n\n":                                 "Tu total: %d\n\n",
+	"%s has %d banked\n":                                 "%s tiene %d acumulados\n",
+	"🎯 Do you (H)it or (S)tay? ":                         "🎯 ¿(P)edir o (Q)uedarse? ",
+	"You drew %s. Who gets it?\n":                        "Sacaste %s. ¿Para quién es?\n",
+	"Please enter 'H' for Hit or 'S' for Stay: ":         "Escribe 'P' para pedir o 'Q' para quedarte: ",
+	"✅ Correct! Best move: %s\n":                         "✅ ¡Correcto! Mejor jugada: %s\n",
+	"❌ Not quite. Best move: %s\n":                       "❌ No del todo. Mejor jugada: %s\n",
+	"\n🧩 Solved %d of %d puzzles, streak %d (best %d)\n": "\n🧩 Resueltos %d de %d problemas, racha %d (mejor %d)\n",
+	"Staying banks %d. Hitting busts %.0f%% of the time and averages %.1f points after one card.": "Quedarte asegura %d. Pedir te pasa el %.0f%% de las veces y promedia %.1f puntos tras una carta.",
+	"Freezing %s denies the most: they expect to gain %.1f points by hitting.":                    "Congelar a %s es lo que más le quita: espera ganar %.1f puntos pidiendo.",
+	"%s has %d points at stake and about a %.0f%% chance to bust over three cards.":               "%s arriesga %d puntos y tiene cerca de un %.0f%% de pasarse en tres cartas.",
+	"Generated position":         "Posición generada",
+	"You":                        "Tú",
+	"Big hand, long odds":        "Mano grande, pocas probabilidades",
+	"A slow start":               "Un comienzo lento",
+	"One away from Flip 7":       "A una carta de Flip 7",
+	"Who flips three?":           "¿Quién voltea tres?",
+	"Cold feet":                  "Pies fríos",
+	"Doubled up":                 "Doble o nada",
+	"📅 Daily challenge for %s\n": "📅 Reto diario del %s\n",
+	"📅 Flip 7 daily %s: %s scored %d in %d rounds\n":   "📅 Flip 7 diario %s: %s anotó %d en %d rondas\n",
+	"   Also played: %s scored %d in %d rounds\n":      "   También jugó: %s anotó %d en %d rondas\n",
+	"\nChoose computer difficulty:":                    "\nElige la dificultad de la computadora:",
+	"  %d) Custom (choose each computer's strategy)\n": "  %d) Personalizada (elige la estrategia de cada computadora)\n",
+	"Easy":   "Fácil",
+	"Medium": "Media",
+	"Hard":   "Difícil",
+	"Expert": "Experta",
+	"  %d) Adaptive (adjusts to your win rate between games)\n":                          "  %d) Adaptativa (se ajusta a tu tasa de victorias entre partidas)\n",
+	"🎚️ Adaptive computers at skill %.2f (your recent win rate: %.0f%% over %d games)\n": "🎚️ Computadoras adaptativas con habilidad %.2f (tu tasa reciente de victorias: %.0f%% en %d partidas)\n",
+	"The odds were in my favor. They were!":                                              "Las probabilidades estaban a mi favor. ¡Lo estaban!",
+	"I knew I should have stayed.":                                                       "Sabía que debía quedarme.",
+	"Safety first, %s. Especially yours.":                                                "La seguridad primero, %s. Sobre todo la tuya.",
+	"Let's keep things calm, %s.":                                                        "Mantengamos la calma, %s.",
+	"Careful now, %s.":                                                                   "Con cuidado, %s.",
+	"Three cards, %s. Count them carefully.":                                             "Tres cartas, %s. Cuéntalas con cuidado.",
+	"Seven, one careful step at a time.":                                                 "Siete, un paso prudente a la vez.",
+	"Slow and steady.":                                                                   "Despacio y con buena letra.",
+	"Worth it!":                                                                          "¡Valió la pena!",
+	"Again! Deal me in again!":                                                           "¡Otra vez! ¡Repárteme otra vez!",
+	"Chill out, %s!":                                                                     "¡Relájate, %s!",
+	"Ice to meet you, %s.":                                                               "Un placer congelarte, %s.",
+	"Go big, %s!":                                                                        "¡A lo grande, %s!",
+	"Flip 'em, %s! Flip 'em all!":                                                        "¡Voltéalas, %s! ¡Voltéalas todas!",
+	"FLIP SEVEN! Who needs odds?":                                                        "¡FLIP SIETE! ¿Quién necesita probabilidades?",
+	"Fortune favors the bold!":                                                           "¡La fortuna favorece a los audaces!",
+	"A statistically acceptable outcome.":                                                "Un resultado estadísticamente aceptable.",
+	"Recalculating...":                                                                   "Recalculando...",
+	"Freezing the leader maximizes my equity, %s.":                                       "Congelar al líder maximiza mi ventaja, %s.",
+	"Nothing personal, %s. Just math.":                                                   "Nada personal, %s. Solo matemáticas.",
+	"Your expected value just dropped, %s.":                                              "Tu valor esperado acaba de bajar, %s.",
+	"Flip 7, exactly as projected.":                                                      "Flip 7, tal como estaba previsto.",
+	"The numbers never lie.":                                                             "Los números nunca mienten.",
+	"Argh! Not again!":                                                                   "¡Argh! ¡Otra vez no!",
+	"That one hurt.":                                                                     "Eso dolió.",
+	"Not so fast, %s!":                                                                   "¡No tan rápido, %s!",
+	"Your lead ends here, %s.":                                                           "Tu ventaja termina aquí, %s.",
+	"Let's see you handle this, %s!":                                                     "¡A ver cómo manejas esto, %s!",
+	"Seven! Catch me if you can!":                                                        "¡Siete! ¡Atrápame si puedes!",
+	"Frozen? Fine by me, %s. I'll keep what I have.":                                     "¿Congelado? Me parece bien, %s. Me quedo con lo que tengo.",
+	"Three cards, %s? That's not how I play.":                                            "¿Tres cartas, %s? Así no es como juego yo.",
+	"Thank you, %s. I'll put it somewhere safe.":                                         "Gracias, %s. La guardaré en un lugar seguro.",
+	"Hey! I was just getting started, %s!":                                               "¡Eh! ¡Estaba empezando, %s!",
+	"Three more? Thanks, %s, I love it!":                                                 "¿Tres más? ¡Gracias, %s, me encanta!",
+	"A safety net, %s? Now I can really go for it!":                                      "¿Una red de seguridad, %s? ¡Ahora sí que voy a por todas!",
+	"Noted, %s. Adjusting my model.":                                                     "Anotado, %s. Ajustando mi modelo.",
+	"Variance introduced, %s. Noted.":                                                    "Varianza introducida, %s. Anotado.",
+	"A free option, %s. Accepted.":                                                       "Una opción gratuita, %s. Aceptada.",
+	"You'll pay for that, %s!":                                                           "¡Me las pagarás, %s!",
+	"Bring it on, %s!":                                                                   "¡Adelante, %s!",
+	"You'll regret helping me, %s.":                                                      "Te arrepentirás de ayudarme, %s.",
+	"Never in doubt!":                                                                    "¡Nunca lo dudé!",
+	"   💬 %s: \"%s\"\n":                                                                  "   💬 %s: \"%s\"\n",
+	"Nothing to undo this round":                                                         "No hay nada que deshacer en esta ronda",
+	"↩️ Undone: back to your previous decision":                                          "↩️ Deshecho: vuelves a tu decisión anterior",
+
+	// State editor
+	"🛠️ State editor: type help for commands, done to go back to the game": "🛠️ Editor de estado: escribe help para ver los comandos, done para volver a la partida",
+	"edit> ":                 "editar> ",
+	"Can't do that: %v\n":    "No se puede: %v\n",
+	"Now in round %d\n":      "Ahora en la ronda %d\n",
+	"%s now has %d points\n": "%s tiene ahora %d puntos\n",
+	"%s is now %s\n":         "%s está ahora %s\n",
+	"Editor commands (players by seat number or the start of their name):":                "Comandos del editor (jugadores por número de asiento o el principio de su nombre):",
+	"  score <player> <points>  - set a player's total score":                             "  score <jugador> <puntos> - fija la puntuación total de un jugador",
+	"  give <player> <card>...  - move cards from the deck into a hand, e.g. give 2 7 x2": "  give <jugador> <carta>... - pasa cartas del mazo a una mano, p. ej. give 2 7 x2",
+	"  state <player> <state>   - make a player active, stayed, frozen or busted":         "  state <jugador> <estado> - deja a un jugador active, stayed, frozen o busted",
+	"  round <n>                - set the round number":                                   "  round <n>                - fija el número de ronda",
+	"  hands                    - show every hand and the scores":                         "  hands                    - muestra todas las manos y las puntuaciones",
+	"  done                     - go back to the game":                                    "  done                     - vuelve a la partida",
+	"  quit      - quit the game, optionally saving it":                                   "  quit      - sale de la partida, con opción de guardarla",
+	"The discard pile is empty":                                                           "La pila de descartes está vacía",
+	"Discard pile (%d cards): %s\n":                                                       "Pila de descartes (%d cartas): %s\n",
+	"The deck is empty":                                                                   "El mazo está vacío",
+	"Final round: you win if it ends now":                                                 "Última ronda: ganas si termina ahora",
+	"  🏁 The game ends if the round ends now":                                             "  🏁 La partida termina si la ronda termina ahora",
+	"Final round: %s wins if it ends now; you need %d more points to overtake\n":          "Última ronda: %s gana si termina ahora; te faltan %d puntos para adelantarle\n",
+	"Chance of being frozen before your next turn: %.1f%%\n":                              "Probabilidad de que te congelen antes de tu próximo turno: %.1f%%\n",
+	"Chance to bust if you hit: %.1f%% (%d cards in the deck)\n":                          "Probabilidad de pasarte si pides: %.1f%% (%d cartas en el mazo)\n",
+	"Numbers left in the deck: %s\n":                                                      "Números que quedan en el mazo: %s\n",
+	"Nothing has happened yet":                                                            "Todavía no ha pasado nada",
+	"\n\n🛑 Interrupted":                                                                   "\n\n🛑 Interrumpida",
+	"Really quit? (y/n): ":                                                                "¿Seguro que quieres salir? (s/n): ",
+	"Save the game before quitting? (y/n): ":                                              "¿Guardar la partida antes de salir? (s/n): ",
+	"Save file [%s]: ":                                                                    "Archivo de guardado [%s]: ",
+	"Could not save the game: %v\n":                                                       "No se pudo guardar la partida: %v\n",
+	"Game saved to %s. Resume with: flip7 -resume %s\n":                                   "Partida guardada en %s. Continúala con: flip7 -resume %s\n",
+	"y":   "s",
+	"yes": "si",
+
+	// Debug card picker
+	"\n🐛 DEBUG: Choose a card to draw:":                            "\n🐛 DEPURACIÓN: Elige la carta a sacar:",
+	"Available cards (%d total):\n":                                "Cartas disponibles (%d en total):\n",
+	"\nNumber Cards:":                                              "\nCartas numéricas:",
+	"\nAction Cards:":                                              "\nCartas de acción:",
+	"\nModifier Cards:":                                            "\nCartas modificadoras:",
+	"\n🐛 DEBUG: Choose a card for %s to draw:\n":                   "\n🐛 DEPURACIÓN: Elige la carta que saca %s:\n",
+	"  %-4d [%d] (%d available)%s\n":                               "  %-4d [%d] (%d disponibles)%s\n",
+	"  %-4s %s (%d available)\n":                                   "  %-4s %s (%d disponibles)\n",
+	"\nType a card (e.g. 7, f3, +10, x2), or r for a random one: ": "\nEscribe una carta (p. ej. 7, f3, +10, x2), o r para una al azar: ",
+	"Unknown card %q; type a card listed above, or r: ":            "Carta desconocida %q; escribe una de la lista, o r: ",
+	"No %s left in the deck; type another card: ":                  "No quedan %s en el mazo; escribe otra carta: ",
+	"  🆘 uses %s's Second Chance":                                  "  🆘 gasta la Segunda Oportunidad de %s",
+	"  💥 busts %s":                                                 "  💥 %s se pasa",
+
+	// Simulation
+	"\n🎲 Running %d games for statistical analysis...\n":        "\n🎲 Jugando %d partidas para el análisis estadístico...\n",
+	"⚡ Game %d/%d... (%.1fs elapsed)\n":                         "⚡ Partida %d/%d... (%.1fs transcurridos)\n",
+	"\n🔁 Dealing %d boards to %d seatings each (%d games)...\n": "\n🔁 Repartiendo %d mazos a %d disposiciones cada uno (%d partidas)...\n",
+	"⚡ Game %d... (%.1fs of %s)\n":                              "⚡ Partida %d... (%.1fs de %s)\n",
+	"⚡ Board %d... (%.1fs of %s)\n":                             "⚡ Mazo %d... (%.1fs de %s)\n",
+	"\n🔁 Dealing boards to %d seatings each for %s...\n":        "\n🔁 Repartiendo mazos a %d disposiciones cada uno durante %s...\n",
+	"\n🎲 Running games for %s for statistical analysis...\n":    "\n🎲 Jugando partidas durante %s para el análisis estadístico...\n",
+	"🛑 Interrupted after %d games\n":                            "🛑 Interrumpida tras %d partidas\n",
+	"⏱️ Time is up after %d games\n":                            "⏱️ Se acabó el tiempo tras %d partidas\n",
+	"🎯 Stopping after %d games: %s\n":                           "🎯 Parando tras %d partidas: %s\n",
+	"every win rate is within ± %.1f%%":                         "todas las tasas de victoria están a ± %.1f%%",
+	"%s leads %s by %.1f%% ± %.1f%%":                            "%s aventaja a %s en %.1f%% ± %.1f%%",
+	"⚡ Game %d... (%.1fs elapsed)\n":                            "⚡ Partida %d... (%.1fs transcurridos)\n",
+	"⚡ Board %d... (%.1fs elapsed)\n":                           "⚡ Mazo %d... (%.1fs transcurridos)\n",
+	"\n🔁 Dealing boards to %d seatings each until %s...\n":      "\n🔁 Repartiendo mazos a %d disposiciones cada uno hasta %s...\n",
+	"\n🎲 Running games until %s for statistical analysis...\n":  "\n🎲 Jugando partidas hasta %s para el análisis estadístico...\n",
+	"\n↩️ Resuming simulation: %d games played in %s\n":         "\n↩️ Reanudando la simulación: %d partidas jugadas en %s\n",
+	"⚡ Board %d/%d... (%.1fs elapsed)\n":                        "⚡ Mazo %d/%d... (%.1fs transcurridos)\n",
+	"💺 RESULTS BY SEAT (seat 1 acts first, seat %d deals)\n":    "💺 RESULTADOS POR ASIENTO (el asiento 1 juega primero, el %d reparte)\n",
+	"SEAT":      "ASIENTO",
+	"GAMES":     "PARTIDAS",
+	"AVG SCORE": "MEDIA",
+	"AVG ROUND": "MEDIA RONDA",
+	"No clear seat advantage: earlier seats won %.1f%%, later seats %.1f%%\n":                   "Ningún asiento tiene ventaja clara: los primeros ganaron el %.1f%%, los últimos el %.1f%%\n",
+	"Acting earlier helps: earlier seats won %.1f%%, later seats %.1f%%\n":                      "Jugar antes ayuda: los primeros asientos ganaron el %.1f%%, los últimos el %.1f%%\n",
+	"Acting later helps: earlier seats won %.1f%%, later seats %.1f%%\n":                        "Jugar después ayuda: los primeros asientos ganaron el %.1f%%, los últimos el %.1f%%\n",
+	"Each player kept their seat, so seat and strategy are mixed; use -rotate to separate them": "Cada jugador mantuvo su asiento, así que asiento y estrategia se mezclan; usa -rotate para separarlos",
+	"Flip 7":                                 "Flip 7",
+	"Comeback":                               "Remontada",
+	"Bust-fest":                              "Pasadas en masa",
+	"Longest game":                           "Partida más larga",
+	"%s flipped 7 for %d points in round %d": "%s consiguió Flip 7 con %d puntos en la ronda %d",
+	"%d players busted in round %d":          "%d jugadores se pasaron en la ronda %d",
+	"%s won from %d points behind":           "%s ganó remontando %d puntos",
+	"%d rounds, won by %s":                   "%d rondas, ganó %s",
+	"⭐ NOTABLE GAMES (replay one with -seed and a single game)": "⭐ PARTIDAS DESTACADAS (repite una con -seed y una sola partida)",
+	"%s (%d games)":                              "%s (%d partidas)",
+	"%-26s seed %-10d %s\n":                      "%-26s semilla %-10d %s\n",
+	"No notable games":                           "Ninguna partida destacada",
+	"📄 Game results written to %s\n":             "📄 Resultados de las partidas guardados en %s\n",
+	"📄 Report written to %s\n":                   "📄 Informe guardado en %s\n",
+	"⚠️ Keeping parameters v%d: %v\n":            "⚠️ Se mantienen los parámetros v%d: %v\n",
+	"🎛️ Parameters v%d from %s, from game %d:\n": "🎛️ Parámetros v%d de %s, desde la partida %d:\n",
+	"🎛️ PARAMETER VERSIONS (%s)\n":               "🎛️ VERSIONES DE LOS PARÁMETROS (%s)\n",
+	"v%d: no games\n":                            "v%d: ninguna partida\n",
+	"v%d: games %d-%d\n":                         "v%d: partidas %d-%d\n",
+	"unchanged":                                  "sin cambios",
+	"Flip 7 simulation report":                   "Informe de simulación de Flip 7",
+	"%d games from seed %d. %s Games last %.1f rounds on average.": "%d partidas desde la semilla %d. %s Las partidas duran %.1f rondas de media.",
+	"Win rates": "Porcentaje de victorias",
+	"Error bars are two standard errors either side of the win rate.": "Las barras de error abarcan dos errores estándar a cada lado del porcentaje de victorias.",
+	"Head to head": "Cara a cara",
+	"How often the row's player finished the game with more points than the column's.": "Cuántas veces el jugador de la fila terminó la partida con más puntos que el de la columna.",
+	"Final scores":                                             "Puntuaciones finales",
+	"%d-%d: %.1f%%":                                            "%d-%d: %.1f%%",
+	"Action card impact":                                       "Impacto de las cartas de acción",
+	"Average game: %.1f rounds\n":                              "Partida media: %.1f rondas\n",
+	"%-20s average score %.1f\n":                               "%-20s puntuación media %.1f\n",
+	"🧪 Running %d experiments, %d at a time...\n":              "🧪 Ejecutando %d experimentos, %d a la vez...\n",
+	"✅ %s: %d games in %.1fs\n":                                "✅ %s: %d partidas en %.1fs\n",
+	"🧪 %s - %d games, seeds %d-%d, first to %d\n":              "🧪 %s - %d partidas, semillas %d-%d, a %d puntos\n",
+	"🧪 EXPERIMENTS COMPARED":                                   "🧪 COMPARACIÓN DE EXPERIMENTOS",
+	"🧪 PAIRED WITH %s - seat %d, %d games on the same deals\n": "🧪 EMPAREJADOS CON %s - asiento %d, %d partidas con los mismos repartos\n",
+	"SCORE":    "PUNTOS",
+	"SPEEDUP":  "AHORRO",
+	"baseline": "referencia",
+	"%-12s played %d games, baseline %d; not paired\n":                   "%-12s jugó %d partidas, la referencia %d; sin emparejar\n",
+	"Win rate and score are the experiment's less the baseline's on the": "El % de victorias y los puntos son los del experimento menos los de",
+	"same deals. SPEEDUP is how many times as many games comparing them": "la referencia con los mismos repartos. AHORRO es cuántas veces más",
+	"unpaired would take for the same win rate margin.":                  "partidas harían falta sin emparejar para el mismo margen.",
+	"EXPERIMENT": "EXPERIMENTO",
+	"ROUNDS":     "RONDAS",
+	"TOP PLAYER": "MEJOR JUGADOR",
+	"🔍 Playing %d counter-strategies against %s, %d games each...\n": "🔍 Jugando %d contraestrategias contra %s, %d partidas cada una...\n",
+	"🔍 Refining around the best of each family: %d more...\n":        "🔍 Afinando alrededor de la mejor de cada familia: %d más...\n",
+	"🔍 Replaying %s on fresh deals...\n":                             "🔍 Repitiendo %s con repartos nuevos...\n",
+	"🔍 Searched %d counter-strategies in %.1fs\n":                    "🔍 Se probaron %d contraestrategias en %.1fs\n",
+	"🔍 BEST RESPONSES TO %s - %d games each from seed %d\n":          "🔍 MEJORES RESPUESTAS A %s - %d partidas cada una desde la semilla %d\n",
+	"COUNTER-STRATEGY":  "CONTRAESTRATEGIA",
+	"... and %d more\n": "... y %d más\n",
+	"On fresh deals %s won %.1f%% ± %.1f%% against %s\n":                           "Con repartos nuevos %s ganó el %.1f%% ± %.1f%% contra %s\n",
+	"%s is exploitable: %s beats it %.1f%% to %.1f%%\n":                            "%s es explotable: %s le gana %.1f%% a %.1f%%\n",
+	"No counter-strategy searched beats %s; it looks near optimal against these\n": "Ninguna contraestrategia probada vence a %s; parece casi óptima frente a ellas\n",
+	"📄 Decisions written to %s\n":                                                  "📄 Decisiones escritas en %s\n",
+	"⏳ GAME LENGTH":                                                                "⏳ DURACIÓN DE LAS PARTIDAS",
+	"Average game: %.1f rounds, %.1f turns a round, %.1f cards drawn\n":            "Partida media: %.1f rondas, %.1f turnos por ronda, %.1f cartas robadas\n",
+	"🧮 BEST SINGLE-ROUND PLAY (%s)\n":                                              "🧮 MEJOR JUGADA EN UNA RONDA (%s)\n",
+	"fresh deck":                                                                   "mazo nuevo",
+	"seen: %s":                                                                     "vistas: %s",
+	"Expected round score with best play: %.2f\n":                                  "Puntuación esperada por ronda con la mejor jugada: %.2f\n",
+	"NUMBERS":     "NÚMEROS",
+	"HANDS":       "MANOS",
+	"HIT":         "PEDIR",
+	"HIGHEST HIT": "PIDE HASTA",
+	"LOWEST STAY": "PLANTA DESDE",
+	"Scores are without modifiers; the table covers every modifier too": "Puntuaciones sin modificadores; la tabla cubre también cada modificador",
+	"🌳 Wrote %d decisions, %d draws and %d outcomes to %s\n":            "🌳 Escritas %d decisiones, %d robos y %d resultados en %s\n",
+	"🌐 Serving the web client at %s\n":                                  "🌐 Sirviendo el cliente web en %s\n",
+	"STAY":                                                              "PLANTARSE",
+	"💬 Chat vote: %s Type %s (%ds)\n":                                   "💬 Votación del chat: %s Escribid %s (%ds)\n",
+	"💬 No clear winner from chat; %s plays its own strategy\n":          "💬 El chat no se decide; %s juega su propia estrategia\n",
+	"💬 Chat chose %s (%d of %d votes)\n":                                "💬 El chat eligió %s (%d de %d votos)\n",
+	"%s has %d points this round. Hit or stay?":                         "%s lleva %d puntos esta ronda. ¿Pedir o plantarse?",
+	"Your hand: %v, %d points this round":                               "Tu mano: %v, %d puntos esta ronda",
+	", %.0f%% chance to bust if you hit":                                ", %.0f%% de probabilidad de pasarte si pides",
+	"<@%s>, do you want to hit or stay?":                                "<@%s>, ¿quieres pedir o plantarte?",
+	"<@%s>, do you want to play a held action card?":                    "<@%s>, ¿quieres jugar una carta de acción guardada?",
+	"Hit":                                  "Pedir",
+	"Stay":                                 "Plantarse",
+	"<@%s> chose %s":                       "<@%s> eligió %s",
+	"It's <@%s>'s decision, not yours.":    "La decisión es de <@%s>, no tuya.",
+	"That decision has already been made.": "Esa decisión ya está tomada.",
+	"That game is over.":                   "Esa partida ha terminado.",
+	"Usage: /flip7 [@players] [computer strategies] [winning score]\n%v":                                                                                       "Uso: /flip7 [@jugadores] [estrategias de la máquina] [puntuación para ganar]\n%v",
+	"Usage: /flip7 [@players] [computer strategies] [winning score]\nMention at least one other player or name a computer strategy, e.g. /flip7 @alice expert": "Uso: /flip7 [@jugadores] [estrategias de la máquina] [puntuación para ganar]\nMenciona al menos a otro jugador o nombra una estrategia de la máquina, p. ej. /flip7 @alice expert",
+	"🎮 Flip 7 game %s started, first to %d points. Answer when the buttons ask you.":                                                                           "🎮 Empieza la partida de Flip 7 %s, gana quien llegue a %d puntos. Responde cuando te lo pidan los botones.",
+	"🏆 LADDER - SEASON %d (from %s)\n": "🏆 CLASIFICACIÓN - TEMPORADA %d (desde %s)\n",
+	"Ended %s\n":                       "Terminó el %s\n",
+	"⚠️  flagged: decisions follow the hidden deck too closely to be chance": "⚠️  marcado: sus decisiones siguen el mazo oculto demasiado bien para ser azar",
+	"No ranked games yet": "Aún no hay partidas clasificatorias",
+	"Player":              "Jugador",
+	"Rating":              "Elo",
+	"Games":               "Partidas",
+	"🏆 Registered %s. Your token, which won't be shown again: %s\n": "🏆 %s registrado. Tu token, que no se volverá a mostrar: %s\n",
+	"Play ranked games over a WebSocket at %s\n":                    "Juega partidas clasificatorias por WebSocket en %s\n",
+	"🏟️  %d bots, %d tables of %d, %d games each\n":                 "🏟️  %d bots, %d mesas de %d, %d partidas cada una\n",
+	"🎲 Game %d/%d\n":                           "🎲 Partida %d/%d\n",
+	"🏟️  ARENA - %d games, first to %d\n":      "🏟️  ARENA - %d partidas, gana quien llegue a %d\n",
+	"      ⏰ ran out of time on %d turns\n":    "      ⏰ se quedó sin tiempo en %d turnos\n",
+	"⏰ <@%s> ran out of time: %s":              "⏰ <@%s> se quedó sin tiempo: %s",
+	"      ❌ disqualified, %s\n":               "      ❌ descalificado, %s\n",
+	"%d games skipped for disqualified bots\n": "%d partidas omitidas por bots descalificados\n",
+	"📦 Results written to %s\n":                "📦 Resultados escritos en %s\n",
+	"🧮 Table written to %s\n":                  "🧮 Tabla escrita en %s\n",
+	"Rounds per game":                          "Rondas por partida",
+	"Turns per round":                          "Turnos por ronda",
+	"Cards drawn per game":                     "Cartas robadas por partida",
+	"📊 SCORE DISTRIBUTIONS":                    "📊 DISTRIBUCIÓN DE PUNTUACIONES",
+	"%s, final score":                          "%s, puntuación final",
+	"%s, round score":                          "%s, puntuación por ronda",
+	"%s: P50 %d, P90 %d, P99 %d\n":             "%s: P50 %d, P90 %d, P99 %d\n",
+	"📊 Score distributions written to %s\n":    "📊 Distribuciones de puntuación escritas en %s\n",
+	"🃏 ACTION CARD IMPACT":                     "🃏 IMPACTO DE LAS CARTAS DE ACCIÓN",
+	"STRATEGY":                                 "ESTRATEGIA",
+	"FREEZES":                                  "CONGELA",
+	"DENIED":                                   "QUITADO",
+	"BUSTED":                                   "PASADOS",
+	"SAVES":                                    "SALVAS",
+	"KEPT":                                     "GUARDADO",
+	"ROUND ENDS":                               "FINAL DE RONDA",
+	"STAYED":                                   "PLANTADOS",
+	"FROZEN":                                   "CONGELADOS",
+	"DENIED: points a Freeze cost its target against their strategy's average round;\n        below zero, freezing them banked more than they usually score": "QUITADO: puntos que un Congelar costó a su objetivo frente a la ronda media de su estrategia;\n         bajo cero, congelarlo le aseguró más de lo que suele sumar",
+	"BUSTED: Flip Threes on other players that made them bust":     "PASADOS: Voltea Tres jugados a otros que les hicieron pasarse",
+	"KEPT: average round score after a Second Chance saved a bust": "GUARDADO: puntuación media de la ronda tras salvarse con Segunda Oportunidad",
+	"🎴 RESULTS BY FIRST DEALER (%s)\n":                             "🎴 RESULTADOS POR PRIMER REPARTIDOR (%s)\n",
+	"FIRST DEALER":                                                 "1.er REPARTIDOR",
+	"The first dealer won %.1f%% of games and the first to act %.1f%% (an even share is %.1f%%)\n": "El primer repartidor ganó el %.1f%% de las partidas y el primero en jugar el %.1f%% (el reparto justo es %.1f%%)\n",
+	"⚔️ DUEL - %d games from seed %d\n":                                                            "⚔️ DUELO - %d partidas desde la semilla %d\n",
+	"Wins":                                                                                         "Victorias",
+	"Win rate":                                                                                     "Porcentaje de victorias",
+	"Average final score":                                                                          "Puntuación final media",
+	"Average winning margin":                                                                       "Margen medio al ganar",
+	"Rounds busted":                                                                                "Rondas pasadas",
+	"Rounds with Flip 7":                                                                           "Rondas con Flip 7",
+	"Average round score":                                                                          "Puntuación media por ronda",
+	"Average banked round":                                                                         "Media de rondas plantadas",
+	"Round scores:":                                                                                "Puntuaciones por ronda:",
+	"busted":                                                                                       "pasado",
+	"Too close to call: %.1f%% ± %.1f%% for %s\n":                                                  "Demasiado igualado: %.1f%% ± %.1f%% para %s\n",
+	"%s wins %.1f%% ± %.1f%% of games against %s\n":                                                "%s gana el %.1f%% ± %.1f%% de las partidas contra %s\n",
+	"Compared with %s: bust rate %+.1f%%, Flip 7 rate %+.1f%%, round score %+.1f\n":                "Frente a %s: pasadas %+.1f%%, Flip 7 %+.1f%%, puntos por ronda %+.1f\n",
+	"📝 Markdown report written to %s\n":                                                            "📝 Informe en Markdown guardado en %s\n",
+	"⚔️ Duel: %s vs %s":                                                                            "⚔️ Duelo: %s contra %s",
+	"%d games from seed %d, first to %d points. Every deck is dealt twice with the seats swapped.": "%d partidas desde la semilla %d, gana quien llegue a %d puntos. Cada mazo se reparte dos veces intercambiando los asientos.",
+	"Standings":          "Clasificación",
+	"Per-strategy stats": "Estadísticas por estrategia",
+	"Round scores":       "Puntuaciones por ronda",
+	"Notable games":      "Partidas destacadas",
+	"Kind":               "Tipo",
+	"Seed":               "Semilla",
+	"What happened":      "Qué pasó",
+	"Play a deal again both ways with `flip7 duel -a %s -b %s -score %d -n 2 -seed SEED`.": "Repite un reparto en ambos sentidos con `flip7 duel -a %s -b %s -score %d -n 2 -seed SEMILLA`.",
+	"🏟️ Arena: %d bots":                                        "🏟️ Arena: %d bots",
+	"%d games at tables of %d, first to %d points, played %s.": "%d partidas en mesas de %d, gana quien llegue a %d puntos, jugadas el %s.",
+	"Bot":          "Bot",
+	"Timeouts":     "Sin tiempo",
+	"Disqualified": "Descalificado",
+	"Rounds":       "Rondas",
+	"Every game is in `games.jsonl` with its seed.": "Todas las partidas están en `games.jsonl` con su semilla.",
+	"🏆 SIMULATION RESULTS - %d GAMES COMPLETED\n":   "🏆 RESULTADOS DE LA SIMULACIÓN - %d PARTIDAS JUGADAS\n",
+	"PLAYER":                              "JUGADOR",
+	"WINS":                                "VICTORIAS",
+	"WIN RATE":                            "% VICTORIAS",
+	"PERFORMANCE":                         "RENDIMIENTO",
+	"🔥 DOMINANT":                          "🔥 DOMINANTE",
+	"💪 STRONG":                            "💪 FUERTE",
+	"👍 DECENT":                            "👍 DECENTE",
+	"😔 WEAK":                              "😔 DÉBIL",
+	"Total Games: %d\n":                   "Partidas totales: %d\n",
+	"Victory Margin: %.1f%% (%s vs %s)\n": "Margen de victoria: %.1f%% (%s contra %s)\n",
+
+	"Not recording the game: %v\n":                                             "No se graba la partida: %v\n",
+	"Could not write the replay: %v\n":                                         "No se pudo escribir la repetición: %v\n",
+	"🎞️ Replay written to %s. Review it with: flip7 analyze %s\n":              "🎞️ Repetición guardada en %s. Revísala con: flip7 analyze %s\n",
+	"🔍 Playing out %d decisions both ways, %d games each...\n":                 "🔍 Jugando %d decisiones de las dos maneras, %d partidas cada una...\n",
+	"🔍 Analyzed in %.1fs\n":                                                    "🔍 Analizada en %.1fs\n",
+	"🔍 GAME REVIEW - %d games per choice, blunders give away %.0f%% or more\n": "🔍 REVISIÓN DE LA PARTIDA - %d partidas por opción, un error grave regala un %.0f%% o más\n",
+	"chose to stay": "se plantó",
+	"chose to hit":  "pidió carta",
+	"hitting":       "pedir carta",
+	"staying":       "plantarse",
+	"❌ Round %d, %s %s holding %s (total %d)\n":                      "❌ Ronda %d, %s %s con %s (total %d)\n",
+	"   wins %.0f%% of games played out; %s wins %.0f%% (-%.0f%%)\n": "   gana el %.0f%% de las partidas jugadas; %s gana el %.0f%% (-%.0f%%)\n",
+	"No blunders":       "Ningún error grave",
+	"DECISIONS":         "DECISIONES",
+	"BLUNDERS":          "ERRORES",
+	"ACCURACY":          "PRECISIÓN",
+	"%s won the game\n": "%s ganó la partida\n",
+	"Not showing the chances of winning: %v\n":                          "No se muestran las probabilidades de ganar: %v\n",
+	"🎬 Wrote %s: %d rounds in %.0fs. Play it with: asciinema play %s\n": "🎬 Se escribió %s: %d rondas en %.0fs. Reprodúcelo con: asciinema play %s\n",
+	"🎮 Flip 7 - %s":          "🎮 Flip 7 - %s",
+	"stayed":                 "se plantó",
+	"busted on %s":           "se pasó con %s",
+	"FLIP 7!":                "¡FLIP 7!",
+	"⚖️ Chances of winning:": "⚖️ Probabilidades de ganar:",
+
+	// TUI
+	"Loading...":          "Cargando...",
+	"📊 Scores - Round %d": "📊 Puntuaciones - Ronda %d",
+	"🃏 Deck":              "🃏 Mazo",
+	"\nCards left: %d\nDiscards:   %d\nDealer:     %s": "\nQuedan:     %d\nDescartes:  %d\nReparte:    %s",
+	"🎴 Hands":                    "🎴 Manos",
+	"Game started":               "Partida iniciada",
+	"Round %d started, %s deals": "Empieza la ronda %d, reparte %s",
+	"%s drew %s":                 "%s sacó %s",
+	"%s played %s on %s":         "%s jugó %s sobre %s",
+	"%s stayed with %d":          "%s se plantó con %d",
+	"%s busted on %s":            "%s se pasó con %s",
+	"%s used Second Chance":      "%s usó la Segunda Oportunidad",
+	"%s achieved FLIP 7!":        "¡%s consiguió FLIP 7!",
+	"Round %d ended":             "Terminó la ronda %d",
+	"%s wins with %d points!":    "¡%s gana con %d puntos!",
+	"The discards were shuffled back into the deck (%d cards)": "Los descartes volvieron a barajarse en el mazo (%d cartas)",
+	"%s was burned":                   "%s se quemó",
+	"The top %d cards were peeked at": "Se miraron las %d cartas de arriba",
+	"   🔥 Burned: %s\n":               "   🔥 Quemada: %s\n",
+	"   🔥 No card is left to burn":    "   🔥 No queda ninguna carta que quemar",
+	"No card was left to burn":        "No quedaba ninguna carta que quemar",
+	"   🔀 The discards are shuffled back into the deck (%d cards)\n": "   🔀 Los descartes se barajan de nuevo en el mazo (%d cartas)\n",
+	"   🃏 The deck has run out! Everyone still in the round stays.":  "   🃏 ¡Se acabó el mazo! Todos los que siguen en la ronda se plantan.",
+	"Game finished - press any key to exit":                          "Partida terminada - pulsa una tecla para salir",
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// gameMetrics counts every game the process plays once metrics are
+// served; new games pick it up from here
+var gameMetrics *Metrics
+
+// Metrics counts the games a long-running flip7 process plays, for
+// Prometheus to scrape: games in progress and finished, wins by
+// strategy, hit/stay decisions and how long they took, and games
+// abandoned part way, whether their input closed or the env agent
+// reset them
+type Metrics struct {
+	mu              sync.Mutex
+	inProgress      int
+	games           int
+	abandoned       int
+	decisions       int
+	decisionSeconds float64
+	wins            map[string]int
+}
+
+// NewMetrics creates empty metrics
+func NewMetrics() *Metrics {
+	return &Metrics{wins: make(map[string]int)}
+}
+
+// StartMetrics serves the process's metrics at /metrics on addr, such as
+// ":9090", and counts every game played from now on
+func StartMetrics(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	gameMetrics = NewMetrics()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", gameMetrics)
+	go http.Serve(l, mux)
+	return nil
+}
+
+// trackGame counts a game as in progress, returning a function that
+// counts it as finished, or abandoned if it ended in err
+func (m *Metrics) trackGame(g *Game) func(err error) {
+	m.mu.Lock()
+	m.inProgress++
+	m.mu.Unlock()
+	return func(err error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.inProgress--
+		if err != nil {
+			m.abandoned++
+			return
+		}
+		m.games++
+		m.wins[strategyOf(g.getWinner())]++
+	}
+}
+
+// decided counts a hit/stay decision and how long it took
+func (m *Metrics) decided(took time.Duration) {
+	m.mu.Lock()
+	m.decisions++
+	m.decisionSeconds += took.Seconds()
+	m.mu.Unlock()
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.write(w)
+}
+
+// write writes the metrics in the Prometheus text format
+func (m *Metrics) write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	metric("flip7_games_in_progress", "gauge", "Games being played.")
+	fmt.Fprintf(w, "flip7_games_in_progress %d\n", m.inProgress)
+	metric("flip7_games_total", "counter", "Games played to the end.")
+	fmt.Fprintf(w, "flip7_games_total %d\n", m.games)
+	metric("flip7_games_abandoned_total", "counter", "Games that ended part way, such as a player disconnecting.")
+	fmt.Fprintf(w, "flip7_games_abandoned_total %d\n", m.abandoned)
+	metric("flip7_wins_total", "counter", "Games won, by the winner's strategy.")
+	for _, strategy := range slices.Sorted(maps.Keys(m.wins)) {
+		fmt.Fprintf(w, "flip7_wins_total{strategy=%s} %d\n", strconv.Quote(strategy), m.wins[strategy])
+	}
+	metric("flip7_decision_seconds", "summary", "Time taken by hit/stay decisions.")
+	fmt.Fprintf(w, "flip7_decision_seconds_sum %s\n", strconv.FormatFloat(m.decisionSeconds, 'g', -1, 64))
+	fmt.Fprintf(w, "flip7_decision_seconds_count %d\n", m.decisions)
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// comebackDeficit is how far behind the leader a winner must have been
+	// for the win to count as a comeback
+	comebackDeficit = 50
+	// bustFestBusts is how many players must bust in one round for the
+	// round to be notable
+	bustFestBusts = 4
+)
+
+// NotableKind is a kind of game worth replaying
+type NotableKind int
+
+const (
+	NotableFlip7 NotableKind = iota
+	NotableComeback
+	NotableBustFest
+	NotableLongest
+	numNotableKinds
+)
+
+// String returns the heading for the kind of game
+func (k NotableKind) String() string {
+	switch k {
+	case NotableFlip7:
+		return T("Flip 7")
+	case NotableComeback:
+		return T("Comeback")
+	case NotableBustFest:
+		return T("Bust-fest")
+	case NotableLongest:
+		return T("Longest game")
+	}
+	return "Unknown"
+}
+
+// NotableGame is the seed of a simulated game and what made it stand out.
+// Rank orders games of the same kind; the highest is kept.
+type NotableGame struct {
+	Seed    int64
+	Summary string
+	Rank    int
+}
+
+// NotableGames keeps the most remarkable simulated game of each kind and
+// how many games of that kind there were
+type NotableGames struct {
+	Best  [numNotableKinds]*NotableGame
+	Count [numNotableKinds]int
+
+	// The game being played
+	seed       int64
+	rounds     int
+	busts      int
+	flip7      PlayerInterface
+	bestFlip7  NotableGame
+	bestBusts  NotableGame
+	deficits   map[PlayerInterface]int
+	found      [numNotableKinds]bool
+	recordable bool
+}
+
+// NewNotableGames creates an empty list of notable games
+func NewNotableGames() *NotableGames {
+	return &NotableGames{deficits: make(map[PlayerInterface]int)}
+}
+
+// trackNotables looks for notable games among the games the game plays
+func (g *Game) trackNotables(n *NotableGames) {
+	g.Subscribe(func(event Event) {
+		if n.recordable {
+			n.observe(g, event)
+		}
+	})
+}
+
+// startGame begins watching a game dealt from the given seed. Games that
+// can't be replayed from their seed alone are skipped.
+func (n *NotableGames) startGame(seed int64, recordable bool) {
+	n.seed = seed
+	n.recordable = recordable
+	n.rounds = 0
+	n.bestFlip7 = NotableGame{}
+	n.bestBusts = NotableGame{}
+	clear(n.deficits)
+	clear(n.found[:])
+}
+
+// observe follows a game's events
+func (n *NotableGames) observe(g *Game, event Event) {
+	switch event.Type {
+	case RoundStarted:
+		n.rounds++
+		n.busts = 0
+		n.flip7 = nil
+	case PlayerBusted:
+		n.busts++
+	case Flip7Achieved:
+		n.flip7 = event.Player
+	case PlayerScored:
+		if event.Player == n.flip7 && event.Score > n.bestFlip7.Rank {
+			n.found[NotableFlip7] = true
+			n.bestFlip7 = NotableGame{n.seed, fmt.Sprintf(T("%s flipped 7 for %d points in round %d"), event.Player.GetName(), event.Score, event.Round), event.Score}
+		}
+	case RoundEnded:
+		if n.busts >= bustFestBusts && n.busts > n.bestBusts.Rank {
+			n.found[NotableBustFest] = true
+			n.bestBusts = NotableGame{n.seed, fmt.Sprintf(T("%d players busted in round %d"), n.busts, event.Round), n.busts}
+		}
+		lead := 0
+		for _, p := range g.players {
+			lead = max(lead, p.GetTotalScore())
+		}
+		for _, p := range g.players {
+			n.deficits[p] = max(n.deficits[p], lead-p.GetTotalScore())
+		}
+	}
+}
+
+// endGame records the finished game under every kind it belongs to
+func (n *NotableGames) endGame(winner PlayerInterface) {
+	if !n.recordable {
+		return
+	}
+	if deficit := n.deficits[winner]; deficit >= comebackDeficit {
+		n.found[NotableComeback] = true
+		n.record(NotableComeback, NotableGame{n.seed, fmt.Sprintf(T("%s won from %d points behind"), winner.GetName(), deficit), deficit})
+	}
+	if n.found[NotableFlip7] {
+		n.record(NotableFlip7, n.bestFlip7)
+	}
+	if n.found[NotableBustFest] {
+		n.record(NotableBustFest, n.bestBusts)
+	}
+	n.record(NotableLongest, NotableGame{n.seed, fmt.Sprintf(T("%d rounds, won by %s"), n.rounds, winner.GetName()), n.rounds})
+}
+
+// record counts a game of the given kind and keeps it if it beats the best
+func (n *NotableGames) record(kind NotableKind, game NotableGame) {
+	n.Count[kind]++
+	if best := n.Best[kind]; best == nil || game.Rank > best.Rank {
+		n.Best[kind] = &game
+	}
+}
+
+// displayNotableGames lists the best game of each kind with its seed
+func (g *Game) displayNotableGames(n *NotableGames) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf("%s\n", T("⭐ NOTABLE GAMES (replay one with -seed and a single game)"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	found := false
+	for kind := range numNotableKinds {
+		best := n.Best[kind]
+		if best == nil {
+			continue
+		}
+		found = true
+		heading := kind.String()
+		if kind != NotableLongest {
+			heading = fmt.Sprintf(T("%s (%d games)"), heading, n.Count[kind])
+		}
+		g.resultf(T("%-26s seed %-10d %s\n"), heading, best.Seed, best.Summary)
+	}
+	if !found {
+		g.resultf("%s\n", T("No notable games"))
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Speed controls how fast AI-only games are shown to a spectator
+type Speed struct {
+	Name     string
+	Draw     time.Duration
+	Action   time.Duration
+	Stay     time.Duration
+	Bust     time.Duration
+	Flip7    time.Duration
+	RoundEnd time.Duration
+}
+
+// speeds are the available pacing presets, slowest last
+var speeds = []Speed{
+	{Name: "instant"},
+	{
+		Name:     "fast",
+		Draw:     150 * time.Millisecond,
+		Action:   300 * time.Millisecond,
+		Stay:     200 * time.Millisecond,
+		Bust:     400 * time.Millisecond,
+		Flip7:    800 * time.Millisecond,
+		RoundEnd: 500 * time.Millisecond,
+	},
+	{
+		Name:     "normal",
+		Draw:     400 * time.Millisecond,
+		Action:   800 * time.Millisecond,
+		Stay:     500 * time.Millisecond,
+		Bust:     1000 * time.Millisecond,
+		Flip7:    2000 * time.Millisecond,
+		RoundEnd: 1500 * time.Millisecond,
+	},
+	{
+		Name:     "dramatic",
+		Draw:     900 * time.Millisecond,
+		Action:   1500 * time.Millisecond,
+		Stay:     800 * time.Millisecond,
+		Bust:     2500 * time.Millisecond,
+		Flip7:    4000 * time.Millisecond,
+		RoundEnd: 3000 * time.Millisecond,
+	},
+}
+
+// ParseSpeed looks up a pacing preset by name
+func ParseSpeed(name string) (Speed, error) {
+	names := make([]string, 0, len(speeds))
+	for _, speed := range speeds {
+		if speed.Name == strings.ToLower(name) {
+			return speed, nil
+		}
+		names = append(names, speed.Name)
+	}
+	return Speed{}, fmt.Errorf("unknown speed %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// delay returns how long to pause after an event
+func (s Speed) delay(event Event) time.Duration {
+	switch event.Type {
+	case CardDrawn:
+		return s.Draw
+	case ActionPlayed:
+		return s.Action
+	case PlayerStayed:
+		return s.Stay
+	case PlayerBusted:
+		return s.Bust
+	case Flip7Achieved:
+		return s.Flip7
+	case RoundEnded:
+		return s.RoundEnd
+	}
+	return 0
+}
+
+// Pacer pauses after game events so a spectator can follow along
+type Pacer struct {
+	speed Speed
+	sleep func(time.Duration)
+}
+
+// NewPacer creates a pacer for a speed preset
+func NewPacer(speed Speed) *Pacer {
+	return &Pacer{speed: speed, sleep: time.Sleep}
+}
+
+// OnEvent is an EventListener that sleeps according to the speed preset
+func (p *Pacer) OnEvent(event Event) {
+	if d := p.speed.delay(event); d > 0 {
+		p.sleep(d)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Comparison pairs every experiment in a batch with a baseline
+// experiment, game by game. The experiments deal the same seeds, so game
+// i of each is dealt the same cards: common random numbers. The player
+// in Seat of each lineup is compared, and only the differences between
+// the lineups are left to tell the games apart, so a small difference
+// shows up in far fewer games than comparing win rates would take.
+type Comparison struct {
+	Baseline string `yaml:"baseline" toml:"baseline"`
+	Seat     int    `yaml:"seat" toml:"seat"`
+}
+
+// GameOutcomes keeps one player's result in every game of a simulation,
+// in the order they were played, for pairing with another simulation's
+type GameOutcomes struct {
+	Player string
+	Wins   []bool
+	Scores []int
+}
+
+// recordGame notes whether the player won the game and their final score
+func (o *GameOutcomes) recordGame(g *Game, winner PlayerInterface) {
+	score := 0
+	for _, p := range g.players {
+		if p.GetName() == o.Player {
+			score = p.GetTotalScore()
+		}
+	}
+	o.Wins = append(o.Wins, winner.GetName() == o.Player)
+	o.Scores = append(o.Scores, score)
+}
+
+// baseline returns the experiment the others are compared with: the one
+// named, or the first
+func (c *Comparison) baseline(experiments []Experiment) (int, bool) {
+	if c.Baseline == "" {
+		return 0, len(experiments) > 0
+	}
+	for i, e := range experiments {
+		if e.Name == c.Baseline {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// seat is the compared player's seat, counting from 1
+func (c *Comparison) seat() int {
+	return orDefault(c.Seat, 1)
+}
+
+// validate reports why the experiments can't be paired: each must deal
+// the same seeds as the baseline, with the same number of games for each
+// seed, and have a player in the compared seat
+func (c *Comparison) validate(experiments []Experiment) []error {
+	var errs []error
+	base, ok := c.baseline(experiments)
+	if !ok {
+		return []error{fmt.Errorf("  compare: baseline: no experiment is called %q", c.Baseline)}
+	}
+	if c.Seat < 0 {
+		errs = append(errs, fmt.Errorf("  compare: seat must be 1 or more"))
+	}
+	b := &experiments[base]
+	first, games, err := b.seedRange()
+	if err != nil {
+		return errs
+	}
+	for i := range experiments {
+		e := &experiments[i]
+		where := fmt.Sprintf("  experiments[%d]", i)
+		if len(e.Players) < c.seat() {
+			errs = append(errs, fmt.Errorf("%s: compare: no player in seat %d", where, c.seat()))
+		}
+		if i == base {
+			continue
+		}
+		if f, n, err := e.seedRange(); err == nil && (f != first || n != games) {
+			errs = append(errs, fmt.Errorf("%s: compare: deals seeds %d-%d, baseline %s deals %d-%d", where, f, f+int64(n)-1, b.Name, first, first+int64(games)-1))
+		}
+		if e.Duplicate != b.Duplicate || e.Duplicate && len(e.Players) != len(b.Players) {
+			errs = append(errs, fmt.Errorf("%s: compare: duplicate boards must match baseline %s's", where, b.Name))
+		}
+	}
+	return errs
+}
+
+// pairedDifference is the average of b[i] - a[i] and two standard errors
+// around it, and the margin the same games would have had unpaired
+func pairedDifference(a, b []float64) (mean, margin, unpaired float64) {
+	n := float64(len(a))
+	if n < 2 {
+		return 0, 0, 0
+	}
+	variance := func(xs []float64) (mean, v float64) {
+		for _, x := range xs {
+			mean += x
+		}
+		mean /= n
+		for _, x := range xs {
+			v += (x - mean) * (x - mean)
+		}
+		return mean, v / (n - 1)
+	}
+	diffs := make([]float64, len(a))
+	for i := range a {
+		diffs[i] = b[i] - a[i]
+	}
+	mean, v := variance(diffs)
+	_, va := variance(a)
+	_, vb := variance(b)
+	return mean, 2 * math.Sqrt(v/n), 2 * math.Sqrt((va+vb)/n)
+}
+
+// displayPairedComparison shows how the compared player in each
+// experiment did against the baseline's on the same deals: the
+// difference in win rate and final score with their paired margins, and
+// how many times the games an unpaired comparison would need for margins
+// as small
+func (g *Game) displayPairedComparison(results []*ExperimentResult, base, seat int) {
+	b := results[base].Sim.Outcomes
+	outcomes := func(o *GameOutcomes) (wins, scores []float64) {
+		for i, won := range o.Wins {
+			win := 0.0
+			if won {
+				win = 1
+			}
+			wins = append(wins, win)
+			scores = append(scores, float64(o.Scores[i]))
+		}
+		return wins, scores
+	}
+	baseWins, baseScores := outcomes(b)
+
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🧪 PAIRED WITH %s - seat %d, %d games on the same deals\n"), results[base].Name, seat, len(b.Wins))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	// ± takes two bytes, so its columns are a byte wider
+	g.resultf("%-12s %-14s %8s %8s %6s %7s %7s\n", T("EXPERIMENT"), T("PLAYER"), T("WIN RATE"), "± 2 SE", T("SCORE"), "± 2 SE", T("SPEEDUP"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%-12s %-14s %8s\n", results[base].Name, b.Player, T("baseline"))
+	for i, r := range results {
+		if i == base {
+			continue
+		}
+		o := r.Sim.Outcomes
+		if len(o.Wins) != len(b.Wins) {
+			g.resultf(T("%-12s played %d games, baseline %d; not paired\n"), r.Name, len(o.Wins), len(b.Wins))
+			continue
+		}
+		wins, scores := outcomes(o)
+		rate, rateMargin, unpaired := pairedDifference(baseWins, wins)
+		score, scoreMargin, _ := pairedDifference(baseScores, scores)
+		speedup := "-"
+		if rateMargin > 0 {
+			speedup = fmt.Sprintf("%.1fx", math.Pow(unpaired/rateMargin, 2))
+		}
+		g.resultf("%-12s %-14s %+7.1f%% %6.1f%% %+6.1f %6.1f %7s\n", r.Name, o.Player, 100*rate, 100*rateMargin, score, scoreMargin, speedup)
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%s\n", T("Win rate and score are the experiment's less the baseline's on the"))
+	g.resultf("%s\n", T("same deals. SPEEDUP is how many times as many games comparing them"))
+	g.resultf("%s\n", T("unpaired would take for the same win rate margin."))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// paramsInterval is how often a running simulation looks at its
+// parameters file for changes
+const paramsInterval = time.Second
+
+// ParamVersion is one version of a simulation's parameters file: the
+// strategies it gave players, by name, and the games played with them
+type ParamVersion struct {
+	Version    int
+	FirstGame  int
+	Games      int
+	Strategies map[string]string
+	Wins       map[string]int
+}
+
+// SetParams makes simulations read players' strategies from a YAML or
+// TOML file, and read it again whenever it changes, so strategies can be
+// tuned while the simulation runs
+func (g *Game) SetParams(path string) {
+	g.params = path
+}
+
+// readParams reads a parameters file: strategies, as -ai writes them, by
+// player name
+func readParams(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var params map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(data, &params)
+	} else {
+		err = yaml.Unmarshal(data, &params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading parameters %s: %w", path, err)
+	}
+	return params, nil
+}
+
+// paramsPlayer finds the player a parameters file names: by their whole
+// name, or by the name before the strategy their name ends with
+func paramsPlayer(players []PlayerInterface, name string) (*ComputerPlayer, bool) {
+	for _, player := range players {
+		p, ok := player.(*ComputerPlayer)
+		if ok && (p.GetName() == name || strings.HasPrefix(p.GetName(), name+" (")) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// reloadParams reads the simulation's parameters file if it has changed
+// since it was last read, and from the next game on gives players the
+// strategies it names. Players keep their names, so their totals carry
+// on. A file that can't be used is reported and the strategies stay as
+// they were, unless it's the first read: then the simulation can't start.
+func (g *Game) reloadParams(sim *Simulation, lineup []PlayerInterface) error {
+	if !sim.paramsChecked.IsZero() && g.now().Sub(sim.paramsChecked) < paramsInterval {
+		return nil
+	}
+	sim.paramsChecked = g.now()
+	info, err := os.Stat(sim.Params)
+	first := sim.paramsModTime.IsZero()
+	if err == nil && info.ModTime().Equal(sim.paramsModTime) {
+		return nil
+	}
+	if err == nil {
+		sim.paramsModTime = info.ModTime()
+		err = g.applyParams(sim, lineup)
+	}
+	if err != nil && first {
+		return err
+	}
+	// Each problem is reported once, not every time the file is looked at
+	if err != nil && err.Error() != sim.paramsErr {
+		g.printf(T("⚠️ Keeping parameters v%d: %v\n"), sim.paramsVersion(), err)
+	}
+	sim.paramsErr = ""
+	if err != nil {
+		sim.paramsErr = err.Error()
+	}
+	return nil
+}
+
+// applyParams gives players the strategies in the parameters file. A
+// file that changes nothing, like the one a resumed simulation left off
+// with, doesn't start a new version.
+func (g *Game) applyParams(sim *Simulation, lineup []PlayerInterface) error {
+	params, err := readParams(sim.Params)
+	if err != nil {
+		return err
+	}
+	specs := make(map[*ComputerPlayer]StrategySpec, len(params))
+	for name, s := range params {
+		p, ok := paramsPlayer(lineup, name)
+		if !ok {
+			return fmt.Errorf("%s: no computer player is called %s", sim.Params, name)
+		}
+		config, err := parseStrategy(name, s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", sim.Params, err)
+		}
+		spec, err := config.Spec()
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", sim.Params, s, err)
+		}
+		specs[p] = spec
+	}
+	n := len(sim.ParamVersions)
+	if n > 0 && maps.Equal(sim.ParamVersions[n-1].Strategies, params) {
+		return nil
+	}
+
+	for p, spec := range specs {
+		p.setSpec(spec)
+	}
+	if g.fastStrategies {
+		g.useFastStrategies(lineup)
+	}
+	// The checkpoint resumes with the strategies being played
+	players, err := savedPlayers(lineup)
+	if err != nil {
+		return err
+	}
+	sim.Players = players
+
+	version := ParamVersion{Version: n + 1, FirstGame: sim.gamesPlayed(len(lineup)) + 1, Strategies: params, Wins: make(map[string]int)}
+	sim.ParamVersions = append(sim.ParamVersions, version)
+	g.printf(T("🎛️ Parameters v%d from %s, from game %d:\n"), version.Version, sim.Params, version.FirstGame)
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		g.printf("   %-20s %s\n", name, params[name])
+	}
+	return nil
+}
+
+// recordParams counts the game just played under the parameters it was
+// played with
+func (sim *Simulation) recordParams(winner PlayerInterface) {
+	if len(sim.ParamVersions) == 0 {
+		return
+	}
+	version := &sim.ParamVersions[len(sim.ParamVersions)-1]
+	version.Games++
+	version.Wins[winner.GetName()]++
+}
+
+// paramsVersion is the version of the parameters being played, or 0
+// without a parameters file
+func (sim *Simulation) paramsVersion() int {
+	return len(sim.ParamVersions)
+}
+
+// displayParamVersions shows which games were played with each version
+// of the parameters, and every player's win rate with it
+func (g *Game) displayParamVersions(sim *Simulation, names []string) {
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🎛️ PARAMETER VERSIONS (%s)\n"), sim.Params)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	for _, v := range sim.ParamVersions {
+		if v.Games == 0 {
+			g.resultf(T("v%d: no games\n"), v.Version)
+			continue
+		}
+		g.resultf(T("v%d: games %d-%d\n"), v.Version, v.FirstGame, v.FirstGame+v.Games-1)
+		for _, name := range names {
+			strategy := T("unchanged")
+			for key, s := range v.Strategies {
+				if name == key || strings.HasPrefix(name, key+" (") {
+					strategy = s
+				}
+			}
+			rate := float64(v.Wins[name]) / float64(v.Games)
+			margin := 2 * math.Sqrt(rate*(1-rate)/float64(v.Games))
+			g.resultf("   %-24s %-20s %5.1f%% ± %.1f%%\n", name, strategy, 100*rate, 100*margin)
+		}
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/bits"
 	"slices"
 	"strings"
 )
@@ -13,8 +16,28 @@ const (
 	Active PlayerState = iota
 	Stayed
 	Busted
+	Frozen
+	Out
 )
 
+// String returns the state's name, as scenarios and the env protocol
+// write it
+func (s PlayerState) String() string {
+	switch s {
+	case Active:
+		return "active"
+	case Stayed:
+		return "stayed"
+	case Busted:
+		return "busted"
+	case Frozen:
+		return "frozen"
+	case Out:
+		return "out"
+	}
+	return "unknown"
+}
+
 // PlayerType represents whether a player is human or computer
 type PlayerType int
 
@@ -28,22 +51,30 @@ type PlayerInterface interface {
 	AddToTotalScore()
 	Bust()
 	CalculateRoundScore() int
-	ChooseActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error)
-	ChoosePositiveActionTarget(gameState *GameState, actionType ActionType) (PlayerInterface, error)
+	ChooseActionCardToPlay(ctx context.Context, gameState *GameState) (*Card, error)
+	Clone() PlayerInterface
+	// DecideAction picks who gets an action card from its legal targets
+	DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error)
+	Freeze()
+	GetBustedBy() *Card
 	GetHand() []*Card
 	GetHandSummary() string
 	GetName() string
 	GetPlayerIcon() string
+	GetState() PlayerState
 	GetTotalScore() int
 	HasCards() bool
 	HasSecondChance() bool
+	HeldActions() []*Card
+	HeldNumbers() uint16
 	IsActive() bool
-	MakeHitStayDecision(gameState *GameState) (bool, error)
+	MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error)
 	NumberOfNumberCards() int
 	ResetForNewRound() []*Card
-	ShowHand()
+	ShowHand(w io.Writer, r Renderer)
 	Stay()
 	UseSecondChance() *Card
+	Reactions
 }
 
 // Player represents a game player
@@ -55,6 +86,16 @@ type BasePlayer struct {
 	ActionCards   []*Card
 	State         PlayerState
 	SecondChance  bool
+	// BustedBy is the number card that busted the player this round
+	BustedBy *Card
+	// Eliminated is set once the player is knocked out of an
+	// elimination game; they sit out every round after
+	Eliminated bool
+
+	// held has bit n set while NumberCards holds the number n
+	held uint16
+	// scorer scores the hand, by the standard rules if nil
+	scorer Scorer
 }
 
 func (p *BasePlayer) Init(name string) {
@@ -63,6 +104,7 @@ func (p *BasePlayer) Init(name string) {
 	p.ModifierCards = make([]*Card, 0)
 	p.ActionCards = make([]*Card, 0)
 	p.State = Active
+	p.held = 0
 }
 
 func (p *BasePlayer) GetName() string {
@@ -73,6 +115,28 @@ func (p *BasePlayer) HasSecondChance() bool {
 	return p.SecondChance
 }
 
+// Holds reports whether the player has the number card value in hand
+func (p *BasePlayer) Holds(value int) bool {
+	return p.held&(1<<value) != 0
+}
+
+// HeldNumbers returns the number cards in hand as a bitmask, bit n set for
+// the number n. Lookahead strategies can copy it instead of the hand.
+func (p *BasePlayer) HeldNumbers() uint16 {
+	return p.held
+}
+
+// GetState returns whether the player is still in the round, stayed,
+// was frozen or busted
+func (p *BasePlayer) GetState() PlayerState {
+	return p.State
+}
+
+// GetBustedBy returns the card the player busted on this round, or nil
+func (p *BasePlayer) GetBustedBy() *Card {
+	return p.BustedBy
+}
+
 func (p *BasePlayer) GetTotalScore() int {
 	return p.TotalScore
 }
@@ -82,20 +146,20 @@ func (p *BasePlayer) AddCard(card *Card) error {
 	switch card.Type {
 	case NumberCard:
 		// Check for duplicate number cards (busting)
-		for _, existing := range p.NumberCards {
-			if existing.Value == card.Value {
-				// Player busts unless they have a second chance
-				if p.HasSecondChance() {
-					return fmt.Errorf("duplicate_with_second_chance:%d", card.Value)
-				}
-				p.Bust()
-				return fmt.Errorf("bust:%d", card.Value)
+		if p.Holds(card.Value) {
+			// Player busts unless they have a second chance
+			if p.HasSecondChance() {
+				return fmt.Errorf("duplicate_with_second_chance:%d", card.Value)
 			}
+			p.BustedBy = card
+			p.Bust()
+			return fmt.Errorf("bust:%d", card.Value)
 		}
 		p.NumberCards = append(p.NumberCards, card)
+		p.held |= 1 << card.Value
 
 		// Check for Flip 7
-		if len(p.NumberCards) == 7 {
+		if bits.OnesCount16(p.held) == 7 {
 			p.Stay()
 			return fmt.Errorf("flip7")
 		}
@@ -152,6 +216,16 @@ func (p *BasePlayer) Stay() {
 	}
 }
 
+// Freeze ends the player's round as a Freeze card does: they bank their
+// points like a stay, but didn't choose to
+func (p *BasePlayer) Freeze() {
+	if p.State == Active {
+		p.State = Frozen
+	} else {
+		panic("freeze when player is not active")
+	}
+}
+
 func (p *BasePlayer) Bust() {
 	if p.State == Active {
 		p.State = Busted
@@ -162,18 +236,29 @@ func (p *BasePlayer) Bust() {
 
 // CalculateRoundScore calculates the player's score for the current round
 func (p *BasePlayer) CalculateRoundScore() int {
-	if p.State == Busted {
+	if p.State == Busted || p.State == Out {
 		return 0
 	}
+	if p.scorer != nil {
+		return p.scorer.ScoreHand(p.NumberCards, p.ModifierCards, bits.OnesCount16(p.held) == 7)
+	}
+	return ScoreHand(p.NumberCards, p.ModifierCards, bits.OnesCount16(p.held) == 7)
+}
 
+// flip7Bonus is the extra score for collecting seven number cards
+const flip7Bonus = 15
+
+// ScoreHand scores a hand that didn't bust: the number cards, doubled by
+// x2, then the other modifiers, then the Flip 7 bonus
+func ScoreHand(numbers, modifiers []*Card, flip7 bool) int {
 	// Calculate base score from number cards
 	numberTotal := 0
-	for _, card := range p.NumberCards {
+	for _, card := range numbers {
 		numberTotal += card.Value
 	}
 
 	// Apply multiplier if present
-	for _, card := range p.ModifierCards {
+	for _, card := range modifiers {
 		if card.Modifier == Multiply2 {
 			numberTotal *= 2
 			break
@@ -182,7 +267,7 @@ func (p *BasePlayer) CalculateRoundScore() int {
 
 	// Add modifier points
 	modifierTotal := 0
-	for _, card := range p.ModifierCards {
+	for _, card := range modifiers {
 		if card.Modifier != Multiply2 {
 			modifierTotal += card.GetPoints()
 		}
@@ -191,8 +276,8 @@ func (p *BasePlayer) CalculateRoundScore() int {
 	total := numberTotal + modifierTotal
 
 	// Add Flip 7 bonus
-	if len(p.NumberCards) == 7 {
-		total += 15
+	if flip7 {
+		total += flip7Bonus
 	}
 
 	return total
@@ -206,11 +291,16 @@ func (p *BasePlayer) AddToTotalScore() {
 // ResetForNewRound resets the player's state for a new round
 func (p *BasePlayer) ResetForNewRound() []*Card {
 	discardedCards := p.GetHand()
-	p.NumberCards = make([]*Card, 0)
-	p.ModifierCards = make([]*Card, 0)
-	p.ActionCards = make([]*Card, 0)
+	p.NumberCards = p.NumberCards[:0]
+	p.ModifierCards = p.ModifierCards[:0]
+	p.ActionCards = p.ActionCards[:0]
+	p.held = 0
 	p.State = Active
+	if p.Eliminated {
+		p.State = Out
+	}
 	p.SecondChance = false
+	p.BustedBy = nil
 	return discardedCards
 }
 
@@ -225,62 +315,94 @@ func (p *BasePlayer) HasCards() bool {
 }
 
 // ShowHand displays the player's current hand
-func (p *BasePlayer) ShowHand() {
-	fmt.Printf("%s:\n", p.Name)
+func (p *BasePlayer) ShowHand(w io.Writer, r Renderer) {
+	fmt.Fprintf(w, "%s:\n", p.Name)
 
-	if len(p.NumberCards) == 0 && len(p.ModifierCards) == 0 {
-		fmt.Println("   No cards")
+	if p.State == Out {
+		fmt.Fprintln(w, T("   ❌ OUT"))
+		fmt.Fprintln(w)
+		return
+	}
+
+	held := p.HeldActions()
+	if len(p.NumberCards) == 0 && len(p.ModifierCards) == 0 && len(held) == 0 {
+		fmt.Fprintln(w, T("   No cards"))
 		return
 	}
 
 	// Show number cards
 	if len(p.NumberCards) > 0 {
-		fmt.Print("   Numbers: ")
+		fmt.Fprint(w, T("   Numbers: "))
 		for i, card := range p.NumberCards {
 			if i > 0 {
-				fmt.Print(" ")
+				fmt.Fprint(w, " ")
 			}
-			fmt.Print(card.String())
+			fmt.Fprint(w, r.Card(card))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
 	}
 
 	// Show modifier cards
 	if len(p.ModifierCards) > 0 {
-		fmt.Print("   Modifiers: ")
+		fmt.Fprint(w, T("   Modifiers: "))
 		for i, card := range p.ModifierCards {
 			if i > 0 {
-				fmt.Print(" ")
+				fmt.Fprint(w, " ")
 			}
-			fmt.Print(card.String())
+			fmt.Fprint(w, r.Card(card))
 		}
-		fmt.Println()
+		fmt.Fprintln(w)
+	}
+
+	// Show action cards held for later
+	if len(held) > 0 {
+		fmt.Fprint(w, T("   Held: "))
+		for i, card := range held {
+			if i > 0 {
+				fmt.Fprint(w, " ")
+			}
+			fmt.Fprint(w, r.Card(card))
+		}
+		fmt.Fprintln(w)
 	}
 
 	// Show special status
 	if p.HasSecondChance() {
-		fmt.Println("   🆘 Has Second Chance")
+		fmt.Fprintln(w, T("   🆘 Has Second Chance"))
 	}
 
 	// Show state
 	switch p.State {
 	case Stayed:
-		fmt.Printf("   ✅ STAYED - Round Score: %d\n", p.CalculateRoundScore())
+		fmt.Fprintf(w, T("   ✅ STAYED - Round Score: %d\n"), p.CalculateRoundScore())
+	case Frozen:
+		fmt.Fprintf(w, T("   ❄️ FROZEN - Round Score: %d\n"), p.CalculateRoundScore())
 	case Busted:
-		fmt.Println("   💥 BUSTED")
+		if p.BustedBy != nil {
+			fmt.Fprintln(w, r.Bust(fmt.Sprintf(T("   💥 BUSTED on %s"), r.Card(p.BustedBy))))
+		} else {
+			fmt.Fprintln(w, r.Bust(T("   💥 BUSTED")))
+		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(w)
 }
 
 // GetHandSummary returns a compact summary of the player's hand
 func (p *BasePlayer) GetHandSummary() string {
+	if p.State == Out {
+		return T("❌ OUT")
+	}
 	if p.State == Busted {
-		return "💥 BUSTED"
+		if p.BustedBy != nil {
+			return fmt.Sprintf(T("💥 BUSTED on %d"), p.BustedBy.Value)
+		}
+		return T("💥 BUSTED")
 	}
 
-	if len(p.NumberCards) == 0 && len(p.ModifierCards) == 0 {
-		return "No cards"
+	held := p.HeldActions()
+	if len(p.NumberCards) == 0 && len(p.ModifierCards) == 0 && len(held) == 0 {
+		return T("No cards")
 	}
 
 	parts := make([]string, 0)
@@ -301,10 +423,21 @@ func (p *BasePlayer) GetHandSummary() string {
 		parts = append(parts, strings.Join(mods, ","))
 	}
 
+	if len(held) > 0 {
+		actions := make([]string, len(held))
+		for i, card := range held {
+			actions[i] = card.String()
+		}
+		parts = append(parts, strings.Join(actions, ","))
+	}
+
 	result := strings.Join(parts, " | ")
 
-	if p.State == Stayed {
-		result += fmt.Sprintf(" (STAYED: %d pts)", p.CalculateRoundScore())
+	switch p.State {
+	case Stayed:
+		result += fmt.Sprintf(T(" (STAYED: %d pts)"), p.CalculateRoundScore())
+	case Frozen:
+		result += fmt.Sprintf(T(" (FROZEN: %d pts)"), p.CalculateRoundScore())
 	}
 
 	return result
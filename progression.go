@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// progressionWidth is the most rounds a sparkline draws; longer games
+// are drawn with each column standing for several rounds
+const progressionWidth = 60
+
+// sparkLevels are the bars a sparkline is drawn with, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇")
+
+// ScoreProgression is every player's total after each round of a game,
+// for drawing how the lead changed hands
+type ScoreProgression struct {
+	Players []PlayerInterface
+	// Rounds are the round numbers, in the order they were played
+	Rounds []int
+	// Totals holds a row per round of every player's total, in seat order
+	Totals [][]int
+}
+
+// trackProgression records every player's total at the end of each
+// round, for the chart shown when the game ends
+func (g *Game) trackProgression(event Event) {
+	if g.silentMode {
+		return
+	}
+	switch event.Type {
+	case GameStarted:
+		g.progression = ScoreProgression{Players: g.players}
+	case RoundEnded:
+		g.progression.Rounds = append(g.progression.Rounds, event.Round)
+		g.progression.Totals = append(g.progression.Totals, totals(g.players))
+	}
+}
+
+// showProgression draws the game's score progression
+func (g *Game) showProgression() {
+	if len(g.progression.Rounds) == 0 {
+		return
+	}
+	g.render(func(w io.Writer) { g.renderer.RenderProgression(w, &g.progression) })
+}
+
+// totals returns every player's total, in seat order
+func totals(players []PlayerInterface) []int {
+	totals := make([]int, len(players))
+	for i, player := range players {
+		totals[i] = player.GetTotalScore()
+	}
+	return totals
+}
+
+// Series returns one player's total after each round
+func (p *ScoreProgression) Series(player int) []int {
+	series := make([]int, len(p.Totals))
+	for r, totals := range p.Totals {
+		series[r] = totals[player]
+	}
+	return series
+}
+
+// leaders returns who led after each round, or -1 for a tie at the top
+func (p *ScoreProgression) leaders() []int {
+	leaders := make([]int, len(p.Totals))
+	for r, totals := range p.Totals {
+		leaders[r] = -1
+		best := -1
+		for i, total := range totals {
+			switch {
+			case total > best:
+				best, leaders[r] = total, i
+			case total == best:
+				leaders[r] = -1
+			}
+		}
+	}
+	return leaders
+}
+
+// writeProgression draws a sparkline of every player's total by round,
+// all on the same scale, then the changes of lead and the biggest round
+func writeProgression(w io.Writer, r Renderer, p *ScoreProgression) {
+	top := 0
+	for _, totals := range p.Totals {
+		for _, total := range totals {
+			top = max(top, total)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%s\n", r.Heading(fmt.Sprintf(T("📈 Score by round (rounds %d-%d):"), p.Rounds[0], p.Rounds[len(p.Rounds)-1])))
+	bestPlayer, bestRound, bestGain := -1, 0, 0
+	for i, player := range p.Players {
+		series := p.Series(i)
+		fmt.Fprintf(w, "   %-20s %s %3d\n", player.GetName(), sparkline(series, top), series[len(series)-1])
+		prev := 0
+		if p.Rounds[0] > 1 {
+			prev = series[0]
+		}
+		for r, total := range series {
+			if gain := total - prev; gain > bestGain {
+				bestPlayer, bestRound, bestGain = i, p.Rounds[r], gain
+			}
+			prev = total
+		}
+	}
+
+	var lead []string
+	leaders := p.leaders()
+	for start := 0; start < len(leaders); {
+		end := start
+		for end+1 < len(leaders) && leaders[end+1] == leaders[start] {
+			end++
+		}
+		rounds := fmt.Sprint(p.Rounds[start])
+		if end > start {
+			rounds = fmt.Sprintf("%d-%d", p.Rounds[start], p.Rounds[end])
+		}
+		who := T("tied")
+		if leaders[start] >= 0 {
+			who = p.Players[leaders[start]].GetName()
+		}
+		lead = append(lead, fmt.Sprintf("%s (%s)", who, rounds))
+		start = end + 1
+	}
+	fmt.Fprintf(w, T("   Lead: %s\n"), strings.Join(lead, " → "))
+	if bestPlayer >= 0 {
+		fmt.Fprintf(w, T("   Biggest round: %s, +%d in round %d\n"), p.Players[bestPlayer].GetName(), bestGain, bestRound)
+	}
+}
+
+// sparkline draws values scaled so that top is the tallest bar, one
+// column per value, or per group of values if there are more than fit
+func sparkline(values []int, top int) string {
+	per := (len(values) + progressionWidth - 1) / progressionWidth
+	var sb strings.Builder
+	for i := per - 1; i < len(values)+per-1; i += per {
+		value := values[min(i, len(values)-1)]
+		level := 0
+		if top > 0 {
+			level = value * (len(sparkLevels) - 1) / top
+		}
+		sb.WriteRune(sparkLevels[max(level, 0)])
+	}
+	return sb.String()
+}
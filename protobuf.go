@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// maxProtoFrame is the largest protobuf frame the env reads
+const maxProtoFrame = 1 << 20
+
+// The append functions write one field of a protobuf message as proto3
+// does, leaving out fields with their zero value
+
+func appendTag(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendInt(b []byte, field, v int) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.AppendUvarint(appendTag(b, field, wireVarint), uint64(int64(v)))
+}
+
+func appendBool(b []byte, field int, v bool) []byte {
+	if !v {
+		return b
+	}
+	return appendInt(b, field, 1)
+}
+
+func appendDouble(b []byte, field int, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	return binary.LittleEndian.AppendUint64(appendTag(b, field, wireFixed64), math.Float64bits(v))
+}
+
+func appendBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(appendTag(b, field, wireBytes), uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendBytes(b, field, []byte(s))
+}
+
+func appendPackedDoubles(b []byte, field int, vs []float64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(v))
+	}
+	return appendBytes(b, field, packed)
+}
+
+func appendPackedInts(b []byte, field int, vs []int) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = binary.AppendUvarint(packed, uint64(int64(v)))
+	}
+	return appendBytes(b, field, packed)
+}
+
+// appendProto writes the spec as a Spec message
+func (s envSpec) appendProto(b []byte) []byte {
+	b = appendInt(b, 1, s.Version)
+	for _, action := range s.Actions {
+		b = appendBytes(b, 2, []byte(action))
+	}
+	for _, name := range s.Observation {
+		b = appendBytes(b, 3, []byte(name))
+	}
+	return appendInt(b, 4, s.Players)
+}
+
+// appendProto writes the time step as a TimeStep message
+func (t TimeStep) appendProto(b []byte) []byte {
+	b = appendPackedDoubles(b, 1, t.Observation)
+	b = appendPackedInts(b, 2, t.LegalActions)
+	b = appendDouble(b, 3, t.Reward)
+	b = appendBool(b, 4, t.Done)
+	b = appendInt(b, 5, t.Round)
+	for _, name := range slices.Sorted(maps.Keys(t.Scores)) {
+		entry := appendString(nil, 1, name)
+		entry = appendInt(entry, 2, t.Scores[name])
+		b = appendBytes(b, 6, entry)
+	}
+	for _, event := range t.Events {
+		b = appendBytes(b, 7, event.appendProto(nil))
+	}
+	return appendBytes(b, 8, t.State.appendProto(nil))
+}
+
+// appendProto writes the event as an Event message, its type as the
+// EventType it's named for
+func (m EventMessage) appendProto(b []byte) []byte {
+	for t := GameStarted; t <= DeckPeeked; t++ {
+		if t.String() == m.Type {
+			b = appendInt(b, 1, int(t))
+		}
+	}
+	b = appendInt(b, 2, m.Round)
+	b = appendString(b, 3, m.Player)
+	b = appendString(b, 4, m.Target)
+	b = appendString(b, 5, m.Card)
+	return appendInt(b, 6, m.Score)
+}
+
+// appendProto writes the snapshot as a State message
+func (s StateSnapshot) appendProto(b []byte) []byte {
+	b = appendInt(b, 1, s.Round)
+	b = appendString(b, 2, s.Dealer)
+	b = appendInt(b, 3, s.WinningScore)
+	b = appendInt(b, 4, s.CardsLeft)
+	for _, p := range s.Players {
+		player := appendString(nil, 1, p.Name)
+		player = appendString(player, 2, p.Hand)
+		player = appendInt(player, 3, p.RoundScore)
+		player = appendInt(player, 4, p.TotalScore)
+		player = appendBool(player, 5, p.Active)
+		player = appendBool(player, 6, p.SecondChance)
+		player = appendString(player, 7, p.State)
+		player = appendString(player, 8, p.BustedBy)
+		b = appendBytes(b, 5, player)
+	}
+	return b
+}
+
+// appendProtoResponse writes a response to a request as a Response
+// message
+func appendProtoResponse(b []byte, resp any, err error) []byte {
+	if err != nil {
+		return appendBytes(b, 3, []byte(err.Error()))
+	}
+	switch resp := resp.(type) {
+	case envSpec:
+		return appendBytes(b, 1, resp.appendProto(nil))
+	case TimeStep:
+		return appendBytes(b, 2, resp.appendProto(nil))
+	}
+	return b
+}
+
+// decodeProtoRequest reads a Request message, skipping unknown fields
+func decodeProtoRequest(data []byte) (envRequest, error) {
+	var req envRequest
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return req, fmt.Errorf("malformed request")
+		}
+		data = data[n:]
+		field, wire := int(key>>3), int(key&7)
+
+		var value uint64
+		var bytes []byte
+		switch wire {
+		case wireVarint:
+			value, n = binary.Uvarint(data)
+		case wireFixed64:
+			n = 8
+		case wireFixed32:
+			n = 4
+		case wireBytes:
+			var size uint64
+			size, n = binary.Uvarint(data)
+			if n > 0 && uint64(len(data)-n) >= size {
+				bytes = data[n : n+int(size)]
+				n += int(size)
+			} else {
+				n = -1
+			}
+		default:
+			n = -1
+		}
+		if n <= 0 || n > len(data) {
+			return req, fmt.Errorf("malformed request")
+		}
+		data = data[n:]
+
+		switch {
+		case field == 1 && wire == wireBytes:
+			req.Op = string(bytes)
+		case field == 2 && wire == wireVarint:
+			req.Seed = int64(value)
+		case field == 3 && wire == wireVarint:
+			req.Action = int(int32(value))
+		}
+	}
+	return req, nil
+}
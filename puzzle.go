@@ -0,0 +1,461 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PuzzleSeat is a player at the table in a puzzle position
+type PuzzleSeat struct {
+	Name  string
+	Total int
+	Hand  []*Card
+}
+
+// Puzzle is a position where the human has to find the best move. If
+// Action is nil the question is hit or stay, otherwise the human drew that
+// action card and has to pick which opponent gets it.
+type Puzzle struct {
+	Title     string
+	You       PuzzleSeat
+	Opponents []PuzzleSeat
+	Discards  []*Card
+	Action    *ActionType
+}
+
+// PuzzleAnswer is the solver's move for a puzzle and why it is best
+type PuzzleAnswer struct {
+	Hit    bool
+	Target int
+	Reason string
+}
+
+// PuzzleStats is the puzzle record kept between runs
+type PuzzleStats struct {
+	Attempted  int
+	Solved     int
+	Streak     int
+	BestStreak int
+}
+
+// curatedPuzzles are hand-picked positions played before generated ones
+var curatedPuzzles = []Puzzle{
+	{
+		Title:     "Big hand, long odds",
+		You:       PuzzleSeat{Name: "You", Total: 120, Hand: mustParseCards("12 11 10 9")},
+		Opponents: []PuzzleSeat{{Name: "Bot", Total: 150, Hand: mustParseCards("5")}},
+	},
+	{
+		Title:     "A slow start",
+		You:       PuzzleSeat{Name: "You", Total: 40, Hand: mustParseCards("2 3")},
+		Opponents: []PuzzleSeat{{Name: "Bot", Total: 60, Hand: mustParseCards("8 4")}},
+		Discards:  mustParseCards("12 12 11 3"),
+	},
+	{
+		Title:     "One away from Flip 7",
+		You:       PuzzleSeat{Name: "You", Total: 90, Hand: mustParseCards("0 1 2 3 4 5")},
+		Opponents: []PuzzleSeat{{Name: "Bot", Total: 110, Hand: mustParseCards("9 10")}},
+	},
+	{
+		Title: "Who flips three?",
+		You:   PuzzleSeat{Name: "You", Total: 100, Hand: mustParseCards("6")},
+		Opponents: []PuzzleSeat{
+			{Name: "Alice", Total: 110, Hand: mustParseCards("12 11 10 +10")},
+			{Name: "Bob", Total: 130, Hand: mustParseCards("2")},
+		},
+		Action: actionPtr(FlipThree),
+	},
+	{
+		Title: "Cold feet",
+		You:   PuzzleSeat{Name: "You", Total: 70, Hand: mustParseCards("7 8")},
+		Opponents: []PuzzleSeat{
+			{Name: "Alice", Total: 80, Hand: mustParseCards("3")},
+			{Name: "Bob", Total: 90, Hand: mustParseCards("12 11 10 9 8")},
+		},
+		Action: actionPtr(Freeze),
+	},
+	{
+		Title:     "Doubled up",
+		You:       PuzzleSeat{Name: "You", Total: 150, Hand: mustParseCards("6 7 x2 +4")},
+		Opponents: []PuzzleSeat{{Name: "Bot", Total: 185, Hand: mustParseCards("11 12")}},
+		Discards:  mustParseCards("6 6 7 7 7"),
+	},
+}
+
+func actionPtr(action ActionType) *ActionType {
+	return &action
+}
+
+// parseCard parses a card written as a number, "+4", "x2", "freeze",
+// "flip3" or "chance"
+func parseCard(s string) (*Card, error) {
+	switch strings.ToLower(s) {
+	case "+2":
+		return NewModifierCard(Plus2), nil
+	case "+4":
+		return NewModifierCard(Plus4), nil
+	case "+6":
+		return NewModifierCard(Plus6), nil
+	case "+8":
+		return NewModifierCard(Plus8), nil
+	case "+10":
+		return NewModifierCard(Plus10), nil
+	case "x2":
+		return NewModifierCard(Multiply2), nil
+	case "freeze":
+		return NewActionCard(Freeze), nil
+	case "flip3":
+		return NewActionCard(FlipThree), nil
+	case "chance":
+		return NewActionCard(SecondChance), nil
+	}
+
+	value, err := strconv.Atoi(s)
+	if err != nil || value < 0 || value > 12 {
+		return nil, fmt.Errorf("unknown card %q", s)
+	}
+	return NewNumberCard(value), nil
+}
+
+// formatCard writes a card the way parseCard reads it
+func formatCard(c *Card) string {
+	switch c.Type {
+	case ModifierCard:
+		if c.Modifier == Multiply2 {
+			return "x2"
+		}
+		return fmt.Sprintf("+%d", c.GetPoints())
+	case ActionCard:
+		switch c.Action {
+		case Freeze:
+			return "freeze"
+		case FlipThree:
+			return "flip3"
+		default:
+			return "chance"
+		}
+	}
+	return strconv.Itoa(c.Value)
+}
+
+// mustParseCards parses a space-separated card list for the curated puzzles
+func mustParseCards(s string) []*Card {
+	cards, err := parseCards(s)
+	if err != nil {
+		panic(err)
+	}
+	return cards
+}
+
+// player builds the seat's player state
+func (s PuzzleSeat) player() *BasePlayer {
+	p := &BasePlayer{}
+	p.Init(s.Name)
+	p.TotalScore = s.Total
+	for _, card := range s.Hand {
+		p.AddCard(card)
+	}
+	return p
+}
+
+// unseen returns the cards that are in no hand and not in the discards
+func (pz *Puzzle) unseen() []*Card {
+	full := &Deck{}
+	full.createCards()
+
+	visible := append(append([]*Card(nil), pz.You.Hand...), pz.Discards...)
+	for _, seat := range pz.Opponents {
+		visible = append(visible, seat.Hand...)
+	}
+
+	unseen, _ := takeCards(full.cards, visible)
+	return unseen
+}
+
+// expectedAfterHit tries every unseen card as the next draw and returns
+// the average round score afterwards and the chance of busting. Action
+// cards are counted as leaving the score unchanged.
+func expectedAfterHit(p *BasePlayer, unseen []*Card) (float64, float64) {
+	if len(unseen) == 0 {
+		return float64(p.CalculateRoundScore()), 0
+	}
+
+	total, busts := 0, 0
+	for _, card := range unseen {
+		next := cloneBasePlayer(p)
+		err := next.AddCard(card)
+		switch {
+		case err == nil, err.Error() == "flip7":
+			total += next.CalculateRoundScore()
+		case strings.HasPrefix(err.Error(), "bust"):
+			busts++
+		default:
+			total += p.CalculateRoundScore()
+		}
+	}
+	return float64(total) / float64(len(unseen)), float64(busts) / float64(len(unseen))
+}
+
+// Solve works out the best move for the puzzle
+func (pz *Puzzle) Solve() PuzzleAnswer {
+	unseen := pz.unseen()
+
+	if pz.Action == nil {
+		you := pz.You.player()
+		stay := you.CalculateRoundScore()
+		ev, bust := expectedAfterHit(you, unseen)
+		return PuzzleAnswer{
+			Hit:    ev > float64(stay),
+			Reason: fmt.Sprintf(T("Staying banks %d. Hitting busts %.0f%% of the time and averages %.1f points after one card."), stay, bust*100, ev),
+		}
+	}
+
+	best, bestValue := 0, math.Inf(-1)
+	var reason string
+	for i, seat := range pz.Opponents {
+		opponent := seat.player()
+		score := opponent.CalculateRoundScore()
+		ev, bust := expectedAfterHit(opponent, unseen)
+
+		switch *pz.Action {
+		case Freeze:
+			// Freezing denies the points they expect to gain by hitting
+			if gain := ev - float64(score); gain > bestValue {
+				best, bestValue = i, gain
+				reason = fmt.Sprintf(T("Freezing %s denies the most: they expect to gain %.1f points by hitting."), seat.Name, gain)
+			}
+		case FlipThree:
+			// Three draws put their round score at risk three times
+			bust3 := 1 - math.Pow(1-bust, 3)
+			if loss := bust3 * float64(score); loss > bestValue {
+				best, bestValue = i, loss
+				reason = fmt.Sprintf(T("%s has %d points at stake and about a %.0f%% chance to bust over three cards."), seat.Name, score, bust3*100)
+			}
+		}
+	}
+	return PuzzleAnswer{Target: best, Reason: reason}
+}
+
+// generatePuzzle deals a random position from a shuffled deck, taking
+// every choice from r so a seed replays the same puzzles
+func generatePuzzle(r *rand.Rand) Puzzle {
+	deck := &Deck{rng: r}
+	deck.createCards()
+	deck.Shuffle()
+
+	var discards []*Card
+	deal := func(name string) PuzzleSeat {
+		seat := PuzzleSeat{Name: name, Total: r.Intn(39) * 5}
+		p := &BasePlayer{}
+		p.Init(name)
+		want := 1 + r.Intn(5)
+		for p.NumberOfNumberCards() < want && len(deck.cards) > 0 {
+			card := deck.cards[0]
+			deck.cards = deck.cards[1:]
+			if card.Type == ActionCard || card.Type == NumberCard && p.Holds(card.Value) {
+				discards = append(discards, card)
+				continue
+			}
+			p.AddCard(card)
+			seat.Hand = append(seat.Hand, card)
+		}
+		return seat
+	}
+
+	pz := Puzzle{Title: T("Generated position"), You: deal("You")}
+	for i := 0; i < 1+r.Intn(3); i++ {
+		pz.Opponents = append(pz.Opponents, deal(computerNames[r.Intn(len(computerNames))]))
+	}
+	for i := r.Intn(20); i > 0 && len(deck.cards) > 0; i-- {
+		discards = append(discards, deck.cards[0])
+		deck.cards = deck.cards[1:]
+	}
+	pz.Discards = discards
+
+	if len(pz.Opponents) > 1 && r.Intn(3) == 0 {
+		pz.Action = actionPtr(ActionType(r.Intn(2)))
+	}
+	return pz
+}
+
+// show prints the position and the question
+func (pz *Puzzle) show(out io.Writer, r Renderer, n int) {
+	fmt.Fprintf(out, "\n%s\n", r.Heading(fmt.Sprintf(T("🧩 Puzzle %d: %s"), n, T(pz.Title))))
+	fmt.Fprintf(out, T("Your total: %d\n\n"), pz.You.Total)
+	you := pz.You.player()
+	you.Name = T(you.Name)
+	you.ShowHand(out, r)
+	for _, seat := range pz.Opponents {
+		fmt.Fprintf(out, T("%s has %d banked\n"), seat.Name, seat.Total)
+		seat.player().ShowHand(out, r)
+	}
+	if len(pz.Discards) > 0 {
+		fmt.Fprintf(out, T("Discard pile (%d cards): %s\n"), len(pz.Discards), summarizeCards(r, pz.Discards))
+	}
+
+	if pz.Action == nil {
+		fmt.Fprint(out, T("🎯 Do you (H)it or (S)tay? "))
+		return
+	}
+	fmt.Fprintf(out, T("You drew %s. Who gets it?\n"), r.Card(NewActionCard(*pz.Action)))
+	for i, seat := range pz.Opponents {
+		fmt.Fprintf(out, "   %d) %s\n", i+1, seat.Name)
+	}
+	fmt.Fprintf(out, T("Enter choice (1-%d): "), len(pz.Opponents))
+}
+
+// readMove reads the human's answer to a puzzle
+func (pz *Puzzle) readMove(scanner *bufio.Scanner, out io.Writer) (PuzzleAnswer, error) {
+	for {
+		if !scanner.Scan() {
+			return PuzzleAnswer{}, fmt.Errorf("failed to read input")
+		}
+		choice := strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+		if pz.Action == nil {
+			if choice == "h" || choice == "hit" || choice == T("h") || choice == T("hit") {
+				return PuzzleAnswer{Hit: true}, nil
+			}
+			if choice == "s" || choice == "stay" || choice == T("s") || choice == T("stay") {
+				return PuzzleAnswer{Hit: false}, nil
+			}
+			fmt.Fprint(out, T("Please enter 'H' for Hit or 'S' for Stay: "))
+			continue
+		}
+
+		n, err := strconv.Atoi(choice)
+		if err == nil && n >= 1 && n <= len(pz.Opponents) {
+			return PuzzleAnswer{Target: n - 1}, nil
+		}
+		fmt.Fprintf(out, T("Please enter a number between %d and %d: "), 1, len(pz.Opponents))
+	}
+}
+
+// puzzleStatsPath is where the puzzle record is kept
+func puzzleStatsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "flip7", "puzzles.json"), nil
+}
+
+// loadPuzzleStats reads the puzzle record, starting fresh if there is none
+func loadPuzzleStats(path string) (PuzzleStats, error) {
+	var stats PuzzleStats
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return stats, fmt.Errorf("reading puzzle stats %s: %w", path, err)
+	}
+	return stats, nil
+}
+
+// savePuzzleStats writes the puzzle record
+func savePuzzleStats(path string, stats PuzzleStats) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// RunPuzzles runs the puzzle subcommand: it plays the curated puzzles and
+// then generated ones, checking each answer against the solver
+func RunPuzzles(args []string, in io.Reader, out io.Writer, r Renderer) error {
+	fs := flag.NewFlagSet("puzzle", flag.ContinueOnError)
+	count := fs.Int("n", 5, "Number of puzzles to play")
+	generatedOnly := fs.Bool("generated", false, "Skip the curated puzzles")
+	seed := fs.Int64("seed", 0, "Seed for generated puzzles (0 uses the clock)")
+	statsFile := fs.String("stats", "", "Puzzle record file (defaults to the user config directory)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	path := *statsFile
+	if path == "" {
+		var err error
+		if path, err = puzzleStatsPath(); err != nil {
+			return err
+		}
+	}
+	stats, err := loadPuzzleStats(path)
+	if err != nil {
+		return err
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	random := rand.New(rand.NewSource(*seed))
+	scanner := bufio.NewScanner(in)
+
+	next := 0
+	if *generatedOnly {
+		next = len(curatedPuzzles)
+	}
+	for i := 1; i <= *count; i++ {
+		var pz Puzzle
+		if next < len(curatedPuzzles) {
+			pz = curatedPuzzles[next]
+			next++
+		} else {
+			pz = generatePuzzle(random)
+		}
+
+		pz.show(out, r, i)
+		move, err := pz.readMove(scanner, out)
+		if err != nil {
+			return err
+		}
+
+		answer := pz.Solve()
+		correct := move.Hit == answer.Hit
+		best := T("stay")
+		if answer.Hit {
+			best = T("hit")
+		}
+		if pz.Action != nil {
+			correct = move.Target == answer.Target
+			best = pz.Opponents[answer.Target].Name
+		}
+
+		stats.Attempted++
+		if correct {
+			stats.Solved++
+			stats.Streak++
+			stats.BestStreak = max(stats.BestStreak, stats.Streak)
+			fmt.Fprintf(out, T("✅ Correct! Best move: %s\n"), best)
+		} else {
+			stats.Streak = 0
+			fmt.Fprintf(out, T("❌ Not quite. Best move: %s\n"), best)
+		}
+		fmt.Fprintf(out, "   %s\n", answer.Reason)
+
+		if err := savePuzzleStats(path, stats); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(out, T("\n🧩 Solved %d of %d puzzles, streak %d (best %d)\n"), stats.Solved, stats.Attempted, stats.Streak, stats.BestStreak)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedSource is a rand.Source that is safe to share between goroutines
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
+
+// rng is the source of every random choice outside the deck: computer
+// names and random strategies. Seeding it (and the deck) makes a whole run
+// reproducible.
+var rng = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano())})
+
+// banterRng picks banter lines and computer names for games without a
+// seed of their own. It is kept apart from rng so that showing or hiding
+// banter never changes how a game plays out.
+var banterRng = rand.New(&lockedSource{src: rand.NewSource(time.Now().UnixNano())})
+
+// SetRandom gives the game its own source for the computer players'
+// random choices, so games can run side by side without sharing one
+func (g *Game) SetRandom(r *rand.Rand) {
+	g.rng = r
+}
+
+// splitmix64 is the SplitMix64 mixing function. Consecutive inputs give
+// unrelated outputs, so it derives child seeds from a master seed.
+func splitmix64(x uint64) uint64 {
+	z := x + 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// streamSeed derives the seed of a game's own random stream from the
+// seed its cards are dealt from
+func streamSeed(seed int64) int64 {
+	return int64(splitmix64(uint64(seed)))
+}
+
+// seedRandom gives the game a random source of its own for the computer
+// players' choices, seeded from a child of seed, and another for its
+// banter and computer names, seeded from a child of that. Neither is
+// shared with any other game, so games played side by side replay
+// exactly from their seeds, banter and all, however they're scheduled.
+func (g *Game) seedRandom(seed int64) {
+	g.rngSeed = streamSeed(seed)
+	g.rng = rand.New(rand.NewSource(g.rngSeed))
+	// A simulation reseeds its banter source game after game rather than
+	// allocate one per game that a silent game never reads
+	if g.banter == nil || g.banter == banterRng {
+		g.banter = rand.New(rand.NewSource(streamSeed(g.rngSeed)))
+	} else {
+		g.banter.Seed(streamSeed(g.rngSeed))
+	}
+}
+
+// SeedRandom reseeds the shared random sources
+func SeedRandom(seed int64) {
+	rng.Seed(seed)
+	banterRng.Seed(seed)
+}
@@ -0,0 +1,48 @@
+package main
+
+// Reactions are the callbacks a player gets when the game does something
+// to them, or to an opponent, so strategies that learn from the table
+// can update what they know. BasePlayer implements them all as no-ops;
+// players override the ones they care about.
+type Reactions interface {
+	// OnFrozen is called when by plays a Freeze on the player
+	OnFrozen(by PlayerInterface)
+	// OnFlipThree is called when by plays a Flip Three on the player,
+	// before the three cards are dealt
+	OnFlipThree(by PlayerInterface)
+	// OnReceivedSecondChance is called when from passes the player a
+	// spare Second Chance
+	OnReceivedSecondChance(from PlayerInterface)
+	// OnOpponentBusted is called on every other player when opponent
+	// busts on card
+	OnOpponentBusted(opponent PlayerInterface, card *Card)
+}
+
+func (p *BasePlayer) OnFrozen(by PlayerInterface)                           {}
+func (p *BasePlayer) OnFlipThree(by PlayerInterface)                        {}
+func (p *BasePlayer) OnReceivedSecondChance(from PlayerInterface)           {}
+func (p *BasePlayer) OnOpponentBusted(opponent PlayerInterface, card *Card) {}
+
+// react is an EventListener that calls the players' reaction callbacks
+func (g *Game) react(event Event) {
+	switch event.Type {
+	case ActionPlayed:
+		if event.Target == nil || event.Target == event.Player {
+			return
+		}
+		switch event.Card.Action {
+		case Freeze:
+			event.Target.OnFrozen(event.Player)
+		case FlipThree:
+			event.Target.OnFlipThree(event.Player)
+		case SecondChance:
+			event.Target.OnReceivedSecondChance(event.Player)
+		}
+	case PlayerBusted:
+		for _, player := range g.players {
+			if player != event.Player {
+				player.OnOpponentBusted(event.Player, event.Card)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// recapRound prints a summary of the round when it ends. It is built from
+// the round's events in the history rather than from the game state.
+func (g *Game) recapRound(event Event) {
+	if event.Type != RoundEnded || g.silentMode {
+		return
+	}
+
+	var (
+		best    *Event
+		busts   []Event
+		actions []Event
+		flip7s  []Event
+		scores  []Event
+	)
+	for i, e := range g.history {
+		switch e.Type {
+		case PlayerBusted:
+			busts = append(busts, e)
+		case ActionPlayed:
+			actions = append(actions, e)
+		case Flip7Achieved:
+			flip7s = append(flip7s, e)
+		case PlayerScored:
+			scores = append(scores, e)
+			if best == nil || e.Score > best.Score {
+				best = &g.history[i]
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n", g.renderer.Heading(fmt.Sprintf(T("📰 Round %d recap"), event.Round)))
+	if best != nil && best.Score > 0 {
+		fmt.Fprintf(&sb, T("   Biggest hand: %s with %d points\n"), best.Player.GetName(), best.Score)
+	}
+	for _, e := range flip7s {
+		fmt.Fprintf(&sb, T("   %s flipped 7!\n"), e.Player.GetName())
+	}
+	for _, e := range busts {
+		fmt.Fprintf(&sb, T("   %s busted on %s\n"), e.Player.GetName(), g.renderer.Card(e.Card))
+	}
+	for _, e := range actions {
+		if e.Target == e.Player && e.Card.Action == SecondChance {
+			fmt.Fprintf(&sb, T("   %s kept %s\n"), e.Player.GetName(), g.renderer.Card(e.Card))
+			continue
+		}
+		fmt.Fprintf(&sb, T("   %s played %s on %s\n"), e.Player.GetName(), g.renderer.Card(e.Card), e.Target.GetName())
+	}
+
+	sb.WriteString(T("   Standings:"))
+	sb.WriteString("\n")
+	before := make(map[PlayerInterface]int, len(scores))
+	for rank, e := range standings(scores, func(e Event) int { return e.Player.GetTotalScore() - e.Score }) {
+		before[e.Player] = rank
+	}
+	for rank, e := range standings(scores, func(e Event) int { return e.Player.GetTotalScore() }) {
+		move := ""
+		switch old := before[e.Player]; {
+		case old > rank:
+			move = fmt.Sprintf(" ▲%d", old-rank)
+		case old < rank:
+			move = fmt.Sprintf(" ▼%d", rank-old)
+		}
+		fmt.Fprintf(&sb, T("   %d. %-20s %3d (+%d)%s\n"), rank+1, e.Player.GetName(), e.Player.GetTotalScore(), e.Score, move)
+	}
+	g.print(sb.String())
+}
+
+// standings sorts the scored players by total, keeping seat order on ties
+func standings(scores []Event, total func(Event) int) []Event {
+	sorted := append([]Event(nil), scores...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return total(sorted[i]) > total(sorted[j])
+	})
+	return sorted
+}
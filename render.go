@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Renderer draws the game for a particular kind of output. It decorates
+// the game's narration, and draws hands, the scoreboard and events, so a
+// new frontend is a new Renderer rather than a fork of the game loop.
+type Renderer interface {
+	Card(card *Card) string
+	Bust(text string) string
+	Flip7(text string) string
+	Heading(text string) string
+	RenderHand(w io.Writer, player PlayerInterface)
+	RenderScoreboard(w io.Writer, players []PlayerInterface)
+	// RenderProgression draws every player's total by round when a game
+	// is over
+	RenderProgression(w io.Writer, progression *ScoreProgression)
+	RenderEvent(w io.Writer, event Event)
+	// Narrates reports whether the game's text narration is shown;
+	// renderers that draw the game from its events leave it out
+	Narrates() bool
+}
+
+// PlainRenderer renders text without any decoration
+type PlainRenderer struct{}
+
+func (PlainRenderer) Card(card *Card) string     { return card.String() }
+func (PlainRenderer) Bust(text string) string    { return text }
+func (PlainRenderer) Flip7(text string) string   { return text }
+func (PlainRenderer) Heading(text string) string { return text }
+func (PlainRenderer) Narrates() bool             { return true }
+
+func (r PlainRenderer) RenderHand(w io.Writer, player PlayerInterface) { player.ShowHand(w, r) }
+func (PlainRenderer) RenderScoreboard(w io.Writer, players []PlayerInterface) {
+	writeScoreboard(w, players)
+}
+
+func (r PlainRenderer) RenderProgression(w io.Writer, progression *ScoreProgression) {
+	writeProgression(w, r, progression)
+}
+
+// RenderEvent draws nothing; the narration already tells the story
+func (PlainRenderer) RenderEvent(w io.Writer, event Event) {}
+
+// writeScoreboard writes every player's total as a table, in the order
+// they stand
+func writeScoreboard(w io.Writer, players []PlayerInterface) {
+	fmt.Fprintln(w, T("\n📊 Current Scores:"))
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+	for _, s := range Standings(players, false) {
+		fmt.Fprintf(w, T("%d. %s %-20s: %3d points\n"), s.Place, s.Player.GetPlayerIcon(), s.Player.GetName(), s.Total)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+}
+
+// Theme holds the ANSI SGR codes used for each kind of text
+type Theme struct {
+	Numbers  [13]string // indexed by card value
+	Action   string
+	Modifier string
+	Bust     string
+	Flip7    string
+	Heading  string
+}
+
+// themes are the available color themes, selectable with -theme
+var themes = map[string]Theme{
+	"classic": {
+		Numbers: [13]string{
+			"37", "36", "36", "32", "32", "32", "33",
+			"33", "33", "35", "35", "31", "31",
+		},
+		Action:   "1;34",
+		Modifier: "1;36",
+		Bust:     "1;31",
+		Flip7:    "1;33",
+		Heading:  "1",
+	},
+	"pastel": {
+		Numbers: [13]string{
+			"38;5;252", "38;5;159", "38;5;153", "38;5;151", "38;5;150", "38;5;187", "38;5;186",
+			"38;5;223", "38;5;222", "38;5;217", "38;5;211", "38;5;210", "38;5;204",
+		},
+		Action:   "38;5;147",
+		Modifier: "38;5;122",
+		Bust:     "1;38;5;203",
+		Flip7:    "1;38;5;220",
+		Heading:  "1;38;5;189",
+	},
+	"high-contrast": {
+		Numbers: [13]string{
+			"1;97", "1;96", "1;96", "1;92", "1;92", "1;92", "1;93",
+			"1;93", "1;93", "1;95", "1;95", "1;91", "1;91",
+		},
+		Action:   "1;97;44",
+		Modifier: "1;30;46",
+		Bust:     "1;97;41",
+		Flip7:    "1;30;43",
+		Heading:  "1;4",
+	},
+}
+
+// ThemeNames returns the names of the available themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ANSIRenderer renders text with ANSI color codes from a theme
+type ANSIRenderer struct {
+	theme Theme
+}
+
+// NewANSIRenderer creates a renderer for the given theme
+func NewANSIRenderer(theme Theme) *ANSIRenderer {
+	return &ANSIRenderer{theme: theme}
+}
+
+func (r *ANSIRenderer) color(code, text string) string {
+	if code == "" {
+		return text
+	}
+	return fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, text)
+}
+
+func (r *ANSIRenderer) Card(card *Card) string {
+	switch card.Type {
+	case NumberCard:
+		return r.color(r.theme.Numbers[card.Value], card.String())
+	case ActionCard:
+		return r.color(r.theme.Action, card.String())
+	case ModifierCard:
+		return r.color(r.theme.Modifier, card.String())
+	}
+	return card.String()
+}
+
+func (r *ANSIRenderer) Bust(text string) string    { return r.color(r.theme.Bust, text) }
+func (r *ANSIRenderer) Flip7(text string) string   { return r.color(r.theme.Flip7, text) }
+func (r *ANSIRenderer) Heading(text string) string { return r.color(r.theme.Heading, text) }
+func (r *ANSIRenderer) Narrates() bool             { return true }
+
+func (r *ANSIRenderer) RenderHand(w io.Writer, player PlayerInterface) { player.ShowHand(w, r) }
+func (r *ANSIRenderer) RenderScoreboard(w io.Writer, players []PlayerInterface) {
+	writeScoreboard(w, players)
+}
+
+func (r *ANSIRenderer) RenderProgression(w io.Writer, progression *ScoreProgression) {
+	writeProgression(w, r, progression)
+}
+
+// RenderEvent draws nothing; the narration already tells the story
+func (r *ANSIRenderer) RenderEvent(w io.Writer, event Event) {}
+
+// JSONLinesRenderer draws the game as one JSON object per line, for
+// programs following along: every event as the env protocol sends it,
+// plus "hand" and "scores" lines when the game shows them. The text
+// narration is left out.
+type JSONLinesRenderer struct {
+	PlainRenderer
+}
+
+func (JSONLinesRenderer) Narrates() bool { return false }
+
+func (JSONLinesRenderer) RenderHand(w io.Writer, player PlayerInterface) {
+	hand := struct {
+		Type   string   `json:"type"`
+		Player string   `json:"player"`
+		Cards  []string `json:"cards"`
+		Score  int      `json:"score"`
+	}{Type: "hand", Player: player.GetName(), Cards: []string{}, Score: player.CalculateRoundScore()}
+	for _, card := range player.GetHand() {
+		hand.Cards = append(hand.Cards, formatCard(card))
+	}
+	writeJSONLine(w, hand)
+}
+
+func (JSONLinesRenderer) RenderScoreboard(w io.Writer, players []PlayerInterface) {
+	type score struct {
+		Player string `json:"player"`
+		Score  int    `json:"score"`
+		Place  int    `json:"place"`
+	}
+	scores := make([]score, len(players))
+	for i, player := range players {
+		scores[i] = score{player.GetName(), player.GetTotalScore(), 0}
+	}
+	for _, s := range Standings(players, false) {
+		scores[slices.Index(players, s.Player)].Place = s.Place
+	}
+	writeJSONLine(w, struct {
+		Type   string  `json:"type"`
+		Scores []score `json:"scores"`
+	}{"scores", scores})
+}
+
+func (JSONLinesRenderer) RenderProgression(w io.Writer, progression *ScoreProgression) {
+	type series struct {
+		Player string `json:"player"`
+		Scores []int  `json:"scores"`
+	}
+	lines := make([]series, len(progression.Players))
+	for i, player := range progression.Players {
+		lines[i] = series{player.GetName(), progression.Series(i)}
+	}
+	writeJSONLine(w, struct {
+		Type   string   `json:"type"`
+		Rounds []int    `json:"rounds"`
+		Series []series `json:"series"`
+	}{"progression", progression.Rounds, lines})
+}
+
+func (JSONLinesRenderer) RenderEvent(w io.Writer, event Event) {
+	writeJSONLine(w, event.Message())
+}
+
+// writeJSONLine writes v as a line of JSON
+func writeJSONLine(w io.Writer, v any) {
+	data, _ := json.Marshal(v)
+	w.Write(append(data, '\n'))
+}
+
+// SilentRenderer draws nothing at all, for embedding the engine where
+// only its events and results matter
+type SilentRenderer struct {
+	PlainRenderer
+}
+
+func (SilentRenderer) Narrates() bool                                               { return false }
+func (SilentRenderer) RenderHand(w io.Writer, player PlayerInterface)               {}
+func (SilentRenderer) RenderScoreboard(w io.Writer, players []PlayerInterface)      {}
+func (SilentRenderer) RenderProgression(w io.Writer, progression *ScoreProgression) {}
+func (SilentRenderer) RenderEvent(w io.Writer, event Event)                         {}
+
+// NewRenderer picks a renderer for the output: "console" text, "json"
+// lines or "silent". Console colors are only used when the output is a
+// terminal and NO_COLOR is not set; the theme "none" always disables
+// them.
+func NewRenderer(kind, themeName string, out io.Writer) (Renderer, error) {
+	switch strings.ToLower(kind) {
+	case "", "console":
+	case "json":
+		return JSONLinesRenderer{}, nil
+	case "silent":
+		return SilentRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown renderer %q (available: console, json, silent)", kind)
+	}
+	themeName = strings.ToLower(themeName)
+	if themeName == "none" {
+		return PlainRenderer{}, nil
+	}
+
+	theme, ok := themes[themeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q (available: none, %s)", themeName, strings.Join(ThemeNames(), ", "))
+	}
+
+	if os.Getenv("NO_COLOR") != "" || !isTerminal(out) {
+		return PlainRenderer{}, nil
+	}
+
+	return NewANSIRenderer(theme), nil
+}
+
+// isTerminal reports whether w is a character device such as a TTY
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
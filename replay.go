@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// replayVersion is bumped whenever the replay file format changes
+const replayVersion = 1
+
+// Replay is a recorded game, as -record writes it to a .f7r file: the
+// players and rules, the position at every hit/stay decision with the
+// choice that was made, and everything that happened. It holds what
+// analyze needs to play each position out again either way, and what
+// export needs to show the game again.
+type Replay struct {
+	Version      int
+	Seed         int64 `json:",omitempty"`
+	WinningScore int
+	HoldActions  bool           `json:",omitempty"`
+	Reshuffle    string         `json:",omitempty"`
+	Burn         int            `json:",omitempty"`
+	Scoring      *VariantScorer `json:",omitempty"`
+	Cards        CardSet        `json:",omitempty"`
+	Players      []SavedPlayer
+	Winner       string
+	Decisions    []ReplayDecision
+	Events       []EventMessage `json:",omitempty"`
+}
+
+// ReplayDecision is the position when a player chose to hit or stay.
+// Totals, Hands and States are in seat order; hands and the discards are
+// card lists as scenarios write them.
+type ReplayDecision struct {
+	Round    int
+	Dealer   int
+	Seat     int
+	Hit      bool
+	Totals   []int
+	Hands    []string
+	States   []string
+	Discards string `json:",omitempty"`
+}
+
+// SetRecord makes the game record itself to a replay file at path for
+// the analyze subcommand. The file is written when the game ends.
+func (g *Game) SetRecord(path string) {
+	g.recordFile = path
+}
+
+// newReplay starts a replay of the game as it stands: its players and
+// rules, with no decisions yet. Only first-to-points games can be played
+// out again, so other games can't be replayed.
+func (g *Game) newReplay() (*Replay, error) {
+	if _, ok := g.condition().(FirstToPoints); !ok {
+		return nil, fmt.Errorf("only first-to-points games can be played out again")
+	}
+	players, err := savedPlayers(g.players)
+	if err != nil {
+		return nil, err
+	}
+	r := &Replay{
+		Version:      replayVersion,
+		WinningScore: g.winningScore,
+		HoldActions:  g.holdActions,
+		Burn:         g.burnCards,
+		Cards:        g.cardSet,
+		Players:      players,
+	}
+	if g.seeded {
+		r.Seed = g.seed
+	}
+	if g.reshuffle != ReshuffleMidRound {
+		r.Reshuffle = g.reshuffle.String()
+	}
+	if scoring, ok := g.scorer.(VariantScorer); ok {
+		r.Scoring = &scoring
+	}
+	return r, nil
+}
+
+// recordReplay keeps the replay in step with the game: it starts a new
+// one when a game starts and writes it out when the game ends
+func (g *Game) recordReplay(event Event) {
+	if g.recordFile == "" || g.silentMode {
+		return
+	}
+	if event.Type == GameStarted {
+		replay, err := g.newReplay()
+		if err != nil {
+			g.printf(T("Not recording the game: %v\n"), err)
+		}
+		g.replay = replay
+	}
+	if g.replay == nil {
+		return
+	}
+	g.replay.Events = append(g.replay.Events, event.Message())
+	if event.Type == GameEnded {
+		g.replay.Winner = event.Player.GetName()
+		if err := g.replay.Save(g.recordFile); err != nil {
+			g.printf(T("Could not write the replay: %v\n"), err)
+			return
+		}
+		g.printf(T("🎞️ Replay written to %s. Review it with: flip7 analyze %s\n"), g.recordFile, g.recordFile)
+	}
+}
+
+// record notes the position as player decides whether to hit
+func (r *Replay) record(g *Game, player PlayerInterface, hit bool) {
+	d := g.position(player)
+	d.Hit = hit
+	r.Decisions = append(r.Decisions, d)
+}
+
+// position is the game as it stands with player about to act
+func (g *Game) position(player PlayerInterface) ReplayDecision {
+	d := ReplayDecision{Round: g.round, Dealer: g.dealerIdx}
+	for i, p := range g.players {
+		if p == player {
+			d.Seat = i
+		}
+		d.Totals = append(d.Totals, p.GetTotalScore())
+		d.Hands = append(d.Hands, formatCards(p.GetHand()))
+		d.States = append(d.States, p.GetState().String())
+	}
+	d.Discards = formatCards(g.deck.Discards())
+	return d
+}
+
+// events rebuilds the recorded events, about the players given by name
+func (r *Replay) events(players []PlayerInterface) ([]Event, error) {
+	byName := make(map[string]PlayerInterface, len(players))
+	for _, player := range players {
+		byName[player.GetName()] = player
+	}
+	events := make([]Event, 0, len(r.Events))
+	for i, m := range r.Events {
+		t, ok := parseEventType(m.Type)
+		if !ok {
+			return nil, fmt.Errorf("event %d has unknown type %q", i+1, m.Type)
+		}
+		event := Event{Type: t, Round: m.Round, Player: byName[m.Player], Target: byName[m.Target], Score: m.Score}
+		if m.Card != "" {
+			card, err := parseCard(m.Card)
+			if err != nil {
+				return nil, fmt.Errorf("event %d: %w", i+1, err)
+			}
+			event.Card = card
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// formatCards writes cards as a list parseCards reads
+func formatCards(cards []*Card) string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = formatCard(card)
+	}
+	return strings.Join(names, " ")
+}
+
+// Save writes the replay to path
+func (r *Replay) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadReplay reads a replay file and checks it can be played out
+func LoadReplay(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("reading replay %s: %w", path, err)
+	}
+	if r.Version != replayVersion {
+		return nil, fmt.Errorf("replay %s has version %d, expected %d", path, r.Version, replayVersion)
+	}
+	if len(r.Players) < 2 {
+		return nil, fmt.Errorf("replay %s has %d players, need at least 2", path, len(r.Players))
+	}
+	for _, p := range r.Players {
+		if !p.Human && (p.Strategy == nil || p.Strategy.Choice < 1 || p.Strategy.Choice > len(strategyNames)) {
+			return nil, fmt.Errorf("replay %s has no valid strategy for %s", path, p.Name)
+		}
+	}
+	for i, d := range r.Decisions {
+		n := len(r.Players)
+		if len(d.Totals) != n || len(d.Hands) != n || len(d.States) != n || d.Seat < 0 || d.Seat >= n || d.Dealer < 0 || d.Dealer >= n {
+			return nil, fmt.Errorf("replay %s: decision %d doesn't match the %d players", path, i+1, n)
+		}
+	}
+	return &r, nil
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"slices"
+	"time"
+)
+
+// HeadToHead counts, for every pair of players in a simulation, the games
+// one finished with a higher total than the other
+type HeadToHead struct {
+	Ahead map[string]map[string]int
+}
+
+// NewHeadToHead creates an empty tally
+func NewHeadToHead() *HeadToHead {
+	return &HeadToHead{Ahead: make(map[string]map[string]int)}
+}
+
+// SetReport makes simulations write an HTML report of their results to
+// path
+func (g *Game) SetReport(path string) {
+	g.report = path
+}
+
+// recordGame counts who finished ahead of whom in the game just played
+func (h *HeadToHead) recordGame(g *Game) {
+	for _, p := range g.players {
+		for _, q := range g.players {
+			if p.GetTotalScore() <= q.GetTotalScore() {
+				continue
+			}
+			if h.Ahead[p.GetName()] == nil {
+				h.Ahead[p.GetName()] = make(map[string]int)
+			}
+			h.Ahead[p.GetName()][q.GetName()]++
+		}
+	}
+}
+
+// Report chart sizes, in pixels
+const (
+	reportBarWidth   = 420
+	reportBarHeight  = 26
+	reportLabelWidth = 180
+	reportHistWidth  = 320
+	reportHistHeight = 120
+)
+
+// reportView is everything the report template draws
+type reportView struct {
+	Title     string
+	Generated string
+	Games     int
+	Seed      int64
+	Goal      string
+	Rounds    float64
+	WinRates  []reportWinRate
+	Height    int
+	Width     int
+	Names     []string
+	Matrix    [][]reportCell
+	Hists     []reportHistogram
+	Actions   []actionImpact
+}
+
+// reportWinRate is a bar of the win rate chart with its error bar of two
+// standard errors either side
+type reportWinRate struct {
+	Name           string
+	Wins           int
+	Rate, Margin   float64
+	X, Y, Bar      int
+	ErrFrom, ErrTo int
+	TextX          int
+}
+
+// reportCell is one square of the head-to-head matrix; Shade is its
+// background, from red for always behind to green for always ahead
+type reportCell struct {
+	Rate  float64
+	Self  bool
+	Shade template.CSS
+}
+
+// reportHistogram is one player's final scores as a bar chart
+type reportHistogram struct {
+	Name    string
+	P50     int
+	P90     int
+	Bars    []reportHistBar
+	Low     string
+	High    string
+	Width   int
+	Height  int
+	Missing bool
+}
+
+type reportHistBar struct {
+	X, Y, Width, Height int
+	Label               string
+}
+
+// writeReport writes the simulation's results as a standalone HTML page
+// with its charts drawn in inline SVG
+func (sim *Simulation) writeReport(g *Game, path string, names []string) error {
+	games := sim.gamesPlayed(len(names))
+	view := reportView{
+		Title:     T("Flip 7 simulation report"),
+		Generated: g.now().Format(time.DateTime),
+		Games:     games,
+		Seed:      sim.Seed,
+		Goal:      g.condition().Goal(g),
+		Width:     reportLabelWidth + reportBarWidth + 140,
+		Names:     names,
+	}
+	if games > 0 {
+		view.Rounds = float64(sim.Rounds) / float64(games)
+	}
+
+	// Win rates, best first, with two standard errors either side
+	sorted := slices.Clone(names)
+	slices.SortStableFunc(sorted, func(a, b string) int { return cmp.Compare(sim.Wins[b], sim.Wins[a]) })
+	for i, name := range sorted {
+		w := reportWinRate{Name: name, Wins: sim.Wins[name], X: reportLabelWidth, Y: i * reportBarHeight, TextX: reportLabelWidth + reportBarWidth + 10}
+		if games > 0 {
+			w.Rate = float64(w.Wins) / float64(games)
+			w.Margin = 2 * math.Sqrt(w.Rate*(1-w.Rate)/float64(games))
+		}
+		w.Bar = int(w.Rate * reportBarWidth)
+		w.ErrFrom = reportLabelWidth + int(max(w.Rate-w.Margin, 0)*reportBarWidth)
+		w.ErrTo = reportLabelWidth + int(min(w.Rate+w.Margin, 1)*reportBarWidth)
+		view.WinRates = append(view.WinRates, w)
+	}
+	view.Height = len(names) * reportBarHeight
+
+	// Head-to-head: how often the row's player finished ahead of the
+	// column's
+	if sim.HeadToHead != nil && games > 0 {
+		for _, row := range names {
+			var cells []reportCell
+			for _, col := range names {
+				if row == col {
+					cells = append(cells, reportCell{Self: true})
+					continue
+				}
+				rate := float64(sim.HeadToHead.Ahead[row][col]) / float64(games)
+				shade := template.CSS(fmt.Sprintf("background: hsl(%.0f, 65%%, 82%%)", 120*rate))
+				cells = append(cells, reportCell{Rate: 100 * rate, Shade: shade})
+			}
+			view.Matrix = append(view.Matrix, cells)
+		}
+	}
+
+	if sim.Distributions != nil {
+		for _, name := range names {
+			view.Hists = append(view.Hists, reportHist(name, sim.Distributions.Final[name]))
+		}
+	}
+	if sim.Actions != nil {
+		view.Actions = sim.Actions.impacts()
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reportTemplate.Execute(f, view); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// reportHist draws a distribution bucketed as the console histograms are
+func reportHist(name string, d ScoreDistribution) reportHistogram {
+	h := reportHistogram{Name: name, Width: reportHistWidth, Height: reportHistHeight}
+	if d.Count() == 0 {
+		h.Missing = true
+		return h
+	}
+	h.P50, h.P90 = d.Percentile(50), d.Percentile(90)
+	low, step, buckets := d.buckets()
+	tallest := slices.Max(buckets)
+	width := reportHistWidth / len(buckets)
+	for i, n := range buckets {
+		from := (low/step + i) * step
+		height := n * (reportHistHeight - 20) / tallest
+		h.Bars = append(h.Bars, reportHistBar{
+			X:      i * width,
+			Y:      reportHistHeight - 20 - height,
+			Width:  max(width-2, 1),
+			Height: height,
+			Label:  fmt.Sprintf(T("%d-%d: %.1f%%"), from, from+step-1, 100*float64(n)/float64(d.Count())),
+		})
+	}
+	h.Low = fmt.Sprint(low / step * step)
+	h.High = fmt.Sprint((low/step + len(buckets)) * step)
+	return h
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"T":   T,
+	"pct": func(f float64) string { return fmt.Sprintf("%.1f%%", 100*f) },
+	"add": func(a, b int) int { return a + b },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.meta { color: #666; margin-top: 0; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { padding: 0.3em 0.7em; text-align: right; border: 1px solid #ddd; }
+th:first-child, td:first-child { text-align: left; }
+.self { background: #eee; }
+.hists { display: flex; flex-wrap: wrap; gap: 1.5em; }
+.hist h3 { font-size: 1em; margin: 0.5em 0; }
+svg text { font-size: 12px; font-family: system-ui, sans-serif; }
+.note { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>🎴 {{.Title}}</h1>
+<p class="meta">{{printf (T "%d games from seed %d. %s Games last %.1f rounds on average.") .Games .Seed .Goal .Rounds}} {{.Generated}}</p>
+
+<h2>{{T "Win rates"}}</h2>
+<svg width="{{.Width}}" height="{{add .Height 10}}" role="img">
+{{- range .WinRates}}
+<text x="0" y="{{add .Y 17}}">{{.Name}}</text>
+<rect x="{{.X}}" y="{{add .Y 4}}" width="{{.Bar}}" height="18" fill="#4c78a8"></rect>
+<line x1="{{.ErrFrom}}" x2="{{.ErrTo}}" y1="{{add .Y 13}}" y2="{{add .Y 13}}" stroke="#222" stroke-width="2"></line>
+<line x1="{{.ErrFrom}}" x2="{{.ErrFrom}}" y1="{{add .Y 8}}" y2="{{add .Y 18}}" stroke="#222"></line>
+<line x1="{{.ErrTo}}" x2="{{.ErrTo}}" y1="{{add .Y 8}}" y2="{{add .Y 18}}" stroke="#222"></line>
+<text x="{{.TextX}}" y="{{add .Y 17}}">{{pct .Rate}} ± {{pct .Margin}}</text>
+{{- end}}
+</svg>
+<p class="note">{{T "Error bars are two standard errors either side of the win rate."}}</p>
+
+{{- if .Matrix}}
+<h2>{{T "Head to head"}}</h2>
+<table>
+<tr><th></th>{{range .Names}}<th>{{.}}</th>{{end}}</tr>
+{{- range $i, $row := .Matrix}}
+<tr><th>{{index $.Names $i}}</th>{{range $row}}{{if .Self}}<td class="self"></td>{{else}}<td style="{{.Shade}}">{{printf "%.1f%%" .Rate}}</td>{{end}}{{end}}</tr>
+{{- end}}
+</table>
+<p class="note">{{T "How often the row's player finished the game with more points than the column's."}}</p>
+{{- end}}
+
+{{- if .Hists}}
+<h2>{{T "Final scores"}}</h2>
+<div class="hists">
+{{- range .Hists}}
+<div class="hist">
+<h3>{{.Name}}{{if not .Missing}} · P50 {{.P50}}, P90 {{.P90}}{{end}}</h3>
+{{- if not .Missing}}
+<svg width="{{.Width}}" height="{{.Height}}" role="img">
+{{- range .Bars}}
+<rect x="{{.X}}" y="{{.Y}}" width="{{.Width}}" height="{{.Height}}" fill="#f58518"><title>{{.Label}}</title></rect>
+{{- end}}
+<text x="0" y="{{.Height}}">{{.Low}}</text>
+<text x="{{.Width}}" y="{{.Height}}" text-anchor="end">{{.High}}</text>
+</svg>
+{{- end}}
+</div>
+{{- end}}
+</div>
+{{- end}}
+
+{{- if .Actions}}
+<h2>{{T "Action card impact"}}</h2>
+<table>
+<tr><th>{{T "STRATEGY"}}</th><th>{{T "FREEZES"}}</th><th>{{T "DENIED"}}</th><th>{{T "FLIP 3"}}</th><th>{{T "BUSTED"}}</th><th>{{T "SAVES"}}</th><th>{{T "KEPT"}}</th></tr>
+{{- range .Actions}}
+<tr><td>{{.Strategy}}</td><td>{{.Freezes}}</td><td>{{printf "%.1f" .Denied}}</td><td>{{.FlipThrees}}</td><td>{{printf "%.1f%%" .Busted}}</td><td>{{.SecondChances}}</td><td>{{printf "%.1f" .Kept}}</td></tr>
+{{- end}}
+</table>
+<p class="note">{{T "DENIED: points a Freeze cost its target against their strategy's average round;\n        below zero, freezing them banked more than they usually score"}}<br>
+{{T "BUSTED: Flip Threes on other players that made them bust"}}<br>
+{{T "KEPT: average round score after a Second Chance saved a bust"}}</p>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReshufflePolicy chooses when the discard pile is shuffled back into the
+// deck
+type ReshufflePolicy int
+
+const (
+	// ReshuffleMidRound shuffles the discards back in the moment the deck
+	// runs out, even in the middle of a round, as the printed rules do
+	ReshuffleMidRound ReshufflePolicy = iota
+	// ReshuffleBetweenRounds shuffles the discards back in before every
+	// round and never during one
+	ReshuffleBetweenRounds
+	// ReshuffleWhenSpent leaves the discards out until the deck is
+	// spent, then shuffles them back in before the next round
+	ReshuffleWhenSpent
+)
+
+// reshufflePolicies are the names of the reshuffle policies, in order
+var reshufflePolicies = []string{"mid-round", "between-rounds", "when-spent"}
+
+// ParseReshufflePolicy parses a reshuffle policy name. It reads never,
+// the old name for when-spent, from older configs and replays.
+func ParseReshufflePolicy(name string) (ReshufflePolicy, error) {
+	if strings.ToLower(name) == "never" {
+		return ReshuffleWhenSpent, nil
+	}
+	for i, policy := range reshufflePolicies {
+		if policy == strings.ToLower(name) {
+			return ReshufflePolicy(i), nil
+		}
+	}
+	return ReshuffleMidRound, fmt.Errorf("unknown reshuffle policy %q (available: %s)", name, strings.Join(reshufflePolicies, ", "))
+}
+
+// String returns the policy's name
+func (p ReshufflePolicy) String() string {
+	if p < 0 || int(p) >= len(reshufflePolicies) {
+		return "unknown"
+	}
+	return reshufflePolicies[p]
+}
+
+// SetReshufflePolicy chooses when the discards go back into the deck.
+// Under a policy that doesn't reshuffle mid-round, a round whose deck
+// runs out ends there: everyone still in it stays with what they have.
+func (g *Game) SetReshufflePolicy(policy ReshufflePolicy) {
+	g.reshuffle = policy
+	g.deck.SetReshufflePolicy(policy)
+}
+
+// drawCard draws the next card for player and announces a reshuffle if
+// drawing it set one off. When the deck has run out, because the policy
+// keeps the discards out or every card is in a hand, it ends the round
+// for everyone still in it and returns nil with no error; a deck with
+// cards left that draws none is an error.
+func (g *Game) drawCard(player PlayerInterface) (*Card, error) {
+	if d, ok := g.deck.(drawerSetter); ok {
+		d.setDrawer(player)
+	}
+	left := g.deck.CardsLeft()
+	card := g.deck.DrawCard()
+	if card == nil {
+		if left > 0 {
+			return nil, fmt.Errorf("deck is empty")
+		}
+		g.deckRanOut()
+		return nil, nil
+	}
+	if g.deck.CardsLeft() != left-1 {
+		g.announceReshuffle()
+	}
+	return card, nil
+}
+
+// deckSpent ends the round and reports true if the deck has run out,
+// so nobody is asked to decide with no cards left to draw. A deck that
+// reshuffles mid-round only runs out with every card in a hand.
+func (g *Game) deckSpent() bool {
+	if g.deck.CardsLeft() > 0 || g.reshuffle == ReshuffleMidRound && len(g.deck.Discards()) > 0 {
+		return false
+	}
+	g.deckRanOut()
+	return true
+}
+
+// deckRanOut ends the round for everyone still in it, since the deck has
+// run out and nothing can be shuffled back in until the round is over
+func (g *Game) deckRanOut() {
+	g.narrate(func() { g.println(T("   🃏 The deck has run out! Everyone still in the round stays.")) })
+	for _, player := range g.players {
+		if player.IsActive() {
+			g.playerStay(player)
+		}
+	}
+}
+
+// reshuffleForRound shuffles the discards back into the deck before a
+// round, if the policy calls for it: always between rounds, and only once
+// the deck is spent otherwise. Mid-round reshuffles take care of
+// themselves.
+func (g *Game) reshuffleForRound() {
+	switch {
+	case len(g.deck.Discards()) == 0:
+		return
+	case g.reshuffle == ReshuffleBetweenRounds,
+		g.reshuffle == ReshuffleWhenSpent && g.deck.CardsLeft() == 0:
+		g.deck.Reshuffle()
+		g.announceReshuffle()
+	}
+}
+
+// announceReshuffle tells everyone the discards are back in the deck, so
+// anyone counting cards knows to start again
+func (g *Game) announceReshuffle() {
+	g.narrate(func() {
+		g.printf(T("   🔀 The discards are shuffled back into the deck (%d cards)\n"), g.deck.CardsLeft())
+	})
+	g.emit(Event{Type: DeckReshuffled, Score: g.deck.CardsLeft()})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestParseReshufflePolicy(t *testing.T) {
+	for _, policy := range []ReshufflePolicy{ReshuffleMidRound, ReshuffleBetweenRounds, ReshuffleWhenSpent} {
+		if got, err := ParseReshufflePolicy(policy.String()); err != nil || got != policy {
+			t.Errorf("ParseReshufflePolicy(%q) = %v, %v", policy, got, err)
+		}
+	}
+	if got, err := ParseReshufflePolicy("never"); err != nil || got != ReshuffleWhenSpent {
+		t.Errorf("ParseReshufflePolicy(never) = %v, %v; want when-spent, as older files name it", got, err)
+	}
+	if _, err := ParseReshufflePolicy("sometimes"); err == nil {
+		t.Error("ParseReshufflePolicy(sometimes) took an unknown policy")
+	}
+}
+
+// reshuffleGame sets up a game dealing 1 to 4 under policy, with two of
+// them drawn and discarded, and counts the reshuffles it announces
+func reshuffleGame(policy ReshufflePolicy) (*Game, *int) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetDeck(NewStackedDeck(mustParseCards("1 2 3 4")...))
+	g.SetReshufflePolicy(policy)
+	g.players = table("Ada 0", "Bo 0")
+	g.deck.DiscardCard(g.deck.DrawCard())
+	g.deck.DiscardCard(g.deck.DrawCard())
+	reshuffles := 0
+	g.Subscribe(func(e Event) {
+		if e.Type == DeckReshuffled {
+			reshuffles++
+		}
+	})
+	return g, &reshuffles
+}
+
+func TestReshufflePolicies(t *testing.T) {
+	tests := []struct {
+		policy ReshufflePolicy
+		// whether the round's draws go on past the deck, and whether
+		// the discards go back in before a round with cards still left
+		midRound, withCardsLeft bool
+	}{
+		{ReshuffleMidRound, true, false},
+		{ReshuffleBetweenRounds, false, true},
+		{ReshuffleWhenSpent, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			g, reshuffles := reshuffleGame(tt.policy)
+			g.reshuffleForRound()
+			if got := *reshuffles == 1; got != tt.withCardsLeft {
+				t.Errorf("reshuffled %d times before a round with 2 cards left", *reshuffles)
+			}
+
+			g, reshuffles = reshuffleGame(tt.policy)
+			ada := g.players[0]
+			for range 2 {
+				card, err := g.drawCard(ada)
+				if err != nil || card == nil {
+					t.Fatalf("drawing the cards left: %v, %v", card, err)
+				}
+			}
+			card, err := g.drawCard(ada)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := card != nil; got != tt.midRound {
+				t.Errorf("drew %v from the spent deck mid-round", card)
+			}
+			if tt.midRound {
+				if *reshuffles != 1 {
+					t.Errorf("announced %d reshuffles mid-round, want 1", *reshuffles)
+				}
+				return
+			}
+			if ada.IsActive() || g.players[1].IsActive() {
+				t.Error("the round went on with the deck spent")
+			}
+			g.reshuffleForRound()
+			if *reshuffles != 1 || g.deck.CardsLeft() != 2 {
+				t.Errorf("before the next round: %d reshuffles and %d cards, want the 2 discards back in", *reshuffles, g.deck.CardsLeft())
+			}
+		})
+	}
+}
+
+// TestEveryCardInAHand checks a deck with nothing left to draw or
+// reshuffle ends the round, even mid-round
+func TestEveryCardInAHand(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.players = table("Ada 0", "Bo 0")
+	g.deck.Restore(mustParseCards("5"), nil)
+	ada := g.players[0]
+	card, err := g.drawCard(ada)
+	if card == nil || err != nil {
+		t.Fatalf("drawing the last card: %v, %v", card, err)
+	}
+	ada.AddCard(card)
+
+	card, err = g.drawCard(g.players[1])
+	if card != nil || err != nil {
+		t.Fatalf("drew %v, %v from a deck with every card in a hand", card, err)
+	}
+	if ada.IsActive() || g.players[1].IsActive() {
+		t.Error("the round went on with every card in a hand")
+	}
+	if !g.deckSpent() {
+		t.Error("the deck isn't spent with every card in a hand")
+	}
+}
+
+func TestCardSet(t *testing.T) {
+	cards, err := CardSet{"12": 0, "x2": 3, "flip3": 1}.cards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := countDeck(cards)
+	if counts.Total != 94-12+2-2 || counts.Numbers[12] != 0 || counts.Modifiers != 8 || counts.Actions != 7 {
+		t.Errorf("tally %+v, want no 12s, three x2s and one Flip Three", counts)
+	}
+
+	for _, set := range []CardSet{
+		{"13": 1},
+		{"x2": -1},
+		{"x2": 1, "X2": 2},
+		{"1": 0, "2": 0, "3": 0, "4": 0, "5": 0, "6": 0, "7": 0, "8": 0, "9": 0, "10": 0, "11": 0, "12": 0},
+	} {
+		if _, err := set.cards(); err == nil {
+			t.Errorf("CardSet %v built a deck", set)
+		}
+	}
+
+	g := NewGame()
+	g.SetSeed(1)
+	if err := g.SetCardSet(CardSet{"12": 0}); err != nil {
+		t.Fatal(err)
+	}
+	if g.deck.Size() != 82 || g.deck.Counts().Numbers[12] != 0 {
+		t.Errorf("the game deals %d cards with %d 12s, want 82 and none", g.deck.Size(), g.deck.Counts().Numbers[12])
+	}
+	g.SetSeed(2)
+	if g.deck.Size() != 82 {
+		t.Errorf("reseeding deals %d cards, want the 82 of the card set", g.deck.Size())
+	}
+}
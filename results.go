@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+)
+
+// GameResult is one finished simulated game as written to a results
+// file, one JSON object per line. Seating is the rotation of the seats in
+// a duplicate simulation, and Dealer who dealt the first round when the
+// first deal moves between games. In a team game Team is the winning
+// team and Winners everyone on it.
+type GameResult struct {
+	Game    int            `json:"game"`
+	Seed    int64          `json:"seed"`
+	Stream  int64          `json:"stream,omitempty"` // seed of the game's own random stream
+	Seating int            `json:"seating,omitempty"`
+	Dealer  string         `json:"dealer,omitempty"`
+	Rounds  int            `json:"rounds"`
+	Winner  string         `json:"winner"`
+	Team    string         `json:"team,omitempty"`
+	Winners []string       `json:"winners,omitempty"`
+	Scores  map[string]int `json:"scores"`
+	Params  int            `json:"params,omitempty"` // version of the parameters file played with
+}
+
+// SetResults makes simulations write a record of every game to path as
+// it finishes
+func (g *Game) SetResults(path string) {
+	g.results = path
+}
+
+// resultsWriter appends game records to a results file
+type resultsWriter struct {
+	f    *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+	size int64
+}
+
+// openResults opens the results file for a simulation. A new simulation
+// starts the file afresh; a resumed one cuts it back to the last
+// checkpoint, dropping games that were played after it, and appends.
+func openResults(path string, size int64) (*resultsWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	rw := &resultsWriter{f: f, size: size}
+	rw.w = bufio.NewWriter(countingWriter{f, &rw.size})
+	rw.enc = json.NewEncoder(rw.w)
+	return rw, nil
+}
+
+// write adds a game to the file
+func (rw *resultsWriter) write(result GameResult) error {
+	return rw.enc.Encode(result)
+}
+
+// flush writes out buffered records and returns the file's size
+func (rw *resultsWriter) flush() (int64, error) {
+	err := rw.w.Flush()
+	return rw.size, err
+}
+
+// close flushes and closes the file
+func (rw *resultsWriter) close() error {
+	_, err := rw.flush()
+	if cerr := rw.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// countingWriter adds the number of bytes written to n
+type countingWriter struct {
+	w io.Writer
+	n *int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	*c.n += int64(n)
+	return n, err
+}
+
+// gameResult records the game just played
+func (g *Game) gameResult(game int, seed int64, seating int, winner PlayerInterface) GameResult {
+	result := GameResult{
+		Game:    game,
+		Seed:    seed,
+		Stream:  g.rngSeed,
+		Seating: seating,
+		Rounds:  g.round - 1,
+		Winner:  winner.GetName(),
+		Scores:  make(map[string]int, len(g.players)),
+	}
+	if t, ok := g.condition().(teamer); ok {
+		result.Team = t.team(winner)
+		for _, p := range g.winningSide(winner) {
+			result.Winners = append(result.Winners, p.GetName())
+		}
+	}
+	for _, p := range g.players {
+		result.Scores[p.GetName()] = p.GetTotalScore()
+	}
+	return result
+}
+
+// ResultsSummary is the totals of a results file
+type ResultsSummary struct {
+	Games   int
+	Rounds  int
+	Players []string
+	Wins    map[string]int
+	Scores  map[string]int
+}
+
+// SummarizeResults reads a results file in one pass, keeping only totals
+func SummarizeResults(r io.Reader) (*ResultsSummary, error) {
+	s := &ResultsSummary{Wins: make(map[string]int), Scores: make(map[string]int)}
+	seen := make(map[string]bool)
+	dec := json.NewDecoder(r)
+	for {
+		var result GameResult
+		if err := dec.Decode(&result); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("game %d: %w", s.Games+1, err)
+		}
+
+		s.Games++
+		s.Rounds += result.Rounds
+		if len(result.Winners) == 0 {
+			s.Wins[result.Winner]++
+		}
+		for _, name := range result.Winners {
+			s.Wins[name]++
+		}
+		for _, name := range slices.Sorted(maps.Keys(result.Scores)) {
+			s.Scores[name] += result.Scores[name]
+			if !seen[name] {
+				seen[name] = true
+				s.Players = append(s.Players, name)
+			}
+		}
+	}
+	return s, nil
+}
+
+// RunStats handles the stats subcommand. "stats summarize FILE" shows the
+// results of a simulation from its results file.
+func RunStats(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.Arg(0) != "summarize" || fs.NArg() != 2 {
+		return fmt.Errorf("usage: flip7 stats summarize FILE")
+	}
+
+	f, err := os.Open(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := SummarizeResults(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", fs.Arg(1), err)
+	}
+	if s.Games == 0 {
+		return fmt.Errorf("%s has no games", fs.Arg(1))
+	}
+
+	g := NewGame()
+	g.SetOutput(out)
+	g.displayGameStatistics(s.Games, s.Wins, s.Players)
+	g.displayResultsSummary(s)
+	return nil
+}
+
+// displayResultsSummary shows the averages a results file adds to the
+// win rates
+func (g *Game) displayResultsSummary(s *ResultsSummary) {
+	g.resultf(T("Average game: %.1f rounds\n"), float64(s.Rounds)/float64(s.Games))
+	for _, name := range s.Players {
+		g.resultf(T("%-20s average score %.1f\n"), name, float64(s.Scores[name])/float64(s.Games))
+	}
+}
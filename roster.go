@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// RosterEntry is a computer player a game can draw from: a name and
+// optionally the strategy it prefers and the icon it is shown with
+type RosterEntry struct {
+	Name     string
+	Strategy *StrategySpec `json:",omitempty"`
+	Icon     string        `json:",omitempty"`
+}
+
+// defaultRoster is the roster used when no roster file is given
+func defaultRoster() []RosterEntry {
+	roster := make([]RosterEntry, len(computerNames))
+	for i, name := range computerNames {
+		roster[i] = RosterEntry{Name: name}
+	}
+	return roster
+}
+
+// LoadRoster reads a roster file: a JSON list of roster entries
+func LoadRoster(path string) ([]RosterEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var roster []RosterEntry
+	if err := json.Unmarshal(data, &roster); err != nil {
+		return nil, fmt.Errorf("reading roster %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range roster {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("roster %s has an entry without a name", path)
+		}
+		if seen[entry.Name] {
+			return nil, fmt.Errorf("roster %s lists %s twice", path, entry.Name)
+		}
+		seen[entry.Name] = true
+		if entry.Strategy != nil && (entry.Strategy.Choice < 1 || entry.Strategy.Choice > 12) {
+			return nil, fmt.Errorf("roster %s has an invalid strategy for %s", path, entry.Name)
+		}
+	}
+	return roster, nil
+}
+
+// SetRoster sets the computer players new games draw from
+func (g *Game) SetRoster(roster []RosterEntry) {
+	g.roster = roster
+}
+
+// resetNamePool refills this game's pool of unused roster entries
+func (g *Game) resetNamePool() {
+	if g.roster == nil {
+		g.namePool = defaultRoster()
+		return
+	}
+	g.namePool = slices.Clone(g.roster)
+}
+
+// nextComputer takes a random unused entry from this game's pool. Once the
+// pool runs out, computers are numbered instead.
+func (g *Game) nextComputer() RosterEntry {
+	if len(g.namePool) == 0 {
+		g.extraComputers++
+		return RosterEntry{Name: fmt.Sprintf("CPU %d", g.extraComputers)}
+	}
+	idx := g.banter.Intn(len(g.namePool))
+	entry := g.namePool[idx]
+	g.namePool = slices.Delete(g.namePool, idx, idx+1)
+	return entry
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// saveVersion is bumped whenever the save file format changes
+const saveVersion = 1
+
+// SavedPlayer is a player as stored in a save file
+type SavedPlayer struct {
+	Name       string
+	Human      bool
+	TotalScore int
+	Strategy   *StrategySpec `json:",omitempty"`
+	Icon       string        `json:",omitempty"`
+}
+
+// SavedGame is the contents of a save file. Games are saved with the
+// totals banked so far; a round in progress is replayed from a fresh deal
+// when the game is resumed.
+type SavedGame struct {
+	Version   int
+	SavedAt   time.Time
+	Round     int
+	DealerIdx int
+	Players   []SavedPlayer
+}
+
+// SaveGame writes the game's players and banked scores to path
+func (g *Game) SaveGame(path string) error {
+	players, err := savedPlayers(g.players)
+	if err != nil {
+		return err
+	}
+	saved := SavedGame{
+		Version:   saveVersion,
+		SavedAt:   time.Now(),
+		Round:     g.round,
+		DealerIdx: g.dealerIdx,
+		Players:   players,
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadGame restores players and banked scores from a save file. The game's
+// input and output must already be set up so human players use them.
+func (g *Game) LoadGame(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var saved SavedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("reading save file %s: %w", path, err)
+	}
+	if saved.Version != saveVersion {
+		return fmt.Errorf("save file %s has version %d, expected %d", path, saved.Version, saveVersion)
+	}
+	if len(saved.Players) < 2 {
+		return fmt.Errorf("save file %s has %d players, need at least 2", path, len(saved.Players))
+	}
+	if saved.DealerIdx < 0 || saved.DealerIdx >= len(saved.Players) {
+		return fmt.Errorf("save file %s has invalid dealer %d", path, saved.DealerIdx)
+	}
+
+	players, err := g.restorePlayers(saved.Players)
+	if err != nil {
+		return fmt.Errorf("save file %s %w", path, err)
+	}
+
+	g.players = players
+	g.round = saved.Round
+	g.dealerIdx = saved.DealerIdx
+	return nil
+}
+
+// savedPlayers converts players to their saved form
+func savedPlayers(players []PlayerInterface) ([]SavedPlayer, error) {
+	saved := make([]SavedPlayer, 0, len(players))
+	for _, player := range players {
+		sp := SavedPlayer{
+			Name:       player.GetName(),
+			TotalScore: player.GetTotalScore(),
+		}
+		switch p := player.(type) {
+		case *HumanPlayer:
+			sp.Human = true
+		case *ComputerPlayer:
+			spec := p.Spec
+			sp.Strategy = &spec
+			sp.Icon = p.Icon
+		default:
+			return nil, fmt.Errorf("cannot save player %s of type %T", player.GetName(), player)
+		}
+		saved = append(saved, sp)
+	}
+	return saved, nil
+}
+
+// restorePlayers rebuilds saved players. Human players use the game's
+// input and output.
+func (g *Game) restorePlayers(saved []SavedPlayer) ([]PlayerInterface, error) {
+	players := make([]PlayerInterface, 0, len(saved))
+	for _, sp := range saved {
+		if sp.Human {
+			human := NewHumanPlayer(sp.Name, g.scanner, g.out)
+			human.SetCommandHandler(g.handleCommand)
+			human.TotalScore = sp.TotalScore
+			players = append(players, human)
+			continue
+		}
+
+		if sp.Strategy == nil || sp.Strategy.Choice < 1 || sp.Strategy.Choice > len(strategyNames) {
+			return nil, fmt.Errorf("has no valid strategy for %s", sp.Name)
+		}
+		computer := NewComputerPlayerFromSpec(sp.Name, *sp.Strategy)
+		computer.TotalScore = sp.TotalScore
+		computer.Icon = sp.Icon
+		players = append(players, computer)
+	}
+	return players, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a position part way through a round, read from a YAML file:
+// each player's score and hand, the exact order of the next cards in the
+// deck, and whose turn it is. Play carries on from there as a normal game.
+type Scenario struct {
+	Players  []ScenarioPlayer `yaml:"players"`
+	Deck     string           `yaml:"deck"`
+	Discards string           `yaml:"discards"`
+	Round    int              `yaml:"round"`
+	Dealer   int              `yaml:"dealer"`
+	Turn     *int             `yaml:"turn"`
+	Rules    RulesConfig      `yaml:"rules"`
+}
+
+// ScenarioPlayer is a player in a scenario. Hand is a space-separated card
+// list and State is active (the default), stayed, frozen or busted.
+type ScenarioPlayer struct {
+	PlayerConfig `yaml:",inline"`
+	Total        int    `yaml:"total"`
+	Hand         string `yaml:"hand"`
+	State        string `yaml:"state"`
+}
+
+// scenarioStates maps scenario state names to player states
+var scenarioStates = map[string]PlayerState{
+	"":       Active,
+	"active": Active,
+	"stayed": Stayed,
+	"busted": Busted,
+	"frozen": Frozen,
+}
+
+// LoadScenario reads and validates a scenario file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario %s:\n%w", path, err)
+	}
+	return s, nil
+}
+
+// ParseScenario reads and validates a scenario from YAML
+func ParseScenario(data []byte) (*Scenario, error) {
+	var s Scenario
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate reports every problem with the scenario at once
+func (s *Scenario) Validate() error {
+	cfg := Config{Rules: s.Rules}
+	for _, p := range s.Players {
+		cfg.Players = append(cfg.Players, p.PlayerConfig)
+	}
+	var errs []error
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(s.Players) == 0 {
+		errs = append(errs, fmt.Errorf("  players: need 2-18 players, got none"))
+	}
+
+	if s.Dealer < 0 || s.Dealer >= max(len(s.Players), 1) {
+		errs = append(errs, fmt.Errorf("  dealer: no player %d", s.Dealer))
+	}
+	if s.Turn != nil && (*s.Turn < 0 || *s.Turn >= len(s.Players)) {
+		errs = append(errs, fmt.Errorf("  turn: no player %d", *s.Turn))
+	}
+	if s.Round < 0 {
+		errs = append(errs, fmt.Errorf("  round: must not be negative"))
+	}
+
+	var visible []*Card
+	for i, p := range s.Players {
+		where := fmt.Sprintf("  players[%d]", i)
+		if _, ok := scenarioStates[p.State]; !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown state %q (available: active, stayed, frozen, busted)", where, p.State))
+		}
+		if p.Total < 0 {
+			errs = append(errs, fmt.Errorf("%s: total must not be negative", where))
+		}
+		hand, err := parseCards(p.Hand)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: hand: %w", where, err))
+			continue
+		}
+		if _, err := p.player(hand); err != nil {
+			errs = append(errs, fmt.Errorf("%s: hand: %w", where, err))
+		}
+		visible = append(visible, hand...)
+	}
+
+	for _, field := range []struct{ name, cards string }{{"discards", s.Discards}, {"deck", s.Deck}} {
+		cards, err := parseCards(field.cards)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("  %s: %w", field.name, err))
+			continue
+		}
+		visible = append(visible, cards...)
+	}
+
+	full := &Deck{}
+	full.set, _ = s.Rules.Cards.cards()
+	full.createCards()
+	if _, err := takeCards(full.cards, visible); err != nil {
+		errs = append(errs, fmt.Errorf("  cards: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// player builds the player state a scenario player starts with
+func (p ScenarioPlayer) player(hand []*Card) (*BasePlayer, error) {
+	player := &BasePlayer{}
+	player.Init(p.Name)
+	player.TotalScore = p.Total
+	for _, card := range hand {
+		if err := player.AddCard(card); err != nil {
+			return nil, fmt.Errorf("%s can't be added (%v)", card, err)
+		}
+	}
+	player.State = scenarioStates[p.State]
+	return player, nil
+}
+
+// Apply sets up the game at the scenario's position. The next round the
+// game plays picks up from there instead of dealing. The game's input,
+// output and speed must already be set.
+func (s *Scenario) Apply(g *Game) error {
+	if s.Rules.WinningScore > 0 {
+		g.SetWinningScore(s.Rules.WinningScore)
+	}
+	if s.Rules.HoldActions {
+		g.SetHoldActions(true)
+	}
+	if err := s.Rules.applyDeckRules(g); err != nil {
+		return err
+	}
+	if s.Rules.Scoring != nil {
+		g.SetScorer(*s.Rules.Scoring)
+	}
+
+	humans := 0
+	var visible []*Card
+	g.players = g.players[:0]
+	for _, p := range s.Players {
+		hand, err := parseCards(p.Hand)
+		if err != nil {
+			return err
+		}
+		state, err := p.player(hand)
+		if err != nil {
+			return err
+		}
+		visible = append(visible, hand...)
+
+		if p.Human {
+			humans++
+			human := NewHumanPlayer(p.Name, g.scanner, g.out)
+			human.SetCommandHandler(g.handleCommand)
+			human.BasePlayer = *state
+			g.players = append(g.players, human)
+			continue
+		}
+
+		spec, err := p.Spec()
+		if err != nil {
+			return err
+		}
+		suffix, _, _, _ := buildStrategy(spec)
+		computer := NewComputerPlayerFromSpec(p.Name+suffix, spec)
+		computer.BasePlayer = *state
+		computer.Name = p.Name + suffix
+		computer.Icon = p.Icon
+		g.players = append(g.players, computer)
+	}
+	if s.Rules.Win != "" {
+		names := make([]string, len(g.players))
+		configs := make([]PlayerConfig, len(s.Players))
+		for i, p := range s.Players {
+			names[i], configs[i] = g.players[i].GetName(), p.PlayerConfig
+		}
+		if err := g.SetWinCondition(s.Rules.winSpec(configs, names)); err != nil {
+			return err
+		}
+	}
+
+	discards, err := parseCards(s.Discards)
+	if err != nil {
+		return err
+	}
+	top, err := parseCards(s.Deck)
+	if err != nil {
+		return err
+	}
+	visible = append(visible, discards...)
+
+	if err := g.stackDeck(top, discards, visible); err != nil {
+		return err
+	}
+
+	turn := s.Dealer + 1
+	if s.Turn != nil {
+		turn = *s.Turn
+	}
+	g.round = max(s.Round, 1)
+	g.dealerIdx = s.Dealer
+	g.startTurn = (turn - s.Dealer - 1 + 2*len(g.players)) % len(g.players)
+	g.midRound = true
+
+	if humans == 0 {
+		g.Subscribe(NewPacer(g.speed).OnEvent)
+	}
+	return nil
+}
+
+// parseCards parses a space-separated card list such as "3 7 x2 freeze"
+func parseCards(s string) ([]*Card, error) {
+	var cards []*Card
+	for _, field := range strings.Fields(s) {
+		card, err := parseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
+
+// takeCards returns the pool without the given cards. It reports the
+// first card that the pool doesn't have enough copies of.
+func takeCards(pool, cards []*Card) ([]*Card, error) {
+	rest := slices.Clone(pool)
+	var err error
+	for _, card := range cards {
+		idx := slices.IndexFunc(rest, func(c *Card) bool { return *c == *card })
+		if idx < 0 {
+			if err == nil {
+				err = fmt.Errorf("the deck doesn't have another %s", card)
+			}
+			continue
+		}
+		rest = slices.Delete(rest, idx, idx+1)
+	}
+	return rest, err
+}
+
+// loadScenario reads a scenario file and sets the game up from it
+func loadScenario(g *Game, path string) error {
+	s, err := LoadScenario(path)
+	if err != nil {
+		return err
+	}
+	return s.Apply(g)
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// publishedSchema is a file format with the version its schema is
+// published under and a value of the Go type it's generated from
+type publishedSchema struct {
+	name    string
+	title   string
+	version int
+	value   any
+}
+
+// publishedSchemas are the formats third-party tools read or write
+var publishedSchemas = []publishedSchema{
+	{"save", "Flip 7 save file", saveVersion, SavedGame{}},
+	{"event", "Flip 7 event", envProtocolVersion, EventMessage{}},
+	{"state", "Flip 7 table snapshot", envProtocolVersion, StateSnapshot{}},
+	{"env-request", "Flip 7 env protocol request", envProtocolVersion, envRequest{}},
+	{"env-spec", "Flip 7 env protocol spec response", envProtocolVersion, envSpec{}},
+	{"env-timestep", "Flip 7 env protocol time step response", envProtocolVersion, TimeStep{}},
+	{"env-hello", "Flip 7 env protocol hello response", envProtocolVersion, envHello{}},
+}
+
+// schemaEnums are the values allowed in string fields, by type and JSON
+// field name
+var schemaEnums = map[string][]string{
+	"EventMessage.type": func() []string {
+		var names []string
+		for t := GameStarted; t <= DeckPeeked; t++ {
+			names = append(names, t.String())
+		}
+		return names
+	}(),
+	"envRequest.op":       {"hello", "spec", "reset", "step"},
+	"envRequest.encoding": {"json", "protobuf"},
+	"envHello.encoding":   {"json", "protobuf"},
+}
+
+// fileName is the file a schema is published in
+func (p publishedSchema) fileName() string {
+	return fmt.Sprintf("%s.v%d.schema.json", p.name, p.version)
+}
+
+// generate builds the JSON Schema of the format's Go type
+func (p publishedSchema) generate() map[string]any {
+	defs := make(map[string]any)
+	t := reflect.TypeOf(p.value)
+	root := objectSchema(t, defs)
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = fmt.Sprintf("urn:flip7:schema:%s:v%d", p.name, p.version)
+	root["title"] = p.title
+	delete(defs, t.Name())
+	if len(defs) > 0 {
+		root["$defs"] = defs
+	}
+	return root
+}
+
+// typeSchema is the schema of a Go type as encoding/json writes it.
+// Structs go in defs by name; nil pointers, slices and maps are null.
+func typeSchema(t reflect.Type, defs map[string]any) map[string]any {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return map[string]any{"anyOf": []any{typeSchema(t.Elem(), defs), map[string]any{"type": "null"}}}
+	case reflect.Struct:
+		if _, ok := defs[t.Name()]; !ok {
+			defs[t.Name()] = nil // stops recursion
+			defs[t.Name()] = objectSchema(t, defs)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": []any{"array", "null"}, "items": typeSchema(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": []any{"object", "null"}, "additionalProperties": typeSchema(t.Elem(), defs)}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	}
+	return map[string]any{}
+}
+
+// objectSchema is the schema of a struct's exported JSON fields. Fields
+// without omitempty are required, since encoding/json always writes them.
+func objectSchema(t reflect.Type, defs map[string]any) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, options, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := typeSchema(f.Type, defs)
+		if enum, ok := schemaEnums[t.Name()+"."+name]; ok {
+			s["enum"] = enum
+		}
+		properties[name] = s
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// RunSchema handles the schema subcommand: it writes the published JSON
+// Schemas to a directory, or checks the current formats against the
+// schemas published there
+func RunSchema(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	output := fs.String("o", "", "Write the schemas to this directory")
+	check := fs.String("check", "", "Check the formats are compatible with the schemas published in this directory")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if (*output == "") == (*check == "") {
+		return fmt.Errorf("usage: flip7 schema -o DIR | -check DIR")
+	}
+	if *check != "" {
+		return checkSchemas(*check, out)
+	}
+
+	if err := os.MkdirAll(*output, 0o755); err != nil {
+		return err
+	}
+	for _, p := range publishedSchemas {
+		data, err := json.MarshalIndent(p.generate(), "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(*output, p.fileName())
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s\n", path)
+	}
+	return nil
+}
+
+// checkSchemas compares every format with its newest schema in dir. A
+// format may change compatibly under the same version; a breaking change
+// needs a new version.
+func checkSchemas(dir string, out io.Writer) error {
+	breaking := 0
+	for _, p := range publishedSchemas {
+		published, version, err := newestSchema(dir, p.name)
+		if err != nil {
+			return err
+		}
+		current := normalize(p.generate())
+		switch {
+		case published == nil:
+			fmt.Fprintf(out, "%s: new format, not published yet\n", p.fileName())
+			continue
+		case version > p.version:
+			fmt.Fprintf(out, "%s: older than the published v%d\n", p.fileName(), version)
+			breaking++
+			continue
+		case version < p.version:
+			fmt.Fprintf(out, "%s: new version of v%d, not published yet\n", p.fileName(), version)
+			continue
+		}
+
+		var problems []string
+		compareSchemas(published, current, published, current, p.name, &problems)
+		if len(problems) > 0 {
+			breaking++
+			fmt.Fprintf(out, "%s: breaking changes; bump the version:\n", p.fileName())
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  %s\n", problem)
+			}
+		} else if !reflect.DeepEqual(published, current) {
+			fmt.Fprintf(out, "%s: compatible changes; publish it again\n", p.fileName())
+		} else {
+			fmt.Fprintf(out, "%s: ok\n", p.fileName())
+		}
+	}
+	if breaking > 0 {
+		return fmt.Errorf("%d formats changed incompatibly", breaking)
+	}
+	return nil
+}
+
+// newestSchema reads the highest version of a schema in dir, or nil if
+// there is none
+func newestSchema(dir, name string) (map[string]any, int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, name+".v*.schema.json"))
+	if err != nil {
+		return nil, 0, err
+	}
+	var newest string
+	version := 0
+	for _, path := range paths {
+		v, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), name+".v"), ".schema.json"))
+		if err == nil && v > version {
+			newest, version = path, v
+		}
+	}
+	if newest == "" {
+		return nil, 0, nil
+	}
+	data, err := os.ReadFile(newest)
+	if err != nil {
+		return nil, 0, err
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", newest, err)
+	}
+	return schema, version, nil
+}
+
+// normalize round-trips a schema through JSON so generated and published
+// schemas have the same Go types
+func normalize(schema map[string]any) map[string]any {
+	data, _ := json.Marshal(schema)
+	var v map[string]any
+	json.Unmarshal(data, &v)
+	return v
+}
+
+// compareSchemas notes the changes from old to new that would make a
+// message valid under one invalid under the other: a type or allowed
+// value changing, or a required field coming or going. Fields that were
+// optional may come and go freely.
+func compareSchemas(oldRoot, newRoot, old, new map[string]any, path string, problems *[]string) {
+	old, new = resolve(oldRoot, old), resolve(newRoot, new)
+	if !reflect.DeepEqual(old["type"], new["type"]) || !reflect.DeepEqual(old["format"], new["format"]) {
+		*problems = append(*problems, fmt.Sprintf("%s: type changed from %v to %v", path, old["type"], new["type"]))
+		return
+	}
+	if !reflect.DeepEqual(old["enum"], new["enum"]) {
+		*problems = append(*problems, fmt.Sprintf("%s: allowed values changed", path))
+	}
+
+	for _, key := range []string{"items", "additionalProperties"} {
+		o, okOld := old[key].(map[string]any)
+		n, okNew := new[key].(map[string]any)
+		if okOld && okNew {
+			compareSchemas(oldRoot, newRoot, o, n, path+"[]", problems)
+		}
+	}
+	// A pointer is its type or null
+	o, _ := old["anyOf"].([]any)
+	n, _ := new["anyOf"].([]any)
+	if len(o) != len(n) {
+		*problems = append(*problems, fmt.Sprintf("%s: type changed", path))
+	}
+	for i := range min(len(o), len(n)) {
+		oi, _ := o[i].(map[string]any)
+		ni, _ := n[i].(map[string]any)
+		if oi != nil && ni != nil {
+			compareSchemas(oldRoot, newRoot, oi, ni, path, problems)
+		}
+	}
+
+	oldProps, _ := old["properties"].(map[string]any)
+	newProps, _ := new["properties"].(map[string]any)
+	oldRequired, newRequired := stringSet(old["required"]), stringSet(new["required"])
+	for _, name := range slices.Sorted(maps.Keys(oldProps)) {
+		n, ok := newProps[name].(map[string]any)
+		switch {
+		case !ok && oldRequired[name]:
+			*problems = append(*problems, fmt.Sprintf("%s.%s: required field removed", path, name))
+		case ok:
+			if oldRequired[name] && !newRequired[name] {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: no longer required", path, name))
+			} else if !oldRequired[name] && newRequired[name] {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: now required", path, name))
+			}
+			o, _ := oldProps[name].(map[string]any)
+			compareSchemas(oldRoot, newRoot, o, n, path+"."+name, problems)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(newProps)) {
+		if _, ok := oldProps[name]; !ok && newRequired[name] {
+			*problems = append(*problems, fmt.Sprintf("%s.%s: new required field", path, name))
+		}
+	}
+}
+
+// resolve follows a $ref into the root schema's $defs
+func resolve(root, schema map[string]any) map[string]any {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema
+	}
+	defs, _ := root["$defs"].(map[string]any)
+	def, _ := defs[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+	if def == nil {
+		return map[string]any{}
+	}
+	return def
+}
+
+// stringSet makes a set of a JSON array of strings
+func stringSet(v any) map[string]bool {
+	set := make(map[string]bool)
+	list, _ := v.([]any)
+	for _, s := range list {
+		if s, ok := s.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,85 @@
+package main
+
+import "fmt"
+
+// Scorer scores a round's hand that didn't bust. Variants and
+// expansions swap it to change how a hand adds up; busted hands score
+// nothing whatever the scorer.
+type Scorer interface {
+	ScoreHand(numbers, modifiers []*Card, flip7 bool) int
+}
+
+// StandardScorer scores hands by the published rules
+type StandardScorer struct{}
+
+func (StandardScorer) ScoreHand(numbers, modifiers []*Card, flip7 bool) int {
+	return ScoreHand(numbers, modifiers, flip7)
+}
+
+// VariantScorer scores hands by common house rules. StackMultipliers
+// makes every x2 double the numbers again, rather than only the first,
+// and Flip7Bonus replaces the 15 point Flip 7 bonus when set.
+type VariantScorer struct {
+	StackMultipliers bool `yaml:"stack_multipliers" toml:"stack_multipliers" json:",omitempty"`
+	Flip7Bonus       *int `yaml:"flip7_bonus" toml:"flip7_bonus" json:",omitempty"`
+}
+
+func (s VariantScorer) ScoreHand(numbers, modifiers []*Card, flip7 bool) int {
+	total := 0
+	for _, card := range numbers {
+		total += card.Value
+	}
+	for _, card := range modifiers {
+		if card.Modifier == Multiply2 {
+			total *= 2
+			if !s.StackMultipliers {
+				break
+			}
+		}
+	}
+	for _, card := range modifiers {
+		if card.Modifier != Multiply2 {
+			total += card.GetPoints()
+		}
+	}
+	if flip7 {
+		if s.Flip7Bonus != nil {
+			total += *s.Flip7Bonus
+		} else {
+			total += flip7Bonus
+		}
+	}
+	return total
+}
+
+// validate checks the variant's settings
+func (s VariantScorer) validate() error {
+	if s.Flip7Bonus != nil && *s.Flip7Bonus < 0 {
+		return fmt.Errorf("flip7_bonus must not be negative")
+	}
+	return nil
+}
+
+// flip7BonusOf returns the Flip 7 bonus the player's scorer adds, for
+// strategies weighing the seventh card
+func flip7BonusOf(p PlayerInterface) int {
+	s := p.(baser).base().scorer
+	if s == nil {
+		return flip7Bonus
+	}
+	return s.ScoreHand(nil, nil, true) - s.ScoreHand(nil, nil, false)
+}
+
+// SetScorer changes how the game scores hands, for every player at the
+// table when the next game starts
+func (g *Game) SetScorer(s Scorer) {
+	g.scorer = s
+}
+
+// useScorer hands the game's scorer to every player, so their round
+// scores and the leader strategies see follow it
+func (g *Game) useScorer() {
+	for _, player := range g.players {
+		player.(baser).base().scorer = g.scorer
+	}
+}
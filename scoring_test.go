@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"flip7/bot"
+)
+
+func TestScoreHand(t *testing.T) {
+	tests := []struct {
+		hand string
+		want int
+	}{
+		{"", 0},
+		{"7 9", 16},
+		{"7 9 x2", 32},
+		{"7 9 x2 +4", 36},
+		{"+4 x2 9 7", 36},
+		{"+2 +10", 12},
+		{"0 1 2 3 4 5 6", 21 + flip7Bonus},
+		{"0 1 2 3 4 5 6 x2 +10", 42 + 10 + flip7Bonus},
+		{"12 11 10 9 8 7 6 +8", 63 + 8 + flip7Bonus},
+	}
+	for _, tt := range tests {
+		cards := mustParseCards(tt.hand)
+		var numbers, modifiers []*Card
+		for _, card := range cards {
+			if card.Type == NumberCard {
+				numbers = append(numbers, card)
+			} else {
+				modifiers = append(modifiers, card)
+			}
+		}
+		if got := ScoreHand(numbers, modifiers, len(numbers) == 7); got != tt.want {
+			t.Errorf("ScoreHand(%q) = %d, want %d", tt.hand, got, tt.want)
+		}
+	}
+}
+
+func TestScoringProperties(t *testing.T) {
+	property := func(seed int64) bool {
+		if err := checkScoring(rand.New(rand.NewSource(seed))); err != nil {
+			t.Errorf("seed %d: %v", seed, err)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkScoring deals a random hand and checks the scoring rules hold for
+// it: x2 doubles only the number cards, the other modifiers are added
+// after, Flip 7 is worth 15 more, card order doesn't matter and a busted
+// hand scores nothing. The bot package must agree with the engine on the
+// hand's score and odds.
+func checkScoring(r *rand.Rand) error {
+	deck := &Deck{rng: r}
+	deck.createCards()
+	deck.Shuffle()
+
+	size := r.Intn(8)
+	var numbers, modifiers, doubler []*Card
+	sum, plus := 0, 0
+	for _, card := range deck.cards {
+		switch {
+		case card.Type == NumberCard && len(numbers) < size &&
+			!slices.ContainsFunc(numbers, func(c *Card) bool { return c.Value == card.Value }):
+			numbers = append(numbers, card)
+			sum += card.Value
+		case card.Type == ModifierCard && r.Intn(3) == 0:
+			modifiers = append(modifiers, card)
+			if card.Modifier == Multiply2 {
+				doubler = append(doubler, card)
+			} else {
+				plus += card.GetPoints()
+			}
+		}
+	}
+
+	hand := make([]string, 0, len(numbers)+len(modifiers))
+	for _, card := range slices.Concat(numbers, modifiers) {
+		hand = append(hand, formatCard(card))
+	}
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("scoring %s: %s", strings.Join(hand, " "), fmt.Sprintf(format, args...))
+	}
+
+	flip7 := len(numbers) == 7
+	score := ScoreHand(numbers, modifiers, flip7)
+	if got := ScoreHand(numbers, nil, false); got != sum {
+		return fail("number cards alone scored %d, want their sum %d", got, sum)
+	}
+	if got := ScoreHand(numbers, []*Card{NewModifierCard(Multiply2)}, false); got != 2*sum {
+		return fail("x2 scored %d, want double the numbers %d", got, 2*sum)
+	}
+	if got, want := ScoreHand(numbers, modifiers, false), ScoreHand(numbers, doubler, false)+plus; got != want {
+		return fail("scored %d, want %d with the modifiers added after x2", got, want)
+	}
+	if got, want := ScoreHand(numbers, modifiers, true), ScoreHand(numbers, modifiers, false)+flip7Bonus; got != want {
+		return fail("Flip 7 scored %d, want %d", got, want)
+	}
+	if got := (VariantScorer{}).ScoreHand(numbers, modifiers, flip7); got != score {
+		return fail("variant scorer with no house rules scored %d, want %d", got, score)
+	}
+	reversed := func(cards []*Card) []*Card {
+		cards = slices.Clone(cards)
+		slices.Reverse(cards)
+		return cards
+	}
+	if got := ScoreHand(reversed(numbers), reversed(modifiers), flip7); got != score {
+		return fail("reversed hand scored %d, want %d", got, score)
+	}
+
+	player := &BasePlayer{}
+	player.Init("fuzz")
+	for _, card := range slices.Concat(numbers, modifiers) {
+		player.AddCard(card)
+	}
+	if got := player.CalculateRoundScore(); got != score {
+		return fail("player scored %d, want %d", got, score)
+	}
+	if err := checkBotOdds(player, strings.Join(hand, " "), score); err != nil {
+		return fail("%v", err)
+	}
+	player.State = Busted
+	if got := player.CalculateRoundScore(); got != 0 {
+		return fail("busted player scored %d, want 0", got)
+	}
+	return nil
+}
+
+// checkBotOdds checks the bot package reads a player's hand, scores it and
+// tallies the cards left as the engine does, with only that hand on the
+// table. The odds are the bot package's in both, so they differ only when
+// the hand or the tally does.
+func checkBotOdds(player *BasePlayer, hand string, score int) error {
+	h, err := bot.ParseHand(hand)
+	if err != nil {
+		return fmt.Errorf("bot package can't read the hand: %w", err)
+	}
+	if got := h.Score(); got != score {
+		return fmt.Errorf("bot package scored %d, want %d", got, score)
+	}
+	unseen := bot.FullDeck()
+	unseen.Remove(h...)
+	p := &ComputerPlayer{BasePlayer: *player}
+	state := &GameState{Players: []PlayerInterface{p}}
+	if got, want := bot.BustProbability(h, unseen), CalculateBustProbability(p, state); math.Abs(got-want) > 1e-9 {
+		return fmt.Errorf("bot package bust probability %.4f, want %.4f", got, want)
+	}
+	if got, want := bot.ExpectedPointsFromHit(h, unseen), CalculateExpectedPointsFromHit(p, state); math.Abs(got-want) > 1e-9 {
+		return fmt.Errorf("bot package expected points %.4f, want %.4f", got, want)
+	}
+	return nil
+}
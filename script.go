@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// SetScript makes the game read every answer from a script instead of a
+// terminal. Each answer is echoed after its prompt so the output reads
+// like a terminal session, lines starting with # are comments, and the
+// clock is frozen so the output is the same on every run.
+func (g *Game) SetScript(r io.Reader) {
+	g.SetInput(r)
+	g.scanner.Split(scriptLines(g.out))
+	g.now = func() time.Time { return time.Time{} }
+}
+
+// scriptLines is a bufio.SplitFunc that returns a script's lines, skipping
+// comments and echoing each answer to out
+func scriptLines(out io.Writer) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		// Comments are skipped here rather than by returning no token,
+		// which would end the scan once the whole script has been read
+		skipped := 0
+		for {
+			advance, token, err := bufio.ScanLines(data[skipped:], atEOF)
+			if err != nil || token == nil {
+				return skipped + advance, token, err
+			}
+			if bytes.HasPrefix(bytes.TrimSpace(token), []byte("#")) {
+				skipped += advance
+				continue
+			}
+			fmt.Fprintf(out, "%s\n", token)
+			return skipped + advance, token, nil
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// SeatStats tallies simulation results by seat. Seats are numbered from
+// the dealer's left: seat 1 acts first in the first round and the last
+// seat deals it. Games, Wins and Totals are kept by the seat a player
+// started the game in; Rounds and Points by where they acted each round,
+// since the deal moves on every round.
+type SeatStats struct {
+	Games  []int
+	Wins   []int
+	Totals []int
+	Rounds []int
+	Points []int
+
+	dealer int
+}
+
+// NewSeatStats creates an empty tally for a table of the given size
+func NewSeatStats(seats int) *SeatStats {
+	return &SeatStats{
+		Games:  make([]int, seats),
+		Wins:   make([]int, seats),
+		Totals: make([]int, seats),
+		Rounds: make([]int, seats),
+		Points: make([]int, seats),
+	}
+}
+
+// SetSeatStats turns on the seat-position report for simulations
+func (g *Game) SetSeatStats(enabled bool) {
+	g.seatStats = enabled
+}
+
+// SetRotateSeats makes simulations move every player one seat along after
+// each game, so every strategy plays every seat equally often
+func (g *Game) SetRotateSeats(enabled bool) {
+	g.rotateSeats = enabled
+}
+
+// trackSeats keeps a seat tally up to date with the rounds the game plays
+func (g *Game) trackSeats(stats *SeatStats) {
+	g.Subscribe(func(event Event) {
+		switch event.Type {
+		case RoundStarted:
+			stats.dealer = g.dealerIdx
+		case PlayerScored:
+			pos := stats.position(g, event.Player, stats.dealer)
+			stats.Rounds[pos]++
+			stats.Points[pos] += event.Score
+		}
+	})
+}
+
+// recordGame counts a finished game for every seat, given the seat index
+// of the first dealer
+func (s *SeatStats) recordGame(g *Game, dealer int, winner PlayerInterface) {
+	for _, p := range g.players {
+		seat := s.position(g, p, dealer)
+		s.Games[seat]++
+		s.Totals[seat] += p.GetTotalScore()
+		if p == winner {
+			s.Wins[seat]++
+		}
+	}
+}
+
+// position is where the player acts in a round dealt by the given seat
+// index, counting from 0 for the dealer's left
+func (s *SeatStats) position(g *Game, player PlayerInterface, dealer int) int {
+	n := len(g.players)
+	for i, p := range g.players {
+		if p == player {
+			return (i - dealer - 1 + 2*n) % n
+		}
+	}
+	return 0
+}
+
+// rate is the seat's win rate as a fraction
+func (s *SeatStats) rate(seat int) float64 {
+	if s.Games[seat] == 0 {
+		return 0
+	}
+	return float64(s.Wins[seat]) / float64(s.Games[seat])
+}
+
+// halves compares the win rates of the earlier and later half of the
+// table. It reports both rates and whether the gap is more than two
+// standard errors, i.e. unlikely to be luck.
+func (s *SeatStats) halves() (early, late float64, significant bool) {
+	half := len(s.Games) / 2
+	var earlyWins, earlyGames, lateWins, lateGames int
+	for seat := range s.Games {
+		switch {
+		case seat < half:
+			earlyWins += s.Wins[seat]
+			earlyGames += s.Games[seat]
+		case seat >= len(s.Games)-half:
+			lateWins += s.Wins[seat]
+			lateGames += s.Games[seat]
+		}
+	}
+	if earlyGames == 0 || lateGames == 0 {
+		return 0, 0, false
+	}
+	early = float64(earlyWins) / float64(earlyGames)
+	late = float64(lateWins) / float64(lateGames)
+	stderr := math.Sqrt(early*(1-early)/float64(earlyGames) + late*(1-late)/float64(lateGames))
+	return early, late, math.Abs(early-late) > 2*stderr
+}
+
+// displaySeatStatistics shows win rate and scores by seat and whether
+// acting early or late helps
+func (g *Game) displaySeatStatistics(s *SeatStats) {
+	seats := len(s.Games)
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("💺 RESULTS BY SEAT (seat 1 acts first, seat %d deals)\n"), seats)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf("%-8s %8s %8s %10s %10s %12s\n", T("SEAT"), T("GAMES"), T("WINS"), T("WIN RATE"), T("AVG SCORE"), T("AVG ROUND"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+
+	for seat := range seats {
+		avgScore, avgRound := 0.0, 0.0
+		if s.Games[seat] > 0 {
+			avgScore = float64(s.Totals[seat]) / float64(s.Games[seat])
+		}
+		if s.Rounds[seat] > 0 {
+			avgRound = float64(s.Points[seat]) / float64(s.Rounds[seat])
+		}
+		g.resultf("%-8d %8d %8d %9.1f%% %10.1f %12.1f\n",
+			seat+1, s.Games[seat], s.Wins[seat], s.rate(seat)*100, avgScore, avgRound)
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+
+	early, late, significant := s.halves()
+	switch {
+	case seats < 2:
+	case !significant:
+		g.resultf(T("No clear seat advantage: earlier seats won %.1f%%, later seats %.1f%%\n"), early*100, late*100)
+	case early > late:
+		g.resultf(T("Acting earlier helps: earlier seats won %.1f%%, later seats %.1f%%\n"), early*100, late*100)
+	default:
+		g.resultf(T("Acting later helps: earlier seats won %.1f%%, later seats %.1f%%\n"), early*100, late*100)
+	}
+	if !g.rotateSeats && !g.duplicate && g.dealerMode == DealerFixed {
+		g.resultf("%s\n", T("Each player kept their seat, so seat and strategy are mixed; use -rotate to separate them"))
+	}
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
+
+// rotated returns the players moved k seats along
+func rotated(players []PlayerInterface, k int) []PlayerInterface {
+	k %= len(players)
+	return append(players[k:len(players):len(players)], players[:k]...)
+}
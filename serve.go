@@ -0,0 +1,114 @@
+package main
+
+import (
+	"embed"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// webClient is the single-page web client served by serve -web
+//
+//go:embed web
+var webClient embed.FS
+
+// maxWebName is the longest name a web player may take
+const maxWebName = 20
+
+// RunServe handles the serve subcommand: with -web it serves the web
+// client and a WebSocket at /play where each connection plays its own
+// game against the computer opponents
+func RunServe(args []string, out io.Writer) error {
+	fset := flag.NewFlagSet("serve", flag.ContinueOnError)
+	web := fset.String("web", "", "Serve the web client at this address, e.g. :8080")
+	opponents := fset.String("opponents", "expert,bayesian", "Comma-separated opponent strategies, e.g. expert,play-to:25")
+	score := fset.Int("score", 200, "Points needed to win")
+	ladderFile := fset.String("ladder", "", "Run a ranked ladder, saving accounts and ratings to this file")
+	season := fset.Duration("season", 30*24*time.Hour, "Length of a ladder season (0 never resets ratings)")
+	table := fset.Int("table", 2, "Players per ranked ladder game")
+	turn := fset.Duration("turn", time.Minute, "Time a ranked player has for each decision (0 waits as long as it takes)")
+	onTimeout := fset.String("on-timeout", "stay,leader", "What's decided for a player whose time runs out: stay or hit, and leader, last or random")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if *web == "" {
+		return fmt.Errorf("usage: flip7 serve -web ADDRESS [-ladder FILE]")
+	}
+	if *table < 2 || *table > 18 {
+		return fmt.Errorf("-table must be between 2 and 18")
+	}
+	strategies := strings.Split(*opponents, ",")
+	if _, err := NewEnv(strategies, *score); err != nil {
+		return err
+	}
+
+	static, err := fs.Sub(webClient, "web")
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/play", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := UpgradeWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		e, _ := NewEnv(strategies, *score)
+		e.Name = webPlayerName(r.URL.Query().Get("name"))
+		defer e.Close()
+		servePlayer(e, ws)
+	})
+
+	if *ladderFile != "" {
+		timeout, err := ParseTurnTimeout(*turn, *onTimeout)
+		if err != nil {
+			return err
+		}
+		ladder, err := OpenLadder(*ladderFile, *season, *table, *score, timeout)
+		if err != nil {
+			return err
+		}
+		mux.HandleFunc("/ladder/register", ladder.handleRegister)
+		mux.HandleFunc("/ladder/standings", ladder.handleStandings)
+		mux.HandleFunc("/ladder/play", ladder.handlePlay)
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	report.printf(T("🌐 Serving the web client at %s\n"), *web)
+	return http.ListenAndServe(*web, mux)
+}
+
+// webPlayerName is the name a web player asked for, trimmed to fit the
+// scoreboard
+func webPlayerName(name string) string {
+	name = strings.TrimSpace(name)
+	for utf8.RuneCountInString(name) > maxWebName {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" {
+		return "You"
+	}
+	return name
+}
+
+// servePlayer answers a connection's env protocol requests, one JSON
+// message each way, until it closes
+func servePlayer(e *Env, ws *WebSocket) {
+	for {
+		msg, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := ws.WriteMessage(e.handle(msg)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// checkpointVersion is bumped whenever the checkpoint format changes
+	checkpointVersion = 1
+	// checkpointKind marks a file as a simulation checkpoint rather than a
+	// saved game
+	checkpointKind = "simulation"
+	// checkpointInterval is how often a running simulation saves itself
+	checkpointInterval = 30 * time.Second
+)
+
+// Simulation is the running state of a batch of AI-only games, and the
+// contents of a checkpoint file. Games and Played count boards rather
+// than games in a duplicate simulation. Seed is the seed of the last game
+// dealt, or of the first board when duplicate. Scores and Rounds total
+// the players' final scores and the rounds played.
+type Simulation struct {
+	Kind          string
+	Version       int
+	SavedAt       time.Time
+	Games         int
+	Played        int
+	Elapsed       time.Duration
+	Budget        time.Duration
+	Seed          int64
+	WinningScore  int
+	HoldActions   bool            `json:",omitempty"`
+	Reshuffle     ReshufflePolicy `json:",omitempty"`
+	Burn          int             `json:",omitempty"`
+	Win           WinSpec
+	Scoring       *VariantScorer `json:",omitempty"`
+	Cards         CardSet        `json:",omitempty"`
+	Duplicate     bool
+	Rotate        bool
+	Fresh         bool `json:",omitempty"`
+	Players       []SavedPlayer
+	Wins          map[string]int
+	Scores        map[string]int
+	Rounds        int
+	Dealer        DealerMode          `json:",omitempty"`
+	Dealers       *DealerStats        `json:",omitempty"`
+	Seats         *SeatStats          `json:",omitempty"`
+	Actions       *ActionStats        `json:",omitempty"`
+	Histograms    bool                `json:",omitempty"`
+	ScoresFile    string              `json:",omitempty"`
+	Distributions *ScoreDistributions `json:",omitempty"`
+	Lengths       *LengthStats        `json:",omitempty"`
+	Notables      *NotableGames       `json:",omitempty"`
+	Results       string              `json:",omitempty"`
+	ResultsSize   int64               `json:",omitempty"`
+	Decisions     string              `json:",omitempty"`
+	DecisionsSize int64               `json:",omitempty"`
+	Report        string              `json:",omitempty"`
+	HeadToHead    *HeadToHead         `json:",omitempty"`
+	Params        string              `json:",omitempty"`
+	ParamVersions []ParamVersion      `json:",omitempty"`
+	Stop          *StopRule           `json:",omitempty"`
+	// Outcomes is one player's result in every game, for pairing the games
+	// with another simulation's; it isn't checkpointed
+	Outcomes *GameOutcomes `json:"-"`
+
+	results   *resultsWriter
+	decisions *DecisionLog
+	// When the parameters file was last looked at, its time when it was
+	// last read, and the problem with it, if any
+	paramsChecked time.Time
+	paramsModTime time.Time
+	paramsErr     string
+}
+
+// SetTimeBudget limits simulations to as many games as fit in d. Zero
+// means no limit.
+func (g *Game) SetTimeBudget(d time.Duration) {
+	g.budget = d
+}
+
+// SetCheckpoint makes simulations save their progress to path every so
+// often and when they finish, so they can be resumed
+func (g *Game) SetCheckpoint(path string) {
+	g.checkpoint = path
+}
+
+// newSimulation starts a simulation of numGames games with the current
+// players and options. Zero games means as many as the time budget allows.
+func (g *Game) newSimulation(numGames int) (*Simulation, error) {
+	sim := &Simulation{
+		Games:        numGames,
+		Budget:       g.budget,
+		WinningScore: g.winningScore,
+		HoldActions:  g.holdActions,
+		Reshuffle:    g.reshuffle,
+		Burn:         g.burnCards,
+		Win:          g.winSpec,
+		Duplicate:    g.duplicate,
+		Rotate:       g.rotateSeats,
+		Fresh:        g.freshLearning,
+		Dealer:       g.dealerMode,
+		Wins:         make(map[string]int),
+		Scores:       make(map[string]int),
+		Results:      g.results,
+		Decisions:    g.decisionsFile,
+		Histograms:   g.histograms,
+		ScoresFile:   g.scoresFile,
+		Report:       g.report,
+		Params:       g.params,
+		Stop:         g.stopRule,
+	}
+	players, err := savedPlayers(g.players)
+	if err != nil {
+		return nil, err
+	}
+	sim.Players = players
+	if g.dealerMode != DealerFixed {
+		sim.Dealers = NewDealerStats()
+	}
+	if g.seatStats {
+		sim.Seats = NewSeatStats(len(g.players))
+	}
+	if g.actionStats {
+		sim.Actions = NewActionStats()
+	}
+	if g.histograms || g.scoresFile != "" {
+		sim.Distributions = NewScoreDistributions()
+	}
+	if g.lengthStats {
+		sim.Lengths = NewLengthStats()
+	}
+	if !g.duplicate {
+		sim.Notables = NewNotableGames()
+	}
+	// The report charts the final scores and action cards, so it
+	// collects them whether or not they're shown
+	if g.report != "" {
+		sim.HeadToHead = NewHeadToHead()
+		if sim.Distributions == nil {
+			sim.Distributions = NewScoreDistributions()
+		}
+		if sim.Actions == nil {
+			sim.Actions = NewActionStats()
+		}
+	}
+	if scorer, ok := g.scorer.(VariantScorer); ok {
+		sim.Scoring = &scorer
+	}
+	sim.Cards = g.cardSet
+
+	// Seed every game so notable ones can be replayed and an interrupted
+	// run carries on with the same deals
+	sim.Seed = g.seed
+	if !g.seeded {
+		sim.Seed = g.now().UnixNano()
+	}
+	return sim, nil
+}
+
+// simulate plays the simulation's remaining games, stopping early when
+// the time budget runs out or ctx is cancelled, and shows the results.
+// Games already started finish, so an interrupted simulation's totals
+// and checkpoint only count whole games.
+func (g *Game) simulate(ctx context.Context, sim *Simulation) error {
+	if sim.Seats != nil {
+		g.trackSeats(sim.Seats)
+	}
+	if sim.Actions != nil {
+		g.trackActions(sim.Actions)
+	}
+	if sim.Distributions != nil {
+		g.trackScores(sim.Distributions)
+	}
+	if sim.Lengths != nil {
+		g.trackLengths(sim.Lengths)
+	}
+	if sim.Notables != nil {
+		g.trackNotables(sim.Notables)
+	}
+	g.duplicate = sim.Duplicate
+	g.rotateSeats = sim.Rotate
+	g.freshLearning = sim.Fresh
+	g.dealerMode = sim.Dealer
+	g.winningScore = sim.WinningScore
+	g.holdActions = sim.HoldActions
+	g.SetReshufflePolicy(sim.Reshuffle)
+	g.burnCards = sim.Burn
+	if err := g.SetWinCondition(sim.Win); err != nil {
+		return err
+	}
+	if sim.Scoring != nil {
+		g.SetScorer(*sim.Scoring)
+	}
+	if len(sim.Cards) > 0 {
+		if err := g.SetCardSet(sim.Cards); err != nil {
+			return err
+		}
+	}
+	g.SetSeed(sim.Seed)
+
+	lineup := g.players
+	defer func() { g.players = lineup }()
+	defer g.poolState()()
+	if g.fastStrategies {
+		g.useFastStrategies(lineup)
+	}
+
+	if sim.Results != "" {
+		rw, err := openResults(sim.Results, sim.ResultsSize)
+		if err != nil {
+			return err
+		}
+		defer rw.close()
+		sim.results = rw
+	}
+	if sim.Decisions != "" {
+		d, err := openDecisions(sim.Decisions, sim.DecisionsSize)
+		if err != nil {
+			return err
+		}
+		defer d.close()
+		defer func() { g.decisions = nil }()
+		sim.decisions = d
+		g.trackDecisions(d)
+	}
+
+	if sim.Params != "" {
+		// The first read is shown, although games start out silent
+		silent := g.silentMode
+		g.SetSilentMode(false)
+		err := g.reloadParams(sim, lineup)
+		g.SetSilentMode(silent)
+		if err != nil {
+			return err
+		}
+	}
+
+	progress, budgeted, open := T("⚡ Game %d/%d... (%.1fs elapsed)\n"), T("⚡ Game %d... (%.1fs of %s)\n"), T("⚡ Game %d... (%.1fs elapsed)\n")
+	if sim.Duplicate {
+		progress, budgeted, open = T("⚡ Board %d/%d... (%.1fs elapsed)\n"), T("⚡ Board %d... (%.1fs of %s)\n"), T("⚡ Board %d... (%.1fs elapsed)\n")
+	}
+	names := make([]string, len(lineup))
+	for i, player := range lineup {
+		names[i] = player.GetName()
+	}
+	startTime := g.now()
+	lastProgressTime, lastCheckpoint := startTime, startTime
+	elapsed := func() time.Duration { return sim.Elapsed + g.now().Sub(startTime) }
+	for sim.Games == 0 || sim.Played < sim.Games {
+		if sim.Budget > 0 && elapsed() >= sim.Budget {
+			g.printf(T("⏱️ Time is up after %d games\n"), sim.gamesPlayed(len(lineup)))
+			break
+		}
+		if ctx.Err() != nil {
+			g.printf(T("🛑 Interrupted after %d games\n"), sim.gamesPlayed(len(lineup)))
+			break
+		}
+		if sim.Params != "" {
+			if err := g.reloadParams(sim, lineup); err != nil {
+				return err
+			}
+		}
+
+		// Show progress every 5 seconds or for first game
+		now := g.now()
+		if sim.Played == 0 || now.Sub(lastProgressTime) >= 5*time.Second {
+			switch {
+			case sim.Games == 0 && sim.Budget == 0:
+				g.printf(open, sim.Played+1, elapsed().Seconds())
+			case sim.Games == 0:
+				g.printf(budgeted, sim.Played+1, elapsed().Seconds(), sim.Budget)
+			default:
+				g.printf(progress, sim.Played+1, sim.Games, elapsed().Seconds())
+			}
+			lastProgressTime = now
+		}
+
+		var err error
+		if sim.Duplicate {
+			err = g.playBoard(context.WithoutCancel(ctx), sim, lineup)
+		} else {
+			err = g.playSimulatedGame(context.WithoutCancel(ctx), sim, lineup)
+		}
+		if err != nil {
+			return err
+		}
+		sim.Played++
+		if sim.Stop != nil {
+			if reason, ok := sim.Stop.met(sim, names); ok {
+				g.printf(T("🎯 Stopping after %d games: %s\n"), sim.gamesPlayed(len(lineup)), reason)
+				break
+			}
+		}
+
+		if g.checkpoint != "" && g.now().Sub(lastCheckpoint) >= checkpointInterval {
+			if err := sim.save(g.checkpoint, elapsed()); err != nil {
+				return err
+			}
+			lastCheckpoint = g.now()
+		}
+	}
+
+	if g.checkpoint != "" {
+		if err := sim.save(g.checkpoint, elapsed()); err != nil {
+			return err
+		}
+	}
+	if sim.results != nil {
+		if err := sim.results.close(); err != nil {
+			return err
+		}
+		g.printf(T("📄 Game results written to %s\n"), sim.Results)
+	}
+	if sim.decisions != nil {
+		if err := sim.decisions.close(); err != nil {
+			return err
+		}
+		g.printf(T("📄 Decisions written to %s\n"), sim.Decisions)
+	}
+
+	// The results are shown even when no game was left to play
+	g.SetSilentMode(false)
+	g.players = lineup
+	if sim.ScoresFile != "" {
+		if err := sim.Distributions.export(sim.ScoresFile, names); err != nil {
+			return err
+		}
+		g.printf(T("📊 Score distributions written to %s\n"), sim.ScoresFile)
+	}
+	if sim.Report != "" {
+		if err := sim.writeReport(g, sim.Report, names); err != nil {
+			return err
+		}
+		g.printf(T("📄 Report written to %s\n"), sim.Report)
+	}
+	g.displayGameStatistics(sim.gamesPlayed(len(lineup)), sim.Wins, names)
+	if sim.Dealers != nil {
+		g.displayDealerStatistics(sim.Dealers, sim.Dealer, names)
+	}
+	if sim.Seats != nil {
+		g.displaySeatStatistics(sim.Seats)
+	}
+	if sim.Actions != nil {
+		g.displayActionStatistics(sim.Actions)
+	}
+	if sim.Lengths != nil {
+		g.displayLengthStatistics(sim.Lengths)
+	}
+	if sim.Histograms {
+		g.displayScoreDistributions(sim.Distributions, names)
+	}
+	if sim.Notables != nil {
+		g.displayNotableGames(sim.Notables)
+	}
+	if sim.Params != "" {
+		g.displayParamVersions(sim, names)
+	}
+	g.displayLearning()
+	return nil
+}
+
+// playSimulatedGame plays the next game of the simulation silently
+func (g *Game) playSimulatedGame(ctx context.Context, sim *Simulation, lineup []PlayerInterface) error {
+	if sim.Rotate {
+		g.players = rotated(lineup, sim.Played)
+	}
+
+	// Each game deals from the next seed
+	g.resetGameState()
+	g.seedRandom(g.seed)
+	g.dealerIdx = firstDealer(sim.Dealer, sim.Played, g.seed, len(lineup))
+	sim.Seed = g.seed
+	sim.Notables.startGame(g.seed, (!sim.Rotate || sim.Played%len(lineup) == 0) && g.dealerIdx == 0)
+
+	g.SetSilentMode(true)
+	err := g.runSingleGame(ctx)
+	g.SetSilentMode(false)
+	if err != nil {
+		return fmt.Errorf("error in game %d: %v", sim.Played+1, err)
+	}
+
+	return g.recordSimulatedGame(sim, g.seed, 0)
+}
+
+// recordSimulatedGame adds the game just played to the simulation's
+// totals and results file
+func (g *Game) recordSimulatedGame(sim *Simulation, seed int64, seating int) error {
+	winner := g.getWinner()
+	dealer := firstDealer(sim.Dealer, sim.Played, seed, len(g.players))
+	for _, p := range g.winningSide(winner) {
+		sim.Wins[p.GetName()]++
+	}
+	sim.Rounds += g.round - 1
+	for _, p := range g.players {
+		sim.Scores[p.GetName()] += p.GetTotalScore()
+	}
+	if sim.Notables != nil {
+		sim.Notables.endGame(winner)
+	}
+	if sim.Dealers != nil {
+		sim.Dealers.recordGame(g, dealer, winner)
+	}
+	if sim.Distributions != nil {
+		sim.Distributions.recordGame(g)
+	}
+	if sim.Lengths != nil {
+		sim.Lengths.recordGame(g)
+	}
+	if sim.Seats != nil {
+		sim.Seats.recordGame(g, dealer, winner)
+	}
+	if sim.HeadToHead != nil {
+		sim.HeadToHead.recordGame(g)
+	}
+	if sim.Outcomes != nil {
+		sim.Outcomes.recordGame(g, winner)
+	}
+	sim.recordParams(winner)
+	game := sim.gamesPlayed(len(g.players)) + seating + 1
+	if sim.decisions != nil {
+		if err := sim.decisions.endGame(game, seed, winner); err != nil {
+			return err
+		}
+	}
+	if sim.results != nil {
+		result := g.gameResult(game, seed, seating, winner)
+		result.Params = sim.paramsVersion()
+		if sim.Dealer != DealerFixed {
+			result.Dealer = g.players[dealer].GetName()
+		}
+		return sim.results.write(result)
+	}
+	return nil
+}
+
+// gamesPlayed is how many games have been finished
+func (sim *Simulation) gamesPlayed(seats int) int {
+	return sim.Played * sim.gamesPerPlay(seats)
+}
+
+// gamesPerPlay is how many games each of Played counts for: a board
+// dealt to every seating in duplicate, or one game
+func (sim *Simulation) gamesPerPlay(seats int) int {
+	if sim.Duplicate {
+		return seats
+	}
+	return 1
+}
+
+// save writes the simulation to a checkpoint file. The file is replaced
+// in one step, so an interrupted save leaves the previous checkpoint.
+func (sim *Simulation) save(path string, elapsed time.Duration) error {
+	sim.Kind = checkpointKind
+	sim.Version = checkpointVersion
+	sim.SavedAt = time.Now()
+	sim.Elapsed = elapsed
+	if sim.results != nil {
+		size, err := sim.results.flush()
+		if err != nil {
+			return err
+		}
+		sim.ResultsSize = size
+	}
+	if sim.decisions != nil {
+		size, err := sim.decisions.flush()
+		if err != nil {
+			return err
+		}
+		sim.DecisionsSize = size
+	}
+
+	data, err := json.MarshalIndent(sim, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// IsCheckpoint reports whether path holds a simulation checkpoint rather
+// than a saved game
+func IsCheckpoint(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct{ Kind string }
+	return json.Unmarshal(data, &header) == nil && header.Kind == checkpointKind
+}
+
+// ResumeSimulation carries on with the simulation saved in a checkpoint
+// file, saving further progress back to it unless another checkpoint
+// file was set. A time budget set on the game is added to the time
+// already spent.
+func (g *Game) ResumeSimulation(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var sim Simulation
+	if err := json.Unmarshal(data, &sim); err != nil {
+		return fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	if sim.Version != checkpointVersion {
+		return fmt.Errorf("checkpoint %s has version %d, expected %d", path, sim.Version, checkpointVersion)
+	}
+	if sim.Wins == nil {
+		sim.Wins = make(map[string]int)
+	}
+	if sim.Scores == nil {
+		sim.Scores = make(map[string]int)
+	}
+	if sim.Notables != nil {
+		sim.Notables.deficits = make(map[PlayerInterface]int)
+	}
+	if g.budget > 0 {
+		sim.Budget = sim.Elapsed + g.budget
+	}
+	if g.stopRule != nil {
+		sim.Stop = g.stopRule
+	}
+	if sim.Results == "" {
+		sim.Results = g.results
+	}
+
+	players, err := g.restorePlayers(sim.Players)
+	if err != nil {
+		return fmt.Errorf("checkpoint %s %w", path, err)
+	}
+	for _, player := range players {
+		if _, ok := player.(*ComputerPlayer); !ok {
+			return fmt.Errorf("checkpoint %s: %s is not a computer player", path, player.GetName())
+		}
+	}
+	g.players = players
+	if g.checkpoint == "" {
+		g.checkpoint = path
+	}
+
+	g.printf(T("\n↩️ Resuming simulation: %d games played in %s\n"), sim.gamesPlayed(len(players)), sim.Elapsed.Round(time.Second))
+	g.SetSilentMode(true)
+	return g.simulate(ctx, &sim)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// BenchmarkSilentSimulation times a four-player game as simulations play
+// them, silent and sharing one GameState, and narrated to nowhere for
+// comparison. Silent games do
+// no formatting work, so they allocate far less.
+func BenchmarkSilentSimulation(b *testing.B) {
+	for _, silent := range []bool{true, false} {
+		name := "silent"
+		if !silent {
+			name = "narrated"
+		}
+		b.Run(name, func(b *testing.B) {
+			g := NewGame()
+			g.SetOutput(io.Discard)
+			g.SetCommentary(false)
+			g.SetSeed(1)
+			for i, choice := range []int{1, 2, 6, 9} {
+				spec := StrategySpec{Choice: choice, TargetScore: 25, BustProbability: 0.3}
+				g.players = append(g.players, NewComputerPlayerFromSpec(string(rune('A'+i)), spec))
+			}
+			g.SetSilentMode(silent)
+			defer g.poolState()()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := range b.N {
+				g.resetGameState()
+				g.deck.Seed(int64(i))
+				g.seedRandom(int64(i))
+				if err := g.runSingleGame(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// slackMessageLimit is how much game narrative one Slack message carries
+const slackMessageLimit = 3500
+
+// SlackBot plays Flip 7 in Slack channels. A slash command starts a
+// game, decisions are buttons in the channel, and each game is saved
+// after every answer so it can wait hours between them and survive a
+// restart.
+type SlackBot struct {
+	Token  string      // bot token, for the Web API
+	Secret string      // signing secret, to check requests come from Slack
+	Dir    string      // where games are saved
+	API    string      // Web API base URL
+	Turn   TurnTimeout // for each player's decision
+
+	client *http.Client
+	log    io.Writer
+
+	mu    sync.Mutex
+	games map[string]*slackGame
+}
+
+// slackSeat is a player in a Slack game: a Slack user, or a computer
+// strategy as duel names them
+type slackSeat struct {
+	Name     string `json:"name"`
+	User     string `json:"user,omitempty"`
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// slackGameState is a Slack game as saved. The game is dealt from Seed
+// and replayed through Answers, every decision made so far, so it picks
+// up exactly where it was.
+type slackGameState struct {
+	ID      string      `json:"id"`
+	Channel string      `json:"channel"`
+	Seed    int64       `json:"seed"`
+	Score   int         `json:"score"`
+	Seats   []slackSeat `json:"seats"`
+	Answers []int       `json:"answers"`
+	Done    bool        `json:"done"`
+	Started time.Time   `json:"started"`
+}
+
+// slackGame is a game being played in a channel
+type slackGame struct {
+	bot       *SlackBot
+	state     slackGameState
+	game      *Game
+	narrative bytes.Buffer
+
+	mu     sync.Mutex
+	asked  int // decisions asked for so far, replayed ones included
+	prompt *slackPrompt
+}
+
+// slackPrompt is the decision a game is waiting on
+type slackPrompt struct {
+	seq     int
+	user    string
+	labels  []string
+	answers chan int
+}
+
+// SlackPlayer is a seat played by a Slack user through buttons
+type SlackPlayer struct {
+	BasePlayer
+	user string
+	game *slackGame
+}
+
+// GetPlayerIcon marks a Slack user's seat
+func (p *SlackPlayer) GetPlayerIcon() string {
+	return "👤"
+}
+
+// MakeHitStayDecision asks the user to hit or stay, sending them their
+// hand and chance of busting privately
+func (p *SlackPlayer) MakeHitStayDecision(ctx context.Context, gameState *GameState) (bool, error) {
+	private := fmt.Sprintf(T("Your hand: %v, %d points this round"), p.GetHand(), p.CalculateRoundScore())
+	if gameState.Unseen().Total > 0 {
+		private += fmt.Sprintf(T(", %.0f%% chance to bust if you hit"), 100*CalculateBustProbability(p, gameState))
+	}
+	question := fmt.Sprintf(T("<@%s>, do you want to hit or stay?"), p.user)
+	fallback := 1
+	if p.game.bot.Turn.Hit {
+		fallback = 0
+	}
+	choice, err := p.game.ask(ctx, p.user, question, private, []string{T("Hit"), T("Stay")}, fallback)
+	return choice == 0, err
+}
+
+// ChooseActionCardToPlay asks the user whether to play one of their held
+// action cards, keeping them if their time runs out
+func (p *SlackPlayer) ChooseActionCardToPlay(ctx context.Context, gameState *GameState) (*Card, error) {
+	held := p.HeldActions()
+	labels := []string{T("Not now")}
+	for _, card := range held {
+		labels = append(labels, card.String())
+	}
+	question := fmt.Sprintf(T("<@%s>, do you want to play a held action card?"), p.user)
+	choice, err := p.game.ask(ctx, p.user, question, "", labels, 0)
+	if err != nil || choice == 0 {
+		return nil, err
+	}
+	return held[choice-1], nil
+}
+
+// DecideAction asks the user who gets an action card
+func (p *SlackPlayer) DecideAction(ctx context.Context, gameState *GameState, decision ActionDecision) (PlayerInterface, error) {
+	targets := decision.Targets
+	labels := make([]string, len(targets))
+	for i, player := range targets {
+		labels[i] = player.GetName()
+	}
+	fallback := max(slices.Index(targets, p.game.bot.Turn.ChooseTarget(p, gameState, decision.Action)), 0)
+	choice, err := p.game.ask(ctx, p.user, fmt.Sprintf("<@%s>, %s", p.user, decision.Question()), decision.Hint(), labels, fallback)
+	if err != nil {
+		return nil, err
+	}
+	return targets[choice], nil
+}
+
+// RunSlack handles the slack subcommand: it serves a Slack app's slash
+// command and interactivity requests, resuming any saved games first
+func RunSlack(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("slack", flag.ContinueOnError)
+	addr := fs.String("addr", ":3000", "Address to serve Slack's requests on")
+	dir := fs.String("dir", "slack-games", "Directory games are saved in")
+	api := fs.String("api", "https://slack.com/api", "Slack Web API base URL")
+	turn := fs.Duration("turn", 0, "Time a player has for each decision, e.g. 24h (0 waits as long as it takes)")
+	onTimeout := fs.String("on-timeout", "stay,leader", "What's decided for a player whose time runs out: stay or hit, and leader, last or random")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	timeout, err := ParseTurnTimeout(*turn, *onTimeout)
+	if err != nil {
+		return err
+	}
+	bot := &SlackBot{
+		Token:  os.Getenv("SLACK_BOT_TOKEN"),
+		Secret: os.Getenv("SLACK_SIGNING_SECRET"),
+		Dir:    *dir,
+		API:    *api,
+		Turn:   timeout,
+		client: &http.Client{Timeout: 10 * time.Second},
+		log:    out,
+		games:  make(map[string]*slackGame),
+	}
+	if bot.Token == "" || bot.Secret == "" {
+		return fmt.Errorf("set SLACK_BOT_TOKEN and SLACK_SIGNING_SECRET to the app's bot token and signing secret")
+	}
+	if err := os.MkdirAll(bot.Dir, 0o755); err != nil {
+		return err
+	}
+	if err := bot.resume(); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/commands", bot.handleCommand)
+	mux.HandleFunc("/slack/interactions", bot.handleInteraction)
+	fmt.Fprintf(out, "Serving Slack requests at %s (/slack/commands, /slack/interactions)\n", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+// resume restarts every unfinished game saved in the bot's directory
+func (b *SlackBot) resume() error {
+	paths, err := filepath.Glob(filepath.Join(b.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var state slackGameState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if !state.Done {
+			if err := b.start(state); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// verify checks a request's signature against the signing secret and
+// returns its body
+func (b *SlackBot) verify(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	ts, err := strconv.ParseInt(r.Header.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)).Abs() > 5*time.Minute {
+		return nil, fmt.Errorf("stale or missing timestamp")
+	}
+	mac := hmac.New(sha256.New, []byte(b.Secret))
+	fmt.Fprintf(mac, "v0:%d:%s", ts, body)
+	want := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(want), []byte(r.Header.Get("X-Slack-Signature"))) {
+		return nil, fmt.Errorf("bad signature")
+	}
+	return body, nil
+}
+
+// slackMention matches a user mention in a slash command, <@U123|name>
+var slackMention = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|([^>]+))?>$`)
+
+// handleCommand starts a game from a slash command such as
+// "/flip7 @alice @bob expert 100": the caller and the mentioned users
+// play, with a computer for each strategy named, to the score given
+func (b *SlackBot) handleCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := b.verify(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply := func(inChannel bool, text string) {
+		kind := "ephemeral"
+		if inChannel {
+			kind = "in_channel"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"response_type": kind, "text": text})
+	}
+
+	state := slackGameState{
+		Channel: form.Get("channel_id"),
+		Seed:    time.Now().UnixNano(),
+		Score:   200,
+		Seats:   []slackSeat{{Name: cmp.Or(form.Get("user_name"), form.Get("user_id")), User: form.Get("user_id")}},
+		Started: time.Now(),
+	}
+	state.ID = strconv.FormatInt(state.Seed, 36)
+	users := map[string]bool{form.Get("user_id"): true}
+	bots := 0
+	for _, word := range strings.Fields(form.Get("text")) {
+		if m := slackMention.FindStringSubmatch(word); m != nil {
+			if !users[m[1]] {
+				users[m[1]] = true
+				state.Seats = append(state.Seats, slackSeat{Name: cmp.Or(m[2], m[1]), User: m[1]})
+			}
+			continue
+		}
+		if n, err := strconv.Atoi(word); err == nil && n > 0 {
+			state.Score = n
+			continue
+		}
+		if _, err := parseStrategy("", word); err != nil {
+			reply(false, fmt.Sprintf(T("Usage: /flip7 [@players] [computer strategies] [winning score]\n%v"), err))
+			return
+		}
+		bots++
+		state.Seats = append(state.Seats, slackSeat{Name: fmt.Sprintf("Bot %d", bots), Strategy: word})
+	}
+	if len(state.Seats) < 2 {
+		reply(false, T("Usage: /flip7 [@players] [computer strategies] [winning score]\nMention at least one other player or name a computer strategy, e.g. /flip7 @alice expert"))
+		return
+	}
+	if err := b.start(state); err != nil {
+		reply(false, err.Error())
+		return
+	}
+	reply(true, fmt.Sprintf(T("🎮 Flip 7 game %s started, first to %d points. Answer when the buttons ask you."), state.ID, state.Score))
+}
+
+// handleInteraction takes a button press: the answer to a game's
+// decision, if it's the decision the game is waiting on and the presser
+// is the one it's asking
+func (b *SlackBot) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := b.verify(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var payload struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+		Actions []struct {
+			Value string `json:"value"`
+		} `json:"actions"`
+		ResponseURL string `json:"response_url"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil || len(payload.Actions) == 0 {
+		http.Error(w, "expected a button press", http.StatusBadRequest)
+		return
+	}
+	// Slack wants an answer within three seconds; the game carries on
+	// after it
+	w.WriteHeader(http.StatusOK)
+
+	var id string
+	var seq, choice int
+	fields := strings.Split(payload.Actions[0].Value, ":")
+	if len(fields) == 3 {
+		id = fields[0]
+		seq, _ = strconv.Atoi(fields[1])
+		choice, _ = strconv.Atoi(fields[2])
+	}
+	b.mu.Lock()
+	sg := b.games[id]
+	b.mu.Unlock()
+	if sg == nil {
+		go b.respond(payload.ResponseURL, false, T("That game is over."))
+		return
+	}
+
+	sg.mu.Lock()
+	prompt := sg.prompt
+	switch {
+	case prompt == nil || prompt.seq != seq:
+		sg.mu.Unlock()
+		go b.respond(payload.ResponseURL, false, T("That decision has already been made."))
+	case prompt.user != payload.User.ID:
+		sg.mu.Unlock()
+		go b.respond(payload.ResponseURL, false, fmt.Sprintf(T("It's <@%s>'s decision, not yours."), prompt.user))
+	case choice < 0 || choice >= len(prompt.labels):
+		sg.mu.Unlock()
+	default:
+		sg.prompt = nil
+		sg.mu.Unlock()
+		prompt.answers <- choice
+		go b.respond(payload.ResponseURL, true, fmt.Sprintf(T("<@%s> chose %s"), prompt.user, prompt.labels[choice]))
+	}
+}
+
+// start sets up a game from its saved state and plays it in the
+// background, replaying its answers so far
+func (b *SlackBot) start(state slackGameState) error {
+	sg := &slackGame{bot: b, state: state}
+	g := NewGame()
+	g.SetOutput(sg)
+	g.SetRandom(rand.New(rand.NewSource(state.Seed)))
+	g.SetWinningScore(state.Score)
+	g.deck.Seed(state.Seed)
+	for _, seat := range state.Seats {
+		if seat.User != "" {
+			p := &SlackPlayer{user: seat.User, game: sg}
+			p.BasePlayer.Init(seat.Name)
+			g.players = append(g.players, p)
+			continue
+		}
+		p, err := newStrategyPlayer(seat.Name, seat.Strategy)
+		if err != nil {
+			return err
+		}
+		g.players = append(g.players, p)
+	}
+	g.resetGameState()
+	g.dealerIdx = firstDealer(DealerRandom, 0, state.Seed, len(g.players))
+	sg.game = g
+	if err := sg.save(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.games[state.ID] = sg
+	b.mu.Unlock()
+	go func() {
+		err := g.Run(context.Background())
+		sg.flush()
+		if err != nil {
+			fmt.Fprintf(b.log, "Slack game %s: %v\n", state.ID, err)
+			return
+		}
+		sg.state.Done = true
+		if err := sg.save(); err != nil {
+			fmt.Fprintf(b.log, "Slack game %s: %v\n", state.ID, err)
+		}
+		b.mu.Lock()
+		delete(b.games, state.ID)
+		b.mu.Unlock()
+	}()
+	return nil
+}
+
+// Write collects the game's narrative until the next decision, leaving
+// out what's being replayed since the channel has already seen it
+func (sg *slackGame) Write(p []byte) (int, error) {
+	sg.mu.Lock()
+	replaying := sg.asked < len(sg.state.Answers)
+	sg.mu.Unlock()
+	if !replaying {
+		sg.narrative.Write(p)
+	}
+	return len(p), nil
+}
+
+// flush posts the narrative so far to the channel
+func (sg *slackGame) flush() {
+	text := strings.TrimSpace(sg.narrative.String())
+	sg.narrative.Reset()
+	if text == "" {
+		return
+	}
+	if len(text) > slackMessageLimit {
+		text = "…" + text[len(text)-slackMessageLimit:]
+	}
+	sg.bot.post("chat.postMessage", map[string]any{"channel": sg.state.Channel, "text": "```\n" + text + "\n```"})
+}
+
+// ask puts a decision to a user and waits for their answer, which is
+// saved with the game. Decisions already answered are replayed. If the
+// user's time runs out, the fallback label is chosen for them.
+func (sg *slackGame) ask(ctx context.Context, user, question, private string, labels []string, fallback int) (int, error) {
+	sg.mu.Lock()
+	seq := sg.asked
+	sg.asked++
+	if seq < len(sg.state.Answers) {
+		sg.mu.Unlock()
+		return sg.state.Answers[seq], nil
+	}
+	prompt := &slackPrompt{seq: seq, user: user, labels: labels, answers: make(chan int, 1)}
+	sg.prompt = prompt
+	sg.mu.Unlock()
+
+	sg.flush()
+	if private != "" {
+		sg.bot.post("chat.postEphemeral", map[string]any{"channel": sg.state.Channel, "user": user, "text": private})
+	}
+	var buttons []map[string]any
+	for i, label := range labels {
+		buttons = append(buttons, map[string]any{
+			"type":      "button",
+			"text":      map[string]string{"type": "plain_text", "text": label},
+			"action_id": fmt.Sprintf("choice-%d", i),
+			"value":     fmt.Sprintf("%s:%d:%d", sg.state.ID, seq, i),
+		})
+	}
+	sg.bot.post("chat.postMessage", map[string]any{
+		"channel": sg.state.Channel,
+		"text":    question,
+		"blocks": []any{
+			map[string]any{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": question}},
+			map[string]any{"type": "actions", "elements": buttons},
+		},
+	})
+
+	turn, cancel := sg.bot.Turn.Context(ctx)
+	defer cancel()
+	var choice int
+	select {
+	case choice = <-prompt.answers:
+	case <-turn.Done():
+		sg.mu.Lock()
+		if sg.prompt != prompt {
+			// Answered as time ran out
+			sg.mu.Unlock()
+			choice = <-prompt.answers
+			break
+		}
+		sg.prompt = nil
+		sg.mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		choice = fallback
+		sg.bot.post("chat.postMessage", map[string]any{"channel": sg.state.Channel,
+			"text": fmt.Sprintf(T("⏰ <@%s> ran out of time: %s"), user, labels[fallback])})
+	}
+	sg.mu.Lock()
+	sg.state.Answers = append(sg.state.Answers, choice)
+	sg.mu.Unlock()
+	return choice, sg.save()
+}
+
+// save writes the game's state to the bot's directory
+func (sg *slackGame) save() error {
+	sg.mu.Lock()
+	data, err := json.MarshalIndent(sg.state, "", "  ")
+	sg.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(sg.bot.Dir, sg.state.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// post calls a Slack Web API method, logging any failure
+func (b *SlackBot) post(method string, body any) {
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, b.API+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintf(b.log, "Slack %s: %v\n", method, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+b.Token)
+	resp, err := b.client.Do(req)
+	if err != nil {
+		fmt.Fprintf(b.log, "Slack %s: %v\n", method, err)
+		return
+	}
+	defer resp.Body.Close()
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.OK {
+		fmt.Fprintf(b.log, "Slack %s: %s %v\n", method, result.Error, err)
+	}
+}
+
+// respond answers a button press through its response URL, replacing
+// the buttons or, for a press that didn't count, telling only the presser
+func (b *SlackBot) respond(responseURL string, replace bool, text string) {
+	body := map[string]any{"text": text, "replace_original": replace}
+	if !replace {
+		body["response_type"] = "ephemeral"
+	}
+	data, _ := json.Marshal(body)
+	resp, err := b.client.Post(responseURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		fmt.Fprintf(b.log, "Slack response: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+}
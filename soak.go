@@ -0,0 +1,270 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// soakProgressInterval is how often a soak test reports how it's going
+const soakProgressInterval = 10 * time.Minute
+
+// soakPlayer is a player in a soak setup file, as a config file takes
+// it, with only the settings the soak test varies
+type soakPlayer struct {
+	Name            string  `yaml:"name"`
+	Strategy        string  `yaml:"strategy"`
+	TargetScore     int     `yaml:"target_score,omitempty"`
+	BustProbability float64 `yaml:"bust_probability,omitempty"`
+	GapTolerance    int     `yaml:"gap_tolerance,omitempty"`
+	SlackFactor     int     `yaml:"slack_factor,omitempty"`
+	RiskAversion    float64 `yaml:"risk_aversion,omitempty"`
+	Noise           float64 `yaml:"noise,omitempty"`
+	Target          string  `yaml:"target,omitempty"`
+	Gift            string  `yaml:"gift,omitempty"`
+	FlipThree       string  `yaml:"flip_three,omitempty"`
+	Team            string  `yaml:"team,omitempty"`
+}
+
+// soakSetup is the setup of one soak game as it's saved when the game
+// breaks an invariant. It reads as a config file, so the game can be
+// watched with -config as well as replayed with soak -replay.
+type soakSetup struct {
+	Seed    int64        `yaml:"seed"`
+	Rules   RulesConfig  `yaml:"rules"`
+	Players []soakPlayer `yaml:"players"`
+}
+
+// RunSoak handles the soak subcommand. It keeps playing randomly set up
+// games for as long as it's given, with every rule invariant checked
+// after every event as fuzz checks them. Where fuzz varies the lineup,
+// a soak test varies the rules as well: the winning score, the win
+// condition, house scoring rules, holding action cards, when the deck
+// reshuffles, burning cards and the cards in the deck, difficulty
+// presets and target strategies, and every strategy. Each violation is
+// logged with its seed, and the game's setup is saved to a file that
+// replays it.
+func RunSoak(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("soak", flag.ContinueOnError)
+	hours := fs.Float64("hours", 1, "How long to keep playing, in hours")
+	count := fs.Int("n", 0, "Stop after this many games, even with time left (0 plays until the time is up)")
+	seed := fs.Int64("seed", 0, "Seed for the first game; game i uses seed+i (0 uses the clock)")
+	dir := fs.String("dir", "soak", "Directory to save the setup of each game that breaks an invariant")
+	replay := fs.String("replay", "", "Replay one saved setup with the invariant checks, instead of soaking")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *replay != "" {
+		return replaySoak(*replay, out)
+	}
+	if *hours <= 0 {
+		return fmt.Errorf("-hours must be positive")
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	start := time.Now()
+	deadline := start.Add(time.Duration(*hours * float64(time.Hour)))
+	lastProgress := start
+	games, failures := 0, 0
+	for time.Now().Before(deadline) && (*count == 0 || games < *count) {
+		gameSeed := *seed + int64(games)
+		games++
+		setup := newSoakSetup(gameSeed)
+		if err := setup.play(); err != nil {
+			failures++
+			fmt.Fprintf(out, "FAIL seed %d: %v\n", gameSeed, err)
+			fmt.Fprintf(out, "     reproduce with: flip7 soak -seed %d -n 1\n", gameSeed)
+			if *dir != "" {
+				path, err := setup.save(*dir)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "     setup saved to %s\n", path)
+			}
+		}
+		if now := time.Now(); now.Sub(lastProgress) >= soakProgressInterval {
+			fmt.Fprintf(out, "soak: %d games, %d failures (%s)\n", games, failures, now.Sub(start).Round(time.Second))
+			lastProgress = now
+		}
+	}
+
+	fmt.Fprintf(out, "%d games in %s, %d failures (first seed %d)\n",
+		games, time.Since(start).Round(time.Second), failures, *seed)
+	if failures > 0 {
+		return fmt.Errorf("%d of %d games broke an invariant", failures, games)
+	}
+	return nil
+}
+
+// newSoakSetup sets up a random game from seed: 2 to 18 players with
+// random strategies, and random rules
+func newSoakSetup(seed int64) *soakSetup {
+	r := rand.New(rand.NewSource(seed))
+	s := &soakSetup{Seed: seed}
+	s.Rules.WinningScore = 50 + r.Intn(251)
+	s.Rules.HoldActions = r.Intn(3) == 0
+	s.Rules.Reshuffle = reshufflePolicies[r.Intn(len(reshufflePolicies))]
+	if r.Intn(4) == 0 {
+		s.Rules.Burn = 1 + r.Intn(3)
+	}
+	switch r.Intn(6) {
+	case 0:
+		s.Rules.Win = "rounds"
+		s.Rules.Rounds = 1 + r.Intn(10)
+	case 1:
+		s.Rules.Win = "elimination"
+	case 2:
+		s.Rules.Win = "team"
+	}
+	if r.Intn(3) == 0 {
+		s.Rules.Scoring = &VariantScorer{StackMultipliers: r.Intn(2) == 0}
+		if r.Intn(2) == 0 {
+			bonus := r.Intn(31)
+			s.Rules.Scoring.Flip7Bonus = &bonus
+		}
+	}
+	if r.Intn(6) == 0 {
+		s.Rules.Cards = CardSet{"12": r.Intn(13), "x2": r.Intn(4), "freeze": r.Intn(7)}
+	}
+
+	numPlayers := 2 + r.Intn(17)
+	targets := targetNames()
+	for i := range numPlayers {
+		p := soakPlayer{Name: fmt.Sprintf("P%d", i+1)}
+		if r.Intn(8) == 0 {
+			p.Strategy = difficulties[r.Intn(len(difficulties))].Name
+		} else {
+			spec := randomSpec(r)
+			// imitate needs a corpus of recorded games, so it's left out
+			spec.Choice = 1 + r.Intn(len(strategyNames)-1)
+			p.Strategy, _, _ = strings.Cut(spec.strategyName(), ":")
+			p.Noise = spec.Noise
+			switch spec.Choice {
+			case 1, 11:
+				p.TargetScore = spec.TargetScore
+			case 2:
+				p.BustProbability = spec.BustProbability
+			case 14:
+				p.RiskAversion = 0.5 + float64(r.Intn(20))/2
+			}
+			if spec.Choice == 11 {
+				p.GapTolerance = spec.GapTolerance
+				p.SlackFactor = spec.SlackFactor
+			}
+		}
+		if r.Intn(4) == 0 {
+			p.Target = targets[r.Intn(len(targets))]
+		}
+		if r.Intn(4) == 0 {
+			p.Gift = targets[r.Intn(len(targets))]
+		}
+		if r.Intn(4) == 0 {
+			p.FlipThree = targets[r.Intn(len(targets))]
+		}
+		if s.Rules.Win == "team" {
+			p.Team = string(rune('A' + r.Intn(3)))
+		}
+		s.Players = append(s.Players, p)
+	}
+	return s
+}
+
+// config returns the setup as a config
+func (s *soakSetup) config() *Config {
+	c := &Config{Seed: s.Seed, Rules: s.Rules}
+	for _, p := range s.Players {
+		c.Players = append(c.Players, PlayerConfig{
+			Name:            p.Name,
+			Strategy:        p.Strategy,
+			TargetScore:     p.TargetScore,
+			BustProbability: p.BustProbability,
+			GapTolerance:    p.GapTolerance,
+			SlackFactor:     p.SlackFactor,
+			RiskAversion:    p.RiskAversion,
+			Noise:           p.Noise,
+			Target:          p.Target,
+			Gift:            p.Gift,
+			FlipThree:       p.FlipThree,
+			Team:            p.Team,
+		})
+	}
+	return c
+}
+
+// play plays the setup's game silently with the invariant checks, and
+// reports the first broken invariant, engine error or panic
+func (s *soakSetup) play() (err error) {
+	c := s.config()
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid setup:\n%w", err)
+	}
+
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	g.SetSilentMode(true)
+	g.SetCommentary(false)
+	if err := c.Apply(g); err != nil {
+		return err
+	}
+	g.Subscribe(fuzzInvariants(g))
+
+	win := cmp.Or(c.Rules.Win, "points")
+	where := func() string {
+		return fmt.Sprintf("%d players, %s, round %d", len(g.players), win, g.round)
+	}
+	defer func() {
+		switch p := recover().(type) {
+		case nil:
+		case invariantError:
+			err = fmt.Errorf("%s: %w", where(), p)
+		default:
+			err = fmt.Errorf("%s: panic: %v", where(), p)
+		}
+	}()
+	if err := g.Run(context.Background()); err != nil {
+		return fmt.Errorf("%s: %w", where(), err)
+	}
+	return nil
+}
+
+// save writes the setup to dir, named for its seed, and returns the path
+func (s *soakSetup) save(dir string) (string, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("soak-%d.yaml", s.Seed))
+	return path, os.WriteFile(path, data, 0o644)
+}
+
+// replaySoak plays a saved soak setup once with the invariant checks
+func replaySoak(path string, out io.Writer) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var s soakSetup
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("reading soak setup %s: %w", path, err)
+	}
+	if err := s.play(); err != nil {
+		fmt.Fprintf(out, "FAIL seed %d: %v\n", s.Seed, err)
+		return fmt.Errorf("%s broke an invariant", path)
+	}
+	fmt.Fprintf(out, "ok   seed %d: no invariant broken\n", s.Seed)
+	return nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"strings"
+	"sync"
+)
+
+// solvedTableVersion is the format of the tables the solver writes
+const solvedTableVersion = 1
+
+// SolvedTable is the solver's hit/stay decision for every hand a player
+// can hold in a round. Hit is indexed by the held numbers as a bitmask, as
+// HeldNumbers returns them; bit m of an entry is set when the player
+// should hit holding the modifier cards in mask m, bit n for modifier n.
+type SolvedTable struct {
+	Version int
+	Seen    string   `json:",omitempty"`
+	Value   float64  // expected round score from an empty hand
+	Hit     []uint64 `json:"Hit"`
+}
+
+// solver works out by expectimax the play that maximises a lone player's
+// expected round score, given the cards left in the deck when the round
+// starts. Action cards are left out: drawing one doesn't change the hand,
+// so the draw is as good as drawing again. Opponents' draws and the score
+// needed to win are ignored too; the table is the best single-round play.
+type solver struct {
+	numbers   [13]int // copies of each number in the deck
+	modifiers uint8   // modifier cards in the deck, bit n for modifier n
+	value     []float64
+	solved    []bool
+	hit       []uint64
+}
+
+// newSolver creates a solver for a fresh deck without the seen cards
+func newSolver(seen []*Card) (*solver, error) {
+	s := &solver{
+		value:  make([]float64, 1<<19),
+		solved: make([]bool, 1<<19),
+		hit:    make([]uint64, 1<<13),
+	}
+	for value := range s.numbers {
+		s.numbers[value] = numberCardCopies(value)
+	}
+	s.modifiers = 1<<6 - 1
+	for _, c := range seen {
+		switch c.Type {
+		case NumberCard:
+			if s.numbers[c.Value] == 0 {
+				return nil, fmt.Errorf("seen more %d cards than the deck has", c.Value)
+			}
+			s.numbers[c.Value]--
+		case ModifierCard:
+			bit := uint8(1) << c.Modifier
+			if s.modifiers&bit == 0 {
+				return nil, fmt.Errorf("seen more %s cards than the deck has", formatCard(c))
+			}
+			s.modifiers &^= bit
+		}
+	}
+	return s, nil
+}
+
+// handScore scores held numbers and modifiers as ScoreHand does
+func handScore(numbers uint16, modifiers uint8) int {
+	total := 0
+	for v := range 13 {
+		if numbers&(1<<v) != 0 {
+			total += v
+		}
+	}
+	if modifiers&(1<<Multiply2) != 0 {
+		total *= 2
+	}
+	for m := Plus2; m <= Plus10; m++ {
+		if modifiers&(1<<m) != 0 {
+			total += NewModifierCard(m).GetPoints()
+		}
+	}
+	if bits.OnesCount16(numbers) == 7 {
+		total += flip7Bonus
+	}
+	return total
+}
+
+// solve returns the expected round score of the hand with best play,
+// noting whether to hit in the table
+func (s *solver) solve(numbers uint16, modifiers uint8) float64 {
+	key := int(numbers)<<6 | int(modifiers)
+	if s.solved[key] {
+		return s.value[key]
+	}
+
+	total := 0
+	for v, n := range s.numbers {
+		if numbers&(1<<v) != 0 {
+			n--
+		}
+		total += n
+	}
+	left := s.modifiers &^ modifiers
+	total += bits.OnesCount8(left)
+
+	stay := float64(handScore(numbers, modifiers))
+	best := stay
+	if total > 0 {
+		hit := 0.0
+		for v, n := range s.numbers {
+			if numbers&(1<<v) != 0 || n == 0 {
+				continue // a duplicate busts for nothing
+			}
+			held := numbers | 1<<v
+			if bits.OnesCount16(held) == 7 {
+				hit += float64(n) * float64(handScore(held, modifiers))
+			} else {
+				hit += float64(n) * s.solve(held, modifiers)
+			}
+		}
+		for m := range 6 {
+			if left&(1<<m) != 0 {
+				hit += s.solve(numbers, modifiers|1<<m)
+			}
+		}
+		hit /= float64(total)
+		// A player with no numbers can't stay
+		if hit > stay || numbers == 0 {
+			best = hit
+			s.hit[numbers] |= 1 << modifiers
+		}
+	}
+
+	s.solved[key] = true
+	s.value[key] = best
+	return best
+}
+
+// Solve builds the hit/stay table for a fresh deck without the seen cards
+func Solve(seen []*Card) (*SolvedTable, error) {
+	s, err := newSolver(seen)
+	if err != nil {
+		return nil, err
+	}
+	value := s.solve(0, 0)
+	var names []string
+	for _, c := range seen {
+		names = append(names, formatCard(c))
+	}
+	return &SolvedTable{Version: solvedTableVersion, Seen: strings.Join(names, " "), Value: value, Hit: s.hit}, nil
+}
+
+// ShouldHit looks up the table's decision for a hand
+func (t *SolvedTable) ShouldHit(numbers uint16, modifiers uint8) bool {
+	return t.Hit[numbers]&(1<<modifiers) != 0
+}
+
+// LoadSolvedTable reads a table the solve subcommand wrote
+func LoadSolvedTable(path string) (*SolvedTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t SolvedTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if t.Version != solvedTableVersion || len(t.Hit) != 1<<13 {
+		return nil, fmt.Errorf("%s: not a solved table (version %d)", path, t.Version)
+	}
+	return &t, nil
+}
+
+// solvedTables caches tables by path, "" for the fresh deck's, so every
+// player using one shares it
+var solvedTables = struct {
+	sync.Mutex
+	tables map[string]*SolvedTable
+}{tables: make(map[string]*SolvedTable)}
+
+// solvedTable returns the table at path, solving the fresh deck's table
+// when path is ""
+func solvedTable(path string) (*SolvedTable, error) {
+	solvedTables.Lock()
+	defer solvedTables.Unlock()
+	if t := solvedTables.tables[path]; t != nil {
+		return t, nil
+	}
+	var t *SolvedTable
+	var err error
+	if path == "" {
+		t, err = Solve(nil)
+	} else {
+		t, err = LoadSolvedTable(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	solvedTables.tables[path] = t
+	return t, nil
+}
+
+// TableStrategy hits or stays as the solved table says for the hand
+func TableStrategy(table *SolvedTable) HitOrStayStrategy {
+	return func(self PlayerInterface, gameState *GameState) bool {
+		var modifiers uint8
+		for _, card := range self.GetHand() {
+			if card.Type == ModifierCard {
+				modifiers |= 1 << card.Modifier
+			}
+		}
+		return table.ShouldHit(self.HeldNumbers(), modifiers)
+	}
+}
+
+// RunSolve handles the solve subcommand: it works out the best hit/stay
+// play for every hand and writes it as a table the table strategy plays
+func RunSolve(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("solve", flag.ContinueOnError)
+	seen := fs.String("seen", "", "Cards already out of the deck, e.g. \"12 12 11 +4\"")
+	output := fs.String("o", "", "Write the table to this file as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cards, err := parseCards(*seen)
+	if err != nil {
+		return err
+	}
+	table, err := Solve(cards)
+	if err != nil {
+		return err
+	}
+
+	report := NewGame()
+	report.SetOutput(out)
+	report.displaySolvedTable(table)
+	if *output != "" {
+		data, err := json.Marshal(table)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*output, data, 0644); err != nil {
+			return err
+		}
+		report.printf(T("🧮 Table written to %s\n"), *output)
+	}
+	return nil
+}
+
+// displaySolvedTable summarises the table by how many numbers are held,
+// without modifiers: the highest hand that still hits and the lowest that
+// stays
+func (g *Game) displaySolvedTable(t *SolvedTable) {
+	deck := T("fresh deck")
+	if t.Seen != "" {
+		deck = fmt.Sprintf(T("seen: %s"), t.Seen)
+	}
+	g.resultf("\n%s\n", strings.Repeat("=", 60))
+	g.resultf(T("🧮 BEST SINGLE-ROUND PLAY (%s)\n"), deck)
+	g.resultf("%s\n", strings.Repeat("=", 60))
+	g.resultf(T("Expected round score with best play: %.2f\n"), t.Value)
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%-8s %8s %8s %12s %12s\n", T("NUMBERS"), T("HANDS"), T("HIT"), T("HIGHEST HIT"), T("LOWEST STAY"))
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	for held := 1; held <= 6; held++ {
+		hands, hits := 0, 0
+		highestHit, lowestStay := -1, math.MaxInt
+		for mask := range 1 << 13 {
+			if bits.OnesCount16(uint16(mask)) != held {
+				continue
+			}
+			hands++
+			score := handScore(uint16(mask), 0)
+			if t.ShouldHit(uint16(mask), 0) {
+				hits++
+				highestHit = max(highestHit, score)
+			} else {
+				lowestStay = min(lowestStay, score)
+			}
+		}
+		hit, stay := "-", "-"
+		if highestHit >= 0 {
+			hit = fmt.Sprint(highestHit)
+		}
+		if lowestStay < math.MaxInt {
+			stay = fmt.Sprint(lowestStay)
+		}
+		g.resultf("%-8d %8d %7.1f%% %12s %12s\n", held, hands, 100*float64(hits)/float64(hands), hit, stay)
+	}
+	g.resultf("%s\n", strings.Repeat("-", 60))
+	g.resultf("%s\n", T("Scores are without modifiers; the table covers every modifier too"))
+	g.resultf("%s\n", strings.Repeat("=", 60))
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// gameTracer writes the spans of every game the process plays once
+// tracing is on; new games pick it up from here
+var gameTracer *Tracer
+
+// Tracer writes game spans as OpenTelemetry traces, one OTLP/JSON
+// ExportTraceServiceRequest per line and game, the format the
+// OpenTelemetry Collector's otlpjsonfile receiver reads. Each game is
+// written as it ends, so the file is complete however the process exits.
+type Tracer struct {
+	mu  sync.Mutex
+	f   *os.File
+	ids *rand.Rand
+}
+
+// StartTracing writes the spans of every game played from now on to
+// the file at path
+func StartTracing(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gameTracer = &Tracer{f: f, ids: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	return nil
+}
+
+// id returns a random hex ID of n bytes. The tracer has its own source
+// so tracing doesn't change the games.
+func (t *Tracer) id(n int) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := make([]byte, n)
+	t.ids.Read(b)
+	return fmt.Sprintf("%x", b)
+}
+
+// Span is an OTLP/JSON span. Times are nanoseconds since the epoch,
+// written as strings as OTLP/JSON writes 64-bit integers.
+type Span struct {
+	TraceID      string      `json:"traceId"`
+	SpanID       string      `json:"spanId"`
+	ParentSpanID string      `json:"parentSpanId,omitempty"`
+	Name         string      `json:"name"`
+	Kind         int         `json:"kind"`
+	Start        string      `json:"startTimeUnixNano"`
+	End          string      `json:"endTimeUnixNano"`
+	Attributes   []Attribute `json:"attributes,omitempty"`
+	Status       *SpanStatus `json:"status,omitempty"`
+}
+
+// SpanStatus marks a span that ended in an error
+type SpanStatus struct {
+	Code    int    `json:"code"` // 2 is an error
+	Message string `json:"message"`
+}
+
+// Attribute is an OTLP/JSON key and value
+type Attribute struct {
+	Key   string         `json:"key"`
+	Value AttributeValue `json:"value"`
+}
+
+// AttributeValue holds one of an attribute's possible types
+type AttributeValue struct {
+	String *string  `json:"stringValue,omitempty"`
+	Int    *string  `json:"intValue,omitempty"`
+	Bool   *bool    `json:"boolValue,omitempty"`
+	Double *float64 `json:"doubleValue,omitempty"`
+}
+
+// attr makes an attribute from a string, int, bool or float64
+func attr(key string, value any) Attribute {
+	a := Attribute{Key: key}
+	switch v := value.(type) {
+	case string:
+		a.Value.String = &v
+	case int:
+		s := strconv.Itoa(v)
+		a.Value.Int = &s
+	case bool:
+		a.Value.Bool = &v
+	case float64:
+		a.Value.Double = &v
+	}
+	return a
+}
+
+// SpanRecorder collects the spans of the game being played: the game,
+// and the round, turn and decision in progress. A nil recorder records
+// nothing, so games without tracing pay only for the nil checks.
+type SpanRecorder struct {
+	tracer *Tracer
+	spans  []*Span
+
+	game, round, turn *Span
+}
+
+// start opens a span under parent, or a new trace without one
+func (r *SpanRecorder) start(name string, parent *Span, attrs ...Attribute) *Span {
+	s := &Span{
+		SpanID:     r.tracer.id(8),
+		Name:       name,
+		Kind:       1, // internal
+		Start:      strconv.FormatInt(time.Now().UnixNano(), 10),
+		Attributes: attrs,
+	}
+	if parent != nil {
+		s.TraceID = parent.TraceID
+		s.ParentSpanID = parent.SpanID
+	} else {
+		s.TraceID = r.tracer.id(16)
+	}
+	r.spans = append(r.spans, s)
+	return s
+}
+
+// end closes a span, adding attributes known only at the end
+func (r *SpanRecorder) end(s *Span, err error, attrs ...Attribute) {
+	s.End = strconv.FormatInt(time.Now().UnixNano(), 10)
+	s.Attributes = append(s.Attributes, attrs...)
+	if err != nil {
+		s.Status = &SpanStatus{Code: 2, Message: err.Error()}
+	}
+}
+
+// startGame opens the span of a game
+func (r *SpanRecorder) startGame(g *Game) {
+	if r == nil {
+		return
+	}
+	r.spans = r.spans[:0]
+	r.game = r.start("game", nil, attr("flip7.players", len(g.players)), attr("flip7.winning_score", g.winningScore))
+}
+
+// endGame closes the game's span and writes out the trace
+func (r *SpanRecorder) endGame(g *Game, err error) {
+	if r == nil || r.game == nil {
+		return
+	}
+	// Spans left open by an error end with the game
+	for _, s := range []*Span{r.turn, r.round} {
+		if s != nil && s.End == "" {
+			r.end(s, err)
+		}
+	}
+	var attrs []Attribute
+	if err == nil {
+		winner := g.getWinner()
+		attrs = append(attrs, attr("flip7.rounds", g.round-1), attr("flip7.winner", winner.GetName()),
+			attr("flip7.winner_strategy", strategyOf(winner)), attr("flip7.winner_score", winner.GetTotalScore()))
+	}
+	r.end(r.game, err, attrs...)
+	r.game, r.round, r.turn = nil, nil, nil
+
+	request := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{"attributes": []Attribute{attr("service.name", "flip7")}},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]string{"name": "flip7"},
+				"spans": r.spans,
+			}},
+		}},
+	}
+	data, _ := json.Marshal(request)
+	r.tracer.mu.Lock()
+	r.tracer.f.Write(append(data, '\n'))
+	r.tracer.mu.Unlock()
+}
+
+// startRound opens the span of a round
+func (r *SpanRecorder) startRound(g *Game) {
+	if r == nil || r.game == nil {
+		return
+	}
+	r.round = r.start("round", r.game, attr("flip7.round", g.round), attr("flip7.dealer", g.players[g.dealerIdx].GetName()))
+}
+
+// endRound closes the round's span
+func (r *SpanRecorder) endRound(g *Game, err error) {
+	if r == nil || r.round == nil {
+		return
+	}
+	r.end(r.round, err, attr("flip7.turns", g.turns))
+	r.round = nil
+}
+
+// startTurn opens the span of a player's turn
+func (r *SpanRecorder) startTurn(player PlayerInterface) {
+	if r == nil || r.round == nil {
+		return
+	}
+	r.turn = r.start("turn", r.round, attr("flip7.player", player.GetName()), attr("flip7.strategy", strategyOf(player)))
+}
+
+// endTurn closes the turn's span
+func (r *SpanRecorder) endTurn(player PlayerInterface, err error) {
+	if r == nil || r.turn == nil {
+		return
+	}
+	r.end(r.turn, err, attr("flip7.round_score", player.CalculateRoundScore()), attr("flip7.active", player.IsActive()))
+	r.turn = nil
+}
+
+// decision records a hit/stay decision that started at start as a span
+// of the turn
+func (r *SpanRecorder) decision(player PlayerInterface, state *GameState, start time.Time, hit bool) {
+	if r == nil || r.turn == nil {
+		return
+	}
+	s := r.start("decision", r.turn, attr("flip7.strategy", strategyOf(player)),
+		attr("flip7.hit", hit), attr("flip7.round_score", player.CalculateRoundScore()),
+		attr("flip7.total_score", player.GetTotalScore()), attr("flip7.cards_left", state.Unseen().Total))
+	if state.Unseen().Total > 0 {
+		s.Attributes = append(s.Attributes, attr("flip7.bust_probability", CalculateBustProbability(player, state)))
+	}
+	s.Start = strconv.FormatInt(start.UnixNano(), 10)
+	r.end(s, nil)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"slices"
+)
+
+// StackedDeck deals a fixed sequence of cards in order, for tests. Once
+// the sequence runs out the discards are dealt again in the order they
+// were thrown away, so every draw is predetermined. It never shuffles and
+// ignores debug mode.
+type StackedDeck struct {
+	*Deck
+	stack []*Card
+}
+
+// NewStackedDeck creates a deck that deals the given cards, first card
+// first. Only these cards are in play, and GameState.Unseen counts only
+// them.
+func NewStackedDeck(cards ...*Card) *StackedDeck {
+	d := &StackedDeck{
+		Deck: &Deck{
+			out:    os.Stdout,
+			logger: slog.New(slog.DiscardHandler),
+		},
+		stack: slices.Clone(cards),
+	}
+	d.Reset()
+	return d
+}
+
+// DrawCard deals the next card in the sequence, or nil if every card is
+// in a player's hand, or the reshuffle policy keeps the discards out
+// until the round is over
+func (d *StackedDeck) DrawCard() *Card {
+	if len(d.cards) == 0 && d.reshuffle == ReshuffleMidRound {
+		d.Reshuffle()
+	}
+	if len(d.cards) == 0 {
+		return nil
+	}
+
+	card := d.cards[len(d.cards)-1]
+	d.cards = d.cards[:len(d.cards)-1]
+	d.counts.add(card, -1)
+	if d.tracing() {
+		d.trace("deck draw", "card", card.String(), "cards_left", len(d.cards))
+	}
+	return card
+}
+
+// Reshuffle deals the discards again, in the order they were thrown away,
+// after the cards left
+func (d *StackedDeck) Reshuffle() {
+	recycled := slices.Clone(d.discards)
+	slices.Reverse(recycled)
+	d.cards = append(recycled, d.cards...)
+	d.discards = make([]*Card, 0)
+	d.discardCounts = DeckCounts{}
+	d.counts = countDeck(d.cards)
+	d.logger.Debug("deck recycle", "cards_left", len(d.cards))
+}
+
+// PeekTop returns the next n cards in the sequence before the discards
+// are dealt again, next first; a stacked deck deals in order even in
+// debug mode
+func (d *StackedDeck) PeekTop(n int) []*Card {
+	n = max(min(n, len(d.cards)), 0)
+	top := slices.Clone(d.cards[len(d.cards)-n:])
+	slices.Reverse(top)
+	return top
+}
+
+// BurnCard discards the next card in the sequence, or returns nil if
+// there's none to deal
+func (d *StackedDeck) BurnCard() *Card {
+	card := d.DrawCard()
+	if card != nil {
+		d.DiscardCard(card)
+	}
+	return card
+}
+
+// Shuffle does nothing, since the order is the point of a stacked deck
+func (d *StackedDeck) Shuffle() {}
+
+// Reset puts the whole sequence back on the deck
+func (d *StackedDeck) Reset() {
+	d.cards = slices.Clone(d.stack)
+	slices.Reverse(d.cards)
+	d.discards = make([]*Card, 0)
+	d.discardCounts = DeckCounts{}
+	d.counts = countDeck(d.cards)
+	d.OriginalTotal = len(d.cards)
+}
+
+// Seed resets the deck; a stacked deck deals the same cards whatever the
+// seed
+func (d *StackedDeck) Seed(seed int64) {
+	d.Reset()
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Standing is where a player stands in the game: their banked total,
+// their score in play this round, and their place counting both
+type Standing struct {
+	Player    PlayerInterface
+	Total     int
+	Round     int
+	Projected int // Total plus Round
+	Place     int // 1 for the leader; players level on both scores share it
+}
+
+// Needs returns how many more points the player needs to reach goal,
+// counting what they have in play; 0 once they're there
+func (s Standing) Needs(goal int) int {
+	return max(goal-s.Projected, 0)
+}
+
+// standingOf measures a player, counting their round score while it's
+// in play
+func standingOf(player PlayerInterface, inPlay bool) Standing {
+	s := Standing{Player: player, Total: player.GetTotalScore()}
+	if inPlay {
+		s.Round = player.CalculateRoundScore()
+	}
+	s.Projected = s.Total + s.Round
+	return s
+}
+
+// compareStandings orders a ahead of b on more projected points, then
+// more banked points. Players level on both compare equal, and keep
+// their seat order.
+func compareStandings(a, b Standing) int {
+	if a.Projected != b.Projected {
+		return b.Projected - a.Projected
+	}
+	return b.Total - a.Total
+}
+
+// Standings ranks players from first to last. inPlay counts the round
+// scores still on the table, as the game does while a round runs; leave
+// it off once they're banked into the totals.
+func Standings(players []PlayerInterface, inPlay bool) []Standing {
+	standings := make([]Standing, len(players))
+	for i, player := range players {
+		standings[i] = standingOf(player, inPlay)
+	}
+	slices.SortStableFunc(standings, compareStandings)
+	for i := range standings {
+		standings[i].Place = i + 1
+		if i > 0 && compareStandings(standings[i-1], standings[i]) == 0 {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+	return standings
+}
+
+// leaderOf returns who heads the standings of a round in play, the
+// first seated on a tie, without sorting them all. It's nil without
+// players.
+func leaderOf(players []PlayerInterface) PlayerInterface {
+	var leader PlayerInterface
+	var best Standing
+	for _, player := range players {
+		if s := standingOf(player, true); leader == nil || compareStandings(s, best) < 0 {
+			leader, best = player, s
+		}
+	}
+	return leader
+}
+
+// lastPlaceOf returns who trails the standings of a round in play, the
+// last seated on a tie. It's nil without players.
+func lastPlaceOf(players []PlayerInterface) PlayerInterface {
+	var last PlayerInterface
+	var worst Standing
+	for _, player := range players {
+		if s := standingOf(player, true); last == nil || compareStandings(s, worst) >= 0 {
+			last, worst = player, s
+		}
+	}
+	return last
+}
+
+// Standings ranks the players as the round stands
+func (s *GameState) Standings() []Standing {
+	if s.ranking == nil {
+		s.ranking = Standings(s.Players, true)
+	}
+	return s.ranking
+}
+
+// PointsNeededToOvertake returns how many more points player has to
+// score this round, on top of what they have in play, to pass the
+// current leader; 0 for the leader
+func (s *GameState) PointsNeededToOvertake(player PlayerInterface) int {
+	if s.CurrentLeader == nil || s.CurrentLeader == player {
+		return 0
+	}
+	return max(standingOf(s.CurrentLeader, true).Projected-standingOf(player, true).Projected+1, 0)
+}
+
+// rivals returns the players in the round that player may give an
+// action card to, other than themselves
+func (s *GameState) rivals(player PlayerInterface, actionType ActionType) []PlayerInterface {
+	return slices.DeleteFunc(slices.Clone(s.LegalTargets(player, actionType)), func(p PlayerInterface) bool {
+		return p == player
+	})
+}
+
+// SetLiveStandings shows the standings, with the points in play this
+// round, before every turn
+func (g *Game) SetLiveStandings(enabled bool) {
+	g.liveStandings = enabled
+}
+
+// playsToScore reports whether the game is won by reaching the winning
+// score, so the standings can say what each player still needs
+func (g *Game) playsToScore() bool {
+	switch g.condition().(type) {
+	case FirstToPoints, TeamPoints:
+		return true
+	}
+	return false
+}
+
+// showStandings prints every player's banked total and points in play,
+// best first, with what each still needs to reach the winning score
+func (g *Game) showStandings() {
+	g.print(g.standingsText())
+}
+
+// standingsText is the standings as showStandings prints them
+func (g *Game) standingsText() string {
+	var sb strings.Builder
+	sb.WriteString(T("📈 Standings:"))
+	sb.WriteString("\n")
+	for _, s := range Standings(g.players, true) {
+		fmt.Fprintf(&sb, T("  %d. %-20s %3d + %2d = %3d"), s.Place, s.Player.GetName(), s.Total, s.Round, s.Projected)
+		if g.playsToScore() {
+			if need := s.Needs(g.winningScore); need > 0 {
+				fmt.Fprintf(&sb, T(", needs %d"), need)
+			} else {
+				sb.WriteString(T(", enough to win"))
+			}
+		}
+		sb.WriteString("\n")
+	}
+	if g.endsNow() {
+		sb.WriteString(T("  🏁 The game ends if the round ends now"))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
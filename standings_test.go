@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// seat makes a computer player with total banked and hand on the table
+func seat(name string, total int, hand string) *ComputerPlayer {
+	p := NewComputerPlayer(name, AlwaysHitStrategy, TargetLeaderStrategy, TargetLeaderStrategy)
+	p.TotalScore = total
+	for _, card := range mustParseCards(hand) {
+		p.AddCard(card)
+	}
+	return p
+}
+
+// table seats players described as "name total hand...", one to an entry
+func table(players ...string) []PlayerInterface {
+	seated := make([]PlayerInterface, len(players))
+	for i, player := range players {
+		fields := strings.Fields(player)
+		total, err := strconv.Atoi(fields[1])
+		if err != nil {
+			panic(err)
+		}
+		seated[i] = seat(fields[0], total, strings.Join(fields[2:], " "))
+	}
+	return seated
+}
+
+// placings lists standings as "place.name total+round" from first to last
+func placings(standings []Standing) string {
+	var places []string
+	for _, s := range standings {
+		places = append(places, fmt.Sprintf("%d.%s %d+%d", s.Place, s.Player.GetName(), s.Total, s.Round))
+	}
+	return strings.Join(places, ", ")
+}
+
+// nameOf names a player, or nobody for nil
+func nameOf(p PlayerInterface) string {
+	if p == nil {
+		return "nobody"
+	}
+	return p.GetName()
+}
+
+func TestStandings(t *testing.T) {
+	tests := []struct {
+		name    string
+		players []PlayerInterface
+		inPlay  bool
+		want    string
+		leader  string
+		last    string
+	}{
+		{
+			name:    "more banked breaks a tie on projected points",
+			players: table("Ada 50 5", "Bo 55"),
+			inPlay:  true,
+			want:    "1.Bo 55+0, 2.Ada 50+5",
+			leader:  "Bo",
+			last:    "Ada",
+		},
+		{
+			name:    "level on both share a place in seat order",
+			players: table("Ada 30", "Bo 40 2", "Cy 30"),
+			inPlay:  true,
+			want:    "1.Bo 40+2, 2.Ada 30+0, 2.Cy 30+0",
+			leader:  "Bo",
+			last:    "Cy",
+		},
+		{
+			name:    "the round in play counts",
+			players: table("Ada 100", "Bo 90 12 11"),
+			inPlay:  true,
+			want:    "1.Bo 90+23, 2.Ada 100+0",
+			leader:  "Bo",
+			last:    "Ada",
+		},
+		{
+			name:    "modifiers count in the round score",
+			players: table("Ada 10 3 x2", "Bo 10 5 +2"),
+			inPlay:  true,
+			want:    "1.Bo 10+7, 2.Ada 10+6",
+			leader:  "Bo",
+			last:    "Ada",
+		},
+		{
+			name:    "banked totals alone once the round is off",
+			players: table("Ada 100", "Bo 90 12 11"),
+			inPlay:  false,
+			want:    "1.Ada 100+0, 2.Bo 90+0",
+			leader:  "Bo",
+			last:    "Ada",
+		},
+		{
+			name:    "an all-zero table",
+			players: table("Ada 0", "Bo 0", "Cy 0"),
+			inPlay:  true,
+			want:    "1.Ada 0+0, 1.Bo 0+0, 1.Cy 0+0",
+			leader:  "Ada",
+			last:    "Cy",
+		},
+		{
+			name:    "a lone player",
+			players: table("Ada 20 4"),
+			inPlay:  true,
+			want:    "1.Ada 20+4",
+			leader:  "Ada",
+			last:    "Ada",
+		},
+		{
+			name:    "no players",
+			players: nil,
+			inPlay:  true,
+			want:    "",
+			leader:  "nobody",
+			last:    "nobody",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := placings(Standings(tt.players, tt.inPlay)); got != tt.want {
+				t.Errorf("Standings: got %q, want %q", got, tt.want)
+			}
+			// leaderOf and lastPlaceOf always count the round in play
+			if got := nameOf(leaderOf(tt.players)); got != tt.leader {
+				t.Errorf("leaderOf: got %s, want %s", got, tt.leader)
+			}
+			if got := nameOf(lastPlaceOf(tt.players)); got != tt.last {
+				t.Errorf("lastPlaceOf: got %s, want %s", got, tt.last)
+			}
+		})
+	}
+}
+
+func TestCompareStandings(t *testing.T) {
+	tests := []struct {
+		a, b Standing
+		want int // the sign of the comparison
+	}{
+		{Standing{Total: 10, Projected: 20}, Standing{Total: 15, Projected: 15}, -1},
+		{Standing{Total: 10, Projected: 20}, Standing{Total: 20, Projected: 20}, 1},
+		{Standing{Total: 20, Projected: 20}, Standing{Total: 20, Projected: 20}, 0},
+		{Standing{}, Standing{}, 0},
+	}
+	for _, tt := range tests {
+		got := compareStandings(tt.a, tt.b)
+		if got > 0 {
+			got = 1
+		} else if got < 0 {
+			got = -1
+		}
+		if got != tt.want {
+			t.Errorf("compareStandings(%+v, %+v): got sign %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTargetStrategiesPickRivalsOnZero(t *testing.T) {
+	players := table("Ada 0", "Bo 0", "Cy 0")
+	state := &GameState{Players: players, ActivePlayers: players}
+	ada := players[0]
+	if got := nameOf(TargetLeaderStrategy(ada, state, Freeze)); got != "Bo" {
+		t.Errorf("TargetLeaderStrategy on an all-zero table: got %s, want Bo", got)
+	}
+	if got := nameOf(TargetLastPlaceStrategy(ada, state, FlipThree)); got != "Cy" {
+		t.Errorf("TargetLastPlaceStrategy on an all-zero table: got %s, want Cy", got)
+	}
+
+	alone := &GameState{Players: players[:1], ActivePlayers: players[:1]}
+	if got := nameOf(TargetLeaderStrategy(ada, alone, Freeze)); got != "Ada" {
+		t.Errorf("TargetLeaderStrategy with no rival: got %s, want Ada", got)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stopMinGames is how many games a simulation plays before its stopping
+// rule is checked. Margins from a handful of games are too rough to
+// trust, and a player who hasn't won yet would look certain.
+const stopMinGames = 100
+
+// separatedAlpha is the most often the separated rule may stop a race
+// between two evenly matched players, over all its looks
+const separatedAlpha = 0.05
+
+// StopRule ends a simulation once its results are as precise as asked:
+// when every player's win rate is known to within CI, two standard
+// errors either way, or when Separated is set and the top two players'
+// win rates are further apart than the difference's standard error
+// allows by chance. Either is enough.
+type StopRule struct {
+	CI        float64 `json:",omitempty"`
+	Separated bool    `json:",omitempty"`
+}
+
+// ParseStopRule reads a stopping rule as -stop-when takes it: "ci<1%"
+// or "ci<0.01" for the margin, "separated" for the top two, or both
+// separated by a comma
+func ParseStopRule(s string) (*StopRule, error) {
+	rule := &StopRule{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term == "separated" {
+			rule.Separated = true
+			continue
+		}
+		value, ok := strings.CutPrefix(term, "ci<")
+		if !ok {
+			return nil, fmt.Errorf("stop rule %q: expected ci<N%% or separated", term)
+		}
+		percent := strings.HasSuffix(value, "%")
+		ci, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if percent {
+			ci /= 100
+		}
+		if err != nil || ci <= 0 || ci >= 1 {
+			return nil, fmt.Errorf("stop rule %q: the margin must be between 0 and 100%%", term)
+		}
+		rule.CI = ci
+	}
+	return rule, nil
+}
+
+// String returns the rule as ParseStopRule reads it
+func (r *StopRule) String() string {
+	var terms []string
+	if r.CI > 0 {
+		terms = append(terms, "ci<"+strconv.FormatFloat(100*r.CI, 'f', -1, 64)+"%")
+	}
+	if r.Separated {
+		terms = append(terms, "separated")
+	}
+	return strings.Join(terms, ",")
+}
+
+// SetStopRule makes simulations stop as soon as the rule is met, nil for
+// never. Without a number of games or a time budget, a simulation with a
+// rule plays until it's met.
+func (g *Game) SetStopRule(rule *StopRule) {
+	g.stopRule = rule
+}
+
+// met reports whether the simulation's results are precise enough to
+// stop, and why
+func (r *StopRule) met(sim *Simulation, names []string) (string, bool) {
+	games := sim.gamesPlayed(len(names))
+	if games < stopMinGames {
+		return "", false
+	}
+	n := float64(games)
+	if r.CI > 0 {
+		widest := 0.0
+		for _, name := range names {
+			rate := float64(sim.Wins[name]) / n
+			widest = max(widest, 2*math.Sqrt(rate*(1-rate)/n))
+		}
+		if widest < r.CI {
+			return fmt.Sprintf(T("every win rate is within ± %.1f%%"), 100*widest), true
+		}
+	}
+	look := separatedLook(games, sim.gamesPerPlay(len(names)))
+	if r.Separated && look > 0 && len(names) > 1 {
+		ranked := append([]string(nil), names...)
+		sort.SliceStable(ranked, func(i, j int) bool { return sim.Wins[ranked[i]] > sim.Wins[ranked[j]] })
+		first, second := float64(sim.Wins[ranked[0]])/n, float64(sim.Wins[ranked[1]])/n
+		// One game's win for one is a loss for the other, so the
+		// difference varies more than either rate
+		gap := first - second
+		margin := separatedZ(look) * math.Sqrt((first+second-gap*gap)/n)
+		if gap > margin {
+			return fmt.Sprintf(T("%s leads %s by %.1f%% ± %.1f%%"), ranked[0], ranked[1], 100*gap, 100*margin), true
+		}
+	}
+	return "", false
+}
+
+// separatedLook returns which look at the top two this many games is,
+// counting from 1, or 0 for none. A race checked after every game would
+// be caught by a lead that comes and goes, so it's looked at only at
+// stopMinGames and then each time the games played double; step is how
+// many games each play adds, so a look isn't skipped between plays.
+func separatedLook(games, step int) int {
+	look := 0
+	for threshold := stopMinGames; threshold <= games; threshold *= 2 {
+		look++
+		if games-step < threshold {
+			return look
+		}
+	}
+	return 0
+}
+
+// separatedZ is how many standard errors the lead must be at a look.
+// Look k spends separatedAlpha/2^k of the error rate, so all the looks
+// together spend no more than separatedAlpha: 2.24 at the first, 2.50 at
+// the second and a little more at each after.
+func separatedZ(look int) float64 {
+	alpha := math.Ldexp(separatedAlpha, -look)
+	return math.Sqrt2 * math.Erfinv(1-alpha)
+}
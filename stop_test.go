@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSeparatedLook(t *testing.T) {
+	tests := []struct {
+		games, step, want int
+	}{
+		{99, 1, 0},
+		{100, 1, 1},
+		{101, 1, 0},
+		{199, 1, 0},
+		{200, 1, 2},
+		{400, 1, 3},
+		{12800, 1, 8},
+		// duplicate plays several games at once, and mustn't step over a look
+		{102, 3, 1},
+		{105, 3, 0},
+		{200, 4, 2},
+		{204, 4, 0},
+	}
+	for _, tt := range tests {
+		if got := separatedLook(tt.games, tt.step); got != tt.want {
+			t.Errorf("separatedLook(%d, %d) = %d, want %d", tt.games, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestSeparatedZ(t *testing.T) {
+	if got := separatedZ(1); math.Abs(got-2.241) > 0.001 {
+		t.Errorf("separatedZ(1) = %.3f, want 2.241", got)
+	}
+	for look := 2; look <= 20; look++ {
+		if separatedZ(look) <= separatedZ(look-1) {
+			t.Errorf("separatedZ(%d) = %.3f, no more than at look %d", look, separatedZ(look), look-1)
+		}
+	}
+}
+
+// TestSeparatedErrorRate plays evenly matched races and checks the
+// separated rule calls few of them, over all its looks
+func TestSeparatedErrorRate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays thousands of races")
+	}
+	const races, games = 2000, 12800
+	rule := &StopRule{Separated: true}
+	names := []string{"Ada", "Bo"}
+	r := rand.New(rand.NewSource(1))
+	stopped := 0
+	for range races {
+		sim := &Simulation{Wins: map[string]int{}}
+		for sim.Played < games {
+			sim.Wins[names[r.Intn(2)]]++
+			sim.Played++
+			if _, ok := rule.met(sim, names); ok {
+				stopped++
+				break
+			}
+		}
+	}
+	rate := float64(stopped) / races
+	t.Logf("separated stopped %d of %d evenly matched races", stopped, races)
+	if rate > 0.065 {
+		t.Errorf("separated stopped %.1f%% of evenly matched races, want about %.0f%% at most", 100*rate, 100*separatedAlpha)
+	}
+}
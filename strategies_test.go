@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStrategyDecisions(t *testing.T) {
+	// the high cards a risky hand fears, all on the discard pile
+	highCardsGone := strings.Repeat("12 ", 11) + strings.Repeat("11 ", 10) + strings.Repeat("10 ", 9)
+	const humans = "imitate:testdata/fixtures/humans.jsonl"
+
+	tests := []struct {
+		name     string
+		strategy string
+		players  []string
+		discards string
+		decides  string
+		want     string
+	}{
+		{
+			name:     "play-to hits below its target",
+			strategy: "play-to:25",
+			players:  []string{"Ada 40 7 9", "Bo 60 3"},
+			want:     "hit",
+		},
+		{
+			name:     "play-to stays at its target",
+			strategy: "play-to:25",
+			players:  []string{"Ada 40 7 9 10", "Bo 60 3"},
+			want:     "stay",
+		},
+		{
+			name:     "bust-probability stays with a risky hand",
+			strategy: "bust-probability:0.3",
+			players:  []string{"Ada 40 12 11 10 9", "Bo 60 3"},
+			want:     "stay",
+		},
+		{
+			name:     "bust-probability hits when the high cards are gone",
+			strategy: "bust-probability:0.3",
+			players:  []string{"Ada 40 12 11 10", "Bo 60 3"},
+			discards: highCardsGone,
+			want:     "hit",
+		},
+		{
+			name:     "always-hit hits with anything",
+			strategy: "always-hit",
+			players:  []string{"Ada 0 12 11 10 9 8 7", "Bo 0 3"},
+			want:     "hit",
+		},
+		{
+			name:     "a Second Chance always hits",
+			strategy: "play-to:10",
+			players:  []string{"Ada 40 12 11 chance", "Bo 60 3"},
+			want:     "hit",
+		},
+		{
+			name:     "optimal takes a cheap seventh card",
+			strategy: "optimal",
+			players:  []string{"Ada 90 0 1 2 3 4 5", "Bo 110 9 10"},
+			want:     "hit",
+		},
+		{
+			name:     "optimal won't gamble a big hand on the seventh card",
+			strategy: "optimal",
+			players:  []string{"Ada 90 12 11 10 9 8 7", "Bo 110 9 10"},
+			want:     "stay",
+		},
+		{
+			name:     "risk-utility stays once it has enough to win",
+			strategy: "risk-utility",
+			players:  []string{"Ada 180 12 9", "Bo 110 9 10"},
+			want:     "stay",
+		},
+		{
+			name:     "imitate hits with a small hand, as its humans did",
+			strategy: humans,
+			players:  []string{"Ada 30 6", "Bo 40 3"},
+			want:     "hit",
+		},
+		{
+			name:     "imitate stays with a big hand, as its humans did",
+			strategy: humans,
+			players:  []string{"Ada 30 12 10 8", "Bo 40 3"},
+			want:     "stay",
+		},
+		{
+			name:    "the named player decides",
+			players: []string{"Ada=always-hit 180 12 9", "Bo=play-to:30 110 4"},
+			decides: "Bo",
+			want:    "hit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := position(t, tt.strategy, tt.players...)
+			f.Discards, f.Decides = tt.discards, tt.decides
+			state, player := fixtureState(t, f)
+			assertDecision(t, player, state, tt.want)
+		})
+	}
+}
+
+// TestFixtureFiles checks the fixture files flip7 fixtures checks, so
+// fixtures added there fail go test too
+func TestFixtureFiles(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(fixtureDir, "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		fixtures, err := LoadFixtures(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range fixtures {
+			t.Run(f.Name, func(t *testing.T) {
+				if err := f.Check(); err != nil {
+					t.Errorf("%s: %v", path, err)
+				}
+			})
+		}
+	}
+}
+
+// TestStrategiesWeighTheScorersBonus checks strategies value the seventh
+// number card at the Flip 7 bonus the game scores, not the standard one
+func TestStrategiesWeighTheScorersBonus(t *testing.T) {
+	tests := []struct {
+		strategy string
+		players  []string
+		bonus    int
+		want     string
+	}{
+		{"risk-utility:1", []string{"Ada 100 3 4 5 6 7 8", "Bo 110 9 10"}, 15, "hit"},
+		{"risk-utility:1", []string{"Ada 100 3 4 5 6 7 8", "Bo 110 9 10"}, 0, "stay"},
+		{"optimal", []string{"Ada 100 3 4 5 6 7 8", "Bo 110 9 10"}, 15, "hit"},
+		{"optimal", []string{"Ada 100 3 4 5 6 7 8", "Bo 110 9 10"}, 0, "stay"},
+		{"optimal", []string{"Ada 100 9 8 7 6 5 4", "Bo 110 9 10"}, 15, "stay"},
+		{"optimal", []string{"Ada 100 9 8 7 6 5 4", "Bo 110 9 10"}, 60, "hit"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s with a bonus of %d", tt.strategy, tt.bonus), func(t *testing.T) {
+			f := position(t, tt.strategy, tt.players...)
+			f.Rules.Scoring = &VariantScorer{Flip7Bonus: &tt.bonus}
+			state, player := fixtureState(t, f)
+			assertDecision(t, player, state, tt.want)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SymbolProfile controls which glyphs are used for cards and icons.
+// Output is written with emoji and translated to the profile on the way
+// out, so every writer (game, players, deck, TUI) agrees on the glyphs.
+type SymbolProfile struct {
+	Name     string
+	replacer *strings.Replacer
+}
+
+// EmojiProfile uses the emoji the game is written with
+var EmojiProfile = &SymbolProfile{Name: "emoji"}
+
+// UnicodeProfile uses single-width text symbols for terminals whose fonts
+// lack emoji but that can display UTF-8
+var UnicodeProfile = &SymbolProfile{
+	Name: "unicode",
+	replacer: strings.NewReplacer(
+		"❄️", "❄",
+		"🆘", "♥",
+		"🎲", "↻",
+		"💥", "✗",
+		"🎉", "★",
+		"📊", "≡",
+		"🎯", "▸",
+		"🎮", "►",
+		"🐛", "¤",
+		"🎴", "♠",
+		"🃏", "♦",
+		"🥇", "①",
+		"🥈", "②",
+		"🥉", "③",
+		"🤖", "⚙",
+		"👤", "☺",
+		"😔", "↓",
+		"🔥", "▲",
+		"💪", "↑",
+		"👍", "→",
+		"🏆", "♛",
+		"🍿", "…",
+		"✅", "✓",
+		"⚡", "»",
+		"↩️", "↩",
+		"🔢", "#",
+		"📰", "≡",
+		"🧩", "◆",
+		"📅", "▦",
+		"🎚️", "≈",
+		"💬", "»",
+		"❌", "✗",
+		"🔁", "↻",
+		"💺", "⑁",
+		"⭐", "☆",
+		"⏱️", "◷",
+		"📄", "≡",
+		"🧪", "⚗",
+		"⚔️", "⚔",
+		"⏳", "⌛",
+		"🔍", "?",
+		"🧮", "≡",
+		"🌳", "♣",
+		"🌐", "◎",
+		"🎰", "♠",
+		"📈", "↗",
+		"🏁", "⚑",
+		"🔀", "⇄",
+		"🛠️", "⚒",
+		"🎞️", "▣",
+		"⚖️", "⚖",
+		"🎬", "▶",
+		"📝", "✎",
+		"🎛️", "≋",
+		"🏟️", "⌂",
+		"📦", "▣",
+		"⚠️", "⚠",
+		"⏰", "◷",
+		"🛑", "■",
+	),
+}
+
+// ASCIIProfile uses plain 7-bit ASCII only
+var ASCIIProfile = &SymbolProfile{
+	Name: "ascii",
+	replacer: strings.NewReplacer(
+		"[❄️ FREEZE]", "[FREEZE]",
+		"[🎲 FLIP 3]", "[FLIP 3]",
+		"[🆘 2ND CHANCE]", "[2ND CHANCE]",
+		"❄️", "*",
+		"🆘", "+",
+		"🎲", "@",
+		"💥", "X",
+		"🎉", "!",
+		"📊", "#",
+		"🎯", ">",
+		"🎮", ">",
+		"🐛", "%",
+		"🎴", "*",
+		"🃏", "=",
+		"🥇", "1st",
+		"🥈", "2nd",
+		"🥉", "3rd",
+		"🤖", "[AI]",
+		"👤", "[H]",
+		"😔", "",
+		"🔥", "",
+		"💪", "",
+		"👍", "",
+		"🏆", "#",
+		"🍿", "",
+		"✅", "OK",
+		"⚡", ">",
+		"↩️", "<-",
+		"🔢", "#",
+		"📰", "#",
+		"🧩", "?",
+		"📅", "#",
+		"🎚️", "~",
+		"💬", ">",
+		"❌", "X",
+		"🔁", "@",
+		"💺", "#",
+		"⭐", "*",
+		"⏱️", "~",
+		"📄", "#",
+		"🧪", "#",
+		"⚔️", "X",
+		"⏳", "~",
+		"🔍", "?",
+		"🧮", "#",
+		"🌳", "*",
+		"🌐", "@",
+		"🎰", "$",
+		"📈", "#",
+		"🏁", "!",
+		"🔀", "~",
+		"🛠️", "%",
+		"🎞️", "*",
+		"⚖️", "=",
+		"🎬", ">",
+		"📝", "=",
+		"🎛️", "~",
+		"🏟️", "#",
+		"📦", "#",
+		"⚠️", "!",
+		"⏰", "~",
+		"🛑", "X",
+		"▲", "^",
+		"▼", "v",
+		"×", "x",
+		"±", "+-",
+		"→", "->",
+		"»", ">>",
+		"█", "_",
+		"▓", "#",
+		"░", ".",
+		"▁", "_",
+		"▂", ".",
+		"▃", "-",
+		"▄", "=",
+		"▅", "+",
+		"▆", "*",
+		"▇", "#",
+	),
+}
+
+var symbolProfiles = []*SymbolProfile{ASCIIProfile, UnicodeProfile, EmojiProfile}
+
+// Apply translates emoji in text to the profile's glyphs
+func (p *SymbolProfile) Apply(text string) string {
+	if p == nil || p.replacer == nil {
+		return text
+	}
+	return p.replacer.Replace(text)
+}
+
+// profileWriter translates everything written through it to a profile
+type profileWriter struct {
+	w       io.Writer
+	profile *SymbolProfile
+}
+
+func (pw *profileWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(pw.w, pw.profile.Apply(string(b))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// NewProfileWriter wraps w so that all output uses the profile's glyphs
+func NewProfileWriter(w io.Writer, profile *SymbolProfile) io.Writer {
+	if profile == nil || profile.replacer == nil {
+		return w
+	}
+	return &profileWriter{w: w, profile: profile}
+}
+
+// LookupSymbolProfile returns the named profile; "auto" detects one from
+// the environment
+func LookupSymbolProfile(name string) (*SymbolProfile, error) {
+	if strings.ToLower(name) == "auto" {
+		return DetectSymbolProfile(), nil
+	}
+	for _, profile := range symbolProfiles {
+		if profile.Name == strings.ToLower(name) {
+			return profile, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown symbol profile %q (available: auto, ascii, unicode, emoji)", name)
+}
+
+// DetectSymbolProfile guesses what the terminal can display from the
+// locale and terminal type. Terminals without a UTF-8 locale get ASCII,
+// the Linux console (which has no emoji font) gets Unicode, and
+// everything else gets emoji.
+func DetectSymbolProfile() *SymbolProfile {
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return ASCIIProfile
+	}
+
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	locale = strings.ToUpper(locale)
+	if !strings.Contains(locale, "UTF-8") && !strings.Contains(locale, "UTF8") {
+		// Windows Terminal sets WT_SESSION and handles emoji without a locale
+		if os.Getenv("WT_SESSION") != "" {
+			return EmojiProfile
+		}
+		return ASCIIProfile
+	}
+
+	if term == "linux" || strings.HasPrefix(term, "vt") {
+		return UnicodeProfile
+	}
+
+	return EmojiProfile
+}
@@ -0,0 +1,97 @@
+package main
+
+// Settings of the freeze threat model
+const (
+	// freezeLeaderShare is how often an opponent is taken to freeze the
+	// leader among their rivals; the rest of the time they're taken to
+	// pick a rival at random
+	freezeLeaderShare = 0.75
+	// freezeThreatPush is how far a bust threshold moves for each unit
+	// of freeze threat above or below an even share of the table's
+	freezeThreatPush = 0.5
+)
+
+// FreezeThreat is the chance that one opponent freezes a player before
+// the player's next turn
+type FreezeThreat struct {
+	Opponent PlayerInterface
+	Chance   float64
+}
+
+// freezeChance is the chance that opponent has a Freeze to play on their
+// next turn: certain when they hold one under the hold rule, otherwise
+// the chance their card is one of the unseen Freezes
+func (s *GameState) freezeChance(opponent PlayerInterface) float64 {
+	for _, card := range opponent.HeldActions() {
+		if card.Action == Freeze {
+			return 1
+		}
+	}
+	unseen := s.Unseen()
+	if unseen.Total == 0 {
+		return 0
+	}
+	return float64(unseen.Freezes) / float64(unseen.Total)
+}
+
+// FreezeThreats estimates, for every opponent still in the round, the
+// chance they freeze player before the player's next turn, as each of
+// them takes a turn first. Who an opponent would freeze can't be seen,
+// so they're taken to freeze the leader among their rivals most of the
+// time and a rival at random otherwise, which is how the built-in
+// strategies play.
+func (s *GameState) FreezeThreats(player PlayerInterface) []FreezeThreat {
+	var threats []FreezeThreat
+	for _, opponent := range s.ActivePlayers {
+		if opponent == player {
+			continue
+		}
+		rivals := s.rivals(opponent, Freeze)
+		aimed := 0.0
+		for _, rival := range rivals {
+			if rival == player {
+				aimed = (1 - freezeLeaderShare) / float64(len(rivals))
+				break
+			}
+		}
+		if aimed > 0 && leaderOf(rivals) == player {
+			aimed += freezeLeaderShare
+		}
+		threats = append(threats, FreezeThreat{Opponent: opponent, Chance: s.freezeChance(opponent) * aimed})
+	}
+	return threats
+}
+
+// FreezeThreat is the chance that any opponent freezes player before
+// the player's next turn
+func (s *GameState) FreezeThreat(player PlayerInterface) float64 {
+	spared := 1.0
+	for _, threat := range s.FreezeThreats(player) {
+		spared *= 1 - threat.Chance
+	}
+	return 1 - spared
+}
+
+// evenFreezeThreat is the chance player would be frozen before their
+// next turn if every opponent picked their Freeze target at random
+func (s *GameState) evenFreezeThreat(player PlayerInterface) float64 {
+	spared := 1.0
+	for _, opponent := range s.ActivePlayers {
+		if opponent == player {
+			continue
+		}
+		if rivals := s.rivals(opponent, Freeze); len(rivals) > 0 {
+			spared *= 1 - s.freezeChance(opponent)/float64(len(rivals))
+		}
+	}
+	return 1 - spared
+}
+
+// freezeThreatAdjustment is how far to move a bust threshold for the
+// freeze threat player faces. A player likely to be frozen before their
+// next turn pushes, since this card may be their last and a Freeze
+// after it banks it safely; a player less threatened than an even share
+// will get more turns and stays a little earlier.
+func freezeThreatAdjustment(player PlayerInterface, gameState *GameState) float64 {
+	return freezeThreatPush * (gameState.FreezeThreat(player) - gameState.evenFreezeThreat(player))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TurnTimeout is how long a networked player or external bot has for
+// each decision, and what's decided for them when it runs out, so one
+// stalled client can't hang a table. Hit makes the hit/stay decision a
+// hit rather than a stay, and Target picks who a Freeze or Flip Three
+// goes to: "leader", "last" or "random". A spare Second Chance goes to
+// last place. A zero Limit waits as long as it takes.
+type TurnTimeout struct {
+	Limit  time.Duration
+	Hit    bool
+	Target string
+}
+
+// ParseTurnTimeout reads the fallback for a timeout as "stay" or "hit"
+// and a target, comma-separated, e.g. "stay,leader"
+func ParseTurnTimeout(limit time.Duration, fallback string) (TurnTimeout, error) {
+	t := TurnTimeout{Limit: limit, Target: "leader"}
+	if limit < 0 {
+		return t, fmt.Errorf("the turn time can't be negative")
+	}
+	for _, part := range strings.Split(fallback, ",") {
+		switch strings.TrimSpace(part) {
+		case "", "stay":
+			t.Hit = false
+		case "hit":
+			t.Hit = true
+		case "leader", "last", "random":
+			t.Target = strings.TrimSpace(part)
+		default:
+			return t, fmt.Errorf("unknown timeout fallback %q (available: stay, hit, leader, last, random)", part)
+		}
+	}
+	return t, nil
+}
+
+// String describes the fallback as ParseTurnTimeout reads it
+func (t TurnTimeout) String() string {
+	if t.Hit {
+		return "hit," + t.Target
+	}
+	return "stay," + t.Target
+}
+
+// Context returns a context that's done when the decision's time is up
+func (t TurnTimeout) Context(parent context.Context) (context.Context, context.CancelFunc) {
+	if t.Limit <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, t.Limit)
+}
+
+// ChooseTarget picks the target of an action card for a player who ran
+// out of time
+func (t TurnTimeout) ChooseTarget(self PlayerInterface, gameState *GameState, actionType ActionType) PlayerInterface {
+	if actionType == SecondChance {
+		return TargetLastPlaceStrategy(self, gameState, actionType)
+	}
+	switch t.Target {
+	case "last":
+		return TargetLastPlaceStrategy(self, gameState, actionType)
+	case "random":
+		return TargetRandomStrategy(self, gameState, actionType)
+	}
+	return TargetLeaderStrategy(self, gameState, actionType)
+}
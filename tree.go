@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxTreeNodes is the largest tree the exporter writes; a deeper tree
+// from an empty hand runs to millions of nodes
+const maxTreeNodes = 1000000
+
+// TreeNode is a node of a round's game tree: a hit/stay decision, a
+// draw from the deck, or the end of the round with the score it makes
+type TreeNode struct {
+	Kind     string     `json:"kind"` // decision, chance or terminal
+	Hand     string     `json:"hand"`
+	Payoff   float64    `json:"payoff"` // the score, at terminals
+	Cutoff   bool       `json:"cutoff,omitempty"`
+	Children []TreeEdge `json:"children,omitempty"`
+}
+
+// TreeEdge is an action from a decision node, or a card drawn at a
+// chance node Count times out of the node's Total
+type TreeEdge struct {
+	Label       string    `json:"label"`
+	Count       int       `json:"count,omitempty"`
+	Total       int       `json:"total,omitempty"`
+	Probability float64   `json:"probability,omitempty"`
+	Node        *TreeNode `json:"node"`
+}
+
+// GameTree is the tree of one player's round from a hand, as the solver
+// sees it: action cards are drawn again and opponents are left out. Draws
+// past the depth limit are cut off, the node scoring the solver's value
+// of the hand with best play from there.
+type GameTree struct {
+	Root      *TreeNode
+	Decisions int
+	Chances   int
+	Terminals int
+
+	solver *solver
+}
+
+// handLabel writes held numbers and modifiers as parseCards reads them
+func handLabel(numbers uint16, modifiers uint8) string {
+	var cards []string
+	for v := range 13 {
+		if numbers&(1<<v) != 0 {
+			cards = append(cards, strconv.Itoa(v))
+		}
+	}
+	for m := Plus2; m <= Multiply2; m++ {
+		if modifiers&(1<<m) != 0 {
+			cards = append(cards, formatCard(NewModifierCard(m)))
+		}
+	}
+	return strings.Join(cards, " ")
+}
+
+// BuildGameTree builds the tree of a round from a hand, with the seen
+// cards out of the deck, expanding at most depth draws
+func BuildGameTree(hand, seen []*Card, depth int) (*GameTree, error) {
+	var numbers uint16
+	var modifiers uint8
+	for _, c := range hand {
+		switch c.Type {
+		case NumberCard:
+			if numbers&(1<<c.Value) != 0 {
+				return nil, fmt.Errorf("the hand holds %d twice, which busts", c.Value)
+			}
+			numbers |= 1 << c.Value
+		case ModifierCard:
+			modifiers |= 1 << c.Modifier
+		default:
+			return nil, fmt.Errorf("the hand can only hold numbers and modifiers")
+		}
+	}
+	if bits.OnesCount16(numbers) >= 7 {
+		return nil, fmt.Errorf("the hand already has Flip 7")
+	}
+	// Held cards come out of the deck too
+	if _, err := newSolver(append(append([]*Card(nil), seen...), hand...)); err != nil {
+		return nil, err
+	}
+	s, err := newSolver(seen)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &GameTree{solver: s}
+	t.Root, err = t.decision(numbers, modifiers, depth)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// terminal ends the round with a score
+func (t *GameTree) terminal(numbers uint16, modifiers uint8, payoff float64) *TreeNode {
+	t.Terminals++
+	return &TreeNode{Kind: "terminal", Hand: handLabel(numbers, modifiers), Payoff: payoff}
+}
+
+// grow counts a new inner node against the size limit
+func (t *GameTree) grow() error {
+	if t.Decisions+t.Chances+t.Terminals >= maxTreeNodes {
+		return fmt.Errorf("the tree has over %d nodes; use a smaller -depth", maxTreeNodes)
+	}
+	return nil
+}
+
+// decision is the player choosing to hit or stay holding a hand. A
+// player without numbers has to hit; an empty deck leaves only staying.
+func (t *GameTree) decision(numbers uint16, modifiers uint8, depth int) (*TreeNode, error) {
+	if err := t.grow(); err != nil {
+		return nil, err
+	}
+	if depth == 0 {
+		node := t.terminal(numbers, modifiers, t.solver.solve(numbers, modifiers))
+		node.Cutoff = true
+		return node, nil
+	}
+
+	t.Decisions++
+	node := &TreeNode{Kind: "decision", Hand: handLabel(numbers, modifiers)}
+	hit, err := t.chance(numbers, modifiers, depth)
+	if err != nil {
+		return nil, err
+	}
+	if hit != nil {
+		node.Children = append(node.Children, TreeEdge{Label: "hit", Node: hit})
+	}
+	if numbers != 0 || hit == nil {
+		stay := t.terminal(numbers, modifiers, float64(handScore(numbers, modifiers)))
+		node.Children = append(node.Children, TreeEdge{Label: "stay", Node: stay})
+	}
+	return node, nil
+}
+
+// chance is the draw after a hit, one branch per card that can come up
+// and one for every duplicate, which busts. It's nil when the deck has
+// nothing left to draw.
+func (t *GameTree) chance(numbers uint16, modifiers uint8, depth int) (*TreeNode, error) {
+	type draw struct {
+		label string
+		count int
+		next  func() (*TreeNode, error)
+	}
+	var draws []draw
+	bust, total := 0, 0
+	for v, n := range t.solver.numbers {
+		if numbers&(1<<v) != 0 {
+			bust += n - 1
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		held := numbers | 1<<v
+		draws = append(draws, draw{strconv.Itoa(v), n, func() (*TreeNode, error) {
+			if bits.OnesCount16(held) == 7 {
+				return t.terminal(held, modifiers, float64(handScore(held, modifiers))), nil
+			}
+			return t.decision(held, modifiers, depth-1)
+		}})
+		total += n
+	}
+	for m := Plus2; m <= Multiply2; m++ {
+		if (t.solver.modifiers&^modifiers)&(1<<m) == 0 {
+			continue
+		}
+		draws = append(draws, draw{formatCard(NewModifierCard(m)), 1, func() (*TreeNode, error) {
+			return t.decision(numbers, modifiers|1<<m, depth-1)
+		}})
+		total++
+	}
+	if bust > 0 {
+		draws = append(draws, draw{"bust", bust, func() (*TreeNode, error) {
+			return t.terminal(numbers, modifiers, 0), nil
+		}})
+		total += bust
+	}
+	if total == 0 {
+		return nil, nil
+	}
+
+	t.Chances++
+	node := &TreeNode{Kind: "chance", Hand: handLabel(numbers, modifiers)}
+	for _, d := range draws {
+		child, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, TreeEdge{
+			Label:       d.label,
+			Count:       d.count,
+			Total:       total,
+			Probability: float64(d.count) / float64(total),
+			Node:        child,
+		})
+	}
+	return node, nil
+}
+
+// WriteEFG writes the tree in Gambit's extensive form format, which
+// Gambit and OpenSpiel's EFG game loader read. Every decision is its own
+// information set, since the player sees their whole hand.
+func (t *GameTree) WriteEFG(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, `EFG 2 R "Flip 7 round" { "Player" }`)
+	fmt.Fprintln(bw, `""`)
+	fmt.Fprintln(bw)
+
+	infosets, chances, outcomes := 0, 0, 0
+	var write func(n *TreeNode)
+	write = func(n *TreeNode) {
+		switch n.Kind {
+		case "decision":
+			infosets++
+			fmt.Fprintf(bw, "p %q 1 %d %q {", n.Hand, infosets, n.Hand)
+			for _, e := range n.Children {
+				fmt.Fprintf(bw, " %q", e.Label)
+			}
+			fmt.Fprintln(bw, " } 0")
+		case "chance":
+			chances++
+			fmt.Fprintf(bw, "c %q %d \"\" {", n.Hand, chances)
+			for _, e := range n.Children {
+				fmt.Fprintf(bw, " %q %d/%d", e.Label, e.Count, e.Total)
+			}
+			fmt.Fprintln(bw, " } 0")
+		default:
+			outcomes++
+			fmt.Fprintf(bw, "t %q %d \"\" { %s }\n", n.Hand, outcomes, strconv.FormatFloat(n.Payoff, 'f', -1, 64))
+		}
+		for _, e := range n.Children {
+			write(e.Node)
+		}
+	}
+	write(t.Root)
+	return bw.Flush()
+}
+
+// WriteJSON writes the tree as nested JSON nodes
+func (t *GameTree) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	return enc.Encode(t.Root)
+}
+
+// RunTree handles the tree subcommand: it exports the game tree of a
+// round from a hand for external solvers
+func RunTree(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("tree", flag.ContinueOnError)
+	hand := fs.String("hand", "", "Cards in hand, e.g. \"3 7 x2\"")
+	seen := fs.String("seen", "", "Cards already out of the deck, e.g. \"12 12 11 +4\"")
+	depth := fs.Int("depth", 2, "Most draws to expand before cutting the tree off")
+	format := fs.String("format", "efg", "Tree format: efg (Gambit) or json")
+	output := fs.String("o", "", "Write the tree to this file instead of standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *depth < 1 {
+		return fmt.Errorf("-depth must be at least 1")
+	}
+	if *format != "efg" && *format != "json" {
+		return fmt.Errorf("unknown format %q (available: efg, json)", *format)
+	}
+	handCards, err := parseCards(*hand)
+	if err != nil {
+		return err
+	}
+	seenCards, err := parseCards(*seen)
+	if err != nil {
+		return err
+	}
+	t, err := BuildGameTree(handCards, seenCards, *depth)
+	if err != nil {
+		return err
+	}
+
+	w := out
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		err = t.WriteJSON(w)
+	} else {
+		err = t.WriteEFG(w)
+	}
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		report := NewGame()
+		report.SetOutput(out)
+		report.printf(T("🌳 Wrote %d decisions, %d draws and %d outcomes to %s\n"), t.Decisions, t.Chances, t.Terminals, *output)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+//go:build !js
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tuiPlayerView is a copy of the parts of a player the TUI displays
+type tuiPlayerView struct {
+	Icon  string
+	Name  string
+	Total int
+	Hand  string
+	// Chance is their chance of winning, if the evaluation bar is on
+	Chance float64
+}
+
+// tuiSnapshot is a copy of the game state taken on the game goroutine so
+// the TUI never reads live game state while the engine is mutating it
+type tuiSnapshot struct {
+	Round     int
+	Dealer    string
+	Players   []tuiPlayerView
+	CardsLeft int
+	Discards  int
+	// Chances is set when the evaluation bar has worked out the players'
+	// chances of winning
+	Chances bool
+}
+
+type tuiSnapshotMsg struct {
+	snapshot  tuiSnapshot
+	lastEvent string
+}
+
+type tuiOutputMsg string
+
+type tuiDoneMsg struct {
+	err error
+}
+
+// tuiWriter forwards game output into the TUI log panel
+type tuiWriter struct {
+	program *tea.Program
+}
+
+func (w *tuiWriter) Write(p []byte) (int, error) {
+	w.program.Send(tuiOutputMsg(p))
+	return len(p), nil
+}
+
+type tuiModel struct {
+	snapshot    tuiSnapshot
+	lastEvent   string
+	log         []string
+	partial     string
+	input       string
+	inputWriter *io.PipeWriter
+	profile     *SymbolProfile
+	width       int
+	height      int
+	done        bool
+	err         error
+}
+
+var (
+	tuiPanelStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			Padding(0, 1)
+	tuiTitleStyle = lipgloss.NewStyle().Bold(true)
+)
+
+// RunTUI runs the game inside a full-screen terminal UI. The game engine
+// runs on its own goroutine; its output is captured into the log panel and
+// its events refresh the scoreboard, hands and deck panels.
+func RunTUI(ctx context.Context, game *Game, profile *SymbolProfile) error {
+	inputReader, inputWriter := io.Pipe()
+	model := &tuiModel{inputWriter: inputWriter, profile: profile}
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	game.SetInput(inputReader)
+	game.evalPanel = true
+	game.SetOutput(NewProfileWriter(&tuiWriter{program: program}, profile))
+	game.Subscribe(func(event Event) {
+		program.Send(tuiSnapshotMsg{
+			snapshot:  game.tuiSnapshot(profile),
+			lastEvent: profile.Apply(describeEvent(event)),
+		})
+	})
+
+	go func() {
+		err := game.Run(ctx)
+		program.Send(tuiDoneMsg{err: err})
+	}()
+
+	finalModel, err := program.Run()
+	inputWriter.Close()
+	if err != nil {
+		return err
+	}
+	return finalModel.(*tuiModel).err
+}
+
+// tuiSnapshot copies the state shown by the TUI panels
+func (g *Game) tuiSnapshot(profile *SymbolProfile) tuiSnapshot {
+	snapshot := tuiSnapshot{
+		Round:     g.round,
+		CardsLeft: g.deck.CardsLeft(),
+		Discards:  len(g.deck.Discards()),
+		Chances:   len(g.winChances) == len(g.players),
+	}
+	if len(g.players) > 0 {
+		snapshot.Dealer = g.players[g.dealerIdx].GetName()
+	}
+	for i, player := range g.players {
+		view := tuiPlayerView{
+			Icon:  profile.Apply(player.GetPlayerIcon()),
+			Name:  player.GetName(),
+			Total: player.GetTotalScore(),
+			Hand:  profile.Apply(player.GetHandSummary()),
+		}
+		if snapshot.Chances {
+			view.Chance = g.winChances[i]
+		}
+		snapshot.Players = append(snapshot.Players, view)
+	}
+	return snapshot
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tuiSnapshotMsg:
+		m.snapshot = msg.snapshot
+		m.lastEvent = msg.lastEvent
+
+	case tuiOutputMsg:
+		m.appendOutput(string(msg))
+
+	case tuiDoneMsg:
+		m.done = true
+		m.err = msg.err
+		m.lastEvent = T("Game finished - press any key to exit")
+
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC || m.done {
+			return m, tea.Quit
+		}
+		switch msg.Type {
+		case tea.KeyEnter:
+			line := m.input
+			m.input = ""
+			m.log = append(m.log, m.partial+line)
+			m.partial = ""
+			writer := m.inputWriter
+			return m, func() tea.Msg {
+				io.WriteString(writer, line+"\n")
+				return nil
+			}
+		case tea.KeyBackspace:
+			if len(m.input) > 0 {
+				runes := []rune(m.input)
+				m.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.input += string(msg.Runes)
+		}
+	}
+
+	return m, nil
+}
+
+// appendOutput splits game output into log lines, keeping any trailing
+// partial line (usually a prompt) for display next to the input area
+func (m *tuiModel) appendOutput(text string) {
+	lines := strings.Split(m.partial+text, "\n")
+	m.log = append(m.log, lines[:len(lines)-1]...)
+	m.partial = lines[len(lines)-1]
+}
+
+func (m *tuiModel) View() string {
+	if m.width == 0 {
+		return T("Loading...")
+	}
+
+	// Scoreboard
+	var scores strings.Builder
+	scores.WriteString(tuiTitleStyle.Render(m.profile.Apply(fmt.Sprintf(T("📊 Scores - Round %d"), m.snapshot.Round))))
+	for _, player := range m.snapshot.Players {
+		scores.WriteString(fmt.Sprintf("\n%s %-20s %3d", player.Icon, player.Name, player.Total))
+	}
+
+	// Deck
+	deck := tuiTitleStyle.Render(m.profile.Apply(T("🃏 Deck"))) +
+		fmt.Sprintf(T("\nCards left: %d\nDiscards:   %d\nDealer:     %s"),
+			m.snapshot.CardsLeft, m.snapshot.Discards, m.snapshot.Dealer)
+
+	panels := []string{tuiPanelStyle.Render(scores.String()), tuiPanelStyle.Render(deck)}
+
+	// Chances of winning
+	if m.snapshot.Chances {
+		var chances strings.Builder
+		chances.WriteString(tuiTitleStyle.Render(m.profile.Apply(T("⚖️ Chances of winning:"))))
+		for _, player := range m.snapshot.Players {
+			chances.WriteString(fmt.Sprintf("\n%-20s %s %3.0f%%", player.Name, m.profile.Apply(evalBar(player.Chance)), 100*player.Chance))
+		}
+		panels = append(panels, tuiPanelStyle.Render(chances.String()))
+	}
+	top := lipgloss.JoinHorizontal(lipgloss.Top, panels...)
+
+	// Hands
+	var hands strings.Builder
+	hands.WriteString(tuiTitleStyle.Render(m.profile.Apply(T("🎴 Hands"))))
+	for _, player := range m.snapshot.Players {
+		hands.WriteString(fmt.Sprintf("\n%-20s %s", player.Name, player.Hand))
+	}
+	handsPanel := tuiPanelStyle.Width(m.width - 2).Render(hands.String())
+
+	status := tuiTitleStyle.Render(m.profile.Apply("» ") + m.lastEvent)
+	inputLine := tuiPanelStyle.Width(m.width - 2).Render(m.partial + m.input + m.profile.Apply("█"))
+
+	// The log gets whatever vertical space is left
+	used := lipgloss.Height(top) + lipgloss.Height(handsPanel) + lipgloss.Height(inputLine) + 3
+	logHeight := max(m.height-used, 1)
+	logLines := m.log
+	if len(logLines) > logHeight {
+		logLines = logLines[len(logLines)-logHeight:]
+	}
+	logPanel := tuiPanelStyle.Width(m.width - 2).Height(logHeight).Render(strings.Join(logLines, "\n"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, top, handsPanel, logPanel, status, inputLine)
+}
@@ -0,0 +1,93 @@
+package main
+
+import "slices"
+
+// engineState is a copy of everything a human's decision can change, taken
+// at each human decision point so the decision can be undone
+type engineState struct {
+	player    PlayerInterface
+	deckCards []*Card
+	discards  []*Card
+	players   []BasePlayer
+	history   []Event
+}
+
+// baser is implemented by every player type that embeds BasePlayer
+type baser interface {
+	base() *BasePlayer
+}
+
+func (p *BasePlayer) base() *BasePlayer {
+	return p
+}
+
+// SetUndoEnabled allows humans to undo their last decision. It is meant
+// for casual and debug games and is never used in simulations.
+func (g *Game) SetUndoEnabled(enabled bool) {
+	g.undoEnabled = enabled
+}
+
+// canUndo reports whether undo is available in this game
+func (g *Game) canUndo() bool {
+	return g.undoEnabled && !g.silentMode
+}
+
+// pushUndoState records the state before a human decides
+func (g *Game) pushUndoState(player PlayerInterface) {
+	state := engineState{
+		player:    player,
+		deckCards: slices.Clone(g.deck.Cards()),
+		discards:  slices.Clone(g.deck.Discards()),
+		history:   slices.Clone(g.history),
+	}
+	for _, p := range g.players {
+		state.players = append(state.players, cloneBasePlayer(p.(baser).base()))
+	}
+	g.undoStack = append(g.undoStack, state)
+}
+
+// clearUndo forgets all undo states; decisions can't be undone across rounds
+func (g *Game) clearUndo(event Event) {
+	if event.Type == RoundStarted {
+		g.undoStack = g.undoStack[:0]
+	}
+}
+
+// undo restores the state before the player's previous decision. The top
+// of the stack is the decision the player is being asked for now.
+func (g *Game) undo(player PlayerInterface) bool {
+	idx := -1
+	seenCurrent := false
+	for i := len(g.undoStack) - 1; i >= 0; i-- {
+		if g.undoStack[i].player != player {
+			continue
+		}
+		if !seenCurrent {
+			seenCurrent = true
+			continue
+		}
+		idx = i
+		break
+	}
+	if idx < 0 {
+		return false
+	}
+
+	state := g.undoStack[idx]
+	g.deck.Restore(slices.Clone(state.deckCards), slices.Clone(state.discards))
+	g.history = slices.Clone(state.history)
+	for i, p := range g.players {
+		*p.(baser).base() = cloneBasePlayer(&state.players[i])
+	}
+	g.undoStack = g.undoStack[:idx+1]
+	return true
+}
+
+// cloneBasePlayer copies a player's state, including its hand slices
+func cloneBasePlayer(p *BasePlayer) BasePlayer {
+	clone := *p
+	clone.NumberCards = slices.Clone(p.NumberCards)
+	clone.ModifierCards = slices.Clone(p.ModifierCards)
+	clone.ActionCards = slices.Clone(p.ActionCards)
+	return clone
+}
@@ -0,0 +1,75 @@
+package main
+
+import "math"
+
+// crraUtility is constant relative risk aversion utility: ln(x) at an
+// aversion of 1, otherwise (x^(1-aversion) - 1) / (1 - aversion). It's
+// concave for any aversion above 0, so a sure x is worth more than a
+// gamble that averages x, and more so the higher the aversion.
+func crraUtility(x, aversion float64) float64 {
+	if aversion == 1 {
+		return math.Log(x)
+	}
+	return (math.Pow(x, 1-aversion) - 1) / (1 - aversion)
+}
+
+// gameUtility is what a game score is worth to a risk-utility player.
+// Points past the winning score are worth nothing more, and the score
+// is counted from 1 so a player with nothing yet has a finite utility.
+func gameUtility(score, winningScore int, aversion float64) float64 {
+	if winningScore > 0 {
+		score = min(score, winningScore)
+	}
+	return crraUtility(float64(score+1), aversion)
+}
+
+// RiskUtilityStrategy hits when the next card's expected utility of the
+// game score beats what staying banks. The utility is concave, so the
+// points a player already has in play weigh more than the same expected
+// points from another card; a player who already has enough to win
+// gains nothing by hitting and protects the lead. The higher the
+// aversion, the sooner it stays.
+func RiskUtilityStrategy(aversion float64) HitOrStayStrategy {
+	return func(self PlayerInterface, gameState *GameState) bool {
+		remaining := gameState.Unseen()
+		if remaining.Total == 0 {
+			return false
+		}
+		total := self.GetTotalScore()
+		current := total + self.CalculateRoundScore()
+		utility := func(score int) float64 {
+			return gameUtility(score, gameState.WinningScore, aversion)
+		}
+
+		// A number card adds its value, doubled under a x2, and the
+		// seventh adds the scorer's Flip 7 bonus; a number already held
+		// busts and loses the round's points. Action cards are counted
+		// as changing nothing, and modifiers as adding their average.
+		multiplier := 1
+		if hasMultiplier(self) {
+			multiplier = 2
+		}
+		bonus := 0
+		if self.NumberOfNumberCards() == 6 {
+			bonus = flip7BonusOf(self)
+		}
+		held := self.HeldNumbers()
+		expected := 0.0
+		for value, copies := range remaining.Numbers {
+			if copies == 0 {
+				continue
+			}
+			if held&(1<<value) != 0 {
+				expected += float64(copies) * utility(total)
+			} else {
+				expected += float64(copies) * utility(current+value*multiplier+bonus)
+			}
+		}
+		if remaining.Modifiers > 0 {
+			expected += float64(remaining.Modifiers) * utility(current+remaining.ModifierPoints/remaining.Modifiers)
+		}
+		expected += float64(remaining.Actions) * utility(current)
+
+		return expected/float64(remaining.Total) > utility(current)
+	}
+}
@@ -0,0 +1,177 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall/js"
+)
+
+// wasmGame is a game the page is playing, through the same environment
+// as the env subcommand, with the page's event subscribers
+type wasmGame struct {
+	env         *Env
+	step        TimeStep
+	subscribers map[int]js.Value
+	nextSub     int
+}
+
+// wasmOptions are createGame's options
+type wasmOptions struct {
+	Opponents []string `json:"opponents"`
+	Score     int      `json:"score"`
+	Seed      int64    `json:"seed"`
+}
+
+var (
+	wasmGames  = make(map[int]*wasmGame)
+	wasmNextID = 1
+)
+
+// main exposes the engine to JavaScript as the global flip7 object and
+// waits for the page to call it
+func main() {
+	js.Global().Set("flip7", js.ValueOf(map[string]any{
+		"createGame":    js.FuncOf(wasmCreateGame),
+		"getState":      js.FuncOf(wasmGetState),
+		"applyDecision": js.FuncOf(wasmApplyDecision),
+		"subscribe":     js.FuncOf(wasmSubscribe),
+		"closeGame":     js.FuncOf(wasmCloseGame),
+	}))
+	select {}
+}
+
+// toJS converts a Go value to a JavaScript one through JSON
+func toJS(v any) js.Value {
+	data, _ := json.Marshal(v)
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+// jsError is what the API returns for an error, as the env protocol does
+func jsError(err error) js.Value {
+	return toJS(map[string]string{"error": err.Error()})
+}
+
+// wasmGameArg finds the game whose ID is the first argument
+func wasmGameArg(args []js.Value) (int, *wasmGame, error) {
+	if len(args) == 0 || args[0].Type() != js.TypeNumber {
+		return 0, nil, fmt.Errorf("expected a game ID")
+	}
+	id := args[0].Int()
+	game, ok := wasmGames[id]
+	if !ok {
+		return 0, nil, fmt.Errorf("no game %d", id)
+	}
+	return id, game, nil
+}
+
+// publish hands the events of a time step to the game's subscribers
+func (w *wasmGame) publish(step TimeStep) {
+	w.step = step
+	for _, event := range step.Events {
+		v := toJS(event)
+		for _, callback := range w.subscribers {
+			callback.Invoke(v)
+		}
+	}
+}
+
+// wasmCreateGame starts a game from options such as {opponents:
+// ["expert"], score: 200, seed: 1} and returns its ID
+func wasmCreateGame(this js.Value, args []js.Value) any {
+	opts := wasmOptions{Opponents: []string{"expert"}, Score: 200}
+	if len(args) > 0 && args[0].Type() == js.TypeObject {
+		data := js.Global().Get("JSON").Call("stringify", args[0]).String()
+		if err := json.NewDecoder(strings.NewReader(data)).Decode(&opts); err != nil {
+			return jsError(err)
+		}
+	}
+	env, err := NewEnv(opts.Opponents, opts.Score)
+	if err != nil {
+		return jsError(err)
+	}
+	step, err := env.Reset(opts.Seed)
+	if err != nil {
+		return jsError(err)
+	}
+	id := wasmNextID
+	wasmNextID++
+	wasmGames[id] = &wasmGame{env: env, step: step, subscribers: make(map[int]js.Value)}
+	return id
+}
+
+// wasmGetState returns the game's latest time step: the table, the
+// player's observation and legal actions, and the reward once it's done
+func wasmGetState(this js.Value, args []js.Value) any {
+	_, game, err := wasmGameArg(args)
+	if err != nil {
+		return jsError(err)
+	}
+	return toJS(game.step)
+}
+
+// wasmApplyDecision plays "hit" or "stay" (or 1 or 0) and returns the
+// next time step
+func wasmApplyDecision(this js.Value, args []js.Value) any {
+	_, game, err := wasmGameArg(args)
+	if err != nil {
+		return jsError(err)
+	}
+	action := -1
+	if len(args) > 1 {
+		switch args[1].Type() {
+		case js.TypeNumber:
+			action = args[1].Int()
+		case js.TypeString:
+			for i, name := range envActions {
+				if name == args[1].String() {
+					action = i
+				}
+			}
+		}
+	}
+	if action < 0 {
+		return jsError(fmt.Errorf("expected a decision: hit or stay"))
+	}
+	step, err := game.env.Step(action)
+	if err != nil {
+		return jsError(err)
+	}
+	game.publish(step)
+	return toJS(step)
+}
+
+// wasmSubscribe calls a function with every event of the game from now
+// on, returning a function that stops it
+func wasmSubscribe(this js.Value, args []js.Value) any {
+	_, game, err := wasmGameArg(args)
+	if err != nil {
+		return jsError(err)
+	}
+	if len(args) < 2 || args[1].Type() != js.TypeFunction {
+		return jsError(fmt.Errorf("expected a callback"))
+	}
+	sub := game.nextSub
+	game.nextSub++
+	game.subscribers[sub] = args[1]
+	var unsubscribe js.Func
+	unsubscribe = js.FuncOf(func(this js.Value, args []js.Value) any {
+		delete(game.subscribers, sub)
+		unsubscribe.Release()
+		return nil
+	})
+	return unsubscribe
+}
+
+// wasmCloseGame ends a game and forgets it
+func wasmCloseGame(this js.Value, args []js.Value) any {
+	id, game, err := wasmGameArg(args)
+	if err != nil {
+		return jsError(err)
+	}
+	game.env.Close()
+	delete(wasmGames, id)
+	return nil
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WinCondition decides when a game is over, who won it, and who is
+// ahead while it runs. New ways to win plug in here without touching
+// the game loop.
+type WinCondition interface {
+	// Goal describes how the game is won, for the opening banner
+	Goal(g *Game) string
+	// Over reports whether the rounds played so far have decided the game
+	Over(g *Game) bool
+	// Winner returns who won a game that is over
+	Winner(g *Game) PlayerInterface
+	// Leader returns who is ahead now, counting the points in play this
+	// round, for strategies that play against the leader
+	Leader(g *Game) PlayerInterface
+}
+
+// eliminator is a WinCondition that knocks a player out after each round
+type eliminator interface {
+	eliminate(g *Game) PlayerInterface
+}
+
+// finalRounder is a WinCondition that can tell the game would be over if
+// the round ended as it stands
+type finalRounder interface {
+	endsNow(g *Game) bool
+}
+
+// announcer is a WinCondition with its own game over line
+type announcer interface {
+	announce(g *Game, winner PlayerInterface) string
+}
+
+// teamer is a WinCondition played in teams, where a win is the whole
+// team's
+type teamer interface {
+	team(p PlayerInterface) string
+}
+
+// WinSpec names a win condition and its settings, as config files and
+// checkpoints write it. Kind is points (the default), rounds,
+// elimination or team. Rounds is the length of a rounds game and Teams
+// maps player names to team names in a team game; anyone left out
+// plays as a team of one.
+type WinSpec struct {
+	Kind   string            `json:",omitempty"`
+	Rounds int               `json:",omitempty"`
+	Teams  map[string]string `json:",omitempty"`
+}
+
+// winKinds are the win conditions WinSpec can name
+var winKinds = []string{"points", "rounds", "elimination", "team"}
+
+// Condition builds the win condition the spec names
+func (s WinSpec) Condition() (WinCondition, error) {
+	switch s.Kind {
+	case "", "points":
+		return FirstToPoints{}, nil
+	case "rounds":
+		if s.Rounds < 1 {
+			return nil, fmt.Errorf("a rounds game needs at least 1 round")
+		}
+		return FixedRounds{Rounds: s.Rounds}, nil
+	case "elimination":
+		return Elimination{}, nil
+	case "team":
+		return TeamPoints{Teams: s.Teams}, nil
+	}
+	return nil, fmt.Errorf("unknown win condition %q (available: %s)", s.Kind, strings.Join(winKinds, ", "))
+}
+
+// SetWinCondition changes how the game is won. Points-based conditions
+// play to the winning score.
+func (g *Game) SetWinCondition(spec WinSpec) error {
+	condition, err := spec.Condition()
+	if err != nil {
+		return err
+	}
+	g.winSpec = spec
+	g.winCondition = condition
+	return nil
+}
+
+// condition returns the game's win condition, first to the winning
+// score unless another was set
+func (g *Game) condition() WinCondition {
+	if g.winCondition == nil {
+		return FirstToPoints{}
+	}
+	return g.winCondition
+}
+
+// endsNow reports whether the game would be over if the round ended as
+// it stands, so the leader would win it
+func (g *Game) endsNow() bool {
+	c, ok := g.condition().(finalRounder)
+	return ok && c.endsNow(g)
+}
+
+// mostPoints returns the player with the highest total among players,
+// the first of them on a tie
+func mostPoints(players []PlayerInterface) PlayerInterface {
+	var winner PlayerInterface
+	maxScore := -1
+	for _, player := range players {
+		if player.GetTotalScore() > maxScore {
+			maxScore = player.GetTotalScore()
+			winner = player
+		}
+	}
+	return winner
+}
+
+// winningSide returns who a win is credited to: the winner, or in a
+// team game everyone on the winner's team, in seat order
+func (g *Game) winningSide(winner PlayerInterface) []PlayerInterface {
+	t, ok := g.condition().(teamer)
+	if !ok {
+		return []PlayerInterface{winner}
+	}
+	return slices.DeleteFunc(slices.Clone(g.players), func(p PlayerInterface) bool {
+		return t.team(p) != t.team(winner)
+	})
+}
+
+// FirstToPoints is the standard game: the round that takes anyone to the
+// winning score ends it, and the highest total wins
+type FirstToPoints struct{}
+
+func (FirstToPoints) Goal(g *Game) string {
+	return fmt.Sprintf(T("First to %d points wins!"), g.winningScore)
+}
+
+func (FirstToPoints) Over(g *Game) bool {
+	for _, player := range g.players {
+		if player.GetTotalScore() >= g.winningScore {
+			return true
+		}
+	}
+	return false
+}
+
+func (FirstToPoints) endsNow(g *Game) bool {
+	for _, player := range g.players {
+		if player.GetTotalScore()+player.CalculateRoundScore() >= g.winningScore {
+			return true
+		}
+	}
+	return false
+}
+
+func (FirstToPoints) Winner(g *Game) PlayerInterface {
+	return mostPoints(g.players)
+}
+
+func (FirstToPoints) Leader(g *Game) PlayerInterface {
+	return leaderOf(g.players)
+}
+
+// FixedRounds plays a set number of rounds; the highest total wins
+type FixedRounds struct {
+	Rounds int
+}
+
+func (c FixedRounds) Goal(g *Game) string {
+	return fmt.Sprintf(T("Most points after %d rounds wins!"), c.Rounds)
+}
+
+func (c FixedRounds) Over(g *Game) bool {
+	return g.round > c.Rounds
+}
+
+func (c FixedRounds) endsNow(g *Game) bool {
+	return g.round >= c.Rounds
+}
+
+func (FixedRounds) Winner(g *Game) PlayerInterface {
+	return mostPoints(g.players)
+}
+
+func (FixedRounds) Leader(g *Game) PlayerInterface {
+	return leaderOf(g.players)
+}
+
+// Elimination knocks out the player with the lowest total after every
+// round, the first of them on a tie, and the last player left wins.
+// Knocked out players sit the remaining rounds out.
+type Elimination struct{}
+
+func (Elimination) Goal(g *Game) string {
+	return T("Lowest total is knocked out after each round; last player in wins!")
+}
+
+// remaining returns the players not knocked out yet
+func (Elimination) remaining(g *Game) []PlayerInterface {
+	return slices.DeleteFunc(slices.Clone(g.players), func(p PlayerInterface) bool {
+		return p.(baser).base().Eliminated
+	})
+}
+
+func (c Elimination) Over(g *Game) bool {
+	return len(c.remaining(g)) <= 1
+}
+
+// endsNow is true with two players left, as the round knocks one out
+func (c Elimination) endsNow(g *Game) bool {
+	return len(c.remaining(g)) <= 2
+}
+
+func (c Elimination) Winner(g *Game) PlayerInterface {
+	if remaining := c.remaining(g); len(remaining) > 0 {
+		return remaining[0]
+	}
+	return mostPoints(g.players)
+}
+
+func (c Elimination) Leader(g *Game) PlayerInterface {
+	return leaderOf(c.remaining(g))
+}
+
+func (c Elimination) eliminate(g *Game) PlayerInterface {
+	remaining := c.remaining(g)
+	if len(remaining) <= 1 {
+		return nil
+	}
+	var lowest PlayerInterface
+	for _, player := range remaining {
+		if lowest == nil || player.GetTotalScore() < lowest.GetTotalScore() {
+			lowest = player
+		}
+	}
+	return lowest
+}
+
+// TeamPoints adds up teammates' totals: the round that takes a team to
+// the winning score ends the game, and the team with the most points
+// wins. Teams maps player names to team names; anyone left out plays
+// alone.
+type TeamPoints struct {
+	Teams map[string]string
+}
+
+// team returns the team a player is on
+func (c TeamPoints) team(p PlayerInterface) string {
+	if team, ok := c.Teams[p.GetName()]; ok {
+		return team
+	}
+	return p.GetName()
+}
+
+// totals adds up each team's score, with or without the points in play,
+// and lists the teams in seat order
+func (c TeamPoints) totals(g *Game, inPlay bool) (map[string]int, []string) {
+	totals := make(map[string]int)
+	var teams []string
+	for _, player := range g.players {
+		team := c.team(player)
+		if _, ok := totals[team]; !ok {
+			teams = append(teams, team)
+		}
+		totals[team] += player.GetTotalScore()
+		if inPlay {
+			totals[team] += player.CalculateRoundScore()
+		}
+	}
+	return totals, teams
+}
+
+// best returns the team with the highest total, the first on a tie, and
+// its highest scoring player
+func (c TeamPoints) best(g *Game, inPlay bool) (string, PlayerInterface) {
+	totals, teams := c.totals(g, inPlay)
+	best := teams[0]
+	for _, team := range teams[1:] {
+		if totals[team] > totals[best] {
+			best = team
+		}
+	}
+	members := slices.DeleteFunc(slices.Clone(g.players), func(p PlayerInterface) bool { return c.team(p) != best })
+	if inPlay {
+		return best, leaderOf(members)
+	}
+	return best, mostPoints(members)
+}
+
+func (TeamPoints) Goal(g *Game) string {
+	return fmt.Sprintf(T("First team to %d points wins!"), g.winningScore)
+}
+
+func (c TeamPoints) Over(g *Game) bool {
+	totals, _ := c.totals(g, false)
+	for _, total := range totals {
+		if total >= g.winningScore {
+			return true
+		}
+	}
+	return false
+}
+
+func (c TeamPoints) endsNow(g *Game) bool {
+	totals, _ := c.totals(g, true)
+	for _, total := range totals {
+		if total >= g.winningScore {
+			return true
+		}
+	}
+	return false
+}
+
+func (c TeamPoints) Winner(g *Game) PlayerInterface {
+	_, player := c.best(g, false)
+	return player
+}
+
+func (c TeamPoints) Leader(g *Game) PlayerInterface {
+	_, player := c.best(g, true)
+	return player
+}
+
+func (c TeamPoints) announce(g *Game, winner PlayerInterface) string {
+	totals, _ := c.totals(g, false)
+	team := c.team(winner)
+	return fmt.Sprintf(T("🎉 GAME OVER! Team %s wins with %d points! 🎉"), team, totals[team])
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestKnockedOutSeatsDontDeal(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	if err := g.SetWinCondition(WinSpec{Kind: "elimination"}); err != nil {
+		t.Fatal(err)
+	}
+	g.players = table("Ada 50", "Bo 10", "Cy 30", "Di 40")
+	g.players[2].(baser).base().Eliminated = true
+
+	// Bo is knocked out, and Cy is already out, so the deal passes to Di
+	g.nextRound()
+	if !g.players[1].(baser).base().Eliminated {
+		t.Fatal("Bo wasn't knocked out with the lowest total")
+	}
+	if got := nameOf(g.players[g.dealerIdx]); got != "Di" {
+		t.Errorf("%s deals after Ada, want Di", got)
+	}
+	g.nextRound()
+	if got := nameOf(g.players[g.dealerIdx]); got != "Ada" {
+		t.Errorf("%s deals after Di, want Ada", got)
+	}
+}
+
+func TestTeamWinsAreTheTeams(t *testing.T) {
+	g := NewGame()
+	g.SetOutput(io.Discard)
+	if err := g.SetWinCondition(WinSpec{Kind: "team", Teams: map[string]string{"Ada": "red", "Cy": "red"}}); err != nil {
+		t.Fatal(err)
+	}
+	g.players = table("Ada 100", "Bo 150", "Cy 120")
+
+	winner := g.getWinner()
+	result := g.gameResult(1, 1, 0, winner)
+	if result.Team != "red" || !slices.Equal(result.Winners, []string{"Ada", "Cy"}) {
+		t.Errorf("the result credits team %q and %v, want red and [Ada Cy]", result.Team, result.Winners)
+	}
+
+	sim := &Simulation{Wins: map[string]int{}, Scores: map[string]int{}}
+	if err := g.recordSimulatedGame(sim, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if sim.Wins["Ada"] != 1 || sim.Wins["Cy"] != 1 || sim.Wins["Bo"] != 0 {
+		t.Errorf("the simulation credits %v, want a win each for Ada and Cy", sim.Wins)
+	}
+
+	var file bytes.Buffer
+	if err := json.NewEncoder(&file).Encode(result); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := SummarizeResults(&file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Wins["Ada"] != 1 || summary.Wins["Cy"] != 1 || summary.Wins["Bo"] != 0 {
+		t.Errorf("the summary credits %v, want a win each for Ada and Cy", summary.Wins)
+	}
+}